@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+)
+
+// EvalCase is a labelled incident from the corpus
+type EvalCase struct {
+	ID                string            `json:"id"`
+	Title             string            `json:"title"`
+	Description       string            `json:"description"`
+	Logs              []string          `json:"logs,omitempty"`
+	ExpectedSeverity  string            `json:"expected_severity"`
+	RootCauseKeywords []string          `json:"expected_root_cause_keywords"`
+	AdditionalContext map[string]string `json:"additional_context,omitempty"`
+}
+
+// Variant is one configuration under evaluation: a client plus the prompt version it is asked to use
+type Variant struct {
+	Name          string
+	PromptVersion string
+	Client        ai.Client
+	// RCA also generates an RCA per case and scores its root cause
+	RCA bool
+}
+
+// CaseResult is the scored outcome of one case for one variant
+type CaseResult struct {
+	CaseID            string        `json:"case_id"`
+	PredictedSeverity string        `json:"predicted_severity"`
+	SeverityCorrect   bool          `json:"severity_correct"`
+	RootCauseRecall   float64       `json:"root_cause_recall"`
+	MissingKeywords   []string      `json:"missing_keywords,omitempty"`
+	ValidJSON         bool          `json:"valid_json"`
+	Latency           time.Duration `json:"latency_ns"`
+	Error             string        `json:"error,omitempty"`
+}
+
+// Summary aggregates case results for a variant
+type Summary struct {
+	Variant          string       `json:"variant"`
+	Provider         string       `json:"provider"`
+	Model            string       `json:"model"`
+	PromptVersion    string       `json:"prompt_version"`
+	Cases            int          `json:"cases"`
+	Errors           int          `json:"errors"`
+	SeverityAccuracy float64      `json:"severity_accuracy"`
+	RootCauseRecall  float64      `json:"root_cause_recall"`
+	JSONValidity     float64      `json:"json_validity"`
+	MeanLatencyMs    float64      `json:"mean_latency_ms"`
+	P95LatencyMs     float64      `json:"p95_latency_ms"`
+	Results          []CaseResult `json:"results"`
+}
+
+// Report compares a baseline variant with an optional candidate
+type Report struct {
+	Baseline  *Summary `json:"baseline"`
+	Candidate *Summary `json:"candidate,omitempty"`
+}
+
+// LoadCorpus reads a JSON array of labelled incidents
+func LoadCorpus(path string) ([]EvalCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corpus: %w", err)
+	}
+
+	var cases []EvalCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("invalid corpus %s: %w", path, err)
+	}
+	for i, c := range cases {
+		if c.ID == "" || c.Title == "" {
+			return nil, fmt.Errorf("corpus case %d: id and title are required", i)
+		}
+	}
+	return cases, nil
+}
+
+// Evaluate runs every case through the variant and scores the results
+func Evaluate(ctx context.Context, v Variant, cases []EvalCase, timeout time.Duration) *Summary {
+	summary := &Summary{
+		Variant:       v.Name,
+		Provider:      string(v.Client.Provider()),
+		Model:         v.Client.Model(),
+		PromptVersion: v.PromptVersion,
+		Cases:         len(cases),
+	}
+	if summary.PromptVersion == "" {
+		summary.PromptVersion = ai.DefaultPromptVersion
+	}
+
+	for _, c := range cases {
+		summary.Results = append(summary.Results, evaluateCase(ctx, v, c, timeout))
+	}
+
+	var latencies []float64
+	for _, r := range summary.Results {
+		if r.Error != "" {
+			summary.Errors++
+		}
+		if r.SeverityCorrect {
+			summary.SeverityAccuracy++
+		}
+		if r.ValidJSON {
+			summary.JSONValidity++
+		}
+		summary.RootCauseRecall += r.RootCauseRecall
+		latencies = append(latencies, float64(r.Latency.Microseconds())/1000)
+	}
+	if n := float64(len(cases)); n > 0 {
+		summary.SeverityAccuracy /= n
+		summary.JSONValidity /= n
+		summary.RootCauseRecall /= n
+		summary.MeanLatencyMs, summary.P95LatencyMs = latencyStats(latencies)
+	}
+	return summary
+}
+
+func evaluateCase(ctx context.Context, v Variant, c EvalCase, timeout time.Duration) CaseResult {
+	result := CaseResult{CaseID: c.ID}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	analysis, err := v.Client.AnalyzeIncident(ctx, ai.AnalysisRequest{
+		IncidentTitle:     c.Title,
+		IncidentDesc:      c.Description,
+		Logs:              c.Logs,
+		AdditionalContext: c.AdditionalContext,
+		PromptVersion:     v.PromptVersion,
+	})
+	if err != nil {
+		result.Latency = time.Since(start)
+		result.Error = err.Error()
+		result.MissingKeywords = c.RootCauseKeywords
+		return result
+	}
+
+	evidence := []string{strings.Join(analysis.RootCauses, " ")}
+	valid := ai.ValidJSONResponse(analysis.RawResponse)
+
+	if v.RCA {
+		rca, err := v.Client.GenerateRCA(ctx, ai.RCARequest{
+			IncidentTitle: c.Title,
+			IncidentDesc:  c.Description,
+			Analysis:      *analysis,
+		})
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			evidence = append(evidence, rca.RootCause)
+			valid = valid && ai.ValidJSONResponse(rca.RawResponse)
+		}
+	}
+	result.Latency = time.Since(start)
+
+	result.PredictedSeverity = strings.ToLower(strings.TrimSpace(analysis.SuggestedSeverity))
+	result.SeverityCorrect = result.PredictedSeverity == strings.ToLower(c.ExpectedSeverity)
+	result.ValidJSON = valid
+	result.RootCauseRecall, result.MissingKeywords = keywordRecall(strings.Join(evidence, " "), c.RootCauseKeywords)
+	return result
+}
+
+// keywordRecall returns the share of keywords found (case-insensitively) in text and the ones missing
+func keywordRecall(text string, keywords []string) (float64, []string) {
+	if len(keywords) == 0 {
+		return 1, nil
+	}
+
+	text = strings.ToLower(text)
+	var missing []string
+	for _, kw := range keywords {
+		if !strings.Contains(text, strings.ToLower(kw)) {
+			missing = append(missing, kw)
+		}
+	}
+	return float64(len(keywords)-len(missing)) / float64(len(keywords)), missing
+}
+
+// latencyStats returns the mean and nearest-rank 95th percentile
+func latencyStats(ms []float64) (float64, float64) {
+	if len(ms) == 0 {
+		return 0, 0
+	}
+	sorted := append([]float64(nil), ms...)
+	sort.Float64s(sorted)
+
+	total := 0.0
+	for _, v := range sorted {
+		total += v
+	}
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return total / float64(len(sorted)), sorted[rank]
+}
+
+// Regressions lists the quality metrics where the candidate is worse than the baseline by more than tolerance
+func (r *Report) Regressions(tolerance float64) []string {
+	if r.Candidate == nil {
+		return nil
+	}
+
+	var regressions []string
+	check := func(name string, base, cand float64) {
+		if base-cand > tolerance {
+			regressions = append(regressions, fmt.Sprintf("%s dropped from %.2f to %.2f", name, base, cand))
+		}
+	}
+	check("severity accuracy", r.Baseline.SeverityAccuracy, r.Candidate.SeverityAccuracy)
+	check("root cause recall", r.Baseline.RootCauseRecall, r.Candidate.RootCauseRecall)
+	check("JSON validity", r.Baseline.JSONValidity, r.Candidate.JSONValidity)
+	return regressions
+}
+
+// WriteMarkdown renders the report as Markdown tables
+func (r *Report) WriteMarkdown(w io.Writer) {
+	summaries := []*Summary{r.Baseline}
+	if r.Candidate != nil {
+		summaries = append(summaries, r.Candidate)
+	}
+
+	fmt.Fprintf(w, "# AI evaluation report\n\n")
+	fmt.Fprintf(w, "| Metric |")
+	for _, s := range summaries {
+		fmt.Fprintf(w, " %s |", s.Variant)
+	}
+	if r.Candidate != nil {
+		fmt.Fprintf(w, " Delta |")
+	}
+	fmt.Fprintf(w, "\n|---|")
+	for range summaries {
+		fmt.Fprintf(w, "---|")
+	}
+	if r.Candidate != nil {
+		fmt.Fprintf(w, "---|")
+	}
+	fmt.Fprintln(w)
+
+	row := func(name string, value func(*Summary) string, delta func() string) {
+		fmt.Fprintf(w, "| %s |", name)
+		for _, s := range summaries {
+			fmt.Fprintf(w, " %s |", value(s))
+		}
+		if r.Candidate != nil {
+			fmt.Fprintf(w, " %s |", delta())
+		}
+		fmt.Fprintln(w)
+	}
+	metric := func(name string, get func(*Summary) float64, format string) {
+		row(name, func(s *Summary) string { return fmt.Sprintf(format, get(s)) }, func() string {
+			return fmt.Sprintf("%+"+strings.TrimPrefix(format, "%"), get(r.Candidate)-get(r.Baseline))
+		})
+	}
+
+	row("Provider / model", func(s *Summary) string { return s.Provider + " / " + s.Model }, func() string { return "" })
+	row("Prompt version", func(s *Summary) string { return s.PromptVersion }, func() string { return "" })
+	metric("Severity accuracy", func(s *Summary) float64 { return s.SeverityAccuracy }, "%.2f")
+	metric("Root cause recall", func(s *Summary) float64 { return s.RootCauseRecall }, "%.2f")
+	metric("JSON validity", func(s *Summary) float64 { return s.JSONValidity }, "%.2f")
+	metric("Mean latency (ms)", func(s *Summary) float64 { return s.MeanLatencyMs }, "%.1f")
+	metric("P95 latency (ms)", func(s *Summary) float64 { return s.P95LatencyMs }, "%.1f")
+	metric("Errors", func(s *Summary) float64 { return float64(s.Errors) }, "%.0f")
+
+	fmt.Fprintf(w, "\n## Cases\n\n| Case |")
+	for _, s := range summaries {
+		fmt.Fprintf(w, " %s severity | %s recall |", s.Variant, s.Variant)
+	}
+	fmt.Fprintf(w, "\n|---|")
+	for range summaries {
+		fmt.Fprintf(w, "---|---|")
+	}
+	fmt.Fprintln(w)
+
+	for i, res := range r.Baseline.Results {
+		fmt.Fprintf(w, "| %s |", res.CaseID)
+		for _, s := range summaries {
+			cr := s.Results[i]
+			mark := "✗"
+			if cr.SeverityCorrect {
+				mark = "✓"
+			}
+			severity := cr.PredictedSeverity
+			if cr.Error != "" {
+				severity = "error"
+			}
+			fmt.Fprintf(w, " %s %s | %.2f |", mark, severity, cr.RootCauseRecall)
+		}
+		fmt.Fprintln(w)
+	}
+}
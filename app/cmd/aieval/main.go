@@ -0,0 +1,147 @@
+// Command aieval scores AI incident analysis against a labelled corpus and compares two
+// variants (prompt versions, models or providers). With cassettes in replay mode it runs
+// entirely offline, which is how CI uses it.
+//
+//	aieval -corpus testdata/corpus.json \
+//	  -a "name=v1,provider=openai,model=gpt-4,prompt=v1,cassette=testdata/openai-v1.cassette.json" \
+//	  -b "name=v2,provider=openai,model=gpt-4,prompt=v2,cassette=testdata/openai-v2.cassette.json"
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "aieval:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("aieval", flag.ContinueOnError)
+	corpusPath := fs.String("corpus", "testdata/corpus.json", "path to the labelled incident corpus")
+	specA := fs.String("a", "", "baseline variant: comma separated key=value pairs (name, provider, model, prompt, cassette, mode, base_url, rca)")
+	specB := fs.String("b", "", "candidate variant to compare against the baseline (optional)")
+	format := fs.String("format", "markdown", "report format: markdown or json")
+	timeout := fs.Duration("timeout", 60*time.Second, "per-case timeout")
+	maxRegression := fs.Float64("max-regression", -1, "exit non-zero when a candidate quality metric drops by more than this (disabled when negative)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *specA == "" {
+		return fmt.Errorf("-a is required")
+	}
+
+	cases, err := LoadCorpus(*corpusPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	baseline, err := parseVariant(*specA, "baseline")
+	if err != nil {
+		return err
+	}
+	report := &Report{Baseline: Evaluate(ctx, baseline, cases, *timeout)}
+
+	if *specB != "" {
+		candidate, err := parseVariant(*specB, "candidate")
+		if err != nil {
+			return err
+		}
+		report.Candidate = Evaluate(ctx, candidate, cases, *timeout)
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	case "markdown":
+		report.WriteMarkdown(out)
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	if *maxRegression >= 0 {
+		if regressions := report.Regressions(*maxRegression); len(regressions) > 0 {
+			return fmt.Errorf("quality regression: %s", strings.Join(regressions, "; "))
+		}
+	}
+	return nil
+}
+
+// parseVariant builds a variant from a spec such as "provider=openai,model=gpt-4,prompt=v2,cassette=x.json"
+func parseVariant(spec, defaultName string) (Variant, error) {
+	fields := map[string]string{}
+	for _, part := range strings.Split(spec, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return Variant{}, fmt.Errorf("invalid variant field %q (want key=value)", part)
+		}
+		fields[kv[0]] = kv[1]
+	}
+
+	v := Variant{
+		Name:          fields["name"],
+		PromptVersion: fields["prompt"],
+	}
+	if v.Name == "" {
+		v.Name = defaultName
+	}
+	if fields["rca"] != "" {
+		rca, err := strconv.ParseBool(fields["rca"])
+		if err != nil {
+			return Variant{}, fmt.Errorf("invalid rca value %q", fields["rca"])
+		}
+		v.RCA = rca
+	}
+
+	provider := ai.Provider(fields["provider"])
+	cfg := ai.ClientConfig{
+		Provider: provider,
+		Model:    fields["model"],
+		BaseURL:  fields["base_url"],
+		APIKey:   apiKeyFor(provider),
+	}
+
+	var err error
+	if cassette := fields["cassette"]; cassette != "" {
+		mode := ai.CassetteMode(fields["mode"])
+		if mode == "" {
+			mode = ai.CassetteReplay
+		}
+		v.Client, _, err = ai.NewCassetteClient(cfg, cassette, mode)
+	} else {
+		v.Client, err = ai.NewClient(cfg)
+	}
+	if err != nil {
+		return Variant{}, fmt.Errorf("variant %s: %w", v.Name, err)
+	}
+	return v, nil
+}
+
+// apiKeyFor reads the provider API key from the environment
+func apiKeyFor(provider ai.Provider) string {
+	switch provider {
+	case ai.ProviderAnthropic:
+		return os.Getenv("ANTHROPIC_API_KEY")
+	case ai.ProviderOpenAI:
+		return os.Getenv("OPENAI_API_KEY")
+	}
+	return ""
+}
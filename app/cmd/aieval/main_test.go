@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const (
+	variantV1 = "name=v1,provider=openai,model=gpt-4,prompt=v1,cassette=testdata/openai-v1.cassette.json"
+	variantV2 = "name=v2,provider=openai,model=gpt-4,prompt=v2,cassette=testdata/openai-v2.cassette.json"
+)
+
+func TestRunReplayComparison(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"-a", variantV1, "-b", variantV2, "-format", "json"}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+
+	if report.Baseline.Cases != 6 || report.Candidate.Cases != 6 {
+		t.Fatalf("expected 6 cases per variant, got %d and %d", report.Baseline.Cases, report.Candidate.Cases)
+	}
+	if report.Baseline.Errors != 0 || report.Candidate.Errors != 0 {
+		t.Errorf("expected replay without errors, got %d and %d", report.Baseline.Errors, report.Candidate.Errors)
+	}
+	if report.Baseline.SeverityAccuracy != 0.5 || report.Candidate.SeverityAccuracy != 1 {
+		t.Errorf("unexpected severity accuracy %.2f / %.2f", report.Baseline.SeverityAccuracy, report.Candidate.SeverityAccuracy)
+	}
+	if report.Baseline.JSONValidity >= 1 {
+		t.Errorf("expected the prose response in v1 to count as invalid JSON, got %.2f", report.Baseline.JSONValidity)
+	}
+	if report.Candidate.RootCauseRecall != 1 {
+		t.Errorf("expected full root cause recall for v2, got %.2f", report.Candidate.RootCauseRecall)
+	}
+}
+
+func TestRunDetectsRegression(t *testing.T) {
+	var out bytes.Buffer
+	err := run([]string{"-a", variantV2, "-b", variantV1, "-max-regression", "0.1"}, &out)
+	if err == nil || !strings.Contains(err.Error(), "severity accuracy dropped") {
+		t.Errorf("expected severity regression error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "| Severity accuracy | 1.00 | 0.50 | -0.50 |") {
+		t.Errorf("expected markdown comparison row, got:\n%s", out.String())
+	}
+}
+
+func TestKeywordRecall(t *testing.T) {
+	recall, missing := keywordRecall("Connection pool EXHAUSTED by leaked clients", []string{"connection pool", "exhausted", "dns"})
+	if recall != 2.0/3.0 || len(missing) != 1 || missing[0] != "dns" {
+		t.Errorf("unexpected recall %.2f missing %v", recall, missing)
+	}
+}
@@ -9,9 +9,13 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
+	// Schedules name IANA time zones, which minimal container images do not ship
+	_ "time/tzdata"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,9 +24,19 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/blob"
 	"github.com/Prakash-sa/terraform-aws/app/pkg/config"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/export"
 	"github.com/Prakash-sa/terraform-aws/app/pkg/handlers"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/kube"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/notify"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/promapi"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/rules"
 	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/slack"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/statuspage"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/tracker"
 )
 
 type ctxKey string
@@ -68,6 +82,11 @@ type Server struct {
 	cfg             AppConfig
 	incidentService *service.IncidentService
 	incidentHandler *handlers.IncidentHandler
+	// stopEscalations cancels the background escalation scheduler, SLA evaluator and issue reconciliation
+	stopEscalations context.CancelFunc
+	slackApp        *slack.App
+	// issueSyncInterval is how often linked issues are reconciled; zero when no tracker is configured
+	issueSyncInterval time.Duration
 }
 
 type HealthResponse struct {
@@ -119,13 +138,201 @@ func NewServer(cfg AppConfig) *Server {
 		logger.Warn("failed to create AI client", zap.Error(err))
 	}
 
+	// AI_CASSETTE records provider traffic to a file or replays it offline for tests and demos
+	if cassettePath := getEnv("AI_CASSETTE", ""); cassettePath != "" {
+		mode := ai.CassetteMode(getEnv("AI_CASSETTE_MODE", string(ai.CassetteReplay)))
+		cassetteClient, err := newCassetteClient(ai.Provider(aiCfg.AI.Provider), aiCfg.AI.Model, cassettePath, mode)
+		if err != nil {
+			logger.Warn("failed to load AI cassette", zap.String("path", cassettePath), zap.Error(err))
+		} else {
+			aiClient = cassetteClient
+			logger.Info("AI cassette enabled", zap.String("path", cassettePath), zap.String("mode", string(mode)))
+		}
+	}
+
+	var serviceOpts []service.ServiceOption
+	if deployURL := getEnv("DEPLOY_SOURCE_URL", ""); deployURL != "" {
+		serviceOpts = append(serviceOpts, service.WithDeploySource(service.NewHTTPDeploySource(deployURL)))
+	}
+
+	if rulesFile := getEnv("SEVERITY_RULES_FILE", ""); rulesFile != "" {
+		engine, err := rules.LoadFile(rulesFile)
+		if err != nil {
+			logger.Warn("failed to load severity rules, using defaults", zap.String("path", rulesFile), zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithRulesEngine(engine))
+		}
+	}
+
+	if promptVersion := getEnv("AI_PROMPT_VERSION", ""); promptVersion != "" {
+		serviceOpts = append(serviceOpts, service.WithPromptVersion(promptVersion))
+	}
+
+	if templateDir := getEnv("RCA_TEMPLATE_DIR", ""); templateDir != "" {
+		renderer, err := export.NewRenderer(templateDir)
+		if err != nil {
+			logger.Warn("failed to load RCA templates, using defaults", zap.String("dir", templateDir), zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithRCARenderer(renderer))
+		}
+	}
+
+	// AI_SEVERITY_CLASSIFICATION asks the AI provider for a severity when incidents are created
+	if getEnv("AI_SEVERITY_CLASSIFICATION", "false") == "true" {
+		timeout, err := time.ParseDuration(getEnv("AI_SEVERITY_TIMEOUT", "5s"))
+		if err != nil || timeout <= 0 {
+			logger.Warn("invalid AI_SEVERITY_TIMEOUT, using 5s", zap.Error(err))
+			timeout = 5 * time.Second
+		}
+		serviceOpts = append(serviceOpts, service.WithAISeverity(timeout))
+	}
+
+	blobStore, err := newBlobStore(getEnv("ATTACHMENT_STORE", "local"))
+	if err != nil {
+		logger.Warn("failed to configure attachment storage, attachments disabled", zap.Error(err))
+	} else if blobStore != nil {
+		serviceOpts = append(serviceOpts, service.WithBlobStore(blobStore))
+	}
+	if maxBytes := getEnv("ATTACHMENT_MAX_BYTES", ""); maxBytes != "" {
+		limit, err := strconv.ParseInt(maxBytes, 10, 64)
+		if err != nil || limit <= 0 {
+			logger.Warn("invalid ATTACHMENT_MAX_BYTES, using default", zap.String("value", maxBytes))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithAttachmentLimit(limit))
+		}
+	}
+
+	if getEnv("KUBERNETES_ENRICHMENT", "false") == "true" {
+		client, err := newKubeClient()
+		if err != nil {
+			logger.Warn("failed to configure Kubernetes enrichment, disabled", zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithEnricher(kube.NewEnricher(client)))
+		}
+	}
+
+	if window := getEnv("CHANGE_CORRELATION_WINDOW", ""); window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil || d <= 0 {
+			logger.Warn("invalid CHANGE_CORRELATION_WINDOW, using default", zap.String("value", window))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithChangeWindow(d))
+		}
+	}
+
+	if catalogFile := getEnv("SERVICE_CATALOG_FILE", ""); catalogFile != "" {
+		services, err := service.LoadServiceCatalog(catalogFile)
+		if err != nil {
+			logger.Warn("failed to load service catalog, starting empty", zap.String("path", catalogFile), zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithServiceCatalog(services))
+		}
+	}
+
+	if promURL := getEnv("PROMETHEUS_URL", ""); promURL != "" {
+		enricher, err := newPrometheusEnricher(promURL)
+		if err != nil {
+			logger.Warn("failed to configure Prometheus enrichment, disabled", zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithEnricher(enricher))
+		}
+	}
+
+	if oncallFile := getEnv("ONCALL_CONFIG_FILE", ""); oncallFile != "" {
+		oncall, err := service.LoadOnCallConfig(oncallFile)
+		if err != nil {
+			logger.Warn("failed to load on-call config, starting empty", zap.String("path", oncallFile), zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithOnCallConfig(oncall))
+		}
+	}
+	if policy := getEnv("DEFAULT_ESCALATION_POLICY", ""); policy != "" {
+		serviceOpts = append(serviceOpts, service.WithDefaultEscalationPolicy(policy))
+	}
+
+	if slaFile := getEnv("SLA_CONFIG_FILE", ""); slaFile != "" {
+		sla, err := service.LoadSLAConfig(slaFile)
+		if err != nil {
+			logger.Warn("failed to load SLA config, starting without SLA policies", zap.String("path", slaFile), zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithSLAConfig(sla))
+		}
+	}
+
+	if notifyFile := getEnv("NOTIFICATIONS_CONFIG_FILE", ""); notifyFile != "" {
+		dispatcher, err := newNotifier(notifyFile)
+		if err != nil {
+			logger.Warn("failed to configure notifications, disabled", zap.String("path", notifyFile), zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithNotifier(dispatcher))
+		}
+	}
+
+	if attempts, backoff := getEnv("WEBHOOK_MAX_ATTEMPTS", ""), getEnv("WEBHOOK_RETRY_BACKOFF", ""); attempts != "" || backoff != "" {
+		n, d := service.DefaultWebhookAttempts, service.DefaultWebhookBackoff
+		if attempts != "" {
+			if v, err := strconv.Atoi(attempts); err != nil || v <= 0 {
+				logger.Warn("invalid WEBHOOK_MAX_ATTEMPTS, using default", zap.String("value", attempts))
+			} else {
+				n = v
+			}
+		}
+		if backoff != "" {
+			if v, err := time.ParseDuration(backoff); err != nil || v <= 0 {
+				logger.Warn("invalid WEBHOOK_RETRY_BACKOFF, using default", zap.String("value", backoff))
+			} else {
+				d = v
+			}
+		}
+		serviceOpts = append(serviceOpts, service.WithWebhookRetry(n, d))
+	}
+
+	var issueSyncInterval time.Duration
+	if trackersFile := getEnv("ISSUE_TRACKERS_CONFIG_FILE", ""); trackersFile != "" {
+		trackers, interval, err := newIssueTrackers(trackersFile)
+		if err != nil {
+			logger.Warn("failed to configure issue trackers, disabled", zap.String("path", trackersFile), zap.Error(err))
+		} else {
+			for _, t := range trackers {
+				serviceOpts = append(serviceOpts, service.WithIssueTracker(t))
+			}
+			issueSyncInterval = interval
+		}
+	}
+
+	if statusFile := getEnv("STATUS_PAGE_CONFIG_FILE", ""); statusFile != "" {
+		statusPage, err := statuspage.LoadConfig(statusFile)
+		if err != nil {
+			logger.Warn("failed to load status page config, starting empty", zap.String("path", statusFile), zap.Error(err))
+		} else {
+			serviceOpts = append(serviceOpts, service.WithStatusPage(statusPage))
+		}
+	}
+
 	incidentStore := service.NewIncidentStore()
-	incidentService := service.NewIncidentService(incidentStore, aiClient, logger)
-	incidentHandler := handlers.NewIncidentHandler(incidentService, logger)
+	incidentService := service.NewIncidentService(incidentStore, aiClient, logger, serviceOpts...)
+	if err := prometheus.Register(service.NewMetricsCollector(incidentService)); err != nil {
+		logger.Warn("failed to register incident metrics", zap.Error(err))
+	}
+	var handlerOpts []handlers.HandlerOption
+	if secret := getEnv("CHANGE_WEBHOOK_SECRET", ""); secret != "" {
+		handlerOpts = append(handlerOpts, handlers.WithChangeWebhookSecret(secret))
+	}
+	if slackFile, secret := getEnv("SLACK_CONFIG_FILE", ""), getEnv("SLACK_SIGNING_SECRET", ""); slackFile != "" || secret != "" {
+		app, err := newSlackApp(slackFile, secret)
+		if err != nil {
+			logger.Warn("failed to configure slack app, disabled", zap.String("path", slackFile), zap.Error(err))
+		} else {
+			s.slackApp = app
+			handlerOpts = append(handlerOpts, handlers.WithSlack(app))
+		}
+	}
+	incidentHandler := handlers.NewIncidentHandler(incidentService, logger, handlerOpts...)
 	incidentHandler.RegisterRoutes(s.router)
 
 	s.incidentService = incidentService
 	s.incidentHandler = incidentHandler
+	s.issueSyncInterval = issueSyncInterval
 
 	s.server = &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -146,12 +353,52 @@ func (s *Server) Start() error {
 		zap.Time("start_time", startTime),
 	)
 
+	interval := service.DefaultEscalationInterval
+	if value := getEnv("ESCALATION_INTERVAL", ""); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			logger.Warn("invalid ESCALATION_INTERVAL, using default", zap.String("value", value))
+		} else {
+			interval = d
+		}
+	}
+	slaInterval := service.DefaultSLAInterval
+	if value := getEnv("SLA_EVALUATION_INTERVAL", ""); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			logger.Warn("invalid SLA_EVALUATION_INTERVAL, using default", zap.String("value", value))
+		} else {
+			slaInterval = d
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopEscalations = cancel
+	go s.incidentService.RunEscalations(ctx, interval)
+	go s.incidentService.RunSLAEvaluator(ctx, slaInterval)
+	if s.issueSyncInterval > 0 {
+		go s.incidentService.RunIssueSync(ctx, s.issueSyncInterval)
+	}
+
 	return s.server.ListenAndServe()
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
 	logger.Info("shutting down server gracefully...")
-	return s.server.Shutdown(ctx)
+	err := s.server.Shutdown(ctx)
+	if s.stopEscalations != nil {
+		s.stopEscalations()
+	}
+	if s.slackApp != nil {
+		s.slackApp.Wait()
+	}
+	if s.incidentService != nil {
+		s.incidentService.WaitForClassifications()
+		s.incidentService.WaitForEnrichments()
+		s.incidentService.CloseNotifications()
+		s.incidentService.StopWebhooks()
+		s.incidentService.WaitForIssueSyncs()
+	}
+	return err
 }
 
 func recoverMiddleware(next http.Handler) http.Handler {
@@ -430,6 +677,128 @@ func requestID() string {
 	return fmt.Sprintf("%d", rand.Int63())
 }
 
+// newCassetteClient builds a provider client that records to or replays from a cassette file
+func newCassetteClient(provider ai.Provider, model, path string, mode ai.CassetteMode) (ai.Client, error) {
+	apiKey := ""
+	if mode == ai.CassetteRecord {
+		switch provider {
+		case ai.ProviderAnthropic:
+			apiKey = getEnv("ANTHROPIC_API_KEY", "")
+		default:
+			apiKey = getEnv("OPENAI_API_KEY", "")
+		}
+	}
+
+	client, _, err := ai.NewCassetteClient(ai.ClientConfig{
+		Provider: provider,
+		APIKey:   apiKey,
+		Model:    model,
+	}, path, mode)
+	return client, err
+}
+
+// newBlobStore builds the attachment store selected by ATTACHMENT_STORE; "none" disables attachments
+func newBlobStore(kind string) (blob.Store, error) {
+	switch kind {
+	case "none":
+		return nil, nil
+	case "local":
+		return blob.NewLocalStore(getEnv("ATTACHMENT_DIR", filepath.Join(os.TempDir(), "incident-attachments")))
+	case "s3":
+		return blob.NewS3Store(blob.S3Config{
+			Endpoint:  getEnv("S3_ENDPOINT", ""),
+			Region:    getEnv("S3_REGION", getEnv("AWS_REGION", "")),
+			Bucket:    getEnv("S3_BUCKET", ""),
+			AccessKey: getEnv("S3_ACCESS_KEY_ID", getEnv("AWS_ACCESS_KEY_ID", "")),
+			SecretKey: getEnv("S3_SECRET_ACCESS_KEY", getEnv("AWS_SECRET_ACCESS_KEY", "")),
+			PathStyle: getEnv("S3_PATH_STYLE", "false") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("unknown ATTACHMENT_STORE %q", kind)
+	}
+}
+
+// newKubeClient uses the pod's service account unless KUBERNETES_API_URL points elsewhere
+func newKubeClient() (*kube.Client, error) {
+	cfg := kube.Config{
+		Host:     getEnv("KUBERNETES_API_URL", ""),
+		Token:    getEnv("KUBERNETES_TOKEN", ""),
+		Insecure: getEnv("KUBERNETES_INSECURE", "false") == "true",
+	}
+	if cfg.Host == "" {
+		var err error
+		if cfg, err = kube.InClusterConfig(); err != nil {
+			return nil, err
+		}
+	}
+	cfg.Namespace = getEnv("KUBERNETES_NAMESPACE", cfg.Namespace)
+	return kube.NewClient(cfg)
+}
+
+// newPrometheusEnricher runs the queries in PROMETHEUS_QUERIES_FILE; without it only
+// queries in incident metadata are run
+func newPrometheusEnricher(url string) (*promapi.Enricher, error) {
+	var cfg promapi.Config
+	if path := getEnv("PROMETHEUS_QUERIES_FILE", ""); path != "" {
+		var err error
+		if cfg, err = promapi.LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+	return promapi.NewEnricher(promapi.NewClient(url, getEnv("PROMETHEUS_TOKEN", "")), cfg)
+}
+
+// newNotifier loads the channels and routes in path
+func newNotifier(path string) (*notify.Dispatcher, error) {
+	cfg, err := notify.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return notify.NewDispatcher(cfg, logger)
+}
+
+// newIssueTrackers creates a connector for every configured tracker and returns the reconciliation interval
+func newIssueTrackers(path string) ([]tracker.Connector, time.Duration, error) {
+	cfg, err := tracker.LoadConfig(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(cfg.Trackers) == 0 {
+		return nil, 0, fmt.Errorf("no trackers configured")
+	}
+	interval := service.DefaultIssueSyncInterval
+	if cfg.SyncInterval != "" {
+		if interval, err = time.ParseDuration(cfg.SyncInterval); err != nil || interval <= 0 {
+			return nil, 0, fmt.Errorf("invalid sync_interval %q", cfg.SyncInterval)
+		}
+	}
+
+	connectors := make([]tracker.Connector, 0, len(cfg.Trackers))
+	for _, tc := range cfg.Trackers {
+		connector, err := tracker.New(tc)
+		if err != nil {
+			return nil, 0, err
+		}
+		connectors = append(connectors, connector)
+	}
+	return connectors, interval, nil
+}
+
+// newSlackApp loads the Slack app config; SLACK_SIGNING_SECRET overrides the secret in the file
+func newSlackApp(path, secret string) (*slack.App, error) {
+	var cfg slack.Config
+	if path != "" {
+		var err error
+		if cfg, err = slack.LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+	if secret != "" {
+		cfg.SigningSecret = secret
+	}
+	return slack.NewApp(cfg)
+}
+
 func runHealthCheck(cfg AppConfig) error {
 	client := &http.Client{
 		Timeout: 3 * time.Second,
@@ -0,0 +1,221 @@
+// Package aifake provides a scriptable HTTP server that mimics the OpenAI Chat Completions
+// and Anthropic Messages APIs, so AI code paths can be tested offline against real wire formats.
+package aifake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+)
+
+const (
+	openaiPath    = "/v1/chat/completions"
+	anthropicPath = "/v1/messages"
+)
+
+// Response is a scripted reply. A zero Status means 200.
+type Response struct {
+	Status int
+	Body   string
+	// Delay holds the response back, honouring client cancellation, to simulate slow providers
+	Delay time.Duration
+}
+
+// Request is a request received by the fake server
+type Request struct {
+	Method  string
+	Path    string
+	Headers http.Header
+	Body    []byte
+}
+
+// JSON decodes the request body into v
+func (r Request) JSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Server is a fake provider. Scripted responses are served in FIFO order across both APIs.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	queue    []Response
+	requests []Request
+}
+
+// NewServer starts a fake provider server; callers must Close it
+func NewServer() *Server {
+	s := &Server{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(openaiPath, s.handle)
+	mux.HandleFunc(anthropicPath, s.handle)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Enqueue scripts the next responses
+func (s *Server) Enqueue(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, responses...)
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Config returns a client configuration pointing at the fake server
+func (s *Server) Config(provider ai.Provider) ai.ClientConfig {
+	return ai.ClientConfig{
+		Provider: provider,
+		APIKey:   "test-key",
+		BaseURL:  s.URL,
+		Timeout:  5,
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Headers: r.Header.Clone(),
+		Body:    body,
+	})
+	var resp Response
+	if len(s.queue) > 0 {
+		resp = s.queue[0]
+		s.queue = s.queue[1:]
+	} else {
+		resp = Error(http.StatusInternalServerError, "aifake: no scripted response")
+	}
+	s.mu.Unlock()
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	io.WriteString(w, resp.Body)
+}
+
+// ToolCall describes a tool invocation scripted into a response
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+}
+
+// OpenAIText is a chat completion whose message content is text
+func OpenAIText(text string) Response {
+	return OpenAIUsage(text, 0)
+}
+
+// OpenAIUsage is a chat completion reporting totalTokens of usage
+func OpenAIUsage(text string, totalTokens int) Response {
+	return jsonResponse(map[string]interface{}{
+		"id":      "chatcmpl-fake",
+		"object":  "chat.completion",
+		"choices": []interface{}{map[string]interface{}{"index": 0, "message": map[string]interface{}{"role": "assistant", "content": text}, "finish_reason": "stop"}},
+		"usage":   map[string]interface{}{"total_tokens": totalTokens},
+	})
+}
+
+// OpenAIToolCalls is a chat completion requesting function calls
+func OpenAIToolCalls(calls ...ToolCall) Response {
+	toolCalls := make([]interface{}, 0, len(calls))
+	for _, c := range calls {
+		toolCalls = append(toolCalls, map[string]interface{}{
+			"id":       c.ID,
+			"type":     "function",
+			"function": map[string]interface{}{"name": c.Name, "arguments": c.Arguments},
+		})
+	}
+	return jsonResponse(map[string]interface{}{
+		"id":      "chatcmpl-fake",
+		"object":  "chat.completion",
+		"choices": []interface{}{map[string]interface{}{"index": 0, "message": map[string]interface{}{"role": "assistant", "content": nil, "tool_calls": toolCalls}, "finish_reason": "tool_calls"}},
+		"usage":   map[string]interface{}{"total_tokens": 100},
+	})
+}
+
+// AnthropicText is a Messages API response with a single text block
+func AnthropicText(text string) Response {
+	return jsonResponse(map[string]interface{}{
+		"id":          "msg_fake",
+		"type":        "message",
+		"role":        "assistant",
+		"content":     []interface{}{map[string]interface{}{"type": "text", "text": text}},
+		"stop_reason": "end_turn",
+		"usage":       map[string]interface{}{"input_tokens": 0, "output_tokens": 0},
+	})
+}
+
+// AnthropicToolUse is a Messages API response requesting tool calls
+func AnthropicToolUse(calls ...ToolCall) Response {
+	content := make([]interface{}, 0, len(calls))
+	for _, c := range calls {
+		content = append(content, map[string]interface{}{
+			"type":  "tool_use",
+			"id":    c.ID,
+			"name":  c.Name,
+			"input": json.RawMessage(c.Arguments),
+		})
+	}
+	return jsonResponse(map[string]interface{}{
+		"id":          "msg_fake",
+		"type":        "message",
+		"role":        "assistant",
+		"content":     content,
+		"stop_reason": "tool_use",
+		"usage":       map[string]interface{}{"input_tokens": 80, "output_tokens": 20},
+	})
+}
+
+// Error is a provider error response in the shape both APIs use
+func Error(status int, message string) Response {
+	body, _ := json.Marshal(map[string]interface{}{
+		"type":  "error",
+		"error": map[string]interface{}{"type": "api_error", "message": message},
+	})
+	return Response{Status: status, Body: string(body)}
+}
+
+// Malformed is a 200 response whose body is not valid JSON
+func Malformed() Response {
+	return Response{Body: `{"choices": [ {"message": `}
+}
+
+// Slow delays r by d
+func Slow(r Response, d time.Duration) Response {
+	r.Delay = d
+	return r
+}
+
+func jsonResponse(v interface{}) Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("aifake: %v", err))
+	}
+	return Response{Body: string(body)}
+}
@@ -18,6 +18,7 @@ type AnthropicClient struct {
 	timeout     time.Duration
 	temperature float32
 	maxTokens   int
+	apiURL      string
 	httpClient  *http.Client
 }
 
@@ -45,7 +46,8 @@ type anthropicResponse struct {
 }
 
 const (
-	anthropicAPIURL    = "https://api.anthropic.com/v1/messages"
+	anthropicBaseURL   = "https://api.anthropic.com"
+	anthropicPath      = "/v1/messages"
 	anthropicVersion   = "2023-06-01"
 	defaultClaudeModel = "claude-3-5-sonnet-20241022"
 )
@@ -76,14 +78,21 @@ func NewAnthropicClient(cfg ClientConfig) (*AnthropicClient, error) {
 		maxTokens = 2000
 	}
 
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = anthropicBaseURL
+	}
+
 	return &AnthropicClient{
 		apiKey:      cfg.APIKey,
 		model:       model,
 		timeout:     timeout,
 		temperature: temperature,
 		maxTokens:   maxTokens,
+		apiURL:      baseURL + anthropicPath,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: cfg.Transport,
 		},
 	}, nil
 }
@@ -106,26 +115,10 @@ func (c *AnthropicClient) Health(ctx context.Context) error {
 }
 
 func (c *AnthropicClient) AnalyzeIncident(ctx context.Context, req AnalysisRequest) (*AnalysisResponse, error) {
-	logsText := strings.Join(req.Logs, "\n")
-
-	prompt := fmt.Sprintf(`Analyze this incident and provide structured analysis in JSON format:
-
-Title: %s
-Description: %s
-
-Related Logs:
-%s
-
-Respond with a JSON object containing:
-{
-  "summary": "Brief summary of the incident",
-  "findings": ["finding1", "finding2"],
-  "root_causes": ["cause1", "cause2"],
-  "recommended_actions": ["action1", "action2"],
-  "suggested_severity": "critical|high|medium|low"
-}
-
-Only respond with the JSON object, no additional text.`, req.IncidentTitle, req.IncidentDesc, logsText)
+	prompt, err := analysisPrompt(req)
+	if err != nil {
+		return nil, err
+	}
 
 	system := "You are an expert incident response analyst. Analyze incidents and provide structured JSON responses."
 
@@ -152,6 +145,7 @@ Only respond with the JSON object, no additional text.`, req.IncidentTitle, req.
 func (c *AnthropicClient) GenerateRCA(ctx context.Context, req RCARequest) (*RCAResponse, error) {
 	analysisJSON, _ := json.Marshal(req.Analysis)
 	timelineText := strings.Join(req.Timeline, "\n")
+	notesText := rcaNotesText(req.Notes)
 
 	prompt := fmt.Sprintf(`Generate a comprehensive Root Cause Analysis document for this incident:
 
@@ -164,6 +158,11 @@ Previous Analysis:
 Timeline:
 %s
 
+Responder Notes:
+%s
+
+Treat responder notes marked [key finding] as confirmed facts.
+
 Respond with a JSON object containing:
 {
   "timeline": "Detailed timeline of events",
@@ -174,7 +173,7 @@ Respond with a JSON object containing:
   "lessons_learned": ["lesson1", "lesson2"]
 }
 
-Only respond with the JSON object, no additional text.`, req.IncidentTitle, req.IncidentDesc, string(analysisJSON), timelineText)
+Only respond with the JSON object, no additional text.`, req.IncidentTitle, req.IncidentDesc, string(analysisJSON), timelineText, notesText)
 
 	system := "You are an expert in writing Root Cause Analysis (RCA) documents. Generate comprehensive, structured RCA documents in JSON format."
 
@@ -237,6 +236,79 @@ Only respond with the JSON object, no additional text.`, logsText)
 	return parseSummarizeResponse(resp)
 }
 
+func (c *AnthropicClient) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	system := ChatSystemPrompt(c.model, c.maxTokens, req.Context)
+	history, trimmed := TrimChatHistory(req.Messages, chatHistoryBudget(c.model, c.maxTokens, system))
+
+	messages := make([]anthropicMessage, 0, len(history))
+	for _, m := range history {
+		messages = append(messages, anthropicMessage{
+			Role:    m.Role,
+			Content: m.Content,
+		})
+	}
+
+	anthropicReq := anthropicRequest{
+		Model:       c.model,
+		Messages:    messages,
+		Temperature: c.temperature,
+		MaxTokens:   c.maxTokens,
+	}
+
+	resp, err := c.call(ctx, anthropicReq, system)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChatResponse{
+		Message:         strings.TrimSpace(resp),
+		RawResponse:     resp,
+		TrimmedMessages: trimmed,
+	}, nil
+}
+
+func (c *AnthropicClient) ClassifySeverity(ctx context.Context, req SeverityRequest) (*SeverityResponse, error) {
+	anthropicReq := anthropicRequest{
+		Model: c.model,
+		Messages: []anthropicMessage{
+			{
+				Role:    "user",
+				Content: severityClassificationPrompt(req),
+			},
+		},
+		Temperature: 0,
+		MaxTokens:   200,
+	}
+
+	resp, err := c.call(ctx, anthropicReq, severitySystemPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSeverityResponse(resp)
+}
+
+func (c *AnthropicClient) DraftStatusUpdate(ctx context.Context, req StatusUpdateRequest) (*StatusUpdateResponse, error) {
+	anthropicReq := anthropicRequest{
+		Model: c.model,
+		Messages: []anthropicMessage{
+			{
+				Role:    "user",
+				Content: statusUpdateDraftPrompt(req),
+			},
+		},
+		Temperature: c.temperature,
+		MaxTokens:   400,
+	}
+
+	resp, err := c.call(ctx, anthropicReq, statusUpdateSystemPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStatusUpdateResponse(resp)
+}
+
 func (c *AnthropicClient) Provider() Provider {
 	return ProviderAnthropic
 }
@@ -247,14 +319,33 @@ func (c *AnthropicClient) Model() string {
 
 func (c *AnthropicClient) call(ctx context.Context, req anthropicRequest, system string) (string, error) {
 	req.System = system
-	body, err := json.Marshal(req)
+	respBody, err := c.post(ctx, req)
 	if err != nil {
 		return "", err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", anthropicAPIURL, bytes.NewBuffer(body))
+	var anthropicResp anthropicResponse
+	if err := json.Unmarshal(respBody, &anthropicResp); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	if len(anthropicResp.Content) == 0 {
+		return "", ErrInvalidResponse
+	}
+
+	return anthropicResp.Content[0].Text, nil
+}
+
+// post sends a request body to the Messages API and returns the raw response body
+func (c *AnthropicClient) post(ctx context.Context, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.apiURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
 	}
 
 	httpReq.Header.Set("x-api-key", c.apiKey)
@@ -263,27 +354,18 @@ func (c *AnthropicClient) call(ctx context.Context, req anthropicRequest, system
 
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to call Anthropic API: %w", err)
+		return nil, fmt.Errorf("failed to call Anthropic API: %w", err)
 	}
 	defer httpResp.Body.Close()
 
 	respBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if httpResp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Anthropic API error: %d - %s", httpResp.StatusCode, string(respBody))
-	}
-
-	var anthropicResp anthropicResponse
-	if err := json.Unmarshal(respBody, &anthropicResp); err != nil {
-		return "", fmt.Errorf("%w: %v", ErrInvalidResponse, err)
-	}
-
-	if len(anthropicResp.Content) == 0 {
-		return "", ErrInvalidResponse
+		return nil, fmt.Errorf("Anthropic API error: %d - %s", httpResp.StatusCode, string(respBody))
 	}
 
-	return anthropicResp.Content[0].Text, nil
+	return respBody, nil
 }
@@ -0,0 +1,171 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Anthropic tool-use request/response types
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+}
+
+type anthropicBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+type anthropicBlockMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+type anthropicToolRequest struct {
+	Model       string                  `json:"model"`
+	Messages    []anthropicBlockMessage `json:"messages"`
+	Temperature float32                 `json:"temperature"`
+	MaxTokens   int                     `json:"max_tokens"`
+	System      string                  `json:"system,omitempty"`
+	Tools       []anthropicTool         `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice    `json:"tool_choice,omitempty"`
+}
+
+type anthropicToolResponse struct {
+	Content    []anthropicBlock `json:"content"`
+	StopReason string           `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// anthropicToolConversation implements toolConversation with tool_use/tool_result content blocks
+type anthropicToolConversation struct {
+	client   *AnthropicClient
+	system   string
+	tools    []anthropicTool
+	messages []anthropicBlockMessage
+}
+
+func (c *AnthropicClient) AnalyzeIncidentWithTools(ctx context.Context, req ToolAnalysisRequest) (*ToolAnalysisResponse, error) {
+	registry := req.Tools
+	if registry == nil {
+		registry = NewToolRegistry()
+	}
+
+	defs := registry.Definitions()
+	tools := make([]anthropicTool, 0, len(defs))
+	for _, d := range defs {
+		tools = append(tools, anthropicTool{
+			Name:        d.Name,
+			Description: d.Description,
+			InputSchema: d.Parameters,
+		})
+	}
+
+	conv := &anthropicToolConversation{
+		client: c,
+		system: toolAnalysisSystem,
+		tools:  tools,
+		messages: []anthropicBlockMessage{
+			{
+				Role: "user",
+				Content: []anthropicBlock{{
+					Type: "text",
+					Text: fmt.Sprintf(toolAnalysisPrompt, req.IncidentID, req.IncidentTitle, req.IncidentDesc),
+				}},
+			},
+		},
+	}
+
+	return runToolLoop(ctx, conv, registry, req.Limits)
+}
+
+func (conv *anthropicToolConversation) next(ctx context.Context, allowTools bool) (*toolTurn, error) {
+	req := anthropicToolRequest{
+		Model:       conv.client.model,
+		Messages:    conv.messages,
+		Temperature: conv.client.temperature,
+		MaxTokens:   conv.client.maxTokens,
+		System:      conv.system,
+	}
+	// Tools stay declared once tool_use blocks are in the history; tool_choice stops further calls
+	if len(conv.tools) > 0 {
+		req.Tools = conv.tools
+		if !allowTools {
+			req.ToolChoice = &anthropicToolChoice{Type: "none"}
+		}
+	}
+
+	respBody, err := conv.client.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp anthropicToolResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if len(resp.Content) == 0 {
+		return nil, ErrInvalidResponse
+	}
+
+	turn := &toolTurn{TokensUsed: resp.Usage.InputTokens + resp.Usage.OutputTokens}
+	var text []string
+	for i, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			text = append(text, block.Text)
+		case "tool_use":
+			if len(block.Input) == 0 {
+				resp.Content[i].Input = json.RawMessage("{}")
+			}
+			turn.Calls = append(turn.Calls, ToolCall{
+				ID:        block.ID,
+				Name:      block.Name,
+				Arguments: resp.Content[i].Input,
+			})
+		}
+	}
+	turn.Text = strings.Join(text, "\n")
+
+	conv.messages = append(conv.messages, anthropicBlockMessage{
+		Role:    "assistant",
+		Content: resp.Content,
+	})
+	return turn, nil
+}
+
+func (conv *anthropicToolConversation) addResults(results []toolResult, finalPrompt string) {
+	blocks := make([]anthropicBlock, 0, len(results)+1)
+	for _, r := range results {
+		blocks = append(blocks, anthropicBlock{
+			Type:      "tool_result",
+			ToolUseID: r.Call.ID,
+			Content:   r.Output,
+			IsError:   r.IsError,
+		})
+	}
+	if finalPrompt != "" {
+		blocks = append(blocks, anthropicBlock{Type: "text", Text: finalPrompt})
+	}
+
+	conv.messages = append(conv.messages, anthropicBlockMessage{
+		Role:    "user",
+		Content: blocks,
+	})
+}
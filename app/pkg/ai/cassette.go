@@ -0,0 +1,302 @@
+package ai
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether a cassette records live traffic or replays it
+type CassetteMode string
+
+const (
+	CassetteRecord CassetteMode = "record"
+	CassetteReplay CassetteMode = "replay"
+)
+
+// ErrCassetteMiss is returned in replay mode when no recorded interaction matches a request
+var ErrCassetteMiss = errors.New("no recorded interaction for request")
+
+const redacted = "REDACTED"
+
+// sensitiveHeaders are scrubbed before interactions are written to disk
+var sensitiveHeaders = []string{"Authorization", "X-Api-Key", "Api-Key", "Openai-Organization"}
+
+// CassetteRequest is the recorded half of an interaction sent to the provider
+type CassetteRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// CassetteResponse is the recorded provider reply
+type CassetteResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+// Interaction is a single recorded request/response pair keyed by request hash
+type Interaction struct {
+	Hash     string           `json:"hash"`
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// Cassette is an http.RoundTripper that records provider interactions to a file or replays them
+type Cassette struct {
+	path         string
+	mode         CassetteMode
+	inner        http.RoundTripper
+	secrets      []string
+	interactions []Interaction
+	// played counts how many times each hash has been replayed so repeated identical requests
+	// receive their recorded responses in order
+	played map[string]int
+	mu     sync.Mutex
+}
+
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette opens a cassette file. In record mode a missing file starts an empty cassette.
+// Any secrets given are scrubbed from recorded URLs, headers and bodies.
+func LoadCassette(path string, mode CassetteMode, secrets ...string) (*Cassette, error) {
+	if mode != CassetteRecord && mode != CassetteReplay {
+		return nil, fmt.Errorf("unknown cassette mode: %q", mode)
+	}
+
+	c := &Cassette{
+		path:   path,
+		mode:   mode,
+		inner:  http.DefaultTransport,
+		played: make(map[string]int),
+	}
+	for _, s := range secrets {
+		if s != "" {
+			c.secrets = append(c.secrets, s)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && mode == CassetteRecord {
+			return c, nil
+		}
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	c.interactions = file.Interactions
+	return c, nil
+}
+
+// Interactions returns a copy of the recorded interactions
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// RoundTrip records or replays a single HTTP exchange
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	hash := RequestHash(req.Method, req.URL.Path, body)
+
+	if c.mode == CassetteReplay {
+		return c.replay(req, hash)
+	}
+	return c.record(req, hash, body)
+}
+
+func (c *Cassette) replay(req *http.Request, hash string) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := 0
+	for _, it := range c.interactions {
+		if it.Hash != hash {
+			continue
+		}
+		if seen == c.played[hash] {
+			c.played[hash]++
+			return it.Response.toHTTP(req), nil
+		}
+		seen++
+	}
+
+	// Once every recording for a hash has been played, keep serving the last one
+	if seen > 0 {
+		for i := len(c.interactions) - 1; i >= 0; i-- {
+			if c.interactions[i].Hash == hash {
+				return c.interactions[i].Response.toHTTP(req), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s (hash %s)", ErrCassetteMiss, req.Method, req.URL.Path, hash)
+}
+
+func (c *Cassette) record(req *http.Request, hash string, body []byte) (*http.Response, error) {
+	resp, err := c.inner.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	it := Interaction{
+		Hash: hash,
+		Request: CassetteRequest{
+			Method:  req.Method,
+			URL:     c.scrub(req.URL.String()),
+			Headers: c.scrubHeaders(req.Header),
+		},
+		Response: CassetteResponse{
+			Status:  resp.StatusCode,
+			Headers: c.scrubHeaders(resp.Header),
+			Body:    c.scrub(string(respBody)),
+		},
+	}
+	if len(body) > 0 {
+		scrubbed := c.scrub(string(body))
+		if json.Valid([]byte(scrubbed)) {
+			it.Request.Body = json.RawMessage(scrubbed)
+		} else {
+			quoted, _ := json.Marshal(scrubbed)
+			it.Request.Body = quoted
+		}
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, it)
+	err = c.saveLocked()
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// saveLocked writes the cassette to disk; callers must hold c.mu
+func (c *Cassette) saveLocked() error {
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+func (c *Cassette) scrub(s string) string {
+	for _, secret := range c.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+func (c *Cassette) scrubHeaders(h http.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for k := range h {
+		out[k] = c.scrub(h.Get(k))
+	}
+	for _, k := range sensitiveHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(k)]; ok {
+			out[http.CanonicalHeaderKey(k)] = redacted
+		}
+	}
+	return out
+}
+
+func (r CassetteResponse) toHTTP(req *http.Request) *http.Response {
+	header := make(http.Header, len(r.Headers))
+	for k, v := range r.Headers {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// RequestHash identifies a provider request by method, path and canonical JSON body.
+// Re-encoding the body sorts object keys so semantically equal requests hash the same.
+func RequestHash(method, path string, body []byte) string {
+	canonical := body
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if b, err := json.Marshal(v); err == nil {
+			canonical = b
+		}
+	}
+
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// NewCassetteClient creates a provider client whose HTTP traffic goes through a cassette.
+// Replay mode needs no API key and never touches the network.
+func NewCassetteClient(cfg ClientConfig, path string, mode CassetteMode) (Client, *Cassette, error) {
+	cassette, err := LoadCassette(path, mode, cfg.APIKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Transport != nil {
+		cassette.inner = cfg.Transport
+	}
+
+	if mode == CassetteReplay && cfg.APIKey == "" {
+		cfg.APIKey = redacted
+	}
+	cfg.Transport = cassette
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, cassette, nil
+}
@@ -0,0 +1,57 @@
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai/aifake"
+)
+
+func TestCassetteRecordThenReplay(t *testing.T) {
+	fake := aifake.NewServer()
+	fake.Enqueue(aifake.OpenAIText(`{"summary": "Disk full on db-1", "suggested_severity": "high"}`))
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cfg := fake.Config(ai.ProviderOpenAI)
+	cfg.APIKey = "sk-secret-value"
+
+	recorder, _, err := ai.NewCassetteClient(cfg, path, ai.CassetteRecord)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := ai.AnalysisRequest{IncidentTitle: "DB down", IncidentDesc: "writes failing"}
+	recorded, err := recorder.AnalyzeIncident(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fake.Close()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "sk-secret-value") {
+		t.Fatal("cassette must not contain the API key")
+	}
+
+	// Replay needs neither the key nor the server
+	cfg.APIKey = ""
+	player, _, err := ai.NewCassetteClient(cfg, path, ai.CassetteReplay)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	replayed, err := player.AnalyzeIncident(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed.Summary != recorded.Summary || replayed.SuggestedSeverity != "high" {
+		t.Errorf("expected replayed analysis to match recording, got %+v", replayed)
+	}
+
+	_, err = player.AnalyzeIncident(context.Background(), ai.AnalysisRequest{IncidentTitle: "something else"})
+	if !errors.Is(err, ai.ErrCassetteMiss) {
+		t.Errorf("expected ErrCassetteMiss, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Chat roles shared by both providers
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// ChatMessage represents a single turn in a conversation
+type ChatMessage struct {
+	Role    string
+	Content string
+}
+
+// ChatRequest represents a request for a multi-turn chat completion
+type ChatRequest struct {
+	// Context is grounding material (incident, logs, analysis) placed in the system prompt
+	Context string
+	// Messages is the conversation so far, oldest first, ending with the user's question
+	Messages []ChatMessage
+}
+
+// ChatResponse represents the assistant's reply
+type ChatResponse struct {
+	Message     string
+	RawResponse string
+	// TrimmedMessages is the number of older messages dropped to fit the model window
+	TrimmedMessages int
+}
+
+const chatSystemPrompt = `You are an expert incident response assistant helping responders investigate an incident.
+Answer follow-up questions using the incident context below. Be concise and specific, cite log lines when relevant,
+and say so plainly when the context does not contain the answer.
+
+Incident context:
+%s`
+
+// modelContextWindows maps model name prefixes to their context window in tokens.
+// Longer prefixes must come first so that "gpt-4o" wins over "gpt-4".
+var modelContextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4-32k", 32768},
+	{"gpt-4", 8192},
+	{"gpt-3.5-turbo", 16385},
+	{"claude-", 200000},
+}
+
+const defaultContextWindow = 8192
+
+// ModelContextWindow returns the approximate context window, in tokens, for a model
+func ModelContextWindow(model string) int {
+	for _, w := range modelContextWindows {
+		if strings.HasPrefix(model, w.prefix) {
+			return w.tokens
+		}
+	}
+	return defaultContextWindow
+}
+
+// EstimateTokens gives a rough token count for text (about four characters per token)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// TrimChatHistory drops the oldest messages until the conversation fits within budget tokens.
+// The most recent message is always kept, and the result never starts with an assistant turn
+// because both providers expect a conversation to open with the user.
+func TrimChatHistory(messages []ChatMessage, budget int) ([]ChatMessage, int) {
+	if len(messages) == 0 {
+		return messages, 0
+	}
+
+	start := len(messages) - 1
+	used := EstimateTokens(messages[start].Content)
+	for start > 0 {
+		cost := EstimateTokens(messages[start-1].Content)
+		if used+cost > budget {
+			break
+		}
+		used += cost
+		start--
+	}
+
+	for start < len(messages)-1 && messages[start].Role != RoleUser {
+		start++
+	}
+
+	return messages[start:], start
+}
+
+// ChatSystemPrompt renders the system prompt around the grounding context. The context is cut to at
+// most half of the window left after the reply, so a log-heavy incident still leaves room for history.
+func ChatSystemPrompt(model string, maxTokens int, context string) string {
+	limit := (ModelContextWindow(model) - maxTokens) / 2
+	if limit < 0 {
+		limit = 0
+	}
+	return fmt.Sprintf(chatSystemPrompt, TrimLongText(context, limit*4))
+}
+
+// chatHistoryBudget returns the tokens left for history once the reply and system prompt are reserved
+func chatHistoryBudget(model string, maxTokens int, system string) int {
+	return ModelContextWindow(model) - maxTokens - EstimateTokens(system)
+}
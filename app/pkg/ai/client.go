@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 // Provider represents an AI provider type
@@ -22,6 +23,10 @@ type ClientConfig struct {
 	Timeout     int // seconds
 	Temperature float32
 	MaxTokens   int
+	// BaseURL overrides the provider API host, e.g. to point at a proxy or a fake provider
+	BaseURL string
+	// Transport overrides the HTTP transport, e.g. to record or replay interactions
+	Transport http.RoundTripper
 }
 
 // AnalysisRequest represents a request for incident analysis
@@ -30,6 +35,8 @@ type AnalysisRequest struct {
 	IncidentDesc      string
 	Logs              []string
 	AdditionalContext map[string]string
+	// PromptVersion selects the analysis prompt; empty means DefaultPromptVersion
+	PromptVersion string
 }
 
 // AnalysisResponse represents the response from incident analysis
@@ -48,6 +55,7 @@ type RCARequest struct {
 	IncidentDesc      string
 	Analysis          AnalysisResponse
 	Timeline          []string
+	Notes             []string // responder comments, key findings first
 	AdditionalContext map[string]string
 }
 
@@ -88,6 +96,18 @@ type Client interface {
 	// SummarizeLogs extracts insights from log collections
 	SummarizeLogs(ctx context.Context, req SummarizeRequest) (*SummarizeResponse, error)
 
+	// Chat continues a multi-turn conversation grounded in the request context
+	Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+
+	// ClassifySeverity suggests a severity for a newly created incident
+	ClassifySeverity(ctx context.Context, req SeverityRequest) (*SeverityResponse, error)
+
+	// AnalyzeIncidentWithTools analyzes an incident, letting the model call tools to gather evidence
+	AnalyzeIncidentWithTools(ctx context.Context, req ToolAnalysisRequest) (*ToolAnalysisResponse, error)
+
+	// DraftStatusUpdate drafts a customer-facing status page update for an incident
+	DraftStatusUpdate(ctx context.Context, req StatusUpdateRequest) (*StatusUpdateResponse, error)
+
 	// Health checks if the client is properly configured and accessible
 	Health(ctx context.Context) error
 
@@ -168,6 +188,28 @@ func (c *NoOpClient) SummarizeLogs(ctx context.Context, req SummarizeRequest) (*
 	}, nil
 }
 
+func (c *NoOpClient) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	return &ChatResponse{
+		Message: "AI chat not available (provider not configured)",
+	}, nil
+}
+
+func (c *NoOpClient) ClassifySeverity(ctx context.Context, req SeverityRequest) (*SeverityResponse, error) {
+	return nil, fmt.Errorf("AI provider not configured")
+}
+
+func (c *NoOpClient) AnalyzeIncidentWithTools(ctx context.Context, req ToolAnalysisRequest) (*ToolAnalysisResponse, error) {
+	analysis, _ := c.AnalyzeIncident(ctx, AnalysisRequest{IncidentTitle: req.IncidentTitle, IncidentDesc: req.IncidentDesc})
+	return &ToolAnalysisResponse{
+		AnalysisResponse: *analysis,
+		StopReason:       StopReasonCompleted,
+	}, nil
+}
+
+func (c *NoOpClient) DraftStatusUpdate(ctx context.Context, req StatusUpdateRequest) (*StatusUpdateResponse, error) {
+	return nil, fmt.Errorf("AI provider not configured")
+}
+
 func (c *NoOpClient) Health(ctx context.Context) error {
 	return fmt.Errorf("AI provider not configured")
 }
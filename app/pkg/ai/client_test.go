@@ -0,0 +1,188 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai/aifake"
+)
+
+func newFakeClient(t *testing.T, provider ai.Provider) (ai.Client, *aifake.Server) {
+	t.Helper()
+	fake := aifake.NewServer()
+	t.Cleanup(fake.Close)
+
+	client, err := ai.NewClient(fake.Config(provider))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return client, fake
+}
+
+func TestAnalyzeIncidentParsesMarkdownWrappedJSON(t *testing.T) {
+	for _, provider := range []ai.Provider{ai.ProviderOpenAI, ai.ProviderAnthropic} {
+		client, fake := newFakeClient(t, provider)
+		text := "```json\n{\"summary\": \"Pool exhausted\", \"root_causes\": [\"leaked connections\"], \"suggested_severity\": \"critical\"}\n```"
+		if provider == ai.ProviderOpenAI {
+			fake.Enqueue(aifake.OpenAIText(text))
+		} else {
+			fake.Enqueue(aifake.AnthropicText(text))
+		}
+
+		resp, err := client.AnalyzeIncident(context.Background(), ai.AnalysisRequest{IncidentTitle: "DB"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", provider, err)
+		}
+		if resp.Summary != "Pool exhausted" || resp.SuggestedSeverity != "critical" || len(resp.RootCauses) != 1 {
+			t.Errorf("%s: unexpected analysis %+v", provider, resp)
+		}
+	}
+}
+
+func TestProviderErrors(t *testing.T) {
+	client, fake := newFakeClient(t, ai.ProviderAnthropic)
+
+	fake.Enqueue(aifake.Error(http.StatusTooManyRequests, "rate limited"))
+	if _, err := client.AnalyzeIncident(context.Background(), ai.AnalysisRequest{}); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("expected 429 error, got %v", err)
+	}
+
+	fake.Enqueue(aifake.Malformed())
+	if _, err := client.AnalyzeIncident(context.Background(), ai.AnalysisRequest{}); !errors.Is(err, ai.ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse, got %v", err)
+	}
+
+	fake.Enqueue(aifake.Slow(aifake.AnthropicText("late"), time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.AnalyzeIncident(ctx, ai.AnalysisRequest{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestAnalyzeIncidentWithToolsLoop(t *testing.T) {
+	for _, provider := range []ai.Provider{ai.ProviderOpenAI, ai.ProviderAnthropic} {
+		client, fake := newFakeClient(t, provider)
+		call := aifake.ToolCall{ID: "call_1", Name: "grep_logs", Arguments: `{"pattern":"timeout"}`}
+		final := `{"summary": "Upstream timeouts", "suggested_severity": "high"}`
+		if provider == ai.ProviderOpenAI {
+			fake.Enqueue(aifake.OpenAIToolCalls(call), aifake.OpenAIText(final))
+		} else {
+			fake.Enqueue(aifake.AnthropicToolUse(call), aifake.AnthropicText(final))
+		}
+
+		registry := ai.NewToolRegistry()
+		registry.Register(ai.ToolDefinition{Name: "grep_logs", Parameters: map[string]interface{}{"type": "object"}},
+			func(ctx context.Context, args json.RawMessage) (string, error) { return "3: upstream timeout", nil })
+
+		resp, err := client.AnalyzeIncidentWithTools(context.Background(), ai.ToolAnalysisRequest{IncidentID: "INC-1", Tools: registry})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", provider, err)
+		}
+		if resp.Summary != "Upstream timeouts" || resp.Steps != 2 || len(resp.Trace) != 1 {
+			t.Errorf("%s: unexpected response %+v", provider, resp)
+		}
+
+		// The tool result must be sent back in the provider's wire format
+		second := string(fake.Requests()[1].Body)
+		if !strings.Contains(second, "call_1") || !strings.Contains(second, "3: upstream timeout") {
+			t.Errorf("%s: tool result missing from follow-up request: %s", provider, second)
+		}
+	}
+}
+
+func TestAnalyzeIncidentWithToolsStepLimit(t *testing.T) {
+	client, fake := newFakeClient(t, ai.ProviderOpenAI)
+	call := aifake.ToolCall{ID: "call_1", Name: "noop", Arguments: `{}`}
+	fake.Enqueue(aifake.OpenAIToolCalls(call), aifake.OpenAIText(`{"summary": "partial"}`))
+
+	registry := ai.NewToolRegistry()
+	registry.Register(ai.ToolDefinition{Name: "noop"}, func(ctx context.Context, args json.RawMessage) (string, error) { return "ok", nil })
+
+	resp, err := client.AnalyzeIncidentWithTools(context.Background(), ai.ToolAnalysisRequest{
+		Tools:  registry,
+		Limits: ai.ToolLimits{MaxSteps: 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StopReason != ai.StopReasonStepLimit || resp.Summary != "partial" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if !strings.Contains(string(fake.Requests()[1].Body), `"tool_choice":"none"`) {
+		t.Error("expected the final request to disable tools")
+	}
+}
+
+func TestClassifySeverity(t *testing.T) {
+	client, fake := newFakeClient(t, ai.ProviderOpenAI)
+
+	fake.Enqueue(aifake.OpenAIText("```json\n{\"severity\": \"High\", \"confidence\": 0.7, \"reasoning\": \"checkout degraded\"}\n```"))
+	resp, err := client.ClassifySeverity(context.Background(), ai.SeverityRequest{IncidentTitle: "Checkout slow"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Severity != "high" || resp.Confidence != 0.7 {
+		t.Errorf("unexpected classification %+v", resp)
+	}
+
+	fake.Enqueue(aifake.OpenAIText(`{"severity": "sev1"}`))
+	if _, err := client.ClassifySeverity(context.Background(), ai.SeverityRequest{}); !errors.Is(err, ai.ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse for unknown severity, got %v", err)
+	}
+}
+
+func TestAnalyzeIncidentAdditionalContext(t *testing.T) {
+	client, fake := newFakeClient(t, ai.ProviderOpenAI)
+
+	for _, version := range ai.PromptVersions() {
+		fake.Enqueue(aifake.OpenAIText(`{"summary": "ok"}`))
+		_, err := client.AnalyzeIncident(context.Background(), ai.AnalysisRequest{
+			IncidentTitle:     "Checkout down",
+			PromptVersion:     version,
+			AdditionalContext: map[string]string{"prometheus": "p99 latency 2.1s", "kubernetes": "deployment shop/checkout: 1/3 ready"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		requests := fake.Requests()
+		body := string(requests[len(requests)-1].Body)
+		at := strings.Index(body, `Additional Context:\n[kubernetes]\ndeployment shop/checkout: 1/3 ready\n[prometheus]`)
+		if at < 0 || at > strings.Index(body, "Respond with a JSON object") {
+			t.Errorf("%s: expected sorted context before the response instructions, got %s", version, body)
+		}
+	}
+}
+
+func TestDraftStatusUpdate(t *testing.T) {
+	client, fake := newFakeClient(t, ai.ProviderAnthropic)
+
+	fake.Enqueue(aifake.AnthropicText(`{"title": "Checkout errors", "state": "Identified", "message": "We have identified the issue affecting Checkout."}`))
+	resp, err := client.DraftStatusUpdate(context.Background(), ai.StatusUpdateRequest{
+		IncidentTitle:   "payments-db failover",
+		Components:      []string{"Checkout"},
+		PreviousUpdates: []string{"We are investigating errors at checkout."},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.State != "identified" || resp.Title != "Checkout errors" {
+		t.Errorf("unexpected draft %+v", resp)
+	}
+	body := string(fake.Requests()[0].Body)
+	if !strings.Contains(body, "Affected components: Checkout") || !strings.Contains(body, `- We are investigating errors at checkout.`) {
+		t.Errorf("expected components and previous updates in the prompt, got %s", body)
+	}
+
+	fake.Enqueue(aifake.AnthropicText(`{"state": "fixed", "message": "Done"}`))
+	if _, err := client.DraftStatusUpdate(context.Background(), ai.StatusUpdateRequest{}); !errors.Is(err, ai.ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse for an unknown state, got %v", err)
+	}
+}
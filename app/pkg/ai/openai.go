@@ -18,6 +18,7 @@ type OpenAIClient struct {
 	timeout     time.Duration
 	temperature float32
 	maxTokens   int
+	apiURL      string
 	httpClient  *http.Client
 }
 
@@ -45,8 +46,9 @@ type openaiResponse struct {
 }
 
 const (
-	openaiAPIURL = "https://api.openai.com/v1/chat/completions"
-	defaultModel = "gpt-4"
+	openaiBaseURL = "https://api.openai.com"
+	openaiPath    = "/v1/chat/completions"
+	defaultModel  = "gpt-4"
 )
 
 // NewOpenAIClient creates a new OpenAI client
@@ -75,14 +77,21 @@ func NewOpenAIClient(cfg ClientConfig) (*OpenAIClient, error) {
 		maxTokens = 2000
 	}
 
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = openaiBaseURL
+	}
+
 	return &OpenAIClient{
 		apiKey:      cfg.APIKey,
 		model:       model,
 		timeout:     timeout,
 		temperature: temperature,
 		maxTokens:   maxTokens,
+		apiURL:      baseURL + openaiPath,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: cfg.Transport,
 		},
 	}, nil
 }
@@ -106,26 +115,10 @@ func (c *OpenAIClient) Health(ctx context.Context) error {
 }
 
 func (c *OpenAIClient) AnalyzeIncident(ctx context.Context, req AnalysisRequest) (*AnalysisResponse, error) {
-	logsText := strings.Join(req.Logs, "\n")
-
-	prompt := fmt.Sprintf(`Analyze this incident and provide structured analysis in JSON format:
-
-Title: %s
-Description: %s
-
-Related Logs:
-%s
-
-Respond with a JSON object containing:
-{
-  "summary": "Brief summary of the incident",
-  "findings": ["finding1", "finding2"],
-  "root_causes": ["cause1", "cause2"],
-  "recommended_actions": ["action1", "action2"],
-  "suggested_severity": "critical|high|medium|low"
-}
-
-Only respond with the JSON object, no additional text.`, req.IncidentTitle, req.IncidentDesc, logsText)
+	prompt, err := analysisPrompt(req)
+	if err != nil {
+		return nil, err
+	}
 
 	openaiReq := openaiRequest{
 		Model: c.model,
@@ -154,6 +147,7 @@ Only respond with the JSON object, no additional text.`, req.IncidentTitle, req.
 func (c *OpenAIClient) GenerateRCA(ctx context.Context, req RCARequest) (*RCAResponse, error) {
 	analysisJSON, _ := json.Marshal(req.Analysis)
 	timelineText := strings.Join(req.Timeline, "\n")
+	notesText := rcaNotesText(req.Notes)
 
 	prompt := fmt.Sprintf(`Generate a comprehensive Root Cause Analysis document for this incident:
 
@@ -166,6 +160,11 @@ Previous Analysis:
 Timeline:
 %s
 
+Responder Notes:
+%s
+
+Treat responder notes marked [key finding] as confirmed facts.
+
 Respond with a JSON object containing:
 {
   "timeline": "Detailed timeline of events",
@@ -176,7 +175,7 @@ Respond with a JSON object containing:
   "lessons_learned": ["lesson1", "lesson2"]
 }
 
-Only respond with the JSON object, no additional text.`, req.IncidentTitle, req.IncidentDesc, string(analysisJSON), timelineText)
+Only respond with the JSON object, no additional text.`, req.IncidentTitle, req.IncidentDesc, string(analysisJSON), timelineText, notesText)
 
 	openaiReq := openaiRequest{
 		Model: c.model,
@@ -243,6 +242,91 @@ Only respond with the JSON object, no additional text.`, logsText)
 	return parseSummarizeResponse(resp)
 }
 
+func (c *OpenAIClient) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	system := ChatSystemPrompt(c.model, c.maxTokens, req.Context)
+	history, trimmed := TrimChatHistory(req.Messages, chatHistoryBudget(c.model, c.maxTokens, system))
+
+	messages := make([]openaiMessage, 0, len(history)+1)
+	messages = append(messages, openaiMessage{
+		Role:    "system",
+		Content: system,
+	})
+	for _, m := range history {
+		messages = append(messages, openaiMessage{
+			Role:    m.Role,
+			Content: m.Content,
+		})
+	}
+
+	openaiReq := openaiRequest{
+		Model:       c.model,
+		Messages:    messages,
+		Temperature: c.temperature,
+		MaxTokens:   c.maxTokens,
+	}
+
+	resp, err := c.call(ctx, openaiReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChatResponse{
+		Message:         strings.TrimSpace(resp),
+		RawResponse:     resp,
+		TrimmedMessages: trimmed,
+	}, nil
+}
+
+func (c *OpenAIClient) ClassifySeverity(ctx context.Context, req SeverityRequest) (*SeverityResponse, error) {
+	openaiReq := openaiRequest{
+		Model: c.model,
+		Messages: []openaiMessage{
+			{
+				Role:    "system",
+				Content: severitySystemPrompt,
+			},
+			{
+				Role:    "user",
+				Content: severityClassificationPrompt(req),
+			},
+		},
+		Temperature: 0,
+		MaxTokens:   200,
+	}
+
+	resp, err := c.call(ctx, openaiReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSeverityResponse(resp)
+}
+
+func (c *OpenAIClient) DraftStatusUpdate(ctx context.Context, req StatusUpdateRequest) (*StatusUpdateResponse, error) {
+	openaiReq := openaiRequest{
+		Model: c.model,
+		Messages: []openaiMessage{
+			{
+				Role:    "system",
+				Content: statusUpdateSystemPrompt,
+			},
+			{
+				Role:    "user",
+				Content: statusUpdateDraftPrompt(req),
+			},
+		},
+		Temperature: c.temperature,
+		MaxTokens:   400,
+	}
+
+	resp, err := c.call(ctx, openaiReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStatusUpdateResponse(resp)
+}
+
 func (c *OpenAIClient) Provider() Provider {
 	return ProviderOpenAI
 }
@@ -252,14 +336,33 @@ func (c *OpenAIClient) Model() string {
 }
 
 func (c *OpenAIClient) call(ctx context.Context, req openaiRequest) (string, error) {
-	body, err := json.Marshal(req)
+	respBody, err := c.post(ctx, req)
 	if err != nil {
 		return "", err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", openaiAPIURL, bytes.NewBuffer(body))
+	var openaiResp openaiResponse
+	if err := json.Unmarshal(respBody, &openaiResp); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	if len(openaiResp.Choices) == 0 {
+		return "", ErrInvalidResponse
+	}
+
+	return openaiResp.Choices[0].Message.Content, nil
+}
+
+// post sends a request body to the Chat Completions API and returns the raw response body
+func (c *OpenAIClient) post(ctx context.Context, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.apiURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
 	}
 
 	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
@@ -267,27 +370,18 @@ func (c *OpenAIClient) call(ctx context.Context, req openaiRequest) (string, err
 
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to call OpenAI API: %w", err)
+		return nil, fmt.Errorf("failed to call OpenAI API: %w", err)
 	}
 	defer httpResp.Body.Close()
 
 	respBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if httpResp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("OpenAI API error: %d - %s", httpResp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("OpenAI API error: %d - %s", httpResp.StatusCode, string(respBody))
 	}
 
-	var openaiResp openaiResponse
-	if err := json.Unmarshal(respBody, &openaiResp); err != nil {
-		return "", fmt.Errorf("%w: %v", ErrInvalidResponse, err)
-	}
-
-	if len(openaiResp.Choices) == 0 {
-		return "", ErrInvalidResponse
-	}
-
-	return openaiResp.Choices[0].Message.Content, nil
+	return respBody, nil
 }
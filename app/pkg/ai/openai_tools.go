@@ -0,0 +1,166 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// OpenAI function-calling request/response types
+type openaiFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+type openaiTool struct {
+	Type     string         `json:"type"`
+	Function openaiFunction `json:"function"`
+}
+
+type openaiToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openaiToolMessage struct {
+	Role       string           `json:"role"`
+	Content    *string          `json:"content"`
+	ToolCalls  []openaiToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openaiToolRequest struct {
+	Model       string              `json:"model"`
+	Messages    []openaiToolMessage `json:"messages"`
+	Temperature float32             `json:"temperature"`
+	MaxTokens   int                 `json:"max_tokens"`
+	Tools       []openaiTool        `json:"tools,omitempty"`
+	ToolChoice  string              `json:"tool_choice,omitempty"`
+}
+
+type openaiToolResponse struct {
+	Choices []struct {
+		Message struct {
+			Content   *string          `json:"content"`
+			ToolCalls []openaiToolCall `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// openaiToolConversation implements toolConversation with function calling and tool messages
+type openaiToolConversation struct {
+	client   *OpenAIClient
+	tools    []openaiTool
+	messages []openaiToolMessage
+}
+
+func (c *OpenAIClient) AnalyzeIncidentWithTools(ctx context.Context, req ToolAnalysisRequest) (*ToolAnalysisResponse, error) {
+	registry := req.Tools
+	if registry == nil {
+		registry = NewToolRegistry()
+	}
+
+	defs := registry.Definitions()
+	tools := make([]openaiTool, 0, len(defs))
+	for _, d := range defs {
+		tools = append(tools, openaiTool{
+			Type: "function",
+			Function: openaiFunction{
+				Name:        d.Name,
+				Description: d.Description,
+				Parameters:  d.Parameters,
+			},
+		})
+	}
+
+	system := toolAnalysisSystem
+	prompt := fmt.Sprintf(toolAnalysisPrompt, req.IncidentID, req.IncidentTitle, req.IncidentDesc)
+	conv := &openaiToolConversation{
+		client: c,
+		tools:  tools,
+		messages: []openaiToolMessage{
+			{Role: "system", Content: &system},
+			{Role: "user", Content: &prompt},
+		},
+	}
+
+	return runToolLoop(ctx, conv, registry, req.Limits)
+}
+
+func (conv *openaiToolConversation) next(ctx context.Context, allowTools bool) (*toolTurn, error) {
+	req := openaiToolRequest{
+		Model:       conv.client.model,
+		Messages:    conv.messages,
+		Temperature: conv.client.temperature,
+		MaxTokens:   conv.client.maxTokens,
+	}
+	if len(conv.tools) > 0 {
+		req.Tools = conv.tools
+		if !allowTools {
+			req.ToolChoice = "none"
+		}
+	}
+
+	respBody, err := conv.client.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp openaiToolResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, ErrInvalidResponse
+	}
+
+	msg := resp.Choices[0].Message
+	turn := &toolTurn{TokensUsed: resp.Usage.TotalTokens}
+	if msg.Content != nil {
+		turn.Text = *msg.Content
+	}
+	for _, tc := range msg.ToolCalls {
+		args := json.RawMessage(tc.Function.Arguments)
+		if !json.Valid(args) {
+			args = json.RawMessage("{}")
+		}
+		turn.Calls = append(turn.Calls, ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: args,
+		})
+	}
+
+	conv.messages = append(conv.messages, openaiToolMessage{
+		Role:      "assistant",
+		Content:   msg.Content,
+		ToolCalls: msg.ToolCalls,
+	})
+	return turn, nil
+}
+
+func (conv *openaiToolConversation) addResults(results []toolResult, finalPrompt string) {
+	for _, r := range results {
+		output := r.Output
+		conv.messages = append(conv.messages, openaiToolMessage{
+			Role:       "tool",
+			Content:    &output,
+			ToolCallID: r.Call.ID,
+		})
+	}
+	if finalPrompt != "" {
+		conv.messages = append(conv.messages, openaiToolMessage{
+			Role:    "user",
+			Content: &finalPrompt,
+		})
+	}
+}
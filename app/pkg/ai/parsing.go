@@ -111,6 +111,12 @@ func extractJSON(s string) string {
 	return s
 }
 
+// ValidJSONResponse reports whether a raw model response contains a parseable JSON object
+func ValidJSONResponse(rawResp string) bool {
+	var data map[string]interface{}
+	return json.Unmarshal([]byte(extractJSON(rawResp)), &data) == nil
+}
+
 // getStringValue safely extracts a string value from a map
 func getStringValue(data map[string]interface{}, key string) string {
 	if val, ok := data[key]; ok {
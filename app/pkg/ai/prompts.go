@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultPromptVersion is the analysis prompt used when a request does not pick one
+const DefaultPromptVersion = "v1"
+
+// RCA and tool-assisted analysis each have a single prompt; these versions are recorded with their output
+const (
+	RCAPromptVersion  = "rca-v2"
+	ToolPromptVersion = "tools-v1"
+)
+
+// ErrUnknownPromptVersion is returned when a request names a prompt version that does not exist
+var ErrUnknownPromptVersion = errors.New("unknown prompt version")
+
+// analysisPrompts holds every released analysis prompt so results stay comparable across versions.
+// Each template receives the title, description and logs, in that order.
+var analysisPrompts = map[string]string{
+	"v1": `Analyze this incident and provide structured analysis in JSON format:
+
+Title: %s
+Description: %s
+
+Related Logs:
+%s
+
+Respond with a JSON object containing:
+{
+  "summary": "Brief summary of the incident",
+  "findings": ["finding1", "finding2"],
+  "root_causes": ["cause1", "cause2"],
+  "recommended_actions": ["action1", "action2"],
+  "suggested_severity": "critical|high|medium|low"
+}
+
+Only respond with the JSON object, no additional text.`,
+
+	"v2": `Analyze this incident and provide structured analysis in JSON format.
+
+Title: %s
+Description: %s
+
+Related Logs:
+%s
+
+Base every finding on the description or a specific log line. Name root causes as short, concrete
+technical causes (for example "connection pool exhausted" rather than "database issue").
+
+Choose the severity with this rubric:
+- critical: customer-facing outage, data loss or security breach
+- high: major feature broken or errors for many users
+- medium: degraded performance or partial failure with a workaround
+- low: cosmetic issue or no user impact
+
+Respond with a JSON object containing:
+{
+  "summary": "Brief summary of the incident",
+  "findings": ["finding1", "finding2"],
+  "root_causes": ["cause1", "cause2"],
+  "recommended_actions": ["action1", "action2"],
+  "suggested_severity": "critical|high|medium|low"
+}
+
+Only respond with the JSON object, no additional text.`,
+}
+
+// PromptVersions lists the available analysis prompt versions
+func PromptVersions() []string {
+	versions := make([]string, 0, len(analysisPrompts))
+	for v := range analysisPrompts {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// analysisPrompt renders the analysis prompt for the request's prompt version
+func analysisPrompt(req AnalysisRequest) (string, error) {
+	version := req.PromptVersion
+	if version == "" {
+		version = DefaultPromptVersion
+	}
+
+	tmpl, ok := analysisPrompts[version]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownPromptVersion, version)
+	}
+
+	prompt := fmt.Sprintf(tmpl, req.IncidentTitle, req.IncidentDesc, strings.Join(req.Logs, "\n"))
+	if len(req.AdditionalContext) == 0 {
+		return prompt, nil
+	}
+	// Context from enrichers goes after the logs so every prompt version keeps its instructions last
+	return strings.Replace(prompt, "\n\nRespond with", "\n\n"+additionalContextText(req.AdditionalContext)+"\n\nRespond with", 1), nil
+}
+
+// additionalContextText renders enrichment context sorted by source
+func additionalContextText(sections map[string]string) string {
+	sources := make([]string, 0, len(sections))
+	for source := range sections {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var b strings.Builder
+	b.WriteString("Additional Context:")
+	for _, source := range sources {
+		fmt.Fprintf(&b, "\n[%s]\n%s", source, strings.TrimSpace(sections[source]))
+	}
+	return b.String()
+}
+
+// rcaNotesText renders responder notes for the RCA prompt
+func rcaNotesText(notes []string) string {
+	if len(notes) == 0 {
+		return "(none)"
+	}
+	return "- " + strings.Join(notes, "\n- ")
+}
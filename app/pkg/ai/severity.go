@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SeverityRequest represents a request to classify a new incident's severity
+type SeverityRequest struct {
+	IncidentTitle string
+	IncidentDesc  string
+	Source        string
+	Logs          []string
+}
+
+// SeverityResponse represents the model's severity classification
+type SeverityResponse struct {
+	Severity    string
+	Confidence  float64
+	Reasoning   string
+	RawResponse string
+}
+
+// severityMaxLogs bounds how many trailing log lines are sent; classification must stay fast
+const severityMaxLogs = 20
+
+const severitySystemPrompt = "You are an expert incident triage engineer. Classify incident severity and respond with structured JSON."
+
+const severityPrompt = `Classify the severity of this newly reported incident.
+
+Title: %s
+Description: %s
+Source: %s
+
+Recent logs:
+%s
+
+Severity rubric:
+- critical: service down, data loss or security breach affecting customers
+- high: major feature broken or severely degraded for many users
+- medium: partial degradation, elevated errors or performance issues with a workaround
+- low: minor or cosmetic issue, informational alert
+
+Respond with a JSON object containing:
+{
+  "severity": "critical|high|medium|low",
+  "confidence": 0.0,
+  "reasoning": "One sentence explaining the classification"
+}
+
+Only respond with the JSON object, no additional text.`
+
+// severityClassificationPrompt builds the user prompt for ClassifySeverity
+func severityClassificationPrompt(req SeverityRequest) string {
+	logs := req.Logs
+	if len(logs) > severityMaxLogs {
+		logs = logs[len(logs)-severityMaxLogs:]
+	}
+	logsText := "(none)"
+	if len(logs) > 0 {
+		logsText = TrimLongText(strings.Join(logs, "\n"), 4000)
+	}
+	return fmt.Sprintf(severityPrompt, req.IncidentTitle, req.IncidentDesc, req.Source, logsText)
+}
+
+// parseSeverityResponse parses a classification, rejecting anything but the four known severities
+func parseSeverityResponse(rawResp string) (*SeverityResponse, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(extractJSON(rawResp)), &data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	severity := strings.ToLower(strings.TrimSpace(getStringValue(data, "severity")))
+	switch severity {
+	case "critical", "high", "medium", "low":
+	default:
+		return nil, fmt.Errorf("%w: unknown severity %q", ErrInvalidResponse, severity)
+	}
+
+	confidence, _ := data["confidence"].(float64)
+	return &SeverityResponse{
+		Severity:    severity,
+		Confidence:  confidence,
+		Reasoning:   getStringValue(data, "reasoning"),
+		RawResponse: rawResp,
+	}, nil
+}
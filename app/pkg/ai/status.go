@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StatusUpdateRequest represents a request to draft a customer-facing status update. It carries
+// only what customers may learn about; logs, metadata and responder notes are never included.
+type StatusUpdateRequest struct {
+	IncidentTitle string
+	IncidentDesc  string
+	Severity      string
+	// Summary is the incident's AI analysis summary, if any
+	Summary    string
+	Components []string
+	// State is the status page state the update should announce; empty lets the model choose
+	State string
+	// PreviousUpdates are the messages already published, oldest first
+	PreviousUpdates []string
+}
+
+// StatusUpdateResponse represents a drafted status update
+type StatusUpdateResponse struct {
+	Title       string
+	State       string
+	Message     string
+	RawResponse string
+}
+
+const statusUpdateSystemPrompt = "You are an incident communications manager writing updates for a public status page. Respond with structured JSON."
+
+const statusUpdatePrompt = `Draft the next public status page update for this incident.
+
+Internal title: %s
+Internal description: %s
+Severity: %s
+Internal analysis summary: %s
+Affected components: %s
+State to announce: %s
+
+Updates already published:
+%s
+
+Rules:
+- Write for customers. Describe the impact they see, not how the system works.
+- Never mention hostnames, IP addresses, URLs, internal service or team names, people, log lines, error messages, vendors or root cause details.
+- Refer to affected areas only by the component names above.
+- Do not speculate about causes or promise a time of resolution.
+- Keep the message to two or three short sentences.
+- The state is one of investigating, identified, monitoring or resolved.
+
+Respond with a JSON object containing:
+{
+  "title": "Short customer-facing incident title",
+  "state": "investigating|identified|monitoring|resolved",
+  "message": "The update text"
+}
+
+Only respond with the JSON object, no additional text.`
+
+// statusUpdateDraftPrompt builds the user prompt for DraftStatusUpdate
+func statusUpdateDraftPrompt(req StatusUpdateRequest) string {
+	orNone := func(s string) string {
+		if strings.TrimSpace(s) == "" {
+			return "(none)"
+		}
+		return s
+	}
+	state := req.State
+	if state == "" {
+		state = "(choose the best fit)"
+	}
+	previous := "(none)"
+	if len(req.PreviousUpdates) > 0 {
+		previous = "- " + strings.Join(req.PreviousUpdates, "\n- ")
+	}
+	return fmt.Sprintf(statusUpdatePrompt,
+		req.IncidentTitle,
+		orNone(TrimLongText(req.IncidentDesc, 2000)),
+		req.Severity,
+		orNone(TrimLongText(req.Summary, 2000)),
+		orNone(strings.Join(req.Components, ", ")),
+		state,
+		previous,
+	)
+}
+
+// parseStatusUpdateResponse parses a drafted update, rejecting unknown states and empty messages
+func parseStatusUpdateResponse(rawResp string) (*StatusUpdateResponse, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(extractJSON(rawResp)), &data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	state := strings.ToLower(strings.TrimSpace(getStringValue(data, "state")))
+	switch state {
+	case "investigating", "identified", "monitoring", "resolved":
+	default:
+		return nil, fmt.Errorf("%w: unknown state %q", ErrInvalidResponse, state)
+	}
+	message := strings.TrimSpace(getStringValue(data, "message"))
+	if message == "" {
+		return nil, fmt.Errorf("%w: empty message", ErrInvalidResponse)
+	}
+	return &StatusUpdateResponse{
+		Title:       strings.TrimSpace(getStringValue(data, "title")),
+		State:       state,
+		Message:     message,
+		RawResponse: rawResp,
+	}, nil
+}
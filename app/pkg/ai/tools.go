@@ -0,0 +1,254 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ToolDefinition describes a tool the model may call
+type ToolDefinition struct {
+	Name        string
+	Description string
+	// Parameters is a JSON Schema object describing the tool arguments
+	Parameters map[string]interface{}
+}
+
+// ToolFunc executes a tool call and returns its textual result
+type ToolFunc func(ctx context.Context, args json.RawMessage) (string, error)
+
+// ToolCall represents a model's request to run a tool
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments json.RawMessage
+}
+
+// ToolTraceEntry records a single tool invocation for auditing
+type ToolTraceEntry struct {
+	Step      int
+	Call      ToolCall
+	Output    string
+	Error     string
+	Duration  time.Duration
+	StartedAt time.Time
+}
+
+// ToolLimits bounds the tool-use loop
+type ToolLimits struct {
+	// MaxSteps is the maximum number of model round trips that may request tools
+	MaxSteps int
+	// MaxTokens is the total input+output token budget across all round trips
+	MaxTokens int
+	// MaxOutputChars truncates each tool result before it is returned to the model
+	MaxOutputChars int
+}
+
+// DefaultToolLimits are used when a request does not set its own limits
+var DefaultToolLimits = ToolLimits{
+	MaxSteps:       8,
+	MaxTokens:      60000,
+	MaxOutputChars: 8000,
+}
+
+// Stop reasons reported by the tool-use loop
+const (
+	StopReasonCompleted  = "completed"
+	StopReasonStepLimit  = "step_limit"
+	StopReasonTokenLimit = "token_limit"
+)
+
+// ErrUnknownTool is returned when the model calls a tool that is not registered
+var ErrUnknownTool = errors.New("unknown tool")
+
+type registeredTool struct {
+	def ToolDefinition
+	fn  ToolFunc
+}
+
+// ToolRegistry holds the tools available to the model
+type ToolRegistry struct {
+	tools map[string]registeredTool
+	mu    sync.RWMutex
+}
+
+// NewToolRegistry creates an empty tool registry
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		tools: make(map[string]registeredTool),
+	}
+}
+
+// Register adds a tool, replacing any existing tool with the same name
+func (r *ToolRegistry) Register(def ToolDefinition, fn ToolFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tools[def.Name] = registeredTool{def: def, fn: fn}
+}
+
+// Definitions returns the registered tool definitions sorted by name
+func (r *ToolRegistry) Definitions() []ToolDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defs := make([]ToolDefinition, 0, len(r.tools))
+	for _, t := range r.tools {
+		defs = append(defs, t.def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// Execute runs a tool call
+func (r *ToolRegistry) Execute(ctx context.Context, call ToolCall) (string, error) {
+	r.mu.RLock()
+	tool, ok := r.tools[call.Name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTool, call.Name)
+	}
+
+	args := call.Arguments
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	return tool.fn(ctx, args)
+}
+
+// ToolAnalysisRequest represents a request for incident analysis where the model gathers evidence with tools
+type ToolAnalysisRequest struct {
+	IncidentID    string
+	IncidentTitle string
+	IncidentDesc  string
+	Tools         *ToolRegistry
+	Limits        ToolLimits
+}
+
+// ToolAnalysisResponse represents the analysis together with the tool-call trace
+type ToolAnalysisResponse struct {
+	AnalysisResponse
+	Trace      []ToolTraceEntry
+	Steps      int
+	TokensUsed int
+	StopReason string
+}
+
+const toolAnalysisSystem = "You are an expert incident response analyst. Investigate incidents using the available tools, then provide structured JSON responses."
+
+const toolAnalysisPrompt = `Investigate this incident and provide structured analysis in JSON format.
+
+Incident ID: %s
+Title: %s
+Description: %s
+
+Logs are not included here. Use the available tools to grep the incident's logs, fetch its events,
+search related incidents and list recent deploys before drawing conclusions. Call only the tools you need.
+
+When you have enough evidence, respond with a JSON object containing:
+{
+  "summary": "Brief summary of the incident",
+  "findings": ["finding1", "finding2"],
+  "root_causes": ["cause1", "cause2"],
+  "recommended_actions": ["action1", "action2"],
+  "suggested_severity": "critical|high|medium|low"
+}
+
+Only respond with the JSON object, no additional text.`
+
+const toolFinalAnswerPrompt = "The tool budget is exhausted. Using the evidence gathered so far, respond now with the final JSON object only."
+
+// toolTurn is the outcome of one model round trip
+type toolTurn struct {
+	Text       string
+	Calls      []ToolCall
+	TokensUsed int
+}
+
+// toolResult is the output of a tool call returned to the model
+type toolResult struct {
+	Call    ToolCall
+	Output  string
+	IsError bool
+}
+
+// toolConversation abstracts a provider's wire format for the tool-use loop
+type toolConversation interface {
+	// next sends the conversation to the model; when allowTools is false the model must answer directly
+	next(ctx context.Context, allowTools bool) (*toolTurn, error)
+	// addResults appends tool results (and, when finalPrompt is set, a closing user instruction)
+	addResults(results []toolResult, finalPrompt string)
+}
+
+// withDefaults fills unset limits from DefaultToolLimits
+func (l ToolLimits) withDefaults() ToolLimits {
+	if l.MaxSteps <= 0 {
+		l.MaxSteps = DefaultToolLimits.MaxSteps
+	}
+	if l.MaxTokens <= 0 {
+		l.MaxTokens = DefaultToolLimits.MaxTokens
+	}
+	if l.MaxOutputChars <= 0 {
+		l.MaxOutputChars = DefaultToolLimits.MaxOutputChars
+	}
+	return l
+}
+
+// runToolLoop drives the model until it stops calling tools or a limit is reached
+func runToolLoop(ctx context.Context, conv toolConversation, registry *ToolRegistry, limits ToolLimits) (*ToolAnalysisResponse, error) {
+	limits = limits.withDefaults()
+	result := &ToolAnalysisResponse{StopReason: StopReasonCompleted}
+
+	allowTools := true
+	for {
+		turn, err := conv.next(ctx, allowTools)
+		if err != nil {
+			return nil, err
+		}
+		result.Steps++
+		result.TokensUsed += turn.TokensUsed
+
+		if len(turn.Calls) == 0 || !allowTools {
+			analysis, err := parseAnalysisResponse(turn.Text)
+			if err != nil {
+				return nil, err
+			}
+			result.AnalysisResponse = *analysis
+			return result, nil
+		}
+
+		results := make([]toolResult, 0, len(turn.Calls))
+		for _, call := range turn.Calls {
+			entry := ToolTraceEntry{Step: result.Steps, Call: call, StartedAt: time.Now()}
+			output, err := registry.Execute(ctx, call)
+			entry.Duration = time.Since(entry.StartedAt)
+
+			res := toolResult{Call: call, Output: TrimLongText(output, limits.MaxOutputChars)}
+			if err != nil {
+				entry.Error = err.Error()
+				res.Output = fmt.Sprintf("error: %v", err)
+				res.IsError = true
+			}
+			entry.Output = res.Output
+			result.Trace = append(result.Trace, entry)
+			results = append(results, res)
+		}
+
+		// Once a limit is hit the model gets one last turn, without tools, to produce its answer
+		finalPrompt := ""
+		if result.Steps >= limits.MaxSteps {
+			result.StopReason = StopReasonStepLimit
+			allowTools = false
+			finalPrompt = toolFinalAnswerPrompt
+		} else if result.TokensUsed >= limits.MaxTokens {
+			result.StopReason = StopReasonTokenLimit
+			allowTools = false
+			finalPrompt = toolFinalAnswerPrompt
+		}
+		conv.addResults(results, finalPrompt)
+	}
+}
@@ -0,0 +1,41 @@
+// Package blob stores incident attachments in a pluggable backend.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when no object exists under a key
+var ErrNotFound = errors.New("blob not found")
+
+// ErrInvalidKey is returned for keys that are empty or could escape the store
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store is a flat key/value object store
+type Store interface {
+	// Put writes size bytes from r under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns a time-limited download URL, or "" when the backend cannot serve objects directly
+	URL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// validKey rejects keys that are empty, absolute or contain ".." segments
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
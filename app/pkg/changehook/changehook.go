@@ -0,0 +1,365 @@
+// Package changehook turns CI/CD webhook payloads into change events.
+package changehook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// Provider names a webhook payload format
+type Provider string
+
+const (
+	ProviderGitHub  Provider = "github"
+	ProviderGitLab  Provider = "gitlab"
+	ProviderAWS     Provider = "aws"
+	ProviderGeneric Provider = "generic"
+)
+
+var (
+	// ErrUnknownProvider is returned for providers this package does not parse
+	ErrUnknownProvider = errors.New("unknown webhook provider")
+	// ErrIgnored is returned for valid payloads that do not describe a completed change,
+	// such as pending deployments or failed pipelines
+	ErrIgnored = errors.New("webhook event ignored")
+	// ErrInvalidPayload is returned for payloads that cannot be read
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+	// ErrUnauthorized is returned for deliveries without a valid signature or token
+	ErrUnauthorized = errors.New("webhook not authenticated")
+)
+
+// TokenHeader carries the shared secret for providers that do not sign their payloads
+const TokenHeader = "X-Change-Token"
+
+// Verify authenticates a delivery with the shared secret the way each provider sends it:
+// GitHub signs the body in X-Hub-Signature-256, GitLab sends the secret in X-Gitlab-Token, and
+// EventBridge connections and generic senders send it in X-Change-Token.
+func Verify(provider Provider, header http.Header, body []byte, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("%w: no secret configured", ErrUnauthorized)
+	}
+	var got, want string
+	switch provider {
+	case ProviderGitHub:
+		got, want = header.Get("X-Hub-Signature-256"), Sign(secret, body)
+	case ProviderGitLab:
+		got, want = header.Get("X-Gitlab-Token"), secret
+	case ProviderAWS, ProviderGeneric:
+		got, want = header.Get(TokenHeader), secret
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+	if got == "" || !hmac.Equal([]byte(got), []byte(want)) {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// Sign returns a GitHub webhook signature: "sha256=" followed by the hex HMAC-SHA256 of the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Parse reads a webhook payload from provider. The event type is only needed for GitHub,
+// which sends it in the X-GitHub-Event header.
+func Parse(provider Provider, event string, body []byte) (*models.CreateChangeRequest, error) {
+	var (
+		req *models.CreateChangeRequest
+		err error
+	)
+	switch provider {
+	case ProviderGitHub:
+		req, err = parseGitHub(event, body)
+	case ProviderGitLab:
+		req, err = parseGitLab(body)
+	case ProviderAWS:
+		req, err = parseAWS(body)
+	case ProviderGeneric:
+		req, err = parseGeneric(body)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if req.Service == "" {
+		return nil, fmt.Errorf("%w: no service name", ErrInvalidPayload)
+	}
+	return req, nil
+}
+
+// githubDeploymentStatus is the deployment_status event
+type githubDeploymentStatus struct {
+	DeploymentStatus struct {
+		State       string    `json:"state"`
+		TargetURL   string    `json:"target_url"`
+		LogURL      string    `json:"log_url"`
+		Environment string    `json:"environment"`
+		CreatedAt   time.Time `json:"created_at"`
+	} `json:"deployment_status"`
+	Deployment struct {
+		SHA         string `json:"sha"`
+		Ref         string `json:"ref"`
+		Environment string `json:"environment"`
+		Description string `json:"description"`
+		Creator     struct {
+			Login string `json:"login"`
+		} `json:"creator"`
+	} `json:"deployment"`
+	Repository struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+}
+
+// githubRelease is the release event
+type githubRelease struct {
+	Action  string `json:"action"`
+	Release struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+		Author      struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		TargetCommitish string `json:"target_commitish"`
+	} `json:"release"`
+	Repository struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+}
+
+func parseGitHub(event string, body []byte) (*models.CreateChangeRequest, error) {
+	switch event {
+	case "deployment_status":
+		var p githubDeploymentStatus
+		if err := json.Unmarshal(body, &p); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		if p.DeploymentStatus.State != "success" {
+			return nil, fmt.Errorf("%w: deployment state %q", ErrIgnored, p.DeploymentStatus.State)
+		}
+		env := p.DeploymentStatus.Environment
+		if env == "" {
+			env = p.Deployment.Environment
+		}
+		url := p.DeploymentStatus.TargetURL
+		if url == "" {
+			url = p.DeploymentStatus.LogURL
+		}
+		return &models.CreateChangeRequest{
+			Service:     p.Repository.Name,
+			Version:     p.Deployment.Ref,
+			Commit:      p.Deployment.SHA,
+			Author:      p.Deployment.Creator.Login,
+			Environment: env,
+			Kind:        models.ChangeDeploy,
+			Description: p.Deployment.Description,
+			URL:         url,
+			Timestamp:   timePtr(p.DeploymentStatus.CreatedAt),
+		}, nil
+	case "release":
+		var p githubRelease
+		if err := json.Unmarshal(body, &p); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		if p.Action != "published" {
+			return nil, fmt.Errorf("%w: release action %q", ErrIgnored, p.Action)
+		}
+		return &models.CreateChangeRequest{
+			Service:     p.Repository.Name,
+			Version:     p.Release.TagName,
+			Commit:      p.Release.TargetCommitish,
+			Author:      p.Release.Author.Login,
+			Kind:        models.ChangeDeploy,
+			Description: p.Release.Name,
+			URL:         p.Release.HTMLURL,
+			Timestamp:   timePtr(p.Release.PublishedAt),
+		}, nil
+	case "":
+		return nil, fmt.Errorf("%w: missing X-GitHub-Event header", ErrInvalidPayload)
+	default:
+		return nil, fmt.Errorf("%w: GitHub event %q", ErrIgnored, event)
+	}
+}
+
+// gitlabDeployment is the deployment event
+type gitlabDeployment struct {
+	ObjectKind      string `json:"object_kind"`
+	Status          string `json:"status"`
+	StatusChangedAt string `json:"status_changed_at"`
+	Environment     string `json:"environment"`
+	ShortSHA        string `json:"short_sha"`
+	Ref             string `json:"ref"`
+	DeployableURL   string `json:"deployable_url"`
+	User            struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		Name string `json:"name"`
+	} `json:"project"`
+}
+
+func parseGitLab(body []byte) (*models.CreateChangeRequest, error) {
+	var p gitlabDeployment
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if p.ObjectKind != "deployment" {
+		return nil, fmt.Errorf("%w: GitLab event %q", ErrIgnored, p.ObjectKind)
+	}
+	if p.Status != "success" {
+		return nil, fmt.Errorf("%w: deployment status %q", ErrIgnored, p.Status)
+	}
+	return &models.CreateChangeRequest{
+		Service:     p.Project.Name,
+		Version:     p.Ref,
+		Commit:      p.ShortSHA,
+		Author:      p.User.Username,
+		Environment: p.Environment,
+		Kind:        models.ChangeDeploy,
+		URL:         p.DeployableURL,
+		// GitLab uses "2021-04-28 21:50:00 +0200"
+		Timestamp: parseTime(p.StatusChangedAt, "2006-01-02 15:04:05 -0700", time.RFC3339),
+	}, nil
+}
+
+// awsEvent is an EventBridge event for CodePipeline or CodeBuild. These events carry the AWS region but
+// not the deployment environment, so the changes they record leave the environment empty and correlate
+// with incidents in any environment.
+type awsEvent struct {
+	Source     string    `json:"source"`
+	DetailType string    `json:"detail-type"`
+	Region     string    `json:"region"`
+	Time       time.Time `json:"time"`
+	Detail     struct {
+		// CodePipeline
+		Pipeline    string `json:"pipeline"`
+		ExecutionID string `json:"execution-id"`
+		State       string `json:"state"`
+		// CodeBuild
+		ProjectName           string `json:"project-name"`
+		BuildStatus           string `json:"build-status"`
+		BuildID               string `json:"build-id"`
+		AdditionalInformation struct {
+			SourceVersion string `json:"source-version"`
+			Initiator     string `json:"initiator"`
+		} `json:"additional-information"`
+	} `json:"detail"`
+}
+
+func parseAWS(body []byte) (*models.CreateChangeRequest, error) {
+	var p awsEvent
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	switch p.Source {
+	case "aws.codepipeline":
+		if p.Detail.State != "SUCCEEDED" {
+			return nil, fmt.Errorf("%w: pipeline state %q", ErrIgnored, p.Detail.State)
+		}
+		return &models.CreateChangeRequest{
+			Service:     p.Detail.Pipeline,
+			Version:     p.Detail.ExecutionID,
+			Kind:        models.ChangeDeploy,
+			Description: p.DetailType,
+			Timestamp:   timePtr(p.Time),
+		}, nil
+	case "aws.codebuild":
+		if p.Detail.BuildStatus != "SUCCEEDED" {
+			return nil, fmt.Errorf("%w: build status %q", ErrIgnored, p.Detail.BuildStatus)
+		}
+		return &models.CreateChangeRequest{
+			Service:     p.Detail.ProjectName,
+			Version:     p.Detail.BuildID,
+			Commit:      p.Detail.AdditionalInformation.SourceVersion,
+			Author:      p.Detail.AdditionalInformation.Initiator,
+			Kind:        models.ChangeDeploy,
+			Description: p.DetailType,
+			Timestamp:   timePtr(p.Time),
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: event source %q", ErrIgnored, p.Source)
+	}
+}
+
+// genericAliases lists the keys accepted for each change field, most specific first,
+// so Jenkins, Argo CD notifications, Spinnaker and shell scripts can post without a mapping
+var genericAliases = map[string][]string{
+	"service":     {"service", "app", "application", "project", "component", "repository"},
+	"version":     {"version", "tag", "release", "image_tag"},
+	"commit":      {"commit", "sha", "revision", "git_commit"},
+	"author":      {"author", "user", "triggered_by", "deployer"},
+	"environment": {"environment", "env", "stage", "cluster"},
+	"kind":        {"kind", "type"},
+	"description": {"description", "message", "summary"},
+	"url":         {"url", "build_url", "link"},
+	"timestamp":   {"timestamp", "time", "deployed_at", "finished_at"},
+	"status":      {"status", "result", "state"},
+}
+
+func parseGeneric(body []byte) (*models.CreateChangeRequest, error) {
+	var p map[string]interface{}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	field := func(name string) string {
+		for _, key := range genericAliases[name] {
+			if s, ok := p[key].(string); ok && s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+
+	switch strings.ToLower(field("status")) {
+	case "", "success", "succeeded", "successful", "ok", "deployed", "completed":
+	default:
+		return nil, fmt.Errorf("%w: status %q", ErrIgnored, field("status"))
+	}
+
+	// Other CI systems use "type" for their own event types
+	kind := models.ChangeKind(strings.ToLower(field("kind")))
+	if !kind.Valid() {
+		kind = ""
+	}
+
+	return &models.CreateChangeRequest{
+		Service:     field("service"),
+		Version:     field("version"),
+		Commit:      field("commit"),
+		Author:      field("author"),
+		Environment: field("environment"),
+		Kind:        kind,
+		Description: field("description"),
+		URL:         field("url"),
+		Timestamp:   parseTime(field("timestamp"), time.RFC3339),
+	}, nil
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func parseTime(value string, layouts ...string) *time.Time {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
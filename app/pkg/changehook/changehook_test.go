@@ -0,0 +1,107 @@
+package changehook
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider Provider
+		event    string
+		body     string
+		expected models.CreateChangeRequest
+		at       time.Time
+	}{
+		{
+			name:     "github deployment_status",
+			provider: ProviderGitHub,
+			event:    "deployment_status",
+			body: `{"deployment_status": {"state": "success", "environment": "production", "target_url": "https://ci/1", "created_at": "2024-05-01T09:40:00Z"},
+				"deployment": {"sha": "9f8e7d6c5b4a", "ref": "v1.4.2", "environment": "staging", "creator": {"login": "alice"}},
+				"repository": {"name": "checkout"}}`,
+			expected: models.CreateChangeRequest{Service: "checkout", Version: "v1.4.2", Commit: "9f8e7d6c5b4a", Author: "alice", Environment: "production", Kind: models.ChangeDeploy, URL: "https://ci/1"},
+			at:       time.Date(2024, 5, 1, 9, 40, 0, 0, time.UTC),
+		},
+		{
+			name:     "github release",
+			provider: ProviderGitHub,
+			event:    "release",
+			body:     `{"action": "published", "release": {"tag_name": "v2.0.0", "name": "Big one", "published_at": "2024-05-01T08:00:00Z", "author": {"login": "bob"}}, "repository": {"name": "ledger"}}`,
+			expected: models.CreateChangeRequest{Service: "ledger", Version: "v2.0.0", Author: "bob", Kind: models.ChangeDeploy, Description: "Big one"},
+			at:       time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "gitlab deployment",
+			provider: ProviderGitLab,
+			body:     `{"object_kind": "deployment", "status": "success", "status_changed_at": "2024-05-01 11:40:00 +0200", "environment": "production", "short_sha": "abc1234", "ref": "main", "user": {"username": "carol"}, "project": {"name": "payments"}}`,
+			expected: models.CreateChangeRequest{Service: "payments", Version: "main", Commit: "abc1234", Author: "carol", Environment: "production", Kind: models.ChangeDeploy},
+			at:       time.Date(2024, 5, 1, 9, 40, 0, 0, time.UTC),
+		},
+		{
+			name:     "codepipeline",
+			provider: ProviderAWS,
+			body:     `{"source": "aws.codepipeline", "detail-type": "CodePipeline Pipeline Execution State Change", "region": "us-east-1", "time": "2024-05-01T09:40:00Z", "detail": {"pipeline": "checkout-pipeline", "execution-id": "e-1", "state": "SUCCEEDED"}}`,
+			expected: models.CreateChangeRequest{Service: "checkout-pipeline", Version: "e-1", Kind: models.ChangeDeploy, Description: "CodePipeline Pipeline Execution State Change"},
+			at:       time.Date(2024, 5, 1, 9, 40, 0, 0, time.UTC),
+		},
+		{
+			name:     "codebuild",
+			provider: ProviderAWS,
+			body:     `{"source": "aws.codebuild", "detail-type": "CodeBuild Build State Change", "region": "us-east-1", "time": "2024-05-01T09:40:00Z", "detail": {"project-name": "checkout-build", "build-status": "SUCCEEDED", "build-id": "b-1", "additional-information": {"source-version": "abc", "initiator": "codepipeline/checkout"}}}`,
+			expected: models.CreateChangeRequest{Service: "checkout-build", Version: "b-1", Commit: "abc", Author: "codepipeline/checkout", Kind: models.ChangeDeploy, Description: "CodeBuild Build State Change"},
+			at:       time.Date(2024, 5, 1, 9, 40, 0, 0, time.UTC),
+		},
+		{
+			name:     "generic aliases",
+			provider: ProviderGeneric,
+			body:     `{"application": "search", "tag": "r42", "git_commit": "def", "triggered_by": "jenkins", "env": "prod", "type": "build", "result": "SUCCESS", "time": "2024-05-01T09:40:00Z"}`,
+			expected: models.CreateChangeRequest{Service: "search", Version: "r42", Commit: "def", Author: "jenkins", Environment: "prod"},
+			at:       time.Date(2024, 5, 1, 9, 40, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		req, err := Parse(tt.provider, tt.event, []byte(tt.body))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if req.Timestamp == nil || !req.Timestamp.Equal(tt.at) {
+			t.Errorf("%s: expected timestamp %v, got %v", tt.name, tt.at, req.Timestamp)
+		}
+		req.Timestamp = nil
+		if *req != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.expected, *req)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		provider Provider
+		event    string
+		body     string
+		expected error
+	}{
+		{ProviderGitHub, "deployment_status", `{"deployment_status": {"state": "failure"}, "repository": {"name": "checkout"}}`, ErrIgnored},
+		{ProviderGitHub, "push", `{}`, ErrIgnored},
+		{ProviderGitHub, "", `{}`, ErrInvalidPayload},
+		{ProviderGitLab, "", `{"object_kind": "pipeline"}`, ErrIgnored},
+		{ProviderAWS, "", `{"source": "aws.codepipeline", "detail": {"state": "FAILED"}}`, ErrIgnored},
+		{ProviderGeneric, "", `{"service": "search", "status": "failed"}`, ErrIgnored},
+		{ProviderGeneric, "", `{"version": "1"}`, ErrInvalidPayload},
+		{ProviderGeneric, "", `not json`, ErrInvalidPayload},
+		{"jenkins", "", `{}`, ErrUnknownProvider},
+	}
+
+	for _, tt := range tests {
+		if _, err := Parse(tt.provider, tt.event, []byte(tt.body)); !errors.Is(err, tt.expected) {
+			t.Errorf("%s %s: expected %v, got %v", tt.provider, tt.body, tt.expected, err)
+		}
+	}
+}
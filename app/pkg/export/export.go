@@ -0,0 +1,187 @@
+// Package export renders RCA documents for sharing outside the API.
+package export
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// Format is an RCA export format
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+	FormatPDF      Format = "pdf"
+)
+
+// Template file names; a template directory may override either
+const (
+	MarkdownTemplate = "rca.md.tmpl"
+	HTMLTemplate     = "rca.html.tmpl"
+)
+
+// ErrUnsupportedFormat is returned for formats other than markdown, html and pdf
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Data is passed to the export templates
+type Data struct {
+	Incident    *models.Incident
+	RCA         *models.RCADocument
+	Review      *models.RCAReview
+	GeneratedAt time.Time
+}
+
+// Source describes who wrote an RCA section, e.g. "AI (gpt-4)" or "alice"; empty when unknown
+func (d Data) Source(section string) string {
+	a, ok := d.RCA.Attribution[section]
+	if !ok {
+		return ""
+	}
+	if a.Source == models.ContentSourceAI {
+		return fmt.Sprintf("AI (%s)", a.Author)
+	}
+	return a.Author
+}
+
+// Renderer renders RCA documents from Markdown and HTML templates.
+// PDFs are laid out from the rendered Markdown so both share one template.
+type Renderer struct {
+	markdown *texttemplate.Template
+	html     *htmltemplate.Template
+}
+
+var funcs = map[string]interface{}{
+	"date": formatDate,
+}
+
+// NewRenderer loads the built-in templates, replacing each with the file of the same name in dir if present
+func NewRenderer(dir string) (*Renderer, error) {
+	mdSrc, err := loadTemplate(dir, MarkdownTemplate)
+	if err != nil {
+		return nil, err
+	}
+	htmlSrc, err := loadTemplate(dir, HTMLTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := texttemplate.New(MarkdownTemplate).Funcs(funcs).Parse(mdSrc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", MarkdownTemplate, err)
+	}
+	html, err := htmltemplate.New(HTMLTemplate).Funcs(funcs).Parse(htmlSrc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", HTMLTemplate, err)
+	}
+
+	return &Renderer{markdown: md, html: html}, nil
+}
+
+// Default returns a renderer using the built-in templates
+func Default() *Renderer {
+	r, err := NewRenderer("")
+	if err != nil {
+		panic(fmt.Sprintf("export: invalid built-in templates: %v", err))
+	}
+	return r
+}
+
+// ParseFormat validates a format name; empty means markdown
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatMarkdown:
+		return FormatMarkdown, nil
+	case FormatHTML, FormatPDF:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, s)
+}
+
+// ContentType returns the MIME type for a format
+func ContentType(f Format) string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+// Extension returns the file extension for a format
+func Extension(f Format) string {
+	switch f {
+	case FormatHTML:
+		return "html"
+	case FormatPDF:
+		return "pdf"
+	}
+	return "md"
+}
+
+// Render writes the document in the requested format
+func (r *Renderer) Render(w io.Writer, format Format, data Data) error {
+	switch format {
+	case FormatMarkdown:
+		return r.markdown.Execute(w, data)
+	case FormatHTML:
+		return r.html.Execute(w, data)
+	case FormatPDF:
+		var md bytes.Buffer
+		if err := r.markdown.Execute(&md, data); err != nil {
+			return err
+		}
+		return writePDF(w, md.String(), pdfInfo{
+			Title:   "Postmortem: " + data.Incident.Title,
+			Footer:  data.Incident.ID,
+			Created: data.GeneratedAt,
+		})
+	}
+	return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+func loadTemplate(dir, name string) (string, error) {
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to read template %s: %w", name, err)
+		}
+	}
+
+	data, err := defaultTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// formatDate formats time.Time and *time.Time values, rendering nil as an empty string
+func formatDate(v interface{}) string {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC().Format("2006-01-02 15:04 MST")
+	case *time.Time:
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format("2006-01-02 15:04 MST")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,170 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+func testData() Data {
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	return Data{
+		Incident: &models.Incident{
+			ID:          "INC-1",
+			Title:       "Checkout <errors> — 5xx spike",
+			Description: "Payments returned 503 for 30 minutes",
+			Status:      models.StatusResolved,
+			Severity:    models.SeverityHigh,
+			CreatedAt:   created,
+		},
+		RCA: &models.RCADocument{
+			Revision:           3,
+			Timeline:           []string{"10:00 alert fired", "10:30 rollback"},
+			RootCause:          "Connection pool exhausted (max 10)",
+			Impact:             "1,200 failed checkouts",
+			PreventiveMeasures: []string{"Alert on pool saturation"},
+			Attribution: map[string]models.SectionAttribution{
+				models.RCASectionRootCause: {Source: models.ContentSourceHuman, Author: "alice"},
+				models.RCASectionImpact:    {Source: models.ContentSourceAI, Author: "gpt-4"},
+			},
+		},
+		Review:      &models.RCAReview{Status: models.RCAStatusPublished},
+		GeneratedAt: created,
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Default().Render(&buf, FormatMarkdown, testData()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	md := buf.String()
+
+	for _, want := range []string{
+		"# Postmortem: Checkout <errors> — 5xx spike",
+		"- **RCA revision:** 3 (published)",
+		"## Root Cause\n\n_Written by alice_",
+		"_Written by AI (gpt-4)_",
+		"- 10:30 rollback",
+		"## Lessons Learned\n\n_None recorded._",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q\n%s", want, md)
+		}
+	}
+}
+
+func TestRenderHTMLEscapes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Default().Render(&buf, FormatHTML, testData()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Checkout &lt;errors&gt;") {
+		t.Error("expected incident title to be HTML escaped")
+	}
+	if !strings.Contains(buf.String(), "<li>Alert on pool saturation</li>") {
+		t.Error("expected preventive measures list")
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	data := testData()
+	for i := 0; i < 120; i++ {
+		data.RCA.Timeline = append(data.RCA.Timeline, "12:00 another step in a long investigation that wraps onto a second line of the page, and then some")
+	}
+
+	var buf bytes.Buffer
+	if err := Default().Render(&buf, FormatPDF, data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pdf := buf.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("expected a PDF header and trailer")
+	}
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Error("startxref does not point at the xref table")
+	}
+
+	// Every xref entry must point at its object
+	entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(pdf[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := strconv.Itoa(i+1) + " 0 obj"; !bytes.HasPrefix(pdf[off:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, pdf[off:off+10])
+		}
+	}
+
+	count := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(pdf)
+	if pages, _ := strconv.Atoi(string(count[1])); pages < 2 {
+		t.Errorf("expected long timeline to span pages, got %d", pages)
+	}
+
+	var content []byte
+	for _, stream := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(pdf, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(stream[1]))
+		if err != nil {
+			t.Fatalf("invalid content stream: %v", err)
+		}
+		page, _ := io.ReadAll(zr)
+		content = append(content, page...)
+	}
+	// Text is WinAnsi encoded (the em dash is octal 227) with PDF string escapes
+	if !strings.Contains(string(content), `(Postmortem:) Tj`) || !strings.Contains(string(content), `( \227) Tj`) {
+		t.Errorf("expected title text in page content:\n%s", content[:300])
+	}
+	if !strings.Contains(string(content), `(Connection) Tj ( pool) Tj ( exhausted) Tj ( \(max) Tj`) {
+		t.Error("expected parentheses to be escaped")
+	}
+}
+
+func TestTemplateOverride(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, MarkdownTemplate), []byte("{{.Incident.ID}}: {{.RCA.RootCause}}"), 0o644)
+
+	r, err := NewRenderer(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	r.Render(&buf, FormatMarkdown, testData())
+	if buf.String() != "INC-1: Connection pool exhausted (max 10)" {
+		t.Errorf("expected override template output, got %q", buf.String())
+	}
+
+	// The HTML template was not overridden and still renders
+	buf.Reset()
+	if err := r.Render(&buf, FormatHTML, testData()); err != nil || !strings.Contains(buf.String(), "<h2>Root Cause</h2>") {
+		t.Errorf("expected built-in HTML template, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, HTMLTemplate), []byte("{{.Broken"), 0o644)
+	if _, err := NewRenderer(dir); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(""); err != nil || f != FormatMarkdown {
+		t.Errorf("expected markdown default, got %q %v", f, err)
+	}
+	if _, err := ParseFormat("docx"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,365 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page geometry in points (US Letter)
+const (
+	pageWidth    = 612.0
+	pageHeight   = 792.0
+	pageMargin   = 56.0
+	footerOffset = 30.0
+	bodySize     = 10.5
+	lineSpacing  = 1.4
+	bulletIndent = 14.0
+)
+
+type pdfFont int
+
+const (
+	fontRegular pdfFont = iota
+	fontBold
+	fontItalic
+)
+
+// The standard 14 fonts need no embedding, which keeps the writer dependency-free
+var pdfFontNames = [...]string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+
+// helveticaWidths are the Helvetica glyph widths for ASCII 32-126 in 1/1000 em
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// boldWidthFactor approximates Helvetica-Bold from the regular widths; wrapping errs slightly wide
+const boldWidthFactor = 1.08
+
+// bulletChar is the WinAnsiEncoding bullet
+const bulletChar = "\x95"
+
+type pdfInfo struct {
+	Title   string
+	Footer  string
+	Created time.Time
+}
+
+type pdfWord struct {
+	text string
+	font pdfFont
+}
+
+// pdfLayout flows text onto pages and collects each page's content stream
+type pdfLayout struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+// writePDF lays out a Markdown document (headings, paragraphs, bullets, rules, **bold** and
+// whole-line _italic_) as a PDF. Anything richer is rendered as plain text.
+func writePDF(w io.Writer, markdown string, info pdfInfo) error {
+	l := &pdfLayout{}
+	l.newPage()
+	markdown = toWinAnsi(markdown)
+
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			l.paragraph(parseInline(strings.Join(para, " ")), bodySize, 0, false)
+			l.space(bodySize * 0.6)
+			para = nil
+		}
+	}
+
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "### "):
+			flush()
+			l.heading(trimmed[4:], 12)
+		case strings.HasPrefix(trimmed, "## "):
+			flush()
+			l.heading(trimmed[3:], 14)
+		case strings.HasPrefix(trimmed, "# "):
+			flush()
+			l.heading(trimmed[2:], 18)
+		case trimmed == "---" || trimmed == "***":
+			flush()
+			l.rule()
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flush()
+			l.paragraph(parseInline(trimmed[2:]), bodySize, bulletIndent, true)
+		case len(trimmed) > 2 && strings.HasPrefix(trimmed, "_") && strings.HasSuffix(trimmed, "_"):
+			flush()
+			words := parseInline(trimmed[1 : len(trimmed)-1])
+			for i := range words {
+				words[i].font = fontItalic
+			}
+			l.paragraph(words, bodySize-1, 0, false)
+			l.space(bodySize * 0.4)
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+
+	return l.write(w, info)
+}
+
+// parseInline splits text on ** markers, alternating between regular and bold
+func parseInline(text string) []pdfWord {
+	var words []pdfWord
+	for i, part := range strings.Split(text, "**") {
+		font := fontRegular
+		if i%2 == 1 {
+			font = fontBold
+		}
+		for _, f := range strings.Fields(part) {
+			words = append(words, pdfWord{text: f, font: font})
+		}
+	}
+	return words
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = &bytes.Buffer{}
+	l.pages = append(l.pages, l.page)
+	l.y = pageHeight - pageMargin
+}
+
+// ensure starts a new page unless height fits above the bottom margin
+func (l *pdfLayout) ensure(height float64) {
+	if l.y-height < pageMargin {
+		l.newPage()
+	}
+}
+
+func (l *pdfLayout) space(height float64) {
+	if l.y < pageHeight-pageMargin {
+		l.y -= height
+	}
+}
+
+func (l *pdfLayout) heading(text string, size float64) {
+	l.space(size * 0.6)
+	// Keep a heading with at least two lines of the section that follows
+	l.ensure(size*lineSpacing + 2*bodySize*lineSpacing)
+	words := parseInline(text)
+	for i := range words {
+		words[i].font = fontBold
+	}
+	l.paragraph(words, size, 0, false)
+	l.space(size * 0.3)
+}
+
+func (l *pdfLayout) rule() {
+	l.space(bodySize * 0.5)
+	l.ensure(bodySize)
+	fmt.Fprintf(l.page, "0.6 G 0.5 w %s %s m %s %s l S 0 G\n",
+		num(pageMargin), num(l.y), num(pageWidth-pageMargin), num(l.y))
+	l.y -= bodySize
+}
+
+// paragraph wraps words to the text width and writes them line by line
+func (l *pdfLayout) paragraph(words []pdfWord, size, indent float64, bullet bool) {
+	maxWidth := pageWidth - 2*pageMargin - indent
+	leading := size * lineSpacing
+
+	var lines [][]pdfWord
+	var current []pdfWord
+	width := 0.0
+	for _, w := range splitLongWords(words, size, maxWidth) {
+		ww := textWidth(w.text, w.font, size)
+		if len(current) > 0 && width+textWidth(" ", w.font, size)+ww > maxWidth {
+			lines = append(lines, current)
+			current, width = nil, 0
+		}
+		if len(current) > 0 {
+			width += textWidth(" ", w.font, size)
+		}
+		current = append(current, w)
+		width += ww
+	}
+	if len(current) > 0 {
+		lines = append(lines, current)
+	}
+
+	for i, line := range lines {
+		l.ensure(leading)
+		l.y -= leading
+		x := pageMargin + indent
+
+		if bullet && i == 0 {
+			fmt.Fprintf(l.page, "BT /F1 %s Tf %s %s Td (%s) Tj ET\n", num(size), num(x-10), num(l.y), escapePDF(bulletChar))
+		}
+
+		l.page.WriteString("BT ")
+		fmt.Fprintf(l.page, "%s %s Td ", num(x), num(l.y))
+		font := pdfFont(-1)
+		for j, w := range line {
+			text := w.text
+			if j > 0 {
+				text = " " + text
+			}
+			if w.font != font {
+				font = w.font
+				fmt.Fprintf(l.page, "/F%d %s Tf ", font+1, num(size))
+			}
+			fmt.Fprintf(l.page, "(%s) Tj ", escapePDF(text))
+		}
+		l.page.WriteString("ET\n")
+	}
+}
+
+// splitLongWords breaks words wider than the line (URLs, hashes) into pieces that fit
+func splitLongWords(words []pdfWord, size, maxWidth float64) []pdfWord {
+	var out []pdfWord
+	for _, w := range words {
+		for textWidth(w.text, w.font, size) > maxWidth {
+			cut := 1
+			for cut < len(w.text) && textWidth(w.text[:cut+1], w.font, size) <= maxWidth {
+				cut++
+			}
+			out = append(out, pdfWord{text: w.text[:cut], font: w.font})
+			w.text = w.text[cut:]
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// write serialises the pages, fonts and cross-reference table
+func (l *pdfLayout) write(w io.Writer, info pdfInfo) error {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-6 are fixed; each page then takes a page object and a content stream
+	const firstPage = 7
+	kids := make([]string, len(l.pages))
+	for i := range l.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(l.pages)))
+	for _, name := range pdfFontNames {
+		obj(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	obj(fmt.Sprintf("<< /Title (%s) /Producer (incident-api) /CreationDate (D:%s) >>",
+		escapePDF(toWinAnsi(info.Title)), info.Created.UTC().Format("20060102150405Z")))
+
+	for i, page := range l.pages {
+		footer := toWinAnsi(fmt.Sprintf("%s - page %d of %d", info.Footer, i+1, len(l.pages)))
+		fmt.Fprintf(page, "BT /F1 8 Tf %s %s Td (%s) Tj ET\n", num(pageMargin), num(footerOffset), escapePDF(footer))
+
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			num(pageWidth), num(pageHeight), firstPage+2*i+1))
+		obj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// textWidth measures WinAnsi text in points
+func textWidth(text string, font pdfFont, size float64) float64 {
+	total := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c >= 32 && c <= 126 {
+			total += helveticaWidths[c-32]
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * size / 1000
+	if font == fontBold {
+		width *= boldWidthFactor
+	}
+	return width
+}
+
+// winAnsiReplacements maps common typographic characters to WinAnsiEncoding
+var winAnsiReplacements = map[rune]string{
+	'‘': "\x91", '’': "\x92", '“': "\x93", '”': "\x94",
+	'•': "\x95", '–': "\x96", '—': "\x97", '…': "\x85",
+	'\t': " ",
+}
+
+// toWinAnsi converts UTF-8 to WinAnsiEncoding, replacing characters it cannot represent with '?'
+func toWinAnsi(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 32 && r <= 126, r == '\n':
+			b.WriteRune(r)
+		case winAnsiReplacements[r] != "":
+			b.WriteString(winAnsiReplacements[r])
+		case r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// escapePDF encodes WinAnsi text as the body of a PDF literal string
+func escapePDF(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' || c == '(' || c == ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
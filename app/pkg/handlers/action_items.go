@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// CreateActionItem handles POST /api/v1/incidents/{id}/action-items
+func (h *IncidentHandler) CreateActionItem(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateActionItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	item, err := h.incidentService.CreateActionItem(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondActionItemError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, item)
+}
+
+// ListIncidentActionItems handles GET /api/v1/incidents/{id}/action-items
+func (h *IncidentHandler) ListIncidentActionItems(w http.ResponseWriter, r *http.Request) {
+	items, err := h.incidentService.ListIncidentActionItems(mux.Vars(r)["id"])
+	if err != nil {
+		respondActionItemError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, items)
+}
+
+// PromoteActionItems handles POST /api/v1/incidents/{id}/action-items/promote
+func (h *IncidentHandler) PromoteActionItems(w http.ResponseWriter, r *http.Request) {
+	var req models.PromoteActionItemsRequest
+	// An empty body promotes every preventive measure
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	items, err := h.incidentService.PromoteActionItems(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondActionItemError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, items)
+}
+
+// UpdateActionItem handles PUT /api/v1/incidents/{id}/action-items/{itemId}
+func (h *IncidentHandler) UpdateActionItem(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateActionItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	vars := mux.Vars(r)
+	item, err := h.incidentService.UpdateActionItem(vars["id"], vars["itemId"], &req)
+	if err != nil {
+		respondActionItemError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, item)
+}
+
+// DeleteActionItem handles DELETE /api/v1/incidents/{id}/action-items/{itemId}
+func (h *IncidentHandler) DeleteActionItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.incidentService.DeleteActionItem(vars["id"], vars["itemId"]); err != nil {
+		respondActionItemError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListActionItems handles GET /api/v1/action-items
+// Supports ?owner=, ?status= and ?overdue=true|false filters across all incidents
+func (h *IncidentHandler) ListActionItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ActionItemFilter{
+		Owner:      query.Get("owner"),
+		IncidentID: query.Get("incident_id"),
+	}
+	if status := query.Get("status"); status != "" {
+		s := models.ActionItemStatus(status)
+		filter.Status = &s
+	}
+	if overdue := query.Get("overdue"); overdue != "" {
+		v, err := strconv.ParseBool(overdue)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "overdue must be true or false")
+			return
+		}
+		filter.Overdue = &v
+	}
+
+	respondJSON(w, http.StatusOK, h.incidentService.ListActionItems(filter))
+}
+
+// respondActionItemError maps action item service errors to HTTP status codes
+func respondActionItemError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrActionItemNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrRCANotFound):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidActionItem):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestActionItemHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	base := "/api/v1/incidents/" + created.ID + "/action-items"
+
+	tests := []struct {
+		method   string
+		path     string
+		body     string
+		expected int
+	}{
+		{http.MethodPost, base, `{"title": "Rotate certificates", "owner": "alice", "due_date": "2020-01-01T00:00:00Z"}`, http.StatusCreated},
+		{http.MethodPost, base, `{"owner": "alice"}`, http.StatusBadRequest},
+		{http.MethodPost, base + "/promote", "", http.StatusConflict},
+		{http.MethodPut, base + "/ACT-999", `{"status": "done"}`, http.StatusNotFound},
+		{http.MethodPut, base + "/ACT-2", `{"status": "blocked"}`, http.StatusBadRequest},
+		{http.MethodGet, base, "", http.StatusOK},
+		{http.MethodGet, "/api/v1/action-items?overdue=maybe", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/incidents/INC-missing/action-items", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.method, tt.path, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/action-items?owner=alice&overdue=true", nil))
+	var items []models.ActionItem
+	json.NewDecoder(w.Body).Decode(&items)
+	if len(items) != 1 || !items[0].Overdue || items[0].IncidentID != created.ID {
+		t.Errorf("expected alice's overdue item, got %+v", items)
+	}
+}
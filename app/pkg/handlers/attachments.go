@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/blob"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// multipartOverhead allows for form fields and part headers on top of the attachment size limit
+const multipartOverhead = 1 << 20
+
+// UploadAttachment handles POST /api/v1/incidents/{id}/attachments
+// Expects multipart/form-data with a "file" part and optional "uploader" and "analyze" fields
+func (h *IncidentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.incidentService.AttachmentLimit()+multipartOverhead)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("attachment exceeds %d bytes", h.incidentService.AttachmentLimit()))
+			return
+		}
+		respondError(w, http.StatusBadRequest, "expected multipart/form-data")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "missing file")
+		return
+	}
+	defer file.Close()
+
+	analyze, _ := strconv.ParseBool(r.FormValue("analyze"))
+	req := &models.UploadAttachmentRequest{
+		Filename:          header.Filename,
+		Uploader:          r.FormValue("uploader"),
+		IncludeInAnalysis: analyze,
+	}
+
+	attachment, err := h.incidentService.UploadAttachment(mux.Vars(r)["id"], req, file)
+	if err != nil {
+		respondAttachmentError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, withDownloadURL(attachment))
+}
+
+// ListAttachments handles GET /api/v1/incidents/{id}/attachments
+func (h *IncidentHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	attachments, err := h.incidentService.ListAttachments(mux.Vars(r)["id"])
+	if err != nil {
+		respondAttachmentError(w, err)
+		return
+	}
+	for _, attachment := range attachments {
+		withDownloadURL(attachment)
+	}
+	respondJSON(w, http.StatusOK, attachments)
+}
+
+// GetAttachment handles GET /api/v1/incidents/{id}/attachments/{attachmentId}
+func (h *IncidentHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	attachment, err := h.incidentService.GetAttachment(vars["id"], vars["attachmentId"])
+	if err != nil {
+		respondAttachmentError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, withDownloadURL(attachment))
+}
+
+// DownloadAttachment handles GET /api/v1/incidents/{id}/attachments/{attachmentId}/download
+func (h *IncidentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	attachment, content, err := h.incidentService.OpenAttachment(r.Context(), vars["id"], vars["attachmentId"])
+	if err != nil {
+		respondAttachmentError(w, err)
+		return
+	}
+	defer content.Close()
+
+	// Uploads are untrusted; always download rather than render them in the browser
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.Filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", strconv.Quote(attachment.SHA256))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Warn("attachment download interrupted", zap.String("id", attachment.ID), zap.Error(err))
+	}
+}
+
+// DeleteAttachment handles DELETE /api/v1/incidents/{id}/attachments/{attachmentId}
+func (h *IncidentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.incidentService.DeleteAttachment(vars["id"], vars["attachmentId"]); err != nil {
+		respondAttachmentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// withDownloadURL points attachments without a presigned URL at the API download endpoint
+func withDownloadURL(attachment *models.Attachment) *models.Attachment {
+	if attachment.DownloadURL == "" {
+		attachment.DownloadURL = "/api/v1/incidents/" + attachment.IncidentID + "/attachments/" + attachment.ID + "/download"
+	}
+	return attachment
+}
+
+// respondAttachmentError maps attachment service errors to HTTP status codes
+func respondAttachmentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrAttachmentNotFound), errors.Is(err, blob.ErrNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		respondError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, service.ErrInvalidAttachment):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrAttachmentsDisabled):
+		respondError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
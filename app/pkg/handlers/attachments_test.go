@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/blob"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func multipartUpload(t *testing.T, url, filename, content string, fields map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for k, v := range fields {
+		writer.WriteField(k, v)
+	}
+	if filename != "" {
+		part, _ := writer.CreateFormFile("file", filename)
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, url, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestAttachmentHandlers(t *testing.T) {
+	store, _ := blob.NewLocalStore(t.TempDir())
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop(),
+		service.WithBlobStore(store), service.WithAttachmentLimit(64))
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	base := "/api/v1/incidents/" + created.ID + "/attachments"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, multipartUpload(t, base, "describe.txt", "Name: api-7d9\nStatus: CrashLoopBackOff\n", map[string]string{"uploader": "alice", "analyze": "true"}))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var attachment models.Attachment
+	json.NewDecoder(w.Body).Decode(&attachment)
+	if !attachment.IncludeInAnalysis || attachment.Uploader != "alice" || !strings.HasSuffix(attachment.DownloadURL, "/"+attachment.ID+"/download") {
+		t.Errorf("unexpected attachment %+v", attachment)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, attachment.DownloadURL, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "CrashLoopBackOff") {
+		t.Fatalf("expected download, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Disposition") != `attachment; filename="describe.txt"` || w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("unexpected download headers %v", w.Header())
+	}
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{multipartUpload(t, base, "big.log", strings.Repeat("x", 65), nil), http.StatusRequestEntityTooLarge},
+		{multipartUpload(t, base, "", "", map[string]string{"uploader": "alice"}), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, base, strings.NewReader(`{"logs": []}`)), http.StatusBadRequest},
+		{multipartUpload(t, "/api/v1/incidents/INC-missing/attachments", "a.log", "x", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodGet, base, nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, base+"/ATT-999", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodDelete, base+"/"+attachment.ID, nil), http.StatusNoContent},
+		{httptest.NewRequest(http.MethodGet, base+"/"+attachment.ID+"/download", nil), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+}
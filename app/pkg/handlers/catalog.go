@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// CreateService handles POST /api/v1/services
+func (h *IncidentHandler) CreateService(w http.ResponseWriter, r *http.Request) {
+	var req models.Service
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	svc, err := h.incidentService.CreateService(&req)
+	if err != nil {
+		respondServiceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, svc)
+}
+
+// ListServices handles GET /api/v1/services?team=
+func (h *IncidentHandler) ListServices(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.incidentService.ListServices(r.URL.Query().Get("team")))
+}
+
+// GetService handles GET /api/v1/services/{name}
+func (h *IncidentHandler) GetService(w http.ResponseWriter, r *http.Request) {
+	svc, err := h.incidentService.GetService(mux.Vars(r)["name"])
+	if err != nil {
+		respondServiceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, svc)
+}
+
+// UpdateService handles PUT /api/v1/services/{name}
+func (h *IncidentHandler) UpdateService(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	svc, err := h.incidentService.UpdateService(mux.Vars(r)["name"], &req)
+	if err != nil {
+		respondServiceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, svc)
+}
+
+// DeleteService handles DELETE /api/v1/services/{name}
+func (h *IncidentHandler) DeleteService(w http.ResponseWriter, r *http.Request) {
+	if err := h.incidentService.DeleteService(mux.Vars(r)["name"]); err != nil {
+		respondServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServiceDependencies handles GET /api/v1/services/{name}/dependencies
+func (h *IncidentHandler) ServiceDependencies(w http.ResponseWriter, r *http.Request) {
+	deps, err := h.incidentService.ServiceDependencies(mux.Vars(r)["name"])
+	if err != nil {
+		respondServiceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, deps)
+}
+
+// respondServiceError maps service catalog errors to HTTP status codes
+func respondServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrServiceNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrServiceExists):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidService):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
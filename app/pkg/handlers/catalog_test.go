@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestServiceCatalogHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodPost, "/api/v1/services", strings.NewReader(`{"name": "checkout", "team": "payments", "tier": 1, "dependencies": ["payments-api"]}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/services", strings.NewReader(`{"name": "payments-api", "team": "payments", "tier": 1}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/services", strings.NewReader(`{"name": "checkout", "team": "other"}`)), http.StatusConflict},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/services", strings.NewReader(`{"name": "search"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/services/checkout", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/services/search", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPut, "/api/v1/services/checkout", strings.NewReader(`{"severity_floor": "medium"}`)), http.StatusOK},
+		{httptest.NewRequest(http.MethodPut, "/api/v1/services/checkout", strings.NewReader(`{"tier": 9}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/services/payments-api/dependencies", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents", strings.NewReader(`{"title": "x", "description": "y", "service": "search"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodDelete, "/api/v1/services/search", nil), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/incidents", strings.NewReader(
+		`{"title": "Checkout latency", "description": "p99 up", "tags": ["service:checkout"]}`)))
+	var incident models.Incident
+	json.NewDecoder(w.Body).Decode(&incident)
+	if incident.Service != "checkout" || incident.AssignedTo != "payments" || incident.Severity != models.SeverityMedium {
+		t.Errorf("expected checkout incident for payments at medium, got service %q assigned %q severity %s",
+			incident.Service, incident.AssignedTo, incident.Severity)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/services/payments-api/dependencies", nil))
+	var deps models.ServiceDependencies
+	json.NewDecoder(w.Body).Decode(&deps)
+	if len(deps.Downstream) != 1 || deps.Downstream[0].Name != "checkout" {
+		t.Errorf("expected checkout to depend on payments-api, got %+v", deps)
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/changehook"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// maxWebhookBodyBytes bounds CI webhook payloads, which can include whole commit lists
+const maxWebhookBodyBytes = 5 << 20
+
+// CreateChange handles POST /api/v1/changes
+func (h *IncidentHandler) CreateChange(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	change, err := h.incidentService.RecordChange("api", &req)
+	if err != nil {
+		respondChangeError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, change)
+}
+
+// ListChanges handles GET /api/v1/changes?service=&environment=&kind=&since=&until=
+func (h *IncidentHandler) ListChanges(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ChangeFilter{
+		Service:     query.Get("service"),
+		Environment: query.Get("environment"),
+		Kind:        models.ChangeKind(query.Get("kind")),
+	}
+	var err error
+	if filter.Since, err = timeParam(query, "since"); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Until, err = timeParam(query, "until"); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.incidentService.ListChanges(filter))
+}
+
+// WithChangeWebhookSecret enables the CI/CD change webhooks, authenticated with secret
+func WithChangeWebhookSecret(secret string) HandlerOption {
+	return func(h *IncidentHandler) {
+		h.changeWebhookSecret = secret
+	}
+}
+
+// ChangeWebhook handles POST /api/v1/changes/webhooks/{provider}
+// Events that do not describe a completed change, such as failed builds, are acknowledged and dropped
+func (h *IncidentHandler) ChangeWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.changeWebhookSecret == "" {
+		respondError(w, http.StatusServiceUnavailable, "change webhooks are not configured")
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "webhook payload too large")
+		return
+	}
+
+	provider := changehook.Provider(mux.Vars(r)["provider"])
+	if err := changehook.Verify(provider, r.Header, body, h.changeWebhookSecret); err != nil {
+		if errors.Is(err, changehook.ErrUnknownProvider) {
+			respondError(w, http.StatusNotFound, err.Error())
+		} else {
+			respondError(w, http.StatusUnauthorized, err.Error())
+		}
+		return
+	}
+	req, err := changehook.Parse(provider, r.Header.Get("X-GitHub-Event"), body)
+	if err != nil {
+		switch {
+		case errors.Is(err, changehook.ErrIgnored):
+			respondJSON(w, http.StatusAccepted, map[string]string{"status": "ignored", "reason": err.Error()})
+		case errors.Is(err, changehook.ErrUnknownProvider):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	change, err := h.incidentService.RecordChange(string(provider), req)
+	if err != nil {
+		respondChangeError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, change)
+}
+
+// IncidentChanges handles GET /api/v1/incidents/{id}/changes
+func (h *IncidentHandler) IncidentChanges(w http.ResponseWriter, r *http.Request) {
+	changes, err := h.incidentService.IncidentChanges(mux.Vars(r)["id"])
+	if err != nil {
+		respondChangeError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, changes)
+}
+
+// timeParam parses an optional RFC 3339 query parameter
+func timeParam(query url.Values, name string) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: expected an RFC 3339 time", name)
+	}
+	return &t, nil
+}
+
+func respondChangeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidChange):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/changehook"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestChangeHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop(), WithChangeWebhookSecret("s3cret")).RegisterRoutes(router)
+
+	// webhook signs GitHub deliveries and sends the token for everything else
+	webhook := func(provider, body, secret string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/changes/webhooks/"+provider, strings.NewReader(body))
+		if provider == "github" {
+			req.Header.Set("X-GitHub-Event", "deployment_status")
+			req.Header.Set("X-Hub-Signature-256", changehook.Sign(secret, []byte(body)))
+		} else {
+			req.Header.Set(changehook.TokenHeader, secret)
+		}
+		return req
+	}
+	github := webhook("github", `{"deployment_status": {"state": "success"}, "deployment": {"sha": "abc", "ref": "v2"}, "repository": {"name": "checkout"}}`, "s3cret")
+	pending := webhook("github", `{"deployment_status": {"state": "pending"}, "repository": {"name": "checkout"}}`, "s3cret")
+	forged := webhook("github", `{"deployment_status": {"state": "success"}, "repository": {"name": "checkout"}}`, "guess")
+	gitlab := httptest.NewRequest(http.MethodPost, "/api/v1/changes/webhooks/gitlab", strings.NewReader(`{"object_kind": "deployment", "status": "success", "project": {"name": "payments"}}`))
+	gitlab.Header.Set("X-Gitlab-Token", "s3cret")
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodPost, "/api/v1/changes", strings.NewReader(`{"service": "checkout", "version": "v1", "author": "alice"}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/changes", strings.NewReader(`{"version": "v1"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/changes", strings.NewReader(`{`)), http.StatusBadRequest},
+		{github, http.StatusCreated},
+		{pending, http.StatusAccepted},
+		{forged, http.StatusUnauthorized},
+		{gitlab, http.StatusCreated},
+		{webhook("generic", `{"app": "search", "status": "success"}`, "s3cret"), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/changes/webhooks/generic", strings.NewReader(`{"app": "search", "status": "success"}`)), http.StatusUnauthorized},
+		{webhook("bamboo", `{}`, "s3cret"), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/changes?since=yesterday", nil), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/incidents/INC-missing/changes", nil), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	// Opened after the changes were recorded, so they count as possible causes
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/changes?service=checkout", nil))
+	var changes []models.Change
+	json.NewDecoder(w.Body).Decode(&changes)
+	if len(changes) != 2 || changes[0].Source != "github" {
+		t.Errorf("expected 2 checkout changes, newest from github first, got %+v", changes)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+created.ID+"/changes", nil))
+	var correlated []models.CorrelatedChange
+	json.NewDecoder(w.Body).Decode(&correlated)
+	if len(correlated) != 2 || !correlated[0].Suspect || correlated[0].MatchedBy != "mention" {
+		t.Errorf("expected checkout changes correlated through the title, got %+v", correlated)
+	}
+
+	// AWS pipeline events carry no environment, so they correlate with incidents in any environment
+	pipeline := `{"source": "aws.codepipeline", "detail-type": "CodePipeline Pipeline Execution State Change", "region": "us-east-1", "time": "` +
+		time.Now().Add(-5*time.Minute).UTC().Format(time.RFC3339) + `", "detail": {"pipeline": "ledger", "execution-id": "e-1", "state": "SUCCEEDED"}}`
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, webhook("aws", pipeline, "s3cret"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected the pipeline run to be recorded, got %d: %s", w.Code, w.Body.String())
+	}
+	ledger, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Ledger lag", Description: "replication", Tags: []string{"service:ledger", "env:production"}})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+ledger.ID+"/changes", nil))
+	correlated = nil
+	json.NewDecoder(w.Body).Decode(&correlated)
+	if len(correlated) != 1 || correlated[0].Change.Source != "aws" || !correlated[0].Suspect {
+		t.Errorf("expected the pipeline run correlated with the production incident, got %+v", correlated)
+	}
+
+	// Without a secret the webhooks are off
+	unconfigured := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(unconfigured)
+	w = httptest.NewRecorder()
+	unconfigured.ServeHTTP(w, webhook("generic", `{"app": "search"}`, ""))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a webhook secret, got %d", w.Code)
+	}
+}
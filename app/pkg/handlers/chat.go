@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ChatIncident handles POST /api/v1/incidents/{id}/chat
+func (h *IncidentHandler) ChatIncident(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	var req models.ChatRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Message == "" {
+		respondError(w, http.StatusBadRequest, "message is required")
+		return
+	}
+
+	resp, err := h.incidentService.ChatIncident(id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrIncidentNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// Still return the conversation so far with error message
+		conversation, _ := h.incidentService.GetConversation(id)
+		response := map[string]interface{}{
+			"conversation": conversation,
+			"error":        err.Error(),
+		}
+		h.logger.Warn("chat encountered error but returning conversation", zap.String("id", id), zap.Error(err))
+		respondJSON(w, http.StatusOK, response)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// GetConversation handles GET /api/v1/incidents/{id}/chat
+func (h *IncidentHandler) GetConversation(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	conversation, err := h.incidentService.GetConversation(id)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, conversation)
+}
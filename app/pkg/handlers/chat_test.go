@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestChatIncidentHandler(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Test",
+		Description: "Test",
+	})
+
+	bodyBytes, _ := json.Marshal(models.ChatRequest{Message: "which pod restarted first?"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+created.ID+"/chat", bytes.NewReader(bodyBytes))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp models.ChatResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Reply.Content != "Mock reply" {
+		t.Errorf("expected reply 'Mock reply', got %q", resp.Reply.Content)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+created.ID+"/chat", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var conv models.Conversation
+	json.NewDecoder(w.Body).Decode(&conv)
+	if len(conv.Messages) != 2 {
+		t.Errorf("expected 2 persisted messages, got %d", len(conv.Messages))
+	}
+}
+
+func TestChatIncidentHandlerValidation(t *testing.T) {
+	handler := setupTestHandler()
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/incidents/missing/chat", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/incidents/missing/chat", bytes.NewReader([]byte(`{"message":"hi"}`)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
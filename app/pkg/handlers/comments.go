@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// CreateComment handles POST /api/v1/incidents/{id}/comments
+func (h *IncidentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	comment, err := h.incidentService.CreateComment(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondCommentError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, comment)
+}
+
+// ListComments handles GET /api/v1/incidents/{id}/comments
+func (h *IncidentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	comments, err := h.incidentService.ListComments(mux.Vars(r)["id"])
+	if err != nil {
+		respondCommentError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, comments)
+}
+
+// GetComment handles GET /api/v1/incidents/{id}/comments/{commentId}
+func (h *IncidentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	comment, err := h.incidentService.GetComment(vars["id"], vars["commentId"])
+	if err != nil {
+		respondCommentError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, comment)
+}
+
+// UpdateComment handles PUT /api/v1/incidents/{id}/comments/{commentId}
+func (h *IncidentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	vars := mux.Vars(r)
+	comment, err := h.incidentService.UpdateComment(vars["id"], vars["commentId"], &req)
+	if err != nil {
+		respondCommentError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE /api/v1/incidents/{id}/comments/{commentId}
+func (h *IncidentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.incidentService.DeleteComment(vars["id"], vars["commentId"]); err != nil {
+		respondCommentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondCommentError maps comment service errors to HTTP status codes
+func respondCommentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrCommentNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidComment):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestCommentHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	base := "/api/v1/incidents/" + created.ID + "/comments"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, base, strings.NewReader(`{"author": "alice", "body": "Looks like @bob's deploy", "key_finding": true}`)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	var comment models.Comment
+	json.NewDecoder(w.Body).Decode(&comment)
+
+	tests := []struct {
+		method   string
+		path     string
+		body     string
+		expected int
+	}{
+		{http.MethodPost, base, `{"author": "bob", "body": "Yes", "parent_id": "` + comment.ID + `"}`, http.StatusCreated},
+		{http.MethodPost, base, `{"author": "bob"}`, http.StatusBadRequest},
+		{http.MethodGet, base, "", http.StatusOK},
+		{http.MethodGet, base + "/" + comment.ID, "", http.StatusOK},
+		{http.MethodPut, base + "/" + comment.ID, `{"body": "Looks like the 10:00 deploy"}`, http.StatusOK},
+		{http.MethodPut, base + "/CMT-999", `{"key_finding": false}`, http.StatusNotFound},
+		{http.MethodGet, "/api/v1/incidents/INC-missing/comments", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.method, tt.path, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+created.ID, nil))
+	var incident models.Incident
+	json.NewDecoder(w.Body).Decode(&incident)
+	if len(incident.KeyFindings) != 1 || incident.KeyFindings[0].Body != "Looks like the 10:00 deploy" {
+		t.Errorf("expected edited key finding on the incident, got %+v", incident.KeyFindings)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, base+"/"+comment.ID, nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// EnrichIncident handles POST /api/v1/incidents/{id}/enrich
+func (h *IncidentHandler) EnrichIncident(w http.ResponseWriter, r *http.Request) {
+	incident, err := h.incidentService.RunEnrichment(mux.Vars(r)["id"])
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrIncidentNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrEnrichmentDisabled):
+			respondError(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	respondJSON(w, http.StatusOK, incident)
+}
@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
 	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/slack"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -16,14 +18,24 @@ import (
 type IncidentHandler struct {
 	incidentService *service.IncidentService
 	logger          *zap.Logger
+	slack           *slack.App
+	// changeWebhookSecret authenticates CI/CD change webhooks; empty disables them
+	changeWebhookSecret string
 }
 
+// HandlerOption configures optional integrations on an IncidentHandler
+type HandlerOption func(*IncidentHandler)
+
 // NewIncidentHandler creates a new incident handler
-func NewIncidentHandler(incidentService *service.IncidentService, logger *zap.Logger) *IncidentHandler {
-	return &IncidentHandler{
+func NewIncidentHandler(incidentService *service.IncidentService, logger *zap.Logger, opts ...HandlerOption) *IncidentHandler {
+	h := &IncidentHandler{
 		incidentService: incidentService,
 		logger:          logger,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // RegisterRoutes registers all incident routes
@@ -41,9 +53,153 @@ func (h *IncidentHandler) RegisterRoutes(router *mux.Router) {
 	// Analysis endpoints
 	v1.HandleFunc("/incidents/{id}/analyze", h.AnalyzeIncident).Methods(http.MethodPost)
 	v1.HandleFunc("/incidents/{id}/rca/generate", h.GenerateRCA).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/enrich", h.EnrichIncident).Methods(http.MethodPost)
+
+	// RCA editing and review endpoints
+	v1.HandleFunc("/incidents/{id}/rca", h.EditRCA).Methods(http.MethodPatch)
+	v1.HandleFunc("/incidents/{id}/rca/review", h.GetRCAReview).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/rca/reviewers", h.SetRCAReviewers).Methods(http.MethodPut)
+	v1.HandleFunc("/incidents/{id}/rca/status", h.TransitionRCA).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/rca/approvals", h.ApproveRCA).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/rca/comments", h.CommentOnRCA).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/rca/export", h.ExportRCA).Methods(http.MethodGet)
+
+	// Revision history endpoints
+	v1.HandleFunc("/incidents/{id}/analyses", h.ListAnalyses).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/analyses/diff", h.DiffAnalyses).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/analyses/{rev:[0-9]+}/pin", h.PinAnalysis).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/rca/revisions", h.ListRCARevisions).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/rca/revisions/diff", h.DiffRCARevisions).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/rca/revisions/{rev:[0-9]+}/pin", h.PinRCARevision).Methods(http.MethodPost)
+
+	// Severity classification endpoints
+	v1.HandleFunc("/incidents/{id}/severity/feedback", h.SeverityFeedback).Methods(http.MethodPost)
+	v1.HandleFunc("/severity/accuracy", h.SeverityAccuracy).Methods(http.MethodGet)
+
+	// Incident log endpoints
+	v1.HandleFunc("/incidents/{id}/logs", h.AppendLogs).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/logs", h.ListLogEntries).Methods(http.MethodGet)
+
+	// Comment endpoints
+	v1.HandleFunc("/incidents/{id}/comments", h.CreateComment).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/comments", h.ListComments).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/comments/{commentId}", h.GetComment).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/comments/{commentId}", h.UpdateComment).Methods(http.MethodPut)
+	v1.HandleFunc("/incidents/{id}/comments/{commentId}", h.DeleteComment).Methods(http.MethodDelete)
+
+	// Attachment endpoints
+	v1.HandleFunc("/incidents/{id}/attachments", h.UploadAttachment).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/attachments", h.ListAttachments).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/attachments/{attachmentId}", h.GetAttachment).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/attachments/{attachmentId}", h.DeleteAttachment).Methods(http.MethodDelete)
+	v1.HandleFunc("/incidents/{id}/attachments/{attachmentId}/download", h.DownloadAttachment).Methods(http.MethodGet)
+
+	// Action item endpoints
+	v1.HandleFunc("/incidents/{id}/action-items", h.CreateActionItem).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/action-items", h.ListIncidentActionItems).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/action-items/promote", h.PromoteActionItems).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/action-items/{itemId}", h.UpdateActionItem).Methods(http.MethodPut)
+	v1.HandleFunc("/incidents/{id}/action-items/{itemId}", h.DeleteActionItem).Methods(http.MethodDelete)
+	v1.HandleFunc("/action-items", h.ListActionItems).Methods(http.MethodGet)
+
+	// Change event endpoints
+	v1.HandleFunc("/changes", h.CreateChange).Methods(http.MethodPost)
+	v1.HandleFunc("/changes", h.ListChanges).Methods(http.MethodGet)
+	v1.HandleFunc("/changes/webhooks/{provider}", h.ChangeWebhook).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/changes", h.IncidentChanges).Methods(http.MethodGet)
+
+	// Service catalog endpoints
+	v1.HandleFunc("/services", h.CreateService).Methods(http.MethodPost)
+	v1.HandleFunc("/services", h.ListServices).Methods(http.MethodGet)
+	v1.HandleFunc("/services/{name}", h.GetService).Methods(http.MethodGet)
+	v1.HandleFunc("/services/{name}", h.UpdateService).Methods(http.MethodPut)
+	v1.HandleFunc("/services/{name}", h.DeleteService).Methods(http.MethodDelete)
+	v1.HandleFunc("/services/{name}/dependencies", h.ServiceDependencies).Methods(http.MethodGet)
+
+	// On-call endpoints
+	v1.HandleFunc("/oncall/now", h.OnCallNow).Methods(http.MethodGet)
+	v1.HandleFunc("/oncall/schedules", h.CreateSchedule).Methods(http.MethodPost)
+	v1.HandleFunc("/oncall/schedules", h.ListSchedules).Methods(http.MethodGet)
+	v1.HandleFunc("/oncall/schedules/{name}", h.GetSchedule).Methods(http.MethodGet)
+	v1.HandleFunc("/oncall/schedules/{name}", h.UpdateSchedule).Methods(http.MethodPut)
+	v1.HandleFunc("/oncall/schedules/{name}", h.DeleteSchedule).Methods(http.MethodDelete)
+	v1.HandleFunc("/oncall/schedules/{name}/overrides", h.AddOverride).Methods(http.MethodPost)
+	v1.HandleFunc("/oncall/schedules/{name}/overrides/{overrideId}", h.DeleteOverride).Methods(http.MethodDelete)
+	v1.HandleFunc("/oncall/escalation-policies", h.CreateEscalationPolicy).Methods(http.MethodPost)
+	v1.HandleFunc("/oncall/escalation-policies", h.ListEscalationPolicies).Methods(http.MethodGet)
+	v1.HandleFunc("/oncall/escalation-policies/{name}", h.GetEscalationPolicy).Methods(http.MethodGet)
+	v1.HandleFunc("/oncall/escalation-policies/{name}", h.UpdateEscalationPolicy).Methods(http.MethodPut)
+	v1.HandleFunc("/oncall/escalation-policies/{name}", h.DeleteEscalationPolicy).Methods(http.MethodDelete)
+	v1.HandleFunc("/incidents/{id}/acknowledge", h.AcknowledgeIncident).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/escalate", h.EscalateIncident).Methods(http.MethodPost)
+
+	// Notification endpoints
+	v1.HandleFunc("/incidents/{id}/notifications", h.GetNotificationHistory).Methods(http.MethodGet)
+	v1.HandleFunc("/notifications/dead-letters", h.ListDeadLetterNotifications).Methods(http.MethodGet)
+	v1.HandleFunc("/notifications/dead-letters/{id}/retry", h.RetryNotification).Methods(http.MethodPost)
+
+	// Webhook subscription endpoints
+	v1.HandleFunc("/webhooks/subscriptions", h.CreateSubscription).Methods(http.MethodPost)
+	v1.HandleFunc("/webhooks/subscriptions", h.ListSubscriptions).Methods(http.MethodGet)
+	v1.HandleFunc("/webhooks/subscriptions/{id}", h.GetSubscription).Methods(http.MethodGet)
+	v1.HandleFunc("/webhooks/subscriptions/{id}", h.UpdateSubscription).Methods(http.MethodPut)
+	v1.HandleFunc("/webhooks/subscriptions/{id}", h.DeleteSubscription).Methods(http.MethodDelete)
+	v1.HandleFunc("/webhooks/subscriptions/{id}/deliveries", h.ListDeliveries).Methods(http.MethodGet)
+	v1.HandleFunc("/webhooks/deliveries/{id}", h.GetDelivery).Methods(http.MethodGet)
+	v1.HandleFunc("/webhooks/deliveries/{id}/redeliver", h.Redeliver).Methods(http.MethodPost)
+
+	// Issue tracker endpoints
+	v1.HandleFunc("/incidents/{id}/issue", h.CreateIncidentIssue).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/issue/sync", h.SyncIncidentIssue).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/action-items/{itemId}/issue", h.CreateActionItemIssue).Methods(http.MethodPost)
+	v1.HandleFunc("/trackers/{name}/webhook", h.IssueWebhook).Methods(http.MethodPost)
+
+	// Status page endpoints
+	v1.HandleFunc("/components", h.CreateComponent).Methods(http.MethodPost)
+	v1.HandleFunc("/components", h.ListComponents).Methods(http.MethodGet)
+	v1.HandleFunc("/components/{name}", h.GetComponent).Methods(http.MethodGet)
+	v1.HandleFunc("/components/{name}", h.UpdateComponent).Methods(http.MethodPut)
+	v1.HandleFunc("/components/{name}", h.DeleteComponent).Methods(http.MethodDelete)
+	v1.HandleFunc("/incidents/{id}/status-updates", h.PublishStatusUpdate).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/status-updates", h.ListStatusUpdates).Methods(http.MethodGet)
+	v1.HandleFunc("/incidents/{id}/status-updates/draft", h.DraftStatusUpdate).Methods(http.MethodPost)
+
+	// Public status page endpoints; these show only status updates and components
+	v1.HandleFunc("/status", h.PublicStatus).Methods(http.MethodGet)
+	v1.HandleFunc("/status/feed.{format:rss|atom}", h.StatusFeed).Methods(http.MethodGet)
+
+	// Maintenance window and silence endpoints
+	v1.HandleFunc("/maintenance-windows", h.CreateMaintenanceWindow).Methods(http.MethodPost)
+	v1.HandleFunc("/maintenance-windows", h.ListMaintenanceWindows).Methods(http.MethodGet)
+	v1.HandleFunc("/maintenance-windows/{id}", h.GetMaintenanceWindow).Methods(http.MethodGet)
+	v1.HandleFunc("/maintenance-windows/{id}", h.UpdateMaintenanceWindow).Methods(http.MethodPut)
+	v1.HandleFunc("/maintenance-windows/{id}", h.DeleteMaintenanceWindow).Methods(http.MethodDelete)
+	v1.HandleFunc("/silences", h.CreateSilence).Methods(http.MethodPost)
+	v1.HandleFunc("/silences", h.ListSilences).Methods(http.MethodGet)
+	v1.HandleFunc("/silences/{id}", h.GetSilence).Methods(http.MethodGet)
+	v1.HandleFunc("/silences/{id}", h.ExpireSilence).Methods(http.MethodDelete)
+	v1.HandleFunc("/suppressions", h.ListSuppressions).Methods(http.MethodGet)
+
+	// SLA policy endpoints
+	v1.HandleFunc("/sla/policies", h.ListSLAPolicies).Methods(http.MethodGet)
+	v1.HandleFunc("/sla/policies/{severity}", h.GetSLAPolicy).Methods(http.MethodGet)
+	v1.HandleFunc("/sla/policies/{severity}", h.SetSLAPolicy).Methods(http.MethodPut)
+	v1.HandleFunc("/sla/policies/{severity}", h.DeleteSLAPolicy).Methods(http.MethodDelete)
+
+	// Slack app endpoints
+	v1.HandleFunc("/slack/commands", h.SlackCommand).Methods(http.MethodPost)
+	v1.HandleFunc("/slack/interactions", h.SlackInteraction).Methods(http.MethodPost)
+
+	// Chat endpoints
+	v1.HandleFunc("/incidents/{id}/chat", h.ChatIncident).Methods(http.MethodPost)
+	v1.HandleFunc("/incidents/{id}/chat", h.GetConversation).Methods(http.MethodGet)
 
 	// Log endpoints
 	v1.HandleFunc("/logs/summarize", h.SummarizeLogs).Methods(http.MethodPost)
+
+	// Severity rule endpoints
+	v1.HandleFunc("/rules", h.ListRules).Methods(http.MethodGet)
+	v1.HandleFunc("/rules/test", h.TestRules).Methods(http.MethodPost)
 }
 
 // CreateIncident handles POST /api/v1/incidents
@@ -61,6 +217,15 @@ func (h *IncidentHandler) CreateIncident(w http.ResponseWriter, r *http.Request)
 	}
 
 	incident, err := h.incidentService.CreateIncident(&req)
+	if errors.Is(err, service.ErrIncidentSuppressed) {
+		// Alert sources retry on errors, so a suppressed incident is accepted rather than rejected
+		respondJSON(w, http.StatusAccepted, map[string]string{"status": "suppressed", "reason": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidService) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to create incident", zap.Error(err))
 		respondError(w, http.StatusInternalServerError, "failed to create incident")
@@ -130,6 +295,8 @@ func (h *IncidentHandler) UpdateIncident(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		if err.Error() == fmt.Sprintf("incident not found: %s", id) {
 			respondError(w, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, service.ErrInvalidService) {
+			respondError(w, http.StatusBadRequest, err.Error())
 		} else {
 			h.logger.Error("failed to update incident", zap.String("id", id), zap.Error(err))
 			respondError(w, http.StatusInternalServerError, "failed to update incident")
@@ -154,10 +321,17 @@ func (h *IncidentHandler) DeleteIncident(w http.ResponseWriter, r *http.Request)
 }
 
 // AnalyzeIncident handles POST /api/v1/incidents/{id}/analyze
+// With ?tools=true the model gathers evidence itself through tool calls instead of receiving all logs
 func (h *IncidentHandler) AnalyzeIncident(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
-	incident, err := h.incidentService.AnalyzeIncident(id)
+	var incident *models.Incident
+	var err error
+	if r.URL.Query().Get("tools") == "true" {
+		incident, err = h.incidentService.AnalyzeIncidentWithTools(id)
+	} else {
+		incident, err = h.incidentService.AnalyzeIncident(id)
+	}
 	if err != nil {
 		response := map[string]interface{}{
 			"incident": incident,
@@ -177,6 +351,10 @@ func (h *IncidentHandler) GenerateRCA(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
 	incident, err := h.incidentService.GenerateRCA(id)
+	if errors.Is(err, service.ErrRCALocked) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
 		response := map[string]interface{}{
 			"incident": incident,
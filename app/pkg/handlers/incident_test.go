@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai/aifake"
 	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
 	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
 	"github.com/gorilla/mux"
@@ -47,6 +49,36 @@ func (m *MockAIClient) SummarizeLogs(ctx context.Context, req ai.SummarizeReques
 	}, nil
 }
 
+func (m *MockAIClient) Chat(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+	return &ai.ChatResponse{
+		Message: "Mock reply",
+	}, nil
+}
+
+func (m *MockAIClient) ClassifySeverity(ctx context.Context, req ai.SeverityRequest) (*ai.SeverityResponse, error) {
+	return &ai.SeverityResponse{
+		Severity:   "critical",
+		Confidence: 0.9,
+		Reasoning:  "Mock reasoning",
+	}, nil
+}
+
+func (m *MockAIClient) AnalyzeIncidentWithTools(ctx context.Context, req ai.ToolAnalysisRequest) (*ai.ToolAnalysisResponse, error) {
+	analysis, _ := m.AnalyzeIncident(ctx, ai.AnalysisRequest{})
+	return &ai.ToolAnalysisResponse{
+		AnalysisResponse: *analysis,
+		StopReason:       ai.StopReasonCompleted,
+	}, nil
+}
+
+func (m *MockAIClient) DraftStatusUpdate(ctx context.Context, req ai.StatusUpdateRequest) (*ai.StatusUpdateResponse, error) {
+	return &ai.StatusUpdateResponse{
+		Title:   "Mock title",
+		State:   "investigating",
+		Message: "Mock update",
+	}, nil
+}
+
 func (m *MockAIClient) Health(ctx context.Context) error {
 	return nil
 }
@@ -208,3 +240,62 @@ func TestSummarizeLogsHandler(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 }
+
+func TestGenerateRCAHandlerWithFakeProvider(t *testing.T) {
+	fake := aifake.NewServer()
+	defer fake.Close()
+	client, _ := ai.NewClient(fake.Config(ai.ProviderOpenAI))
+	svc := service.NewIncidentService(service.NewIncidentStore(), client, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Test",
+		Description: "Test",
+	})
+
+	fake.Enqueue(aifake.OpenAIText(`{"root_cause": "Expired TLS certificate", "preventive_measures": ["Alert 14 days before expiry"]}`))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+created.ID+"/rca/generate", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var incident models.Incident
+	json.NewDecoder(w.Body).Decode(&incident)
+	if incident.RCADocument == nil || incident.RCADocument.RootCause != "Expired TLS certificate" {
+		t.Fatalf("expected RCA parsed from provider response, got %+v", incident.RCADocument)
+	}
+
+	fake.Enqueue(aifake.Error(http.StatusServiceUnavailable, "overloaded"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+created.ID+"/rca/generate", nil))
+
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusOK || !strings.Contains(resp["error"].(string), "503") {
+		t.Errorf("expected graceful 200 with provider error, got %d %v", w.Code, resp["error"])
+	}
+}
+
+func TestRulesTestHandler(t *testing.T) {
+	router := mux.NewRouter()
+	setupTestHandler().RegisterRoutes(router)
+
+	body := `{"title": "Payments DEGRADED", "description": "p99 latency doubled"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rules/test", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var result struct {
+		Severity    string   `json:"severity"`
+		Rule        string   `json:"rule"`
+		Explanation []string `json:"explanation"`
+	}
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.Severity != "medium" || result.Rule != "medium-keywords" || len(result.Explanation) == 0 {
+		t.Errorf("unexpected rules result %+v", result)
+	}
+}
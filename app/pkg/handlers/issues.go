@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/tracker"
+	"github.com/gorilla/mux"
+)
+
+// CreateIncidentIssue handles POST /api/v1/incidents/{id}/issue
+func (h *IncidentHandler) CreateIncidentIssue(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeLinkIssueRequest(w, r)
+	if !ok {
+		return
+	}
+	incident, err := h.incidentService.CreateIncidentIssue(mux.Vars(r)["id"], req)
+	if err != nil {
+		respondIssueError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, incident)
+}
+
+// SyncIncidentIssue handles POST /api/v1/incidents/{id}/issue/sync
+func (h *IncidentHandler) SyncIncidentIssue(w http.ResponseWriter, r *http.Request) {
+	incident, err := h.incidentService.SyncIncidentIssue(mux.Vars(r)["id"])
+	if err != nil {
+		respondIssueError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, incident)
+}
+
+// CreateActionItemIssue handles POST /api/v1/incidents/{id}/action-items/{itemId}/issue
+func (h *IncidentHandler) CreateActionItemIssue(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeLinkIssueRequest(w, r)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(r)
+	item, err := h.incidentService.CreateActionItemIssue(vars["id"], vars["itemId"], req)
+	if err != nil {
+		respondIssueError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, item)
+}
+
+// IssueWebhook handles POST /api/v1/trackers/{name}/webhook
+// Events that do not change a linked issue are acknowledged and dropped, so trackers do not retry them
+func (h *IncidentHandler) IssueWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "webhook payload too large")
+		return
+	}
+
+	err = h.incidentService.HandleIssueWebhook(mux.Vars(r)["name"], r.Header, body)
+	switch {
+	case err == nil:
+		respondJSON(w, http.StatusOK, map[string]string{"status": "applied"})
+	case errors.Is(err, tracker.ErrIgnored), errors.Is(err, service.ErrIssueNotLinked):
+		respondJSON(w, http.StatusAccepted, map[string]string{"status": "ignored", "reason": err.Error()})
+	case errors.Is(err, tracker.ErrInvalidSignature):
+		respondError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, tracker.ErrInvalidPayload):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondIssueError(w, err)
+	}
+}
+
+// decodeLinkIssueRequest reads an optional body naming the tracker
+func decodeLinkIssueRequest(w http.ResponseWriter, r *http.Request) (*models.LinkIssueRequest, bool) {
+	var req models.LinkIssueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return nil, false
+	}
+	return &req, true
+}
+
+// respondIssueError maps issue tracker errors to HTTP status codes
+func respondIssueError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrActionItemNotFound),
+		errors.Is(err, service.ErrTrackerNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrIssueAlreadyLinked), errors.Is(err, service.ErrIssueNotLinked):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrIssueTracker):
+		respondError(w, http.StatusBadGateway, err.Error())
+	case errors.Is(err, service.ErrIssueTrackingDisabled):
+		respondError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
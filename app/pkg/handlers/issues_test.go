@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/tracker"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestIssueHandlers(t *testing.T) {
+	// A GitHub API holding issue 1 of acme/incidents, with no comments
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/comments") {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`{"number": 1, "html_url": "https://github.com/acme/incidents/issues/1", "state": "open"}`))
+	}))
+	defer api.Close()
+	github, _ := tracker.New(tracker.TrackerConfig{Name: "github", Type: tracker.TypeGitHub, BaseURL: api.URL,
+		Repo: "acme/incidents", Token: "gh-token", WebhookSecret: "hook-secret"})
+
+	disabled := mux.NewRouter()
+	NewIncidentHandler(service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop()), zap.NewNop()).RegisterRoutes(disabled)
+	w := httptest.NewRecorder()
+	disabled.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/incidents/INC-1/issue", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without trackers, got %d", w.Code)
+	}
+
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop(), service.WithIssueTracker(github))
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+	incident, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+	other, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "db-1"})
+	item, _ := svc.CreateActionItem(other.ID, &models.CreateActionItemRequest{Title: "Add disk alerts"})
+	base := "/api/v1/incidents/" + incident.ID
+
+	closed := `{"action": "closed", "issue": {"number": 1, "state": "closed"}, "repository": {"full_name": "acme/incidents"}}`
+	webhook := func(event, body, signature string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/trackers/github/webhook", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", signature)
+		return req
+	}
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodPost, base+"/issue/sync", nil), http.StatusConflict},
+		{httptest.NewRequest(http.MethodPost, base+"/issue", strings.NewReader(`{"tracker": "jira"}`)), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPost, base+"/issue", strings.NewReader(`{"tracker": `)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/INC-0/issue", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPost, base+"/issue", nil), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, base+"/issue", strings.NewReader(`{"tracker": "github"}`)), http.StatusConflict},
+		{httptest.NewRequest(http.MethodPost, base+"/issue/sync", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+other.ID+"/action-items/"+item.ID+"/issue", nil), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+other.ID+"/action-items/ACT-0/issue", nil), http.StatusNotFound},
+		{webhook("issues", closed, "sha256=00"), http.StatusUnauthorized},
+		{webhook("ping", `{}`, tracker.Sign("hook-secret", []byte(`{}`))), http.StatusAccepted},
+		{webhook("issues", `{`, tracker.Sign("hook-secret", []byte(`{`))), http.StatusBadRequest},
+		{webhook("issues", closed, tracker.Sign("hook-secret", []byte(closed))), http.StatusOK},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/trackers/jira/webhook", strings.NewReader(`{}`)), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+	svc.WaitForIssueSyncs()
+}
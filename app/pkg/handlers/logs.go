@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// maxLogBodyBytes bounds a single log append request
+const maxLogBodyBytes = 10 << 20
+
+// AppendLogs handles POST /api/v1/incidents/{id}/logs
+// JSON bodies use AppendLogsRequest; any other content type is read as raw log lines,
+// with ?format= and ?source= describing them
+func (h *IncidentHandler) AppendLogs(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxLogBodyBytes)
+	query := r.URL.Query()
+
+	var req models.AppendLogsRequest
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondLogsBodyError(w, err)
+			return
+		}
+	} else {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			respondLogsBodyError(w, err)
+			return
+		}
+		req.Lines = strings.Split(string(body), "\n")
+	}
+	if req.Format == "" {
+		req.Format = query.Get("format")
+	}
+	if req.Source == "" {
+		req.Source = query.Get("source")
+	}
+
+	resp, err := h.incidentService.AppendLogs(mux.Vars(r)["id"], &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrIncidentNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidLogs):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	respondJSON(w, http.StatusCreated, resp)
+}
+
+// ListLogEntries handles GET /api/v1/incidents/{id}/logs
+// Supports ?level= (minimum level) and ?source= filters
+func (h *IncidentHandler) ListLogEntries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.LogEntryFilter{
+		MinLevel: models.LogLevel(query.Get("level")),
+		Source:   query.Get("source"),
+	}
+	if filter.MinLevel != "" && filter.MinLevel.Rank() == 0 {
+		respondError(w, http.StatusBadRequest, "level must be debug, info, warn, error or fatal")
+		return
+	}
+
+	entries, err := h.incidentService.ListLogEntries(mux.Vars(r)["id"], filter)
+	if err != nil {
+		if errors.Is(err, service.ErrIncidentNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, entries)
+}
+
+func respondLogsBodyError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	respondError(w, http.StatusBadRequest, "invalid request body")
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestAppendLogsHandler(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	base := "/api/v1/incidents/" + created.ID + "/logs"
+
+	raw := httptest.NewRequest(http.MethodPost, base+"?source=edge", strings.NewReader(
+		"10.0.0.7 - - [01/Jan/2024:10:00:03 +0000] \"GET /health HTTP/1.1\" 200 2\n"+
+			"10.0.0.8 - - [01/Jan/2024:10:00:04 +0000] \"POST /pay HTTP/1.1\" 503 0\n"))
+	raw.Header.Set("Content-Type", "text/plain")
+
+	cloudwatch := httptest.NewRequest(http.MethodPost, base, strings.NewReader(
+		`{"events": [{"timestamp": 1704103205000, "message": "ERROR ledger timeout", "logStreamName": "ledger/1"}], "searchedLogStreams": []}`))
+	cloudwatch.Header.Set("Content-Type", "application/json")
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{raw, http.StatusCreated},
+		{cloudwatch, http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, base+"?format=syslog", strings.NewReader("x")), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/INC-missing/logs", strings.NewReader("x")), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodGet, base+"?level=loud", nil), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base+"?level=error", nil))
+	var entries []models.LogEntry
+	json.NewDecoder(w.Body).Decode(&entries)
+	if len(entries) != 2 || entries[0].Source != "nginx" || entries[1].Source != "ledger/1" {
+		t.Errorf("expected the 503 and the CloudWatch error, got %+v", entries)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// CreateMaintenanceWindow handles POST /api/v1/maintenance-windows
+func (h *IncidentHandler) CreateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	var req models.MaintenanceWindow
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	window, err := h.incidentService.CreateMaintenanceWindow(&req)
+	if err != nil {
+		respondMaintenanceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, window)
+}
+
+// ListMaintenanceWindows handles GET /api/v1/maintenance-windows
+// Supports ?active=true to list only the windows in effect now
+func (h *IncidentHandler) ListMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	active := false
+	if value := r.URL.Query().Get("active"); value != "" {
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "active must be true or false")
+			return
+		}
+		active = v
+	}
+	respondJSON(w, http.StatusOK, h.incidentService.ListMaintenanceWindows(active))
+}
+
+// GetMaintenanceWindow handles GET /api/v1/maintenance-windows/{id}
+func (h *IncidentHandler) GetMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	window, err := h.incidentService.GetMaintenanceWindow(mux.Vars(r)["id"])
+	if err != nil {
+		respondMaintenanceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, window)
+}
+
+// UpdateMaintenanceWindow handles PUT /api/v1/maintenance-windows/{id}
+func (h *IncidentHandler) UpdateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	var req models.MaintenanceWindow
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	window, err := h.incidentService.UpdateMaintenanceWindow(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondMaintenanceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, window)
+}
+
+// DeleteMaintenanceWindow handles DELETE /api/v1/maintenance-windows/{id}
+func (h *IncidentHandler) DeleteMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	if err := h.incidentService.DeleteMaintenanceWindow(mux.Vars(r)["id"]); err != nil {
+		respondMaintenanceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateSilence handles POST /api/v1/silences
+func (h *IncidentHandler) CreateSilence(w http.ResponseWriter, r *http.Request) {
+	var req models.Silence
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	silence, err := h.incidentService.CreateSilence(&req)
+	if err != nil {
+		respondMaintenanceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, silence)
+}
+
+// ListSilences handles GET /api/v1/silences
+// Supports ?state=pending|active|expired
+func (h *IncidentHandler) ListSilences(w http.ResponseWriter, r *http.Request) {
+	var state *models.SilenceState
+	if value := r.URL.Query().Get("state"); value != "" {
+		s := models.SilenceState(value)
+		state = &s
+	}
+	respondJSON(w, http.StatusOK, h.incidentService.ListSilences(state))
+}
+
+// GetSilence handles GET /api/v1/silences/{id}
+func (h *IncidentHandler) GetSilence(w http.ResponseWriter, r *http.Request) {
+	silence, err := h.incidentService.GetSilence(mux.Vars(r)["id"])
+	if err != nil {
+		respondMaintenanceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, silence)
+}
+
+// ExpireSilence handles DELETE /api/v1/silences/{id}, which expires the silence but keeps it on record
+func (h *IncidentHandler) ExpireSilence(w http.ResponseWriter, r *http.Request) {
+	silence, err := h.incidentService.ExpireSilence(mux.Vars(r)["id"])
+	if err != nil {
+		respondMaintenanceError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, silence)
+}
+
+// ListSuppressions handles GET /api/v1/suppressions
+// Supports ?matched_by= to show what one window or silence caught, and ?limit=
+func (h *IncidentHandler) ListSuppressions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.SuppressionFilter{MatchedBy: query.Get("matched_by")}
+	if limit := query.Get("limit"); limit != "" {
+		v, err := strconv.Atoi(limit)
+		if err != nil || v < 1 {
+			respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		filter.Limit = v
+	}
+	respondJSON(w, http.StatusOK, h.incidentService.ListSuppressions(filter))
+}
+
+// respondMaintenanceError maps maintenance window and silence errors to HTTP status codes
+func respondMaintenanceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrMaintenanceWindowNotFound), errors.Is(err, service.ErrSilenceNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrSilenceExpired):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidMaintenanceWindow), errors.Is(err, service.ErrInvalidSilence):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
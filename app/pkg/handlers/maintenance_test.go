@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestMaintenanceHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	start := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	end := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	window, _ := svc.CreateMaintenanceWindow(&models.MaintenanceWindow{
+		Name: "db upgrade", Sources: []string{"alertmanager"}, StartsAt: time.Now().Add(-time.Minute), EndsAt: time.Now().Add(time.Hour),
+	})
+	silence, _ := svc.CreateSilence(&models.Silence{
+		Matchers: []models.Matcher{{Name: "source", Value: "cloudwatch"}}, Comment: "noisy", CreatedBy: "priya", EndsAt: time.Now().Add(time.Hour),
+	})
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodPost, "/api/v1/maintenance-windows", strings.NewReader(`{"name": "network", "tags": ["network"], "starts_at": "`+start+`", "ends_at": "`+end+`"}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/maintenance-windows", strings.NewReader(`{"name": "network", "starts_at": "`+start+`", "ends_at": "`+end+`"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/maintenance-windows?active=true", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/maintenance-windows?active=maybe", nil), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPut, "/api/v1/maintenance-windows/"+window.ID, strings.NewReader(`{"name": "db upgrade", "sources": ["alertmanager"], "action": "downgrade", "starts_at": "`+start+`", "ends_at": "`+end+`"}`)), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/maintenance-windows/MW-0", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents", strings.NewReader(`{"title": "CPU high", "description": "95%", "source": "cloudwatch"}`)), http.StatusAccepted},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents", strings.NewReader(`{"title": "Replication lag", "description": "30s", "source": "alertmanager"}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/silences", strings.NewReader(`{"matchers": [{"name": "tag", "value": "batch"}], "comment": "batch", "ends_at": "`+end+`"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/silences", strings.NewReader(`{"matchers": [{"name": "tag", "value": "batch"}], "comment": "batch", "created_by": "sam", "ends_at": "`+end+`"}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/silences?state=active", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/silences/"+silence.ID, nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodDelete, "/api/v1/silences/"+silence.ID, nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodDelete, "/api/v1/silences/"+silence.ID, nil), http.StatusConflict},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/suppressions?matched_by="+silence.ID, nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/suppressions?limit=0", nil), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodDelete, "/api/v1/maintenance-windows/"+window.ID, nil), http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	entries := svc.ListSuppressions(models.SuppressionFilter{})
+	if len(entries) != 2 || entries[0].Action != models.SuppressionDowngrade || entries[1].MatchedBy != silence.ID {
+		t.Errorf("expected a downgrade after a suppression, got %+v", entries)
+	}
+}
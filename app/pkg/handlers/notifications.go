@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// GetNotificationHistory handles GET /api/v1/incidents/{id}/notifications
+func (h *IncidentHandler) GetNotificationHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := h.incidentService.NotificationHistory(mux.Vars(r)["id"])
+	if err != nil {
+		respondNotificationError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, history)
+}
+
+// ListDeadLetterNotifications handles GET /api/v1/notifications/dead-letters
+func (h *IncidentHandler) ListDeadLetterNotifications(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.incidentService.DeadLetterNotifications()
+	if err != nil {
+		respondNotificationError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, deliveries)
+}
+
+// RetryNotification handles POST /api/v1/notifications/dead-letters/{id}/retry
+func (h *IncidentHandler) RetryNotification(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.incidentService.RedeliverNotification(mux.Vars(r)["id"])
+	if err != nil {
+		respondNotificationError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusAccepted, delivery)
+}
+
+// respondNotificationError maps notification errors to HTTP status codes
+func respondNotificationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrNotificationNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrNotificationsDisabled):
+		respondError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/notify"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestNotificationHandlers(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	dispatcher, err := notify.NewDispatcher(notify.Config{
+		Channels:    []notify.ChannelConfig{{Name: "hook", Type: notify.ChannelWebhook, URL: receiver.URL}},
+		MaxAttempts: 1,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer dispatcher.Close()
+
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop(), service.WithNotifier(dispatcher))
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	incident, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "db-1"})
+	deadline := time.Now().Add(5 * time.Second)
+	for len(dispatcher.DeadLetters()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+incident.ID+"/notifications", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/incidents/INC-missing/notifications", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/notifications/dead-letters", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/notifications/dead-letters/NTF-1/retry", nil), http.StatusAccepted},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/notifications/dead-letters/NTF-9/retry", nil), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+incident.ID+"/notifications", nil))
+	var history []models.NotificationDelivery
+	json.NewDecoder(w.Body).Decode(&history)
+	if len(history) != 1 || history[0].Channel != "hook" || history[0].EventType != models.EventIncidentCreated {
+		t.Errorf("expected one incident.created delivery to hook, got %+v", history)
+	}
+
+	disabled := mux.NewRouter()
+	NewIncidentHandler(service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop()), zap.NewNop()).RegisterRoutes(disabled)
+	w = httptest.NewRecorder()
+	disabled.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/notifications/dead-letters", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a notifier, got %d", w.Code)
+	}
+}
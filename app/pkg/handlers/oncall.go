@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// CreateSchedule handles POST /api/v1/oncall/schedules
+func (h *IncidentHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req models.Schedule
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	schedule, err := h.incidentService.CreateSchedule(&req)
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, schedule)
+}
+
+// ListSchedules handles GET /api/v1/oncall/schedules
+func (h *IncidentHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.incidentService.ListSchedules())
+}
+
+// GetSchedule handles GET /api/v1/oncall/schedules/{name}
+func (h *IncidentHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, err := h.incidentService.GetSchedule(mux.Vars(r)["name"])
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, schedule)
+}
+
+// UpdateSchedule handles PUT /api/v1/oncall/schedules/{name}
+func (h *IncidentHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req models.Schedule
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	schedule, err := h.incidentService.UpdateSchedule(mux.Vars(r)["name"], &req)
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, schedule)
+}
+
+// DeleteSchedule handles DELETE /api/v1/oncall/schedules/{name}
+func (h *IncidentHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	if err := h.incidentService.DeleteSchedule(mux.Vars(r)["name"]); err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddOverride handles POST /api/v1/oncall/schedules/{name}/overrides
+func (h *IncidentHandler) AddOverride(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	override, err := h.incidentService.AddOverride(mux.Vars(r)["name"], &req)
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, override)
+}
+
+// DeleteOverride handles DELETE /api/v1/oncall/schedules/{name}/overrides/{overrideId}
+func (h *IncidentHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.incidentService.DeleteOverride(vars["name"], vars["overrideId"]); err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// OnCallNow handles GET /api/v1/oncall/now?schedule=&at=
+// at previews the rota at another RFC 3339 time
+func (h *IncidentHandler) OnCallNow(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	at, err := timeParam(query, "at")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var when time.Time
+	if at != nil {
+		when = *at
+	}
+	onCall, err := h.incidentService.OnCallNow(query.Get("schedule"), when)
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, onCall)
+}
+
+// CreateEscalationPolicy handles POST /api/v1/oncall/escalation-policies
+func (h *IncidentHandler) CreateEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	var req models.EscalationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	policy, err := h.incidentService.CreateEscalationPolicy(&req)
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, policy)
+}
+
+// ListEscalationPolicies handles GET /api/v1/oncall/escalation-policies
+func (h *IncidentHandler) ListEscalationPolicies(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.incidentService.ListEscalationPolicies())
+}
+
+// GetEscalationPolicy handles GET /api/v1/oncall/escalation-policies/{name}
+func (h *IncidentHandler) GetEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.incidentService.GetEscalationPolicy(mux.Vars(r)["name"])
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, policy)
+}
+
+// UpdateEscalationPolicy handles PUT /api/v1/oncall/escalation-policies/{name}
+func (h *IncidentHandler) UpdateEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	var req models.EscalationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	policy, err := h.incidentService.UpdateEscalationPolicy(mux.Vars(r)["name"], &req)
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, policy)
+}
+
+// DeleteEscalationPolicy handles DELETE /api/v1/oncall/escalation-policies/{name}
+func (h *IncidentHandler) DeleteEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	if err := h.incidentService.DeleteEscalationPolicy(mux.Vars(r)["name"]); err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AcknowledgeIncident handles POST /api/v1/incidents/{id}/acknowledge
+func (h *IncidentHandler) AcknowledgeIncident(w http.ResponseWriter, r *http.Request) {
+	var req models.AcknowledgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	incident, err := h.incidentService.AcknowledgeIncident(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, incident)
+}
+
+// EscalateIncident handles POST /api/v1/incidents/{id}/escalate
+func (h *IncidentHandler) EscalateIncident(w http.ResponseWriter, r *http.Request) {
+	incident, err := h.incidentService.EscalateIncident(mux.Vars(r)["id"])
+	if err != nil {
+		respondOnCallError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, incident)
+}
+
+// respondOnCallError maps schedule, escalation and acknowledgement errors to HTTP status codes
+func respondOnCallError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrScheduleNotFound),
+		errors.Is(err, service.ErrOverrideNotFound), errors.Is(err, service.ErrEscalationPolicyNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrScheduleExists), errors.Is(err, service.ErrEscalationPolicyExists),
+		errors.Is(err, service.ErrNotEscalatable):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidSchedule), errors.Is(err, service.ErrInvalidEscalationPolicy),
+		errors.Is(err, service.ErrInvalidAcknowledgement):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
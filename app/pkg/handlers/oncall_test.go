@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestOnCallHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	schedule := `{"name": "primary", "time_zone": "Europe/London", "layers": [{"users": ["alice", "bob"], "rotation": "weekly", "start_date": "2024-01-01", "handoff_time": "09:00"}]}`
+	policy := `{"name": "default", "levels": [{"targets": [{"type": "schedule", "name": "primary"}], "delay_minutes": 5}]}`
+	incident, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "db-1"})
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodPost, "/api/v1/oncall/schedules", strings.NewReader(schedule)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/oncall/schedules", strings.NewReader(schedule)), http.StatusConflict},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/oncall/schedules", strings.NewReader(`{"name": "empty"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/oncall/schedules/primary/overrides", strings.NewReader(
+			`{"user": "carol", "start": "2024-01-02T00:00:00Z", "end": "2024-01-01T00:00:00Z"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodDelete, "/api/v1/oncall/schedules/primary/overrides/OVR-0", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/oncall/escalation-policies", strings.NewReader(policy)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/oncall/escalation-policies/missing", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/oncall/now?schedule=missing", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/oncall/now?at=tomorrow", nil), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+incident.ID+"/acknowledge", strings.NewReader(`{}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+incident.ID+"/acknowledge", strings.NewReader(`{"user": "alice"}`)), http.StatusOK},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/INC-missing/acknowledge", strings.NewReader(`{"user": "alice"}`)), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+incident.ID+"/escalate", nil), http.StatusConflict},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	// 8 January 09:00 in London is bob's first handoff
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/oncall/now?at=2024-01-08T09:30:00Z", nil))
+	var onCall []models.OnCall
+	json.NewDecoder(w.Body).Decode(&onCall)
+	if len(onCall) != 1 || onCall[0].User != "bob" || onCall[0].Schedule != "primary" {
+		t.Errorf("expected bob on call for primary, got %+v", onCall)
+	}
+}
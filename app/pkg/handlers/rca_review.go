@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/export"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// EditRCA handles PATCH /api/v1/incidents/{id}/rca
+func (h *IncidentHandler) EditRCA(w http.ResponseWriter, r *http.Request) {
+	var req models.RCAPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	incident, err := h.incidentService.EditRCA(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondRCAError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, incident)
+}
+
+// GetRCAReview handles GET /api/v1/incidents/{id}/rca/review
+func (h *IncidentHandler) GetRCAReview(w http.ResponseWriter, r *http.Request) {
+	review, err := h.incidentService.GetRCAReview(mux.Vars(r)["id"])
+	if err != nil {
+		respondRCAError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, review)
+}
+
+// SetRCAReviewers handles PUT /api/v1/incidents/{id}/rca/reviewers
+func (h *IncidentHandler) SetRCAReviewers(w http.ResponseWriter, r *http.Request) {
+	var req models.RCAReviewersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	review, err := h.incidentService.SetRCAReviewers(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondRCAError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, review)
+}
+
+// TransitionRCA handles POST /api/v1/incidents/{id}/rca/status
+func (h *IncidentHandler) TransitionRCA(w http.ResponseWriter, r *http.Request) {
+	var req models.RCAStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	review, err := h.incidentService.TransitionRCA(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondRCAError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, review)
+}
+
+// ApproveRCA handles POST /api/v1/incidents/{id}/rca/approvals
+func (h *IncidentHandler) ApproveRCA(w http.ResponseWriter, r *http.Request) {
+	var req models.RCAApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	review, err := h.incidentService.ApproveRCA(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondRCAError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, review)
+}
+
+// CommentOnRCA handles POST /api/v1/incidents/{id}/rca/comments
+func (h *IncidentHandler) CommentOnRCA(w http.ResponseWriter, r *http.Request) {
+	var req models.RCACommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	comment, err := h.incidentService.CommentOnRCA(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondRCAError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, comment)
+}
+
+// ExportRCA handles GET /api/v1/incidents/{id}/rca/export?format=markdown|html|pdf
+func (h *IncidentHandler) ExportRCA(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := h.incidentService.ExportRCA(id, format)
+	if err != nil {
+		respondRCAError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+"-rca."+export.Extension(format)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func respondRCAError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrRCANotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrRCALocked), errors.Is(err, service.ErrInvalidTransition):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidRCARequest):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
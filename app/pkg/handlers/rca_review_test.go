@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestRCAReviewHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	base := "/api/v1/incidents/" + created.ID + "/rca"
+
+	tests := []struct {
+		method   string
+		path     string
+		body     string
+		expected int
+	}{
+		{http.MethodGet, base + "/review", "", http.StatusNotFound},
+		{http.MethodPatch, base, `{"author": "ic", "root_cause": "Expired certificate"}`, http.StatusOK},
+		{http.MethodPatch, base, `{"root_cause": "x"}`, http.StatusBadRequest},
+		{http.MethodPost, base + "/status", `{"status": "published"}`, http.StatusConflict},
+		{http.MethodPut, base + "/reviewers", `{"reviewers": ["alice"]}`, http.StatusOK},
+		{http.MethodPost, base + "/status", `{"status": "in_review", "actor": "ic"}`, http.StatusOK},
+		{http.MethodPost, base + "/comments", `{"section": "root_cause", "author": "alice", "body": "Which certificate?"}`, http.StatusCreated},
+		{http.MethodPost, base + "/approvals", `{"reviewer": "alice"}`, http.StatusOK},
+		{http.MethodPatch, base, `{"author": "ic", "impact": "x"}`, http.StatusConflict},
+		{http.MethodGet, base + "/review", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d (%s)", tt.method, tt.path, tt.expected, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestExportRCAHandler(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "Test"})
+	path := "/api/v1/incidents/" + created.ID + "/rca/export"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d before an RCA exists, got %d", http.StatusNotFound, w.Code)
+	}
+
+	svc.GenerateRCA(created.ID)
+
+	tests := []struct {
+		format      string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"", http.StatusOK, "text/markdown; charset=utf-8", "# Postmortem: Checkout errors"},
+		{"html", http.StatusOK, "text/html; charset=utf-8", "<h2>Root Cause</h2>"},
+		{"pdf", http.StatusOK, "application/pdf", "%PDF-1.4"},
+		{"docx", http.StatusBadRequest, "application/json", "unsupported export format"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?format="+tt.format, nil))
+
+		if w.Code != tt.status || w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("format %q: expected %d %s, got %d %s", tt.format, tt.status, tt.contentType, w.Code, w.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(w.Body.String(), tt.contains) {
+			t.Errorf("format %q: expected body to contain %q", tt.format, tt.contains)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// ListAnalyses handles GET /api/v1/incidents/{id}/analyses
+func (h *IncidentHandler) ListAnalyses(w http.ResponseWriter, r *http.Request) {
+	history, err := h.incidentService.ListAnalyses(mux.Vars(r)["id"])
+	if err != nil {
+		respondRevisionError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, history)
+}
+
+// PinAnalysis handles POST /api/v1/incidents/{id}/analyses/{rev}/pin
+func (h *IncidentHandler) PinAnalysis(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rev, _ := strconv.Atoi(vars["rev"])
+
+	incident, err := h.incidentService.PinAnalysis(vars["id"], rev)
+	if err != nil {
+		respondRevisionError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, incident)
+}
+
+// DiffAnalyses handles GET /api/v1/incidents/{id}/analyses/diff?from=1&to=2
+func (h *IncidentHandler) DiffAnalyses(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := revisionRange(r)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "from and to revision numbers are required")
+		return
+	}
+
+	diff, err := h.incidentService.DiffAnalyses(mux.Vars(r)["id"], from, to)
+	if err != nil {
+		respondRevisionError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, diff)
+}
+
+// ListRCARevisions handles GET /api/v1/incidents/{id}/rca/revisions
+func (h *IncidentHandler) ListRCARevisions(w http.ResponseWriter, r *http.Request) {
+	history, err := h.incidentService.ListRCARevisions(mux.Vars(r)["id"])
+	if err != nil {
+		respondRevisionError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, history)
+}
+
+// PinRCARevision handles POST /api/v1/incidents/{id}/rca/revisions/{rev}/pin
+func (h *IncidentHandler) PinRCARevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rev, _ := strconv.Atoi(vars["rev"])
+
+	incident, err := h.incidentService.PinRCARevision(vars["id"], rev)
+	if err != nil {
+		respondRevisionError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, incident)
+}
+
+// DiffRCARevisions handles GET /api/v1/incidents/{id}/rca/revisions/diff?from=1&to=2
+func (h *IncidentHandler) DiffRCARevisions(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := revisionRange(r)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "from and to revision numbers are required")
+		return
+	}
+
+	diff, err := h.incidentService.DiffRCARevisions(mux.Vars(r)["id"], from, to)
+	if err != nil {
+		respondRevisionError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, diff)
+}
+
+// revisionRange parses the from and to query parameters
+func revisionRange(r *http.Request) (int, int, bool) {
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		return 0, 0, false
+	}
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
+func respondRevisionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound):
+		respondError(w, http.StatusNotFound, "incident not found")
+	case errors.Is(err, service.ErrRevisionNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrRCALocked):
+		respondError(w, http.StatusConflict, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
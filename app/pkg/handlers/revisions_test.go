@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestAnalysisRevisionHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Test",
+		Description: "Test",
+	})
+	svc.AnalyzeIncident(created.ID)
+	svc.AnalyzeIncident(created.ID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+created.ID+"/analyses", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var history models.AnalysisHistory
+	json.NewDecoder(w.Body).Decode(&history)
+	if w.Code != http.StatusOK || len(history.Revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got status %d and %d revisions", w.Code, len(history.Revisions))
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+created.ID+"/analyses/1/pin", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var incident models.Incident
+	json.NewDecoder(w.Body).Decode(&incident)
+	if w.Code != http.StatusOK || incident.AIAnalysis.Revision != 1 {
+		t.Errorf("expected revision 1 pinned, got status %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+created.ID+"/analyses/diff?from=1&to=5", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for missing revision, got %d", http.StatusNotFound, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+created.ID+"/rca/revisions/diff?from=one", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for bad range, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// ListRules handles GET /api/v1/rules
+func (h *IncidentHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.incidentService.SeverityRules())
+}
+
+// TestRules handles POST /api/v1/rules/test
+func (h *IncidentHandler) TestRules(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateIncidentRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.incidentService.TestRules(&req))
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// SeverityFeedback handles POST /api/v1/incidents/{id}/severity/feedback
+func (h *IncidentHandler) SeverityFeedback(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var req models.SeverityFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	incident, err := h.incidentService.SubmitSeverityFeedback(id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrIncidentNotFound):
+			respondError(w, http.StatusNotFound, "incident not found")
+		case errors.Is(err, service.ErrNoSeveritySuggestion):
+			respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrInvalidSeverity):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to record severity feedback")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, incident)
+}
+
+// SeverityAccuracy handles GET /api/v1/severity/accuracy
+func (h *IncidentHandler) SeverityAccuracy(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.incidentService.SeverityAccuracy())
+}
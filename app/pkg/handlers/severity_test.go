@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestSeverityFeedbackHandler(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop(), service.WithAISeverity(time.Second))
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	created, _ := svc.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Test",
+		Description: "Test",
+	})
+	svc.WaitForClassifications()
+
+	bodyBytes, _ := json.Marshal(models.SeverityFeedbackRequest{Accepted: true, Reviewer: "alice"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+created.ID+"/severity/feedback", bytes.NewReader(bodyBytes))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var incident models.Incident
+	json.NewDecoder(w.Body).Decode(&incident)
+	if incident.Severity != models.SeverityCritical || incident.SeverityClassification.Feedback == nil {
+		t.Errorf("expected accepted AI severity with feedback, got %+v", incident)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/severity/accuracy", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var report models.SeverityAccuracyReport
+	json.NewDecoder(w.Body).Decode(&report)
+	if report.Accepted != 1 || report.AcceptanceRate != 1 {
+		t.Errorf("unexpected accuracy report %+v", report)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/incidents/missing/severity/feedback", bytes.NewReader(bodyBytes))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// ListSLAPolicies handles GET /api/v1/sla/policies
+func (h *IncidentHandler) ListSLAPolicies(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.incidentService.ListSLAPolicies())
+}
+
+// GetSLAPolicy handles GET /api/v1/sla/policies/{severity}
+func (h *IncidentHandler) GetSLAPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.incidentService.GetSLAPolicy(models.Severity(mux.Vars(r)["severity"]))
+	if err != nil {
+		respondSLAError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, policy)
+}
+
+// SetSLAPolicy handles PUT /api/v1/sla/policies/{severity}, creating or replacing the policy
+func (h *IncidentHandler) SetSLAPolicy(w http.ResponseWriter, r *http.Request) {
+	var req models.SLAPolicy
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	policy, err := h.incidentService.SetSLAPolicy(models.Severity(mux.Vars(r)["severity"]), &req)
+	if err != nil {
+		respondSLAError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, policy)
+}
+
+// DeleteSLAPolicy handles DELETE /api/v1/sla/policies/{severity}
+func (h *IncidentHandler) DeleteSLAPolicy(w http.ResponseWriter, r *http.Request) {
+	if err := h.incidentService.DeleteSLAPolicy(models.Severity(mux.Vars(r)["severity"])); err != nil {
+		respondSLAError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondSLAError maps SLA policy errors to HTTP status codes
+func respondSLAError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrSLAPolicyNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidSLAPolicy):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
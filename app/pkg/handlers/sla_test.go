@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestSLAPolicyHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodPut, "/api/v1/sla/policies/critical", strings.NewReader(`{"time_to_acknowledge": "15m", "time_to_resolve": "4h"}`)), http.StatusOK},
+		{httptest.NewRequest(http.MethodPut, "/api/v1/sla/policies/high", strings.NewReader(`{"time_to_acknowledge": "soon"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPut, "/api/v1/sla/policies/urgent", strings.NewReader(`{"time_to_acknowledge": "5m"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPut, "/api/v1/sla/policies/high", strings.NewReader(`{`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/sla/policies", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/sla/policies/critical", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/sla/policies/low", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents", strings.NewReader(`{"title": "Checkout down", "description": "5xx", "severity": "critical"}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodDelete, "/api/v1/sla/policies/critical", nil), http.StatusNoContent},
+		{httptest.NewRequest(http.MethodDelete, "/api/v1/sla/policies/critical", nil), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+		if tt.req.Method == http.MethodPost && !strings.Contains(w.Body.String(), `"sla":{"policy":"critical"`) {
+			t.Errorf("expected the new incident to carry SLA timers, got %s", w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/slack"
+	"go.uber.org/zap"
+)
+
+// maxSlackBodyBytes bounds slash command and interaction bodies
+const maxSlackBodyBytes = 1 << 20
+
+// slackActionAnalyze runs AI analysis; it is a subcommand only, incident messages have no button for it
+const slackActionAnalyze = "incident_analyze"
+
+// slackSubcommands maps slash command verbs to the incident actions they run
+var slackSubcommands = map[string]string{
+	"ack":         slack.ActionAcknowledge,
+	"acknowledge": slack.ActionAcknowledge,
+	"resolve":     slack.ActionResolve,
+	"escalate":    slack.ActionEscalate,
+	"rca":         slack.ActionRCA,
+	"analyze":     slackActionAnalyze,
+}
+
+// slackSeverities are the severities "create" accepts before the title
+var slackSeverities = map[string]models.Severity{
+	"critical": models.SeverityCritical,
+	"high":     models.SeverityHigh,
+	"medium":   models.SeverityMedium,
+	"low":      models.SeverityLow,
+}
+
+// WithSlack serves the Slack app endpoints; without it they return 503
+func WithSlack(app *slack.App) HandlerOption {
+	return func(h *IncidentHandler) {
+		h.slack = app
+	}
+}
+
+// SlackCommand handles POST /api/v1/slack/commands
+// Quick actions answer in the channel; AI analysis and RCA are acknowledged at once and their
+// results posted to the command's response URL when ready.
+func (h *IncidentHandler) SlackCommand(w http.ResponseWriter, r *http.Request) {
+	form, ok := h.readSlackRequest(w, r)
+	if !ok {
+		return
+	}
+	cmd := slack.ParseCommand(form)
+	verb, args := cmd.Subcommand()
+
+	switch {
+	case verb == "create" || verb == "open":
+		respondJSON(w, http.StatusOK, h.slackCreate(cmd, args))
+	case verb == "show" || verb == "status":
+		if len(args) == 0 {
+			respondJSON(w, http.StatusOK, slack.Ephemeral("Usage: `%s %s <incident id>`", cmd.Command, verb))
+			return
+		}
+		incident, err := h.incidentService.GetIncident(args[0])
+		if err != nil {
+			respondJSON(w, http.StatusOK, slack.Ephemeral(":warning: %s", err))
+			return
+		}
+		message := slack.IncidentMessage(incident, h.slack.IncidentURL(incident.ID), "")
+		message.ResponseType = slack.ResponseEphemeral
+		respondJSON(w, http.StatusOK, message)
+	case slackSubcommands[verb] != "":
+		if len(args) == 0 {
+			respondJSON(w, http.StatusOK, slack.Ephemeral("Usage: `%s %s <incident id>`", cmd.Command, verb))
+			return
+		}
+		action, id := slackSubcommands[verb], args[0]
+		if action == slack.ActionRCA || action == slackActionAnalyze {
+			if _, err := h.incidentService.GetIncident(id); err != nil {
+				respondJSON(w, http.StatusOK, slack.Ephemeral(":warning: %s", err))
+				return
+			}
+			h.slack.Go(func() {
+				h.replyToSlack(cmd.ResponseURL, h.runSlackAction(action, id, cmd.User))
+			})
+			respondJSON(w, http.StatusOK, slack.Ephemeral("Working on %s for %s, the result will be posted here.", verb, id))
+			return
+		}
+		respondJSON(w, http.StatusOK, h.runSlackAction(action, id, cmd.User))
+	default:
+		respondJSON(w, http.StatusOK, slack.HelpMessage(cmd.Command))
+	}
+}
+
+// SlackInteraction handles POST /api/v1/slack/interactions
+// Buttons are answered with an empty 200 and their results threaded under the clicked message.
+func (h *IncidentHandler) SlackInteraction(w http.ResponseWriter, r *http.Request) {
+	form, ok := h.readSlackRequest(w, r)
+	if !ok {
+		return
+	}
+	interaction, err := slack.ParseInteraction(form)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, action := range interaction.Actions {
+		action := action
+		h.slack.Go(func() {
+			message := h.runSlackAction(action.ActionID, action.Value, interaction.User)
+			message.ThreadTS = interaction.ThreadTS()
+			h.replyToSlack(interaction.ResponseURL, message)
+		})
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// readSlackRequest reads and verifies a signed Slack request, writing the error response when it fails
+func (h *IncidentHandler) readSlackRequest(w http.ResponseWriter, r *http.Request) (url.Values, bool) {
+	if h.slack == nil {
+		respondError(w, http.StatusServiceUnavailable, "slack integration is not configured")
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackBodyBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "slack payload too large")
+		return nil, false
+	}
+	if err := h.slack.Verify(r.Header, body); err != nil {
+		h.logger.Warn("rejected slack request", zap.Error(err))
+		respondError(w, http.StatusUnauthorized, err.Error())
+		return nil, false
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid form body")
+		return nil, false
+	}
+	return form, true
+}
+
+// slackCreate opens an incident from "create [severity] <title>"
+func (h *IncidentHandler) slackCreate(cmd slack.Command, args []string) slack.Message {
+	req := &models.CreateIncidentRequest{
+		Source:   "slack",
+		Metadata: map[string]interface{}{"slack_channel": cmd.ChannelID, "slack_user": cmd.User.ID},
+	}
+	if len(args) > 0 {
+		if severity, ok := slackSeverities[strings.ToLower(args[0])]; ok {
+			req.Severity = &severity
+			args = args[1:]
+		}
+	}
+	req.Title = strings.Join(args, " ")
+	req.Description = req.Title
+	if req.Title == "" {
+		return slack.Ephemeral("Usage: `%s create [critical|high|medium|low] <title>`", cmd.Command)
+	}
+
+	incident, err := h.incidentService.CreateIncident(req)
+	if err != nil {
+		return slack.Ephemeral(":warning: %s", err)
+	}
+	return slack.IncidentMessage(incident, h.slack.IncidentURL(incident.ID), fmt.Sprintf("<@%s> opened %s", cmd.User.ID, incident.ID))
+}
+
+// runSlackAction runs an incident action for a Slack user and returns the message reporting it.
+// Failures are returned as ephemeral messages so only the user who asked sees them.
+func (h *IncidentHandler) runSlackAction(action, id string, user slack.User) slack.Message {
+	assignee := h.slack.Assignee(user)
+
+	var (
+		incident *models.Incident
+		note     string
+		err      error
+	)
+	switch action {
+	case slack.ActionAcknowledge:
+		incident, err = h.incidentService.AcknowledgeIncident(id, &models.AcknowledgeRequest{User: assignee})
+		if err == nil && incident.AcknowledgedBy == assignee && incident.AssignedTo != assignee {
+			incident, err = h.incidentService.UpdateIncident(id, &models.UpdateIncidentRequest{AssignedTo: &assignee})
+		}
+		note = fmt.Sprintf("<@%s> acknowledged %s", user.ID, id)
+	case slack.ActionResolve:
+		resolved := models.StatusResolved
+		incident, err = h.incidentService.UpdateIncident(id, &models.UpdateIncidentRequest{Status: &resolved})
+		note = fmt.Sprintf("<@%s> resolved %s", user.ID, id)
+	case slack.ActionEscalate:
+		incident, err = h.incidentService.EscalateIncident(id)
+		note = fmt.Sprintf("<@%s> escalated %s", user.ID, id)
+	case slack.ActionRCA:
+		incident, err = h.incidentService.GenerateRCA(id)
+		note = fmt.Sprintf("Root cause analysis for %s, requested by <@%s>", id, user.ID)
+	case slackActionAnalyze:
+		incident, err = h.incidentService.AnalyzeIncident(id)
+		note = fmt.Sprintf("AI analysis of %s, requested by <@%s>", id, user.ID)
+	default:
+		return slack.Ephemeral(":warning: unknown action %q", action)
+	}
+	if err != nil {
+		h.logger.Warn("slack action failed", zap.String("action", action), zap.String("id", id), zap.Error(err))
+		return slack.Ephemeral(":warning: %s", err)
+	}
+
+	h.logger.Info("slack action", zap.String("action", action), zap.String("id", id), zap.String("user", assignee))
+	return slack.IncidentMessage(incident, h.slack.IncidentURL(incident.ID), note)
+}
+
+// replyToSlack posts a message to a response URL, logging failures since the request has already been answered
+func (h *IncidentHandler) replyToSlack(responseURL string, message slack.Message) {
+	if err := h.slack.Respond(context.Background(), responseURL, message); err != nil {
+		h.logger.Warn("failed to reply to slack", zap.Error(err))
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/slack"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const slackTestSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// slackTransport sends posts to hooks.slack.com response URLs to a test server
+type slackTransport struct {
+	target *url.URL
+}
+
+func (t slackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// slackReplies records messages posted to response URLs, keyed by path
+type slackReplies struct {
+	mu       sync.Mutex
+	messages map[string]slack.Message
+}
+
+func (r *slackReplies) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var message slack.Message
+	json.NewDecoder(req.Body).Decode(&message)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages[req.URL.Path] = message
+}
+
+// slackRequest replays a recorded Slack request from testdata/slack, with INC_ID replaced by
+// incidentID, signed at timestamp
+func slackRequest(t *testing.T, path, fixture, incidentID string, timestamp time.Time) *http.Request {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "slack", fixture))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	body := strings.ReplaceAll(string(data), "INC_ID", incidentID)
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(slack.HeaderTimestamp, ts)
+	req.Header.Set(slack.HeaderSignature, slack.Sign(slackTestSecret, ts, []byte(body)))
+	return req
+}
+
+func TestSlackHandlers(t *testing.T) {
+	replies := &slackReplies{messages: map[string]slack.Message{}}
+	server := httptest.NewServer(replies)
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	now := time.Unix(1760781600, 0)
+	app, err := slack.NewApp(slack.Config{SigningSecret: slackTestSecret, Users: map[string]string{"U2147483697": "priya.sharma"}},
+		slack.WithClock(func() time.Time { return now }),
+		slack.WithHTTPClient(&http.Client{Transport: slackTransport{target: target}}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop(), WithSlack(app)).RegisterRoutes(router)
+
+	// /incident create high Checkout API returning 502s
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, slackRequest(t, "/api/v1/slack/commands", "command_create.txt", "", now))
+	var created slack.Message
+	json.NewDecoder(w.Body).Decode(&created)
+	incidents, _ := svc.ListIncidents(nil, nil)
+	if w.Code != http.StatusOK || created.ResponseType != slack.ResponseInChannel || len(incidents) != 1 {
+		t.Fatalf("expected an in-channel reply for one new incident, got %d %+v", w.Code, created)
+	}
+	incident := incidents[0]
+	if incident.Title != "Checkout API returning 502s" || incident.Severity != models.SeverityHigh || incident.Source != "slack" {
+		t.Errorf("expected a high severity incident from slack, got %+v", incident)
+	}
+
+	// /incident ack <id> assigns the incident to the mapped user
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, slackRequest(t, "/api/v1/slack/commands", "command_ack.txt", incident.ID, now))
+	incident, _ = svc.GetIncident(incident.ID)
+	if w.Code != http.StatusOK || incident.AcknowledgedBy != "priya.sharma" || incident.AssignedTo != "priya.sharma" {
+		t.Errorf("expected priya.sharma to acknowledge and take the incident, got %d %q %q", w.Code, incident.AcknowledgedBy, incident.AssignedTo)
+	}
+
+	// /incident analyze <id> answers at once and posts the AI summary to the response URL
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, slackRequest(t, "/api/v1/slack/commands", "command_analyze.txt", incident.ID, now))
+	var working slack.Message
+	json.NewDecoder(w.Body).Decode(&working)
+	if working.ResponseType != slack.ResponseEphemeral {
+		t.Errorf("expected an ephemeral acknowledgement, got %+v", working)
+	}
+	app.Wait()
+	analyzed := replies.messages["/commands/T0001/1190112482/Dy1AbQ0kzl3g6bU0H7tdIdXz"]
+	if !strings.Contains(fmtBlocks(analyzed), "Mock analysis") {
+		t.Errorf("expected the AI summary in the reply, got %+v", analyzed)
+	}
+
+	// The Resolve button threads its result under the incident message
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, slackRequest(t, "/api/v1/slack/interactions", "interaction_resolve.txt", incident.ID, now))
+	app.Wait()
+	resolved := replies.messages["/actions/T0001/1190112483/pL1yNTQ6tWCnOBhYKXZxTVKc"]
+	incident, _ = svc.GetIncident(incident.ID)
+	if w.Code != http.StatusOK || incident.Status != models.StatusResolved {
+		t.Errorf("expected the incident to be resolved, got %d %s", w.Code, incident.Status)
+	}
+	if resolved.ThreadTS != "1760781600.000100" || resolved.ReplaceOriginal || !strings.Contains(resolved.Text, "resolved") {
+		t.Errorf("expected a threaded reply, got %+v", resolved)
+	}
+
+	tampered := slackRequest(t, "/api/v1/slack/commands", "command_ack.txt", incident.ID, now)
+	tampered.Header.Set(slack.HeaderSignature, slack.Sign("wrong", tampered.Header.Get(slack.HeaderTimestamp), nil))
+	unconfigured := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(unconfigured)
+
+	tests := []struct {
+		router   *mux.Router
+		req      *http.Request
+		expected int
+	}{
+		{router, tampered, http.StatusUnauthorized},
+		{router, slackRequest(t, "/api/v1/slack/commands", "command_ack.txt", incident.ID, now.Add(-10*time.Minute)), http.StatusUnauthorized},
+		{unconfigured, slackRequest(t, "/api/v1/slack/commands", "command_ack.txt", incident.ID, now), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d", tt.req.Method, tt.req.URL.Path, tt.expected, w.Code)
+		}
+	}
+}
+
+// fmtBlocks flattens a message's blocks for substring checks
+func fmtBlocks(message slack.Message) string {
+	data, _ := json.Marshal(message.Blocks)
+	return string(data)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/statuspage"
+	"github.com/gorilla/mux"
+)
+
+// CreateComponent handles POST /api/v1/components
+func (h *IncidentHandler) CreateComponent(w http.ResponseWriter, r *http.Request) {
+	var req models.Component
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	component, err := h.incidentService.CreateComponent(&req)
+	if err != nil {
+		respondStatusError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, component)
+}
+
+// ListComponents handles GET /api/v1/components
+func (h *IncidentHandler) ListComponents(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.incidentService.ListComponents())
+}
+
+// GetComponent handles GET /api/v1/components/{name}
+func (h *IncidentHandler) GetComponent(w http.ResponseWriter, r *http.Request) {
+	component, err := h.incidentService.GetComponent(mux.Vars(r)["name"])
+	if err != nil {
+		respondStatusError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, component)
+}
+
+// UpdateComponent handles PUT /api/v1/components/{name}
+func (h *IncidentHandler) UpdateComponent(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateComponentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	component, err := h.incidentService.UpdateComponent(mux.Vars(r)["name"], &req)
+	if err != nil {
+		respondStatusError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, component)
+}
+
+// DeleteComponent handles DELETE /api/v1/components/{name}
+func (h *IncidentHandler) DeleteComponent(w http.ResponseWriter, r *http.Request) {
+	if err := h.incidentService.DeleteComponent(mux.Vars(r)["name"]); err != nil {
+		respondStatusError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PublishStatusUpdate handles POST /api/v1/incidents/{id}/status-updates
+func (h *IncidentHandler) PublishStatusUpdate(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateStatusUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	update, err := h.incidentService.PublishStatusUpdate(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondStatusError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, update)
+}
+
+// ListStatusUpdates handles GET /api/v1/incidents/{id}/status-updates
+func (h *IncidentHandler) ListStatusUpdates(w http.ResponseWriter, r *http.Request) {
+	updates, err := h.incidentService.ListStatusUpdates(mux.Vars(r)["id"])
+	if err != nil {
+		respondStatusError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, updates)
+}
+
+// DraftStatusUpdate handles POST /api/v1/incidents/{id}/status-updates/draft
+func (h *IncidentHandler) DraftStatusUpdate(w http.ResponseWriter, r *http.Request) {
+	var req models.DraftStatusUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	draft, err := h.incidentService.DraftStatusUpdate(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondStatusError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, draft)
+}
+
+// PublicStatus handles GET /api/v1/status. It needs no credentials and may be read from any origin.
+func (h *IncidentHandler) PublicStatus(w http.ResponseWriter, r *http.Request) {
+	setPublicHeaders(w)
+	respondJSON(w, http.StatusOK, h.incidentService.PublicStatus())
+}
+
+// StatusFeed handles GET /api/v1/status/feed.rss and /api/v1/status/feed.atom
+func (h *IncidentHandler) StatusFeed(w http.ResponseWriter, r *http.Request) {
+	format := statuspage.Format(mux.Vars(r)["format"])
+	body, err := h.incidentService.StatusFeed(format)
+	if err != nil {
+		respondStatusError(w, err)
+		return
+	}
+
+	setPublicHeaders(w)
+	w.Header().Set("Content-Type", statuspage.ContentType(format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// setPublicHeaders lets status page readers cache responses briefly and embed them on other sites
+func setPublicHeaders(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+}
+
+// respondStatusError maps status page errors to HTTP status codes
+func respondStatusError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrComponentNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrComponentExists):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidComponent), errors.Is(err, service.ErrInvalidStatusUpdate),
+		errors.Is(err, statuspage.ErrUnsupportedFormat):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestStatusPageHandlers(t *testing.T) {
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+	incident, _ := svc.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+	base := "/api/v1/incidents/" + incident.ID + "/status-updates"
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodPost, "/api/v1/components", strings.NewReader(`{"name": "Checkout", "services": ["checkout"]}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/components", strings.NewReader(`{"name": "checkout"}`)), http.StatusConflict},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/components", strings.NewReader(`{"name": ""}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/components", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodPut, "/api/v1/components/checkout", strings.NewReader(`{"description": "Cart and payment"}`)), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/components/Search", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPost, base+"/draft", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodPost, base+"/draft", strings.NewReader(`{"state": "fixed"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, base, strings.NewReader(`{"state": "investigating", "message": "Looking into it"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, base, strings.NewReader(`{"state": "investigating", "title": "Checkout errors", "message": "Looking into it", "components": {"Checkout": "degraded"}}`)), http.StatusCreated},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/incidents/INC-0/status-updates", strings.NewReader(`{"state": "resolved", "message": "Fixed"}`)), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodGet, base, nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/status", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/status/feed.rss", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/status/feed.atom", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/status/feed.json", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodDelete, "/api/v1/components/Checkout", nil), http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/status/feed.atom", nil))
+	if w.Header().Get("Content-Type") != "application/atom+xml; charset=utf-8" || w.Header().Get("Access-Control-Allow-Origin") != "*" ||
+		!strings.Contains(w.Body.String(), "Looking into it") {
+		t.Errorf("unexpected Atom response %v: %s", w.Header(), w.Body.String())
+	}
+}
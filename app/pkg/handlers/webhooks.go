@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+)
+
+// CreateSubscription handles POST /api/v1/webhooks/subscriptions
+func (h *IncidentHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	subscription, err := h.incidentService.CreateSubscription(&req)
+	if err != nil {
+		respondWebhookError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, subscription)
+}
+
+// ListSubscriptions handles GET /api/v1/webhooks/subscriptions
+func (h *IncidentHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.incidentService.ListSubscriptions())
+}
+
+// GetSubscription handles GET /api/v1/webhooks/subscriptions/{id}
+func (h *IncidentHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, err := h.incidentService.GetSubscription(mux.Vars(r)["id"])
+	if err != nil {
+		respondWebhookError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, subscription)
+}
+
+// UpdateSubscription handles PUT /api/v1/webhooks/subscriptions/{id}
+func (h *IncidentHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	subscription, err := h.incidentService.UpdateSubscription(mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWebhookError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, subscription)
+}
+
+// DeleteSubscription handles DELETE /api/v1/webhooks/subscriptions/{id}
+func (h *IncidentHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := h.incidentService.DeleteSubscription(mux.Vars(r)["id"]); err != nil {
+		respondWebhookError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/v1/webhooks/subscriptions/{id}/deliveries
+// Supports ?status=pending|succeeded|failed
+func (h *IncidentHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	var status *models.WebhookDeliveryStatus
+	if value := r.URL.Query().Get("status"); value != "" {
+		s := models.WebhookDeliveryStatus(value)
+		status = &s
+	}
+
+	deliveries, err := h.incidentService.ListDeliveries(mux.Vars(r)["id"], status)
+	if err != nil {
+		respondWebhookError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, deliveries)
+}
+
+// GetDelivery handles GET /api/v1/webhooks/deliveries/{id}
+func (h *IncidentHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.incidentService.GetDelivery(mux.Vars(r)["id"])
+	if err != nil {
+		respondWebhookError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, delivery)
+}
+
+// Redeliver handles POST /api/v1/webhooks/deliveries/{id}/redeliver
+func (h *IncidentHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.incidentService.Redeliver(mux.Vars(r)["id"])
+	if err != nil {
+		respondWebhookError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusAccepted, delivery)
+}
+
+// respondWebhookError maps webhook subscription and delivery errors to HTTP status codes
+func respondWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrSubscriptionNotFound), errors.Is(err, service.ErrDeliveryNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidSubscription):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
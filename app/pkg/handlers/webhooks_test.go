@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestWebhookHandlers(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	svc := service.NewIncidentService(service.NewIncidentStore(), &MockAIClient{}, zap.NewNop(), service.WithWebhookRetry(1, time.Millisecond))
+	router := mux.NewRouter()
+	NewIncidentHandler(svc, zap.NewNop()).RegisterRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/subscriptions",
+		strings.NewReader(`{"url": "`+receiver.URL+`", "events": ["incident.created"]}`)))
+	var subscription models.WebhookSubscription
+	json.NewDecoder(w.Body).Decode(&subscription)
+	if w.Code != http.StatusCreated || subscription.Secret == "" {
+		t.Fatalf("expected 201 with a secret, got %d %+v", w.Code, subscription)
+	}
+
+	svc.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "db-1"})
+	svc.WaitForWebhooks()
+	deliveries, _ := svc.ListDeliveries(subscription.ID, nil)
+	if len(deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %+v", deliveries)
+	}
+	base := "/api/v1/webhooks/subscriptions/" + subscription.ID
+
+	tests := []struct {
+		req      *http.Request
+		expected int
+	}{
+		{httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/subscriptions", strings.NewReader(`{"url": "mailto:ops@example.com"}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/subscriptions", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, base, nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodPut, base, strings.NewReader(`{"events": ["incident.resolved", "rca.generated"]}`)), http.StatusOK},
+		{httptest.NewRequest(http.MethodPut, base, strings.NewReader(`{"events": ["incident.deleted"]}`)), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodGet, base+"/deliveries?status=succeeded", nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/deliveries/"+deliveries[0].ID, nil), http.StatusOK},
+		{httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/deliveries/WHD-0", nil), http.StatusNotFound},
+		{httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/deliveries/"+deliveries[0].ID+"/redeliver", nil), http.StatusAccepted},
+		{httptest.NewRequest(http.MethodDelete, base, nil), http.StatusNoContent},
+		{httptest.NewRequest(http.MethodGet, base+"/deliveries", nil), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.req.Method, tt.req.URL, tt.expected, w.Code, w.Body.String())
+		}
+	}
+	svc.StopWebhooks()
+}
@@ -0,0 +1,197 @@
+// Package kube reads workload state from the Kubernetes API to enrich incidents.
+// It talks to the REST API directly and needs only read access to pods, events and workloads.
+package kube
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// maxListItems bounds list requests so large namespaces cannot stall enrichment
+	maxListItems = 500
+)
+
+// ErrNotFound is returned when the requested object does not exist
+var ErrNotFound = errors.New("kubernetes object not found")
+
+// Config describes how to reach the API server
+type Config struct {
+	// Host is the API server URL, e.g. https://10.100.0.1:443
+	Host string
+	// Token is a bearer token; TokenFile is re-read on every request so rotated tokens are picked up
+	Token     string
+	TokenFile string
+	// CAFile verifies the API server certificate; Insecure skips verification (tests only)
+	CAFile   string
+	Insecure bool
+	// Namespace is used for targets that do not name one
+	Namespace string
+}
+
+// InClusterConfig builds a Config from the pod's service account
+func InClusterConfig() (Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return Config{}, errors.New("not running in a Kubernetes cluster")
+	}
+
+	cfg := Config{
+		Host:      "https://" + net.JoinHostPort(host, port),
+		TokenFile: serviceAccountDir + "/token",
+		CAFile:    serviceAccountDir + "/ca.crt",
+		Namespace: "default",
+	}
+	if ns, err := os.ReadFile(serviceAccountDir + "/namespace"); err == nil {
+		cfg.Namespace = strings.TrimSpace(string(ns))
+	}
+	return cfg, nil
+}
+
+// Client is a minimal read-only Kubernetes API client
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the configured API server
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("kubernetes host is required")
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = "default"
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: cfg.Insecure}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &Client{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// Namespace returns the namespace used for targets that do not name one
+func (c *Client) Namespace() string {
+	return c.cfg.Namespace
+}
+
+// GetWorkload fetches a Deployment, StatefulSet or DaemonSet
+func (c *Client) GetWorkload(ctx context.Context, kind, namespace, name string) (*Workload, error) {
+	resource, ok := workloadResources[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+	var workload Workload
+	err := c.get(ctx, fmt.Sprintf("/apis/apps/v1/namespaces/%s/%s/%s", url.PathEscape(namespace), resource, url.PathEscape(name)), nil, &workload)
+	return &workload, err
+}
+
+// GetPod fetches a single pod
+func (c *Client) GetPod(ctx context.Context, namespace, name string) (*Pod, error) {
+	var pod Pod
+	err := c.get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/pods/%s", url.PathEscape(namespace), url.PathEscape(name)), nil, &pod)
+	return &pod, err
+}
+
+// ListPods lists pods matching a label selector
+func (c *Client) ListPods(ctx context.Context, namespace, selector string) ([]Pod, error) {
+	var list struct {
+		Items []Pod `json:"items"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/pods", url.PathEscape(namespace)), listQuery(selector), &list)
+	return list.Items, err
+}
+
+// ListReplicaSets lists ReplicaSets matching a label selector
+func (c *Client) ListReplicaSets(ctx context.Context, namespace, selector string) ([]ReplicaSet, error) {
+	var list struct {
+		Items []ReplicaSet `json:"items"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/apis/apps/v1/namespaces/%s/replicasets", url.PathEscape(namespace)), listQuery(selector), &list)
+	return list.Items, err
+}
+
+// ListEvents lists the events about one object, e.g. kind Pod and a pod name
+func (c *Client) ListEvents(ctx context.Context, namespace, kind, name string) ([]Event, error) {
+	var list struct {
+		Items []Event `json:"items"`
+	}
+	query := listQuery("")
+	query.Set("fieldSelector", "involvedObject.kind="+kind+",involvedObject.name="+name)
+	err := c.get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/events", url.PathEscape(namespace)), query, &list)
+	return list.Items, err
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := strings.TrimSuffix(c.cfg.Host, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	token := c.cfg.Token
+	if c.cfg.TokenFile != "" {
+		data, err := os.ReadFile(c.cfg.TokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read service account token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrNotFound, path)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Kubernetes API error: %d - %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func listQuery(selector string) url.Values {
+	query := url.Values{"limit": {fmt.Sprint(maxListItems)}}
+	if selector != "" {
+		query.Set("labelSelector", selector)
+	}
+	return query
+}
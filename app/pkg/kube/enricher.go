@@ -0,0 +1,346 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// EnricherName identifies Kubernetes context on an incident
+const EnricherName = "kubernetes"
+
+// Limits keep the stored context and the prompt compact
+const (
+	maxPods     = 20
+	maxEvents   = 15
+	maxRollouts = 5
+)
+
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// Target is the workload an incident refers to
+type Target struct {
+	Namespace string
+	// Kind is deployment, statefulset, daemonset or pod
+	Kind string
+	Name string
+}
+
+// objectRef names an object whose events are collected
+type objectRef struct {
+	kind string
+	name string
+}
+
+// Enricher attaches Kubernetes workload state to incidents
+type Enricher struct {
+	client *Client
+}
+
+// NewEnricher creates an enricher backed by client
+func NewEnricher(client *Client) *Enricher {
+	return &Enricher{client: client}
+}
+
+// Name identifies the enricher
+func (e *Enricher) Name() string {
+	return EnricherName
+}
+
+// Enrich gathers pod statuses, events and rollout history for the incident's workload.
+// It returns nil when the incident does not name a workload.
+func (e *Enricher) Enrich(ctx context.Context, incident *models.Incident) (*models.Enrichment, error) {
+	target, ok := TargetFor(incident, e.client.Namespace())
+	if !ok {
+		return nil, nil
+	}
+
+	kc, err := e.collect(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Enrichment{
+		Enricher:   EnricherName,
+		Status:     models.EnrichmentOK,
+		FetchedAt:  time.Now(),
+		Summary:    Summarize(kc),
+		Kubernetes: kc,
+	}, nil
+}
+
+// TargetFor finds the workload an incident refers to. Metadata keys (namespace, deployment,
+// statefulset, daemonset, pod) take precedence over "key:value" tags.
+func TargetFor(incident *models.Incident, defaultNamespace string) (Target, bool) {
+	values := make(map[string]string)
+	for _, tag := range incident.Tags {
+		if key, value, ok := strings.Cut(tag, ":"); ok && value != "" {
+			values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	for key, value := range incident.Metadata {
+		if s, ok := value.(string); ok && s != "" {
+			values[strings.ToLower(key)] = s
+		}
+	}
+
+	target := Target{Namespace: values["namespace"]}
+	if target.Namespace == "" {
+		target.Namespace = defaultNamespace
+	}
+	for _, kind := range []string{"deployment", "statefulset", "daemonset", "pod"} {
+		if name := values[kind]; name != "" {
+			target.Kind, target.Name = kind, name
+			return target, true
+		}
+	}
+	return Target{}, false
+}
+
+func (e *Enricher) collect(ctx context.Context, target Target) (*models.KubernetesContext, error) {
+	kc := &models.KubernetesContext{
+		Namespace:    target.Namespace,
+		WorkloadKind: target.Kind,
+		WorkloadName: target.Name,
+	}
+	var pods []Pod
+	if target.Kind == "pod" {
+		pod, err := e.client.GetPod(ctx, target.Namespace, target.Name)
+		if err != nil {
+			return nil, err
+		}
+		pods = []Pod{*pod}
+	} else {
+		workload, err := e.client.GetWorkload(ctx, target.Kind, target.Namespace, target.Name)
+		if err != nil {
+			return nil, err
+		}
+		setReplicas(kc, workload)
+
+		selector, err := workload.Spec.Selector.Query()
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", target.Kind, target.Name, err)
+		}
+		if selector == "" {
+			return nil, fmt.Errorf("%s %s has no label selector", target.Kind, target.Name)
+		}
+		if pods, err = e.client.ListPods(ctx, target.Namespace, selector); err != nil {
+			return nil, err
+		}
+
+		if target.Kind == "deployment" {
+			replicaSets, err := e.client.ListReplicaSets(ctx, target.Namespace, selector)
+			if err != nil {
+				return nil, err
+			}
+			kc.Rollouts = rollouts(replicaSets, target.Name)
+		}
+	}
+	kc.Pods = podStatuses(pods)
+
+	// Events are listed per object, for the workload and the ReplicaSets and pods kept above. A list for
+	// the whole namespace is capped and unordered, so a busy namespace could crowd out the workload's events.
+	objects := []objectRef{{targetKinds[target.Kind], target.Name}}
+	for _, rollout := range kc.Rollouts {
+		objects = append(objects, objectRef{"ReplicaSet", rollout.Name})
+	}
+	for _, pod := range kc.Pods {
+		if pod.Name != target.Name {
+			objects = append(objects, objectRef{"Pod", pod.Name})
+		}
+	}
+	var events []Event
+	for _, object := range objects {
+		list, err := e.client.ListEvents(ctx, target.Namespace, object.kind, object.name)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		events = append(events, list...)
+	}
+	kc.Events = relevantEvents(events)
+	return kc, nil
+}
+
+func setReplicas(kc *models.KubernetesContext, workload *Workload) {
+	status := workload.Status
+	if kc.WorkloadKind == "daemonset" {
+		kc.Replicas, kc.ReadyReplicas, kc.UpdatedReplicas = status.DesiredNumberScheduled, status.NumberReady, status.UpdatedNumberScheduled
+		return
+	}
+	kc.Replicas, kc.ReadyReplicas, kc.UpdatedReplicas = status.Replicas, status.ReadyReplicas, status.UpdatedReplicas
+}
+
+// podStatuses converts pods, unhealthiest first
+func podStatuses(pods []Pod) []models.PodStatus {
+	statuses := make([]models.PodStatus, 0, len(pods))
+	for _, pod := range pods {
+		ps := models.PodStatus{
+			Name:       pod.Metadata.Name,
+			Phase:      pod.Status.Phase,
+			Node:       pod.Spec.NodeName,
+			Ready:      len(pod.Status.ContainerStatuses) > 0,
+			Containers: make([]models.ContainerStatus, 0, len(pod.Status.ContainerStatuses)),
+		}
+		for _, c := range pod.Status.ContainerStatuses {
+			cs := models.ContainerStatus{
+				Name:         c.Name,
+				Image:        c.Image,
+				Ready:        c.Ready,
+				RestartCount: c.RestartCount,
+			}
+			cs.State, cs.Reason = stateOf(c.State)
+			if last := c.LastState.Terminated; last != nil {
+				cs.LastReason = last.Reason
+				cs.LastExitCode = last.ExitCode
+				if !last.FinishedAt.IsZero() {
+					finished := last.FinishedAt
+					cs.LastFinishedAt = &finished
+				}
+			}
+			ps.Ready = ps.Ready && c.Ready
+			ps.Restarts += c.RestartCount
+			ps.Containers = append(ps.Containers, cs)
+		}
+		statuses = append(statuses, ps)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Ready != statuses[j].Ready {
+			return !statuses[i].Ready
+		}
+		if statuses[i].Restarts != statuses[j].Restarts {
+			return statuses[i].Restarts > statuses[j].Restarts
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	if len(statuses) > maxPods {
+		statuses = statuses[:maxPods]
+	}
+	return statuses
+}
+
+func stateOf(state ContainerState) (string, string) {
+	switch {
+	case state.Waiting != nil:
+		return "waiting", state.Waiting.Reason
+	case state.Terminated != nil:
+		return "terminated", state.Terminated.Reason
+	case state.Running != nil:
+		return "running", ""
+	}
+	return "unknown", ""
+}
+
+// relevantEvents converts events, newest first
+func relevantEvents(events []Event) []models.KubernetesEvent {
+	result := make([]models.KubernetesEvent, 0)
+	for _, ev := range events {
+		count := ev.Count
+		if count == 0 {
+			count = 1
+		}
+		result = append(result, models.KubernetesEvent{
+			Type:     ev.Type,
+			Reason:   ev.Reason,
+			Object:   strings.ToLower(ev.InvolvedObject.Kind) + "/" + ev.InvolvedObject.Name,
+			Message:  ev.Message,
+			Count:    count,
+			LastSeen: ev.lastSeen(),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+	if len(result) > maxEvents {
+		result = result[:maxEvents]
+	}
+	return result
+}
+
+// rollouts lists the deployment's ReplicaSets by revision, newest first
+func rollouts(replicaSets []ReplicaSet, deployment string) []models.Rollout {
+	result := make([]models.Rollout, 0)
+	for _, rs := range replicaSets {
+		if !ownedBy(rs.Metadata, "Deployment", deployment) {
+			continue
+		}
+		revision, _ := strconv.Atoi(rs.Metadata.Annotations[revisionAnnotation])
+		images := make([]string, 0, len(rs.Spec.Template.Spec.Containers))
+		for _, c := range rs.Spec.Template.Spec.Containers {
+			images = append(images, c.Image)
+		}
+		result = append(result, models.Rollout{
+			Revision:  revision,
+			Name:      rs.Metadata.Name,
+			Images:    images,
+			Replicas:  rs.Status.Replicas,
+			CreatedAt: rs.Metadata.CreationTimestamp,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Revision > result[j].Revision
+	})
+	if len(result) > maxRollouts {
+		result = result[:maxRollouts]
+	}
+	return result
+}
+
+func ownedBy(meta ObjectMeta, kind, name string) bool {
+	for _, ref := range meta.OwnerReferences {
+		if ref.Kind == kind && ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Summarize renders Kubernetes context as compact text for the analysis prompt
+func Summarize(kc *models.KubernetesContext) string {
+	var b strings.Builder
+	if kc.WorkloadKind == "pod" {
+		fmt.Fprintf(&b, "Pod %s/%s\n", kc.Namespace, kc.WorkloadName)
+	} else {
+		fmt.Fprintf(&b, "%s %s/%s: %d/%d ready, %d updated\n",
+			kc.WorkloadKind, kc.Namespace, kc.WorkloadName, kc.ReadyReplicas, kc.Replicas, kc.UpdatedReplicas)
+	}
+
+	if len(kc.Pods) > 0 {
+		b.WriteString("Pods:\n")
+	}
+	for _, pod := range kc.Pods {
+		fmt.Fprintf(&b, "- %s %s ready=%t restarts=%d\n", pod.Name, pod.Phase, pod.Ready, pod.Restarts)
+		for _, c := range pod.Containers {
+			line := fmt.Sprintf("  %s %s", c.Name, c.State)
+			if c.Reason != "" {
+				line += " (" + c.Reason + ")"
+			}
+			if c.LastReason != "" {
+				line += fmt.Sprintf(", last terminated %s exit %d", c.LastReason, c.LastExitCode)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if len(kc.Events) > 0 {
+		b.WriteString("Recent events:\n")
+	}
+	for _, ev := range kc.Events {
+		fmt.Fprintf(&b, "- %s %s %s %s x%d: %s\n", ev.LastSeen.UTC().Format(time.RFC3339), ev.Type, ev.Reason, ev.Object, ev.Count, ev.Message)
+	}
+
+	if len(kc.Rollouts) > 0 {
+		b.WriteString("Rollouts:\n")
+	}
+	for _, r := range kc.Rollouts {
+		fmt.Fprintf(&b, "- revision %d at %s: %s (%d replicas)\n", r.Revision, r.CreatedAt.UTC().Format(time.RFC3339), strings.Join(r.Images, ", "), r.Replicas)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,233 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// fakeAPI serves canned objects by path, or by path and field selector, and records the label
+// selectors it was asked for
+type fakeAPI struct {
+	objects   map[string]string
+	selectors map[string]string
+	auth      string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.auth = r.Header.Get("Authorization")
+	if sel := r.URL.Query().Get("labelSelector"); sel != "" {
+		f.selectors[r.URL.Path] = sel
+	}
+	key := r.URL.Path
+	if sel := r.URL.Query().Get("fieldSelector"); sel != "" {
+		key += "?" + sel
+	}
+	body, ok := f.objects[key]
+	if !ok {
+		http.Error(w, `{"kind":"Status","reason":"NotFound"}`, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(body))
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *Client) {
+	t.Helper()
+	api := &fakeAPI{
+		selectors: make(map[string]string),
+		objects: map[string]string{
+			"/apis/apps/v1/namespaces/shop/deployments/checkout": `{
+				"metadata": {"name": "checkout", "namespace": "shop"},
+				"spec": {"selector": {"matchLabels": {"app": "checkout", "tier": "web"}}},
+				"status": {"replicas": 3, "readyReplicas": 2, "updatedReplicas": 3}
+			}`,
+			"/api/v1/namespaces/shop/pods": `{"items": [
+				{"metadata": {"name": "checkout-7d9-a"}, "spec": {"nodeName": "node-1"},
+				 "status": {"phase": "Running", "containerStatuses": [
+					{"name": "app", "image": "checkout:1.4", "ready": true, "restartCount": 0, "state": {"running": {"startedAt": "2024-05-01T10:00:00Z"}}}]}},
+				{"metadata": {"name": "checkout-7d9-b"}, "spec": {"nodeName": "node-2"},
+				 "status": {"phase": "Running", "containerStatuses": [
+					{"name": "app", "image": "checkout:1.4", "ready": false, "restartCount": 5,
+					 "state": {"waiting": {"reason": "CrashLoopBackOff"}},
+					 "lastState": {"terminated": {"reason": "OOMKilled", "exitCode": 137, "finishedAt": "2024-05-01T10:05:00Z"}}}]}}
+			]}`,
+			"/apis/apps/v1/namespaces/shop/replicasets": `{"items": [
+				{"metadata": {"name": "checkout-5c4", "annotations": {"deployment.kubernetes.io/revision": "6"},
+				  "creationTimestamp": "2024-04-20T09:00:00Z", "ownerReferences": [{"kind": "Deployment", "name": "checkout"}]},
+				 "spec": {"template": {"spec": {"containers": [{"name": "app", "image": "checkout:1.3"}]}}}, "status": {"replicas": 0}},
+				{"metadata": {"name": "checkout-7d9", "annotations": {"deployment.kubernetes.io/revision": "7"},
+				  "creationTimestamp": "2024-05-01T09:55:00Z", "ownerReferences": [{"kind": "Deployment", "name": "checkout"}]},
+				 "spec": {"template": {"spec": {"containers": [{"name": "app", "image": "checkout:1.4"}]}}}, "status": {"replicas": 3}},
+				{"metadata": {"name": "other-1", "ownerReferences": [{"kind": "Deployment", "name": "other"}]}}
+			]}`,
+			"/api/v1/namespaces/shop/events?involvedObject.kind=Pod,involvedObject.name=checkout-7d9-b": `{"items": [
+				{"involvedObject": {"kind": "Pod", "name": "checkout-7d9-b"}, "type": "Warning", "reason": "BackOff",
+				 "message": "Back-off restarting failed container", "count": 12, "lastTimestamp": "2024-05-01T10:06:00Z"}
+			]}`,
+			"/api/v1/namespaces/shop/events?involvedObject.kind=Deployment,involvedObject.name=checkout": `{"items": [
+				{"involvedObject": {"kind": "Deployment", "name": "checkout"}, "type": "Normal", "reason": "ScalingReplicaSet",
+				 "message": "Scaled up replica set checkout-7d9 to 3", "lastTimestamp": "2024-05-01T09:55:00Z"}
+			]}`,
+			"/api/v1/namespaces/shop/events": `{"items": [
+				{"involvedObject": {"kind": "Pod", "name": "unrelated"}, "type": "Warning", "reason": "Failed",
+				 "message": "not ours", "lastTimestamp": "2024-05-01T10:07:00Z"}
+			]}`,
+		},
+	}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(Config{Host: server.URL, Token: "secret", Namespace: "shop"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return api, client
+}
+
+func TestTargetFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		incident *models.Incident
+		expected Target
+		ok       bool
+	}{
+		{
+			name:     "tags",
+			incident: &models.Incident{Tags: []string{"payments", "namespace:shop", "deployment:checkout"}},
+			expected: Target{Namespace: "shop", Kind: "deployment", Name: "checkout"},
+			ok:       true,
+		},
+		{
+			name: "metadata wins over tags",
+			incident: &models.Incident{
+				Tags:     []string{"deployment:checkout"},
+				Metadata: map[string]interface{}{"namespace": "jobs", "statefulset": "queue"},
+			},
+			expected: Target{Namespace: "jobs", Kind: "deployment", Name: "checkout"},
+			ok:       true,
+		},
+		{
+			name:     "default namespace",
+			incident: &models.Incident{Metadata: map[string]interface{}{"pod": "worker-0"}},
+			expected: Target{Namespace: "default", Kind: "pod", Name: "worker-0"},
+			ok:       true,
+		},
+		{
+			name:     "no workload",
+			incident: &models.Incident{Tags: []string{"namespace:shop"}},
+		},
+	}
+
+	for _, tt := range tests {
+		target, ok := TargetFor(tt.incident, "default")
+		if ok != tt.ok || target != tt.expected {
+			t.Errorf("%s: expected %+v (%t), got %+v (%t)", tt.name, tt.expected, tt.ok, target, ok)
+		}
+	}
+}
+
+func TestEnrichDeployment(t *testing.T) {
+	api, client := newFakeAPI(t)
+	enricher := NewEnricher(client)
+
+	enrichment, err := enricher.Enrich(context.Background(), &models.Incident{Tags: []string{"deployment:checkout"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.auth != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", api.auth)
+	}
+	if got := api.selectors["/api/v1/namespaces/shop/pods"]; got != "app=checkout,tier=web" {
+		t.Errorf("expected pods to be selected by the deployment selector, got %q", got)
+	}
+
+	kc := enrichment.Kubernetes
+	if enrichment.Status != models.EnrichmentOK || kc.Replicas != 3 || kc.ReadyReplicas != 2 {
+		t.Fatalf("unexpected enrichment %+v", enrichment)
+	}
+
+	if len(kc.Pods) != 2 || kc.Pods[0].Name != "checkout-7d9-b" {
+		t.Fatalf("expected the unhealthy pod first, got %+v", kc.Pods)
+	}
+	c := kc.Pods[0].Containers[0]
+	if c.State != "waiting" || c.Reason != "CrashLoopBackOff" || c.LastReason != "OOMKilled" || c.LastExitCode != 137 || c.LastFinishedAt == nil {
+		t.Errorf("unexpected container status %+v", c)
+	}
+	if kc.Pods[0].Restarts != 5 || kc.Pods[0].Ready {
+		t.Errorf("unexpected pod status %+v", kc.Pods[0])
+	}
+
+	if len(kc.Events) != 2 || kc.Events[0].Reason != "BackOff" || kc.Events[0].Object != "pod/checkout-7d9-b" || kc.Events[1].Count != 1 {
+		t.Errorf("expected related events newest first, got %+v", kc.Events)
+	}
+
+	if len(kc.Rollouts) != 2 || kc.Rollouts[0].Revision != 7 || kc.Rollouts[1].Images[0] != "checkout:1.3" {
+		t.Errorf("expected rollouts newest first, got %+v", kc.Rollouts)
+	}
+
+	for _, want := range []string{"deployment shop/checkout: 2/3 ready", "last terminated OOMKilled exit 137", "BackOff pod/checkout-7d9-b x12", "revision 7"} {
+		if !strings.Contains(enrichment.Summary, want) {
+			t.Errorf("expected summary to contain %q, got:\n%s", want, enrichment.Summary)
+		}
+	}
+}
+
+func TestEnrichWithoutTarget(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	enrichment, err := NewEnricher(client).Enrich(context.Background(), &models.Incident{Title: "no workload"})
+	if err != nil || enrichment != nil {
+		t.Errorf("expected no enrichment, got %+v, %v", enrichment, err)
+	}
+}
+
+func TestEnrichMissingWorkload(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	_, err := NewEnricher(client).Enrich(context.Background(), &models.Incident{Tags: []string{"deployment:missing"}})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestEventLastSeen(t *testing.T) {
+	seen := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ev := Event{EventTime: seen}
+	if !ev.lastSeen().Equal(seen) {
+		t.Errorf("expected eventTime fallback, got %v", ev.lastSeen())
+	}
+
+	var decoded Event
+	if err := json.Unmarshal([]byte(`{"lastTimestamp": null, "eventTime": "2024-05-01T10:00:00.000000Z"}`), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decoded.lastSeen().Equal(seen) {
+		t.Errorf("expected eventTime from JSON, got %v", decoded.lastSeen())
+	}
+}
+
+func TestLabelSelectorQuery(t *testing.T) {
+	var selector LabelSelector
+	if err := json.Unmarshal([]byte(`{"matchLabels": {"app": "checkout"}, "matchExpressions": [
+		{"key": "tier", "operator": "In", "values": ["web", "api"]},
+		{"key": "canary", "operator": "DoesNotExist"}
+	]}`), &selector); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := selector.Query(); err != nil || got != "app=checkout,tier in (web,api),!canary" {
+		t.Errorf("expected matchExpressions in the selector, got %q, %v", got, err)
+	}
+
+	selector.MatchExpressions = append(selector.MatchExpressions, LabelSelectorRequirement{Key: "zone", Operator: "Gt", Values: []string{"1"}})
+	if _, err := selector.Query(); err == nil {
+		t.Error("expected an unsupported operator to be rejected")
+	}
+}
@@ -0,0 +1,188 @@
+package kube
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The types below mirror the subset of the Kubernetes API the enricher reads
+
+var workloadResources = map[string]string{
+	"deployment":  "deployments",
+	"statefulset": "statefulsets",
+	"daemonset":   "daemonsets",
+}
+
+// targetKinds maps target kinds to the API kinds events refer to
+var targetKinds = map[string]string{
+	"deployment":  "Deployment",
+	"statefulset": "StatefulSet",
+	"daemonset":   "DaemonSet",
+	"pod":         "Pod",
+}
+
+// ObjectMeta is the standard object metadata
+type ObjectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	OwnerReferences   []OwnerReference  `json:"ownerReferences,omitempty"`
+}
+
+// OwnerReference identifies an object's controller
+type OwnerReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// LabelSelector selects objects by labels
+type LabelSelector struct {
+	MatchLabels      map[string]string          `json:"matchLabels,omitempty"`
+	MatchExpressions []LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+}
+
+// LabelSelectorRequirement is one matchExpressions entry
+type LabelSelectorRequirement struct {
+	Key string `json:"key"`
+	// Operator is In, NotIn, Exists or DoesNotExist
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// Query renders the selector in label selector query syntax. It fails on an operator the query
+// syntax cannot express, since dropping the requirement would select too many pods.
+func (s LabelSelector) Query() (string, error) {
+	parts := make([]string, 0, len(s.MatchLabels))
+	for k, v := range s.MatchLabels {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+
+	for _, req := range s.MatchExpressions {
+		switch req.Operator {
+		case "In", "NotIn":
+			if len(req.Values) == 0 {
+				return "", fmt.Errorf("label selector %s %s needs values", req.Key, req.Operator)
+			}
+			parts = append(parts, fmt.Sprintf("%s %s (%s)", req.Key, strings.ToLower(req.Operator), strings.Join(req.Values, ",")))
+		case "Exists":
+			parts = append(parts, req.Key)
+		case "DoesNotExist":
+			parts = append(parts, "!"+req.Key)
+		default:
+			return "", fmt.Errorf("unsupported label selector operator %q", req.Operator)
+		}
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Container is a container in a pod template
+type Container struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+// PodTemplate is the pod template of a workload or ReplicaSet
+type PodTemplate struct {
+	Spec struct {
+		Containers []Container `json:"containers"`
+	} `json:"spec"`
+}
+
+// Workload is a Deployment, StatefulSet or DaemonSet
+type Workload struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Selector LabelSelector `json:"selector"`
+		Template PodTemplate   `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		Replicas        int `json:"replicas"`
+		ReadyReplicas   int `json:"readyReplicas"`
+		UpdatedReplicas int `json:"updatedReplicas"`
+		// DaemonSets report their replicas differently
+		DesiredNumberScheduled int `json:"desiredNumberScheduled"`
+		NumberReady            int `json:"numberReady"`
+		UpdatedNumberScheduled int `json:"updatedNumberScheduled"`
+	} `json:"status"`
+}
+
+// ReplicaSet is one revision of a Deployment
+type ReplicaSet struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Template PodTemplate `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		Replicas int `json:"replicas"`
+	} `json:"status"`
+}
+
+// Pod is a pod and its container statuses
+type Pod struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		NodeName string `json:"nodeName"`
+	} `json:"spec"`
+	Status struct {
+		Phase             string            `json:"phase"`
+		Reason            string            `json:"reason"`
+		ContainerStatuses []ContainerStatus `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// ContainerStatus is the runtime status of a container
+type ContainerStatus struct {
+	Name         string         `json:"name"`
+	Image        string         `json:"image"`
+	Ready        bool           `json:"ready"`
+	RestartCount int            `json:"restartCount"`
+	State        ContainerState `json:"state"`
+	LastState    ContainerState `json:"lastState"`
+}
+
+// ContainerState holds exactly one of its fields
+type ContainerState struct {
+	Waiting *struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"waiting,omitempty"`
+	Running *struct {
+		StartedAt time.Time `json:"startedAt"`
+	} `json:"running,omitempty"`
+	Terminated *struct {
+		Reason     string    `json:"reason"`
+		ExitCode   int       `json:"exitCode"`
+		FinishedAt time.Time `json:"finishedAt"`
+	} `json:"terminated,omitempty"`
+}
+
+// Event is a core/v1 Event
+type Event struct {
+	Metadata       ObjectMeta `json:"metadata"`
+	InvolvedObject struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"involvedObject"`
+	Type           string    `json:"type"`
+	Reason         string    `json:"reason"`
+	Message        string    `json:"message"`
+	Count          int       `json:"count"`
+	FirstTimestamp time.Time `json:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp"`
+	EventTime      time.Time `json:"eventTime"`
+}
+
+// lastSeen returns the most recent timestamp the event carries
+func (e Event) lastSeen() time.Time {
+	for _, ts := range []time.Time{e.LastTimestamp, e.EventTime, e.FirstTimestamp} {
+		if !ts.IsZero() {
+			return ts
+		}
+	}
+	return e.Metadata.CreationTimestamp
+}
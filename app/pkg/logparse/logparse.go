@@ -0,0 +1,363 @@
+// Package logparse turns raw log lines in common formats into structured entries.
+package logparse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// Format names an input log format
+type Format string
+
+const (
+	FormatAuto       Format = "auto"
+	FormatText       Format = "text"
+	FormatJSON       Format = "json"
+	FormatKlog       Format = "klog"
+	FormatNginx      Format = "nginx"
+	FormatCloudWatch Format = "cloudwatch"
+)
+
+// ErrUnknownFormat is returned for format names this package does not parse
+var ErrUnknownFormat = errors.New("unknown log format")
+
+// maxFields bounds how many extra JSON fields are kept per entry
+const maxFields = 20
+
+// now is the clock used to infer the year for formats that omit it
+var now = time.Now
+
+var (
+	klogPattern = regexp.MustCompile(`^([IWEF])(\d{2})(\d{2}) (\d{2}:\d{2}:\d{2}\.\d{6})\s+(\d+) ([^\]]+:\d+)\] ?(.*)$`)
+	// nginxPattern matches the combined and common access log formats
+	nginxPattern = regexp.MustCompile(`^(\S+) \S+ (\S+) \[([^\]]+)\] "([^"]*)" (\d{3}) (\d+|-)(?: "([^"]*)" "([^"]*)")?`)
+	// textTimePattern matches a leading ISO 8601 / RFC 3339 or syslog timestamp
+	textTimePattern    = regexp.MustCompile(`^\[?(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?|[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2})\]?\s*`)
+	logfmtLevelPattern = regexp.MustCompile(`\blevel=["']?([A-Za-z]+)`)
+	textLevelPattern   = regexp.MustCompile(`(?:^|[\s\[(])(FATAL|PANIC|CRITICAL|CRIT|ERROR|ERR|WARNING|WARN|INFO|DEBUG|TRACE)(?:$|[\s\]):])`)
+)
+
+// ParseFormat validates a format name; empty means auto-detection
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case "":
+		return FormatAuto, nil
+	case FormatAuto, FormatText, FormatJSON, FormatKlog, FormatNginx, FormatCloudWatch:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+}
+
+// ParseLines parses log lines in the given format. With FormatAuto each line's format is detected separately.
+// Indented lines in text logs, such as stack traces, are folded into the previous entry.
+// source is used for entries that do not name their own.
+func ParseLines(lines []string, format Format, source string) []models.LogEntry {
+	entries := make([]models.LogEntry, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r\n")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		lineFormat := format
+		if format == FormatAuto || format == FormatCloudWatch {
+			lineFormat = detect(line)
+		}
+		if lineFormat == FormatText && len(entries) > 0 && isContinuation(line) {
+			last := &entries[len(entries)-1]
+			last.Message += "\n" + line
+			continue
+		}
+
+		entry := parseLine(line, lineFormat)
+		if entry.Source == "" {
+			entry.Source = source
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ParseCloudWatch parses CloudWatch Logs events. Event timestamps are used when the message has none,
+// and the log stream names the source when neither the message nor source does.
+func ParseCloudWatch(events []models.CloudWatchEvent, source string) []models.LogEntry {
+	entries := make([]models.LogEntry, 0, len(events))
+	for _, event := range events {
+		message := strings.TrimRight(event.Message, "\r\n")
+		if strings.TrimSpace(message) == "" {
+			continue
+		}
+
+		entry := parseLine(message, detect(message))
+		if entry.Timestamp == nil && event.Timestamp > 0 {
+			ts := time.UnixMilli(event.Timestamp).UTC()
+			entry.Timestamp = &ts
+		}
+		if entry.Source == "" {
+			entry.Source = source
+		}
+		if entry.Source == "" {
+			entry.Source = event.LogStreamName
+		}
+		entry.Format = string(FormatCloudWatch)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Render formats an entry as a single normalized line: timestamp, level, [source] and message
+func Render(entry models.LogEntry) string {
+	var parts []string
+	if entry.Timestamp != nil {
+		parts = append(parts, entry.Timestamp.UTC().Format(time.RFC3339Nano))
+	}
+	if entry.Level != "" {
+		parts = append(parts, strings.ToUpper(string(entry.Level)))
+	}
+	if entry.Source != "" {
+		parts = append(parts, "["+entry.Source+"]")
+	}
+	parts = append(parts, entry.Message)
+	return strings.Join(parts, " ")
+}
+
+// detect guesses a single line's format
+func detect(line string) Format {
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(line), "{"):
+		return FormatJSON
+	case klogPattern.MatchString(line):
+		return FormatKlog
+	case nginxPattern.MatchString(line):
+		return FormatNginx
+	}
+	return FormatText
+}
+
+// parseLine parses a line in a known format, falling back to text when it does not match
+func parseLine(line string, format Format) models.LogEntry {
+	switch format {
+	case FormatJSON:
+		if entry, ok := parseJSON(line); ok {
+			return entry
+		}
+	case FormatKlog:
+		if entry, ok := parseKlog(line); ok {
+			return entry
+		}
+	case FormatNginx:
+		if entry, ok := parseNginx(line); ok {
+			return entry
+		}
+	}
+	return parseText(line)
+}
+
+// parseJSON handles zap, logrus and similar JSON loggers
+func parseJSON(line string) (models.LogEntry, bool) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &data); err != nil {
+		return models.LogEntry{}, false
+	}
+
+	entry := models.LogEntry{Format: string(FormatJSON)}
+	used := make(map[string]bool)
+	take := func(keys ...string) interface{} {
+		for _, k := range keys {
+			if v, ok := data[k]; ok {
+				used[k] = true
+				return v
+			}
+		}
+		return nil
+	}
+
+	entry.Timestamp = jsonTime(take("ts", "time", "timestamp", "@timestamp", "date"))
+	if level, ok := take("level", "severity", "lvl", "log.level").(string); ok {
+		entry.Level = normalizeLevel(level)
+	}
+	if msg := take("msg", "message", "log"); msg != nil {
+		entry.Message = fmt.Sprint(msg)
+	}
+	if src := take("logger", "source", "service", "component", "app"); src != nil {
+		entry.Source = fmt.Sprint(src)
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		if !used[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if len(entry.Fields) == maxFields {
+			break
+		}
+		switch v := data[k].(type) {
+		case string, float64, bool:
+			if entry.Fields == nil {
+				entry.Fields = make(map[string]string)
+			}
+			entry.Fields[k] = fmt.Sprint(v)
+		}
+	}
+	if entry.Message == "" {
+		entry.Message = strings.TrimSpace(line)
+	}
+	return entry, true
+}
+
+// jsonTime reads epoch seconds (zap's default) or an RFC 3339 style string
+func jsonTime(v interface{}) *time.Time {
+	switch t := v.(type) {
+	case float64:
+		sec, frac := math.Modf(t)
+		// Values this large are epoch milliseconds
+		if t > 1e12 {
+			ts := time.UnixMilli(int64(t)).UTC()
+			return &ts
+		}
+		ts := time.Unix(int64(sec), int64(frac*1e9)).UTC()
+		return &ts
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000Z0700", "2006-01-02 15:04:05"} {
+			if ts, err := time.Parse(layout, t); err == nil {
+				ts = ts.UTC()
+				return &ts
+			}
+		}
+	}
+	return nil
+}
+
+// parseKlog handles Kubernetes klog lines, e.g. "E0102 15:04:05.123456    1234 controller.go:42] message"
+func parseKlog(line string) (models.LogEntry, bool) {
+	m := klogPattern.FindStringSubmatch(line)
+	if m == nil {
+		return models.LogEntry{}, false
+	}
+
+	entry := models.LogEntry{
+		Level:   map[string]models.LogLevel{"I": models.LogLevelInfo, "W": models.LogLevelWarn, "E": models.LogLevelError, "F": models.LogLevelFatal}[m[1]],
+		Message: m[7],
+		Format:  string(FormatKlog),
+		Fields:  map[string]string{"thread": m[5], "caller": m[6]},
+	}
+	if ts, err := time.Parse("01 02 15:04:05.000000", m[2]+" "+m[3]+" "+m[4]); err == nil {
+		entry.Timestamp = withYear(ts)
+	}
+	return entry, true
+}
+
+// parseNginx handles nginx access logs; 5xx responses are errors and 4xx warnings
+func parseNginx(line string) (models.LogEntry, bool) {
+	m := nginxPattern.FindStringSubmatch(line)
+	if m == nil {
+		return models.LogEntry{}, false
+	}
+
+	status, _ := strconv.Atoi(m[5])
+	entry := models.LogEntry{
+		Level:   models.LogLevelInfo,
+		Source:  "nginx",
+		Message: fmt.Sprintf("%s %d", m[4], status),
+		Format:  string(FormatNginx),
+		Fields:  map[string]string{"remote_addr": m[1], "status": m[5], "bytes": m[6]},
+	}
+	switch {
+	case status >= 500:
+		entry.Level = models.LogLevelError
+	case status >= 400:
+		entry.Level = models.LogLevelWarn
+	}
+	if m[2] != "-" {
+		entry.Fields["remote_user"] = m[2]
+	}
+	if m[7] != "" && m[7] != "-" {
+		entry.Fields["referer"] = m[7]
+	}
+	if m[8] != "" {
+		entry.Fields["user_agent"] = m[8]
+	}
+	if ts, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[3]); err == nil {
+		ts = ts.UTC()
+		entry.Timestamp = &ts
+	}
+	return entry, true
+}
+
+// parseText reads an optional leading timestamp and a level keyword from a plain line
+func parseText(line string) models.LogEntry {
+	entry := models.LogEntry{Message: line, Format: string(FormatText)}
+
+	if m := textTimePattern.FindStringSubmatch(line); m != nil {
+		if ts := textTime(m[1]); ts != nil {
+			entry.Timestamp = ts
+			entry.Message = line[len(m[0]):]
+		}
+	}
+
+	if m := logfmtLevelPattern.FindStringSubmatch(entry.Message); m != nil {
+		entry.Level = normalizeLevel(m[1])
+	} else if m := textLevelPattern.FindStringSubmatch(entry.Message); m != nil {
+		entry.Level = normalizeLevel(m[1])
+	}
+	return entry
+}
+
+func textTime(value string) *time.Time {
+	value = strings.Replace(value, ",", ".", 1)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999"} {
+		if ts, err := time.Parse(layout, value); err == nil {
+			ts = ts.UTC()
+			return &ts
+		}
+	}
+	if ts, err := time.Parse(time.Stamp, value); err == nil {
+		return withYear(ts)
+	}
+	return nil
+}
+
+// withYear sets the current year on a timestamp from a format without one,
+// using last year when that would put the entry more than a day in the future
+func withYear(ts time.Time) *time.Time {
+	current := now().UTC()
+	withYear := time.Date(current.Year(), ts.Month(), ts.Day(), ts.Hour(), ts.Minute(), ts.Second(), ts.Nanosecond(), time.UTC)
+	if withYear.After(current.Add(24 * time.Hour)) {
+		withYear = withYear.AddDate(-1, 0, 0)
+	}
+	return &withYear
+}
+
+// normalizeLevel maps the level names used by common loggers onto LogLevel
+func normalizeLevel(level string) models.LogLevel {
+	switch strings.ToLower(level) {
+	case "trace", "debug":
+		return models.LogLevelDebug
+	case "info", "information", "notice":
+		return models.LogLevelInfo
+	case "warn", "warning":
+		return models.LogLevelWarn
+	case "error", "err":
+		return models.LogLevelError
+	case "fatal", "panic", "dpanic", "critical", "crit", "emergency", "alert":
+		return models.LogLevelFatal
+	}
+	return ""
+}
+
+// isContinuation reports whether a text line continues the previous entry, as stack trace lines do
+func isContinuation(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "Caused by:")
+}
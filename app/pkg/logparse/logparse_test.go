@@ -0,0 +1,107 @@
+package logparse
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+func TestParseLinesAutoDetect(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	lines := []string{
+		`{"level":"error","ts":1704103200.5,"logger":"payments","caller":"db/pool.go:88","msg":"pool exhausted","active":50}`,
+		`{"level":"warning","time":"2024-01-01T10:00:01Z","msg":"retrying","service":"checkout"}`,
+		`E0101 10:00:02.000000    4242 reflector.go:138] failed to list *v1.Pod: connection refused`,
+		`10.0.0.7 - - [01/Jan/2024:10:00:03 +0000] "POST /api/pay HTTP/1.1" 502 157 "-" "curl/8.4.0"`,
+		`2024-01-01 10:00:04,250 ERROR [main] Unhandled exception`,
+		`	at com.example.Pay.charge(Pay.java:42)`,
+		`Dec 31 23:59:59 host sshd[1]: session opened`,
+		`level=info msg="served request"`,
+		`plain line without metadata`,
+	}
+
+	entries := ParseLines(lines, FormatAuto, "api")
+	if len(entries) != 8 {
+		t.Fatalf("expected 8 entries (stack trace folded), got %d: %+v", len(entries), entries)
+	}
+
+	tests := []struct {
+		format  string
+		level   models.LogLevel
+		source  string
+		message string
+		ts      string
+	}{
+		{"json", models.LogLevelError, "payments", "pool exhausted", "2024-01-01T10:00:00.5Z"},
+		{"json", models.LogLevelWarn, "checkout", "retrying", "2024-01-01T10:00:01Z"},
+		{"klog", models.LogLevelError, "api", "failed to list *v1.Pod: connection refused", "2024-01-01T10:00:02Z"},
+		{"nginx", models.LogLevelError, "nginx", "POST /api/pay HTTP/1.1 502", "2024-01-01T10:00:03Z"},
+		{"text", models.LogLevelError, "api", "ERROR [main] Unhandled exception\n\tat com.example.Pay.charge(Pay.java:42)", "2024-01-01T10:00:04.25Z"},
+		{"text", "", "api", "host sshd[1]: session opened", "2023-12-31T23:59:59Z"},
+		{"text", models.LogLevelInfo, "api", `level=info msg="served request"`, ""},
+		{"text", "", "api", "plain line without metadata", ""},
+	}
+	for i, tt := range tests {
+		e := entries[i]
+		ts := ""
+		if e.Timestamp != nil {
+			ts = e.Timestamp.Format(time.RFC3339Nano)
+		}
+		if e.Format != tt.format || e.Level != tt.level || e.Source != tt.source || e.Message != tt.message || ts != tt.ts {
+			t.Errorf("entry %d: expected %s/%s/%s/%q/%s, got %s/%s/%s/%q/%s",
+				i, tt.format, tt.level, tt.source, tt.message, tt.ts, e.Format, e.Level, e.Source, e.Message, ts)
+		}
+	}
+
+	if entries[0].Fields["caller"] != "db/pool.go:88" || entries[0].Fields["active"] != "50" {
+		t.Errorf("expected extra JSON fields kept, got %v", entries[0].Fields)
+	}
+	if entries[3].Fields["user_agent"] != "curl/8.4.0" {
+		t.Errorf("expected nginx user agent, got %v", entries[3].Fields)
+	}
+}
+
+func TestParseCloudWatch(t *testing.T) {
+	entries := ParseCloudWatch([]models.CloudWatchEvent{
+		{Timestamp: 1704103200000, Message: "START RequestId: 8f2c Version: $LATEST\n", LogStreamName: "2024/01/01/[$LATEST]abc"},
+		{Timestamp: 1704103201000, Message: `{"level":"error","msg":"timeout calling ledger"}`},
+		{Timestamp: 1704103202000, Message: " "},
+	}, "")
+
+	if len(entries) != 2 {
+		t.Fatalf("expected blank events skipped, got %d", len(entries))
+	}
+	if entries[0].Timestamp.Unix() != 1704103200 || entries[0].Source != "2024/01/01/[$LATEST]abc" || entries[0].Format != "cloudwatch" {
+		t.Errorf("expected event timestamp and stream as source, got %+v", entries[0])
+	}
+	if entries[1].Level != models.LogLevelError || entries[1].Message != "timeout calling ledger" {
+		t.Errorf("expected JSON message parsed, got %+v", entries[1])
+	}
+}
+
+func TestParseFormatAndRender(t *testing.T) {
+	if _, err := ParseFormat("syslog"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+	if f, _ := ParseFormat(""); f != FormatAuto {
+		t.Errorf("expected empty format to auto-detect, got %s", f)
+	}
+
+	explicit := ParseLines([]string{`{"msg": "x"}`}, FormatText, "")
+	if explicit[0].Format != "text" {
+		t.Errorf("expected explicit format to skip detection, got %s", explicit[0].Format)
+	}
+
+	ts := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	rendered := Render(models.LogEntry{Timestamp: &ts, Level: models.LogLevelWarn, Source: "api", Message: "slow"})
+	if rendered != "2024-01-01T10:00:00Z WARN [api] slow" {
+		t.Errorf("unexpected rendering %q", rendered)
+	}
+	if Render(models.LogEntry{Message: "bare"}) != "bare" {
+		t.Error("expected entries without metadata to render as the message")
+	}
+}
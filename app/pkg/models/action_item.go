@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+)
+
+// ActionItemStatus represents the progress of a postmortem action item
+type ActionItemStatus string
+
+const (
+	ActionItemOpen       ActionItemStatus = "open"
+	ActionItemInProgress ActionItemStatus = "in_progress"
+	ActionItemDone       ActionItemStatus = "done"
+	ActionItemWontDo     ActionItemStatus = "wont_do"
+)
+
+// ActionItemPriority represents how urgently an action item must be completed
+type ActionItemPriority string
+
+const (
+	ActionItemPriorityHigh   ActionItemPriority = "high"
+	ActionItemPriorityMedium ActionItemPriority = "medium"
+	ActionItemPriorityLow    ActionItemPriority = "low"
+)
+
+// ActionItem is a follow-up task from an incident, tracked until it is done
+type ActionItem struct {
+	ID          string             `json:"id"`
+	IncidentID  string             `json:"incident_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description,omitempty"`
+	Owner       string             `json:"owner,omitempty"`
+	DueDate     *time.Time         `json:"due_date,omitempty"`
+	Priority    ActionItemPriority `json:"priority"`
+	Status      ActionItemStatus   `json:"status"`
+	TicketURL   string             `json:"ticket_url,omitempty"`
+	// Source is "ai" for items promoted from AI-written preventive measures
+	Source      ContentSource `json:"source"`
+	RCARevision int           `json:"rca_revision,omitempty"`
+	Overdue     bool          `json:"overdue"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+	// Issue links the item to a ticket in an issue tracker; TicketURL is set to its URL
+	Issue *ExternalIssue `json:"issue,omitempty"`
+}
+
+// CreateActionItemRequest represents a request to create an action item
+type CreateActionItemRequest struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description,omitempty"`
+	Owner       string             `json:"owner,omitempty"`
+	DueDate     *time.Time         `json:"due_date,omitempty"`
+	Priority    ActionItemPriority `json:"priority,omitempty"`
+	TicketURL   string             `json:"ticket_url,omitempty"`
+}
+
+// UpdateActionItemRequest represents a request to update an action item
+type UpdateActionItemRequest struct {
+	Title       *string             `json:"title,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	Owner       *string             `json:"owner,omitempty"`
+	DueDate     *time.Time          `json:"due_date,omitempty"`
+	Priority    *ActionItemPriority `json:"priority,omitempty"`
+	Status      *ActionItemStatus   `json:"status,omitempty"`
+	TicketURL   *string             `json:"ticket_url,omitempty"`
+}
+
+// PromoteActionItemsRequest represents a request to turn RCA preventive measures into action items
+type PromoteActionItemsRequest struct {
+	// Measures are indexes into the current RCA's preventive measures; empty promotes all of them
+	Measures []int              `json:"measures,omitempty"`
+	Owner    string             `json:"owner,omitempty"`
+	DueDate  *time.Time         `json:"due_date,omitempty"`
+	Priority ActionItemPriority `json:"priority,omitempty"`
+}
+
+// ActionItemFilter selects action items across incidents
+type ActionItemFilter struct {
+	Owner      string
+	Status     *ActionItemStatus
+	IncidentID string
+	// Overdue, when set, keeps only overdue (true) or not overdue (false) items
+	Overdue *bool
+}
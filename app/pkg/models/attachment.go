@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Attachment describes a file uploaded to an incident; the content lives in the blob store
+type Attachment struct {
+	ID          string `json:"id"`
+	IncidentID  string `json:"incident_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	Uploader    string `json:"uploader,omitempty"`
+	// IncludeInAnalysis feeds a text attachment's lines into AI analysis alongside the incident logs
+	IncludeInAnalysis bool      `json:"include_in_analysis"`
+	DownloadURL       string    `json:"download_url,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Key               string    `json:"-"`
+}
+
+// UploadAttachmentRequest carries the metadata sent with an attachment upload
+type UploadAttachmentRequest struct {
+	Filename          string
+	Uploader          string
+	IncludeInAnalysis bool
+}
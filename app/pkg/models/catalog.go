@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// Service is an entry in the service catalog describing who owns a service and how its incidents are handled
+type Service struct {
+	Name        string `json:"name"`
+	Team        string `json:"team"`
+	Description string `json:"description,omitempty"`
+	// Tier ranks business criticality from 1 (most critical) to 3; zero means untiered
+	Tier           int    `json:"tier,omitempty"`
+	OnCallSchedule string `json:"oncall_schedule,omitempty"`
+	// EscalationPolicy pages the service's incidents; without one they are assigned to OnCallSchedule
+	EscalationPolicy string   `json:"escalation_policy,omitempty"`
+	Runbooks         []string `json:"runbooks,omitempty"`
+	// Dependencies name the upstream services this service calls
+	Dependencies []string `json:"dependencies,omitempty"`
+	// SeverityFloor is the lowest severity automatically assigned to incidents on this service
+	SeverityFloor Severity  `json:"severity_floor,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UpdateServiceRequest represents a request to update a catalog entry; omitted fields are unchanged
+type UpdateServiceRequest struct {
+	Team             *string   `json:"team,omitempty"`
+	Description      *string   `json:"description,omitempty"`
+	Tier             *int      `json:"tier,omitempty"`
+	OnCallSchedule   *string   `json:"oncall_schedule,omitempty"`
+	EscalationPolicy *string   `json:"escalation_policy,omitempty"`
+	Runbooks         []string  `json:"runbooks,omitempty"`
+	Dependencies     []string  `json:"dependencies,omitempty"`
+	SeverityFloor    *Severity `json:"severity_floor,omitempty"`
+}
+
+// ServiceCatalog is the file format for loading services at startup
+type ServiceCatalog struct {
+	Services []Service `json:"services"`
+}
+
+// DependencyNode is a service reached while walking the dependency graph
+type DependencyNode struct {
+	Name string `json:"name"`
+	// Depth is 1 for direct dependencies or dependents
+	Depth int    `json:"depth"`
+	Team  string `json:"team,omitempty"`
+	Tier  int    `json:"tier,omitempty"`
+	// Registered is false for dependencies named by a service but missing from the catalog
+	Registered bool `json:"registered"`
+}
+
+// ServiceDependencies is the transitive dependency graph around a service
+type ServiceDependencies struct {
+	Service    string           `json:"service"`
+	Upstream   []DependencyNode `json:"upstream"`
+	Downstream []DependencyNode `json:"downstream"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+)
+
+// ChangeKind classifies a change to a running system
+type ChangeKind string
+
+const (
+	ChangeDeploy      ChangeKind = "deploy"
+	ChangeConfig      ChangeKind = "config"
+	ChangeInfra       ChangeKind = "infrastructure"
+	ChangeFeatureFlag ChangeKind = "feature_flag"
+	ChangeRollback    ChangeKind = "rollback"
+)
+
+// Valid reports whether k is a known change kind
+func (k ChangeKind) Valid() bool {
+	switch k {
+	case ChangeDeploy, ChangeConfig, ChangeInfra, ChangeFeatureFlag, ChangeRollback:
+		return true
+	}
+	return false
+}
+
+// Change is a deploy or other change to a service, reported by CI/CD or a person.
+// Source names the receiver that recorded it, e.g. api or github.
+type Change struct {
+	ID          string     `json:"id"`
+	Service     string     `json:"service"`
+	Version     string     `json:"version,omitempty"`
+	Commit      string     `json:"commit,omitempty"`
+	Author      string     `json:"author,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	Kind        ChangeKind `json:"kind"`
+	Description string     `json:"description,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Source      string     `json:"source"`
+	Timestamp   time.Time  `json:"timestamp"`
+	ReceivedAt  time.Time  `json:"received_at"`
+}
+
+// CreateChangeRequest records a change; Timestamp defaults to now and Kind to deploy
+type CreateChangeRequest struct {
+	Service     string     `json:"service"`
+	Version     string     `json:"version,omitempty"`
+	Commit      string     `json:"commit,omitempty"`
+	Author      string     `json:"author,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	Kind        ChangeKind `json:"kind,omitempty"`
+	Description string     `json:"description,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Timestamp   *time.Time `json:"timestamp,omitempty"`
+}
+
+// ChangeFilter narrows a change listing; zero fields match everything
+type ChangeFilter struct {
+	Service     string
+	Environment string
+	Kind        ChangeKind
+	Since       *time.Time
+	Until       *time.Time
+}
+
+// CorrelatedChange is a change to one of an incident's services around the time it was opened
+type CorrelatedChange struct {
+	Change Change `json:"change"`
+	// MatchedBy explains how the change's service was tied to the incident, e.g. tag:service:checkout
+	MatchedBy string `json:"matched_by"`
+	// MinutesBefore is negative for changes made after the incident was opened
+	MinutesBefore int     `json:"minutes_before"`
+	Score         float64 `json:"score"`
+	// Suspect marks the change most likely to have caused the incident
+	Suspect bool `json:"suspect"`
+}
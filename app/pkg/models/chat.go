@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// ChatRole identifies who authored a chat message
+type ChatRole string
+
+const (
+	ChatRoleUser      ChatRole = "user"
+	ChatRoleAssistant ChatRole = "assistant"
+)
+
+// ChatMessage represents a single message in an incident conversation
+type ChatMessage struct {
+	Role      ChatRole  `json:"role"`
+	Content   string    `json:"content"`
+	Author    string    `json:"author,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Conversation represents the persisted chat history for an incident
+type Conversation struct {
+	IncidentID string        `json:"incident_id"`
+	Messages   []ChatMessage `json:"messages"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// ChatRequest represents a follow-up question about an incident
+type ChatRequest struct {
+	Message string `json:"message"`
+	Author  string `json:"author,omitempty"`
+}
+
+// ChatResponse represents the assistant reply and the updated conversation
+type ChatResponse struct {
+	Reply           ChatMessage   `json:"reply"`
+	Conversation    *Conversation `json:"conversation"`
+	TrimmedMessages int           `json:"trimmed_messages,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// Comment is a responder note on an incident, with a Markdown body.
+// Replies set ParentID to thread under another comment.
+type Comment struct {
+	ID         string     `json:"id"`
+	IncidentID string     `json:"incident_id"`
+	ParentID   string     `json:"parent_id,omitempty"`
+	Author     string     `json:"author"`
+	Body       string     `json:"body"`
+	Mentions   []string   `json:"mentions,omitempty"`
+	KeyFinding bool       `json:"key_finding"`
+	CreatedAt  time.Time  `json:"created_at"`
+	EditedAt   *time.Time `json:"edited_at,omitempty"`
+	// ExternalID is "<tracker>:<comment id>" once the comment exists on the incident's tracker issue,
+	// whether it was pushed there or imported from it
+	ExternalID string     `json:"external_id,omitempty"`
+	Replies    []*Comment `json:"replies,omitempty"`
+}
+
+// KeyFinding is a comment responders flagged as important, shown on the incident itself
+type KeyFinding struct {
+	CommentID string    `json:"comment_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateCommentRequest represents a request to comment on an incident
+type CreateCommentRequest struct {
+	Author     string `json:"author"`
+	Body       string `json:"body"`
+	ParentID   string `json:"parent_id,omitempty"`
+	KeyFinding bool   `json:"key_finding,omitempty"`
+}
+
+// UpdateCommentRequest represents a request to edit a comment or change its key finding flag
+type UpdateCommentRequest struct {
+	Body       *string `json:"body,omitempty"`
+	KeyFinding *bool   `json:"key_finding,omitempty"`
+}
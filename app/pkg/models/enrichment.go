@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+)
+
+// EnrichmentStatus reports whether an enricher could gather context
+type EnrichmentStatus string
+
+const (
+	EnrichmentOK     EnrichmentStatus = "ok"
+	EnrichmentFailed EnrichmentStatus = "failed"
+)
+
+// Enrichment is context gathered about an incident from an external system
+type Enrichment struct {
+	Enricher  string           `json:"enricher"`
+	Status    EnrichmentStatus `json:"status"`
+	Error     string           `json:"error,omitempty"`
+	FetchedAt time.Time        `json:"fetched_at"`
+	// Summary is the compact text form given to the model
+	Summary    string             `json:"summary,omitempty"`
+	Kubernetes *KubernetesContext `json:"kubernetes,omitempty"`
+	Metrics    []MetricSeries     `json:"metrics,omitempty"`
+}
+
+// KubernetesContext describes the workload an incident is about
+type KubernetesContext struct {
+	Namespace       string            `json:"namespace"`
+	WorkloadKind    string            `json:"workload_kind,omitempty"`
+	WorkloadName    string            `json:"workload_name,omitempty"`
+	Replicas        int               `json:"replicas,omitempty"`
+	ReadyReplicas   int               `json:"ready_replicas,omitempty"`
+	UpdatedReplicas int               `json:"updated_replicas,omitempty"`
+	Pods            []PodStatus       `json:"pods"`
+	Events          []KubernetesEvent `json:"events"`
+	Rollouts        []Rollout         `json:"rollouts,omitempty"`
+}
+
+// PodStatus summarizes a pod's health
+type PodStatus struct {
+	Name       string            `json:"name"`
+	Phase      string            `json:"phase"`
+	Node       string            `json:"node,omitempty"`
+	Ready      bool              `json:"ready"`
+	Restarts   int               `json:"restarts"`
+	Containers []ContainerStatus `json:"containers"`
+}
+
+// ContainerStatus summarizes a container's current and previous state
+type ContainerStatus struct {
+	Name         string `json:"name"`
+	Image        string `json:"image"`
+	Ready        bool   `json:"ready"`
+	RestartCount int    `json:"restart_count"`
+	// State is running, waiting or terminated; Reason explains waiting and terminated states
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+	// LastState records why the previous instance of the container stopped, e.g. OOMKilled
+	LastReason     string     `json:"last_reason,omitempty"`
+	LastExitCode   int        `json:"last_exit_code,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+}
+
+// KubernetesEvent is a recent event for the workload or its pods
+type KubernetesEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Object   string    `json:"object"`
+	Message  string    `json:"message"`
+	Count    int       `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Rollout is one revision of a Deployment, newest first
+type Rollout struct {
+	Revision  int       `json:"revision"`
+	Name      string    `json:"name"`
+	Images    []string  `json:"images"`
+	Replicas  int       `json:"replicas"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MetricSeries summarizes one time series around the time an incident was opened
+type MetricSeries struct {
+	// Name is the configured query name; Labels identify the series within the query result
+	Name   string            `json:"name"`
+	Query  string            `json:"query"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Start  time.Time         `json:"start"`
+	End    time.Time         `json:"end"`
+	Points int               `json:"points"`
+	Min    float64           `json:"min"`
+	Max    float64           `json:"max"`
+	Avg    float64           `json:"avg"`
+	P50    float64           `json:"p50"`
+	P95    float64           `json:"p95"`
+	Last   float64           `json:"last"`
+	// ChangePoints are where the series level shifted, in time order
+	ChangePoints []ChangePoint `json:"change_points,omitempty"`
+}
+
+// ChangePoint marks a shift in a series' mean level
+type ChangePoint struct {
+	At     time.Time `json:"at"`
+	Before float64   `json:"before"`
+	After  float64   `json:"after"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// EventType names something that happened to an incident
+type EventType string
+
+const (
+	EventIncidentCreated         EventType = "incident.created"
+	EventIncidentUpdated         EventType = "incident.updated"
+	EventIncidentSeverityChanged EventType = "incident.severity_changed"
+	EventIncidentResolved        EventType = "incident.resolved"
+	EventIncidentAcknowledged    EventType = "incident.acknowledged"
+	EventIncidentEscalated       EventType = "incident.escalated"
+	EventIncidentSLAAtRisk       EventType = "incident.sla_at_risk"
+	EventIncidentSLABreached     EventType = "incident.sla_breached"
+	EventAnalysisCompleted       EventType = "analysis.completed"
+	EventRCAGenerated            EventType = "rca.generated"
+)
+
+// EventTypes lists every event type in a stable order
+var EventTypes = []EventType{
+	EventIncidentCreated,
+	EventIncidentUpdated,
+	EventIncidentSeverityChanged,
+	EventIncidentResolved,
+	EventIncidentAcknowledged,
+	EventIncidentEscalated,
+	EventIncidentSLAAtRisk,
+	EventIncidentSLABreached,
+	EventAnalysisCompleted,
+	EventRCAGenerated,
+}
+
+// Valid reports whether t is a known event type
+func (t EventType) Valid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is published to subscribers when an incident changes
+type Event struct {
+	ID   string    `json:"id"`
+	Type EventType `json:"type"`
+	At   time.Time `json:"at"`
+	// Incident is a snapshot taken when the event was published
+	Incident *Incident `json:"incident"`
+	// PreviousSeverity is set on incident.severity_changed events
+	PreviousSeverity Severity `json:"previous_severity,omitempty"`
+	// SLATarget is set on incident.sla_at_risk and incident.sla_breached events
+	SLATarget SLATarget `json:"sla_target,omitempty"`
+}
@@ -42,6 +42,31 @@ type Incident struct {
 	ResolvedAt  *time.Time             `json:"resolved_at,omitempty"`
 	AIAnalysis  *AIAnalysis            `json:"ai_analysis,omitempty"`
 	RCADocument *RCADocument           `json:"rca_document,omitempty"`
+
+	// SeverityRule names the rule that set Severity when it was not provided at creation
+	SeverityRule           string                  `json:"severity_rule,omitempty"`
+	SeveritySource         SeveritySource          `json:"severity_source,omitempty"`
+	SeverityClassification *SeverityClassification `json:"severity_classification,omitempty"`
+	RCAReview              *RCAReview              `json:"rca_review,omitempty"`
+	KeyFindings            []KeyFinding            `json:"key_findings,omitempty"`
+	// LogEntries are Logs parsed into structured entries
+	LogEntries []LogEntry `json:"log_entries,omitempty"`
+	// Enrichments hold context from external systems, keyed by enricher name
+	Enrichments map[string]*Enrichment `json:"enrichments,omitempty"`
+	// Service links the incident to a service catalog entry
+	Service string `json:"service,omitempty"`
+	// SeverityAdjustment explains a severity raised by the service's tier or severity floor
+	SeverityAdjustment string `json:"severity_adjustment,omitempty"`
+	// Escalation tracks paging through the incident's escalation policy until it is acknowledged
+	Escalation     *EscalationState `json:"escalation,omitempty"`
+	AcknowledgedAt *time.Time       `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string           `json:"acknowledged_by,omitempty"`
+	// Issue links the incident to a ticket in an issue tracker
+	Issue *ExternalIssue `json:"issue,omitempty"`
+	// SuppressionID links an incident a maintenance window or silence downgraded to its audit entry
+	SuppressionID string `json:"suppression_id,omitempty"`
+	// SLA tracks time to acknowledge and resolve against the policy for the incident's severity
+	SLA *IncidentSLA `json:"sla,omitempty"`
 }
 
 // AIAnalysis represents AI-generated analysis for an incident
@@ -54,6 +79,36 @@ type AIAnalysis struct {
 	GeneratedAt        time.Time `json:"generated_at"`
 	Model              string    `json:"model"`
 	Provider           string    `json:"provider"`
+	// Tool-assisted analyses record every tool the model called for auditability
+	ToolTrace  []ToolCallRecord `json:"tool_trace,omitempty"`
+	Steps      int              `json:"steps,omitempty"`
+	TokensUsed int              `json:"tokens_used,omitempty"`
+	StopReason string           `json:"stop_reason,omitempty"`
+	// Revision numbering and inputs identify this analysis among the incident's history
+	Revision      int    `json:"revision,omitempty"`
+	PromptVersion string `json:"prompt_version,omitempty"`
+	InputsHash    string `json:"inputs_hash,omitempty"`
+}
+
+// ToolCallRecord represents a single tool invocation made during AI analysis
+type ToolCallRecord struct {
+	Step       int       `json:"step"`
+	Tool       string    `json:"tool"`
+	Arguments  string    `json:"arguments"`
+	Output     string    `json:"output"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Deployment represents a deploy reported by a configured deploy source
+type Deployment struct {
+	Service     string    `json:"service"`
+	Version     string    `json:"version,omitempty"`
+	Commit      string    `json:"commit,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	DeployedAt  time.Time `json:"deployed_at"`
 }
 
 // RCADocument represents a root cause analysis document
@@ -67,6 +122,13 @@ type RCADocument struct {
 	GeneratedAt         time.Time `json:"generated_at"`
 	Model               string    `json:"model"`
 	Provider            string    `json:"provider"`
+	// Revision numbering and inputs identify this document among the incident's history
+	Revision      int    `json:"revision,omitempty"`
+	PromptVersion string `json:"prompt_version,omitempty"`
+	InputsHash    string `json:"inputs_hash,omitempty"`
+	// EditedBy is set on revisions created by a human edit
+	EditedBy    string                        `json:"edited_by,omitempty"`
+	Attribution map[string]SectionAttribution `json:"attribution,omitempty"`
 }
 
 // CreateIncidentRequest represents a request to create an incident
@@ -79,6 +141,8 @@ type CreateIncidentRequest struct {
 	Tags        []string               `json:"tags,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 	AssignedTo  string                 `json:"assigned_to,omitempty"`
+	// Service names a catalog entry; when empty it is inferred from service tags and metadata
+	Service string `json:"service,omitempty"`
 }
 
 // UpdateIncidentRequest represents a request to update an incident
@@ -91,6 +155,7 @@ type UpdateIncidentRequest struct {
 	Tags        []string               `json:"tags,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 	AssignedTo  *string                `json:"assigned_to,omitempty"`
+	Service     *string                `json:"service,omitempty"`
 }
 
 // LogSummarizeRequest represents a request to summarize logs
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+)
+
+// IssueState is an issue's progress, normalized across issue trackers
+type IssueState string
+
+const (
+	IssueOpen       IssueState = "open"
+	IssueInProgress IssueState = "in_progress"
+	IssueDone       IssueState = "done"
+)
+
+// ExternalIssue links an incident or action item to an issue in an external tracker
+type ExternalIssue struct {
+	Tracker string `json:"tracker"`
+	Key     string `json:"key"`
+	URL     string `json:"url,omitempty"`
+	// SyncedState is the state both sides had after the last sync; changes on either side are measured from it
+	SyncedState IssueState `json:"synced_state"`
+	SyncedAt    time.Time  `json:"synced_at"`
+	// LastError is the most recent sync failure, cleared by the next successful sync
+	LastError string          `json:"last_error,omitempty"`
+	Conflicts []IssueConflict `json:"conflicts,omitempty"`
+}
+
+// IssueConflict records a sync where both sides changed state since the last sync
+type IssueConflict struct {
+	At       time.Time  `json:"at"`
+	Local    IssueState `json:"local"`
+	Remote   IssueState `json:"remote"`
+	Resolved IssueState `json:"resolved"`
+}
+
+// TrackerIssue is an issue as read from a tracker
+type TrackerIssue struct {
+	Key      string           `json:"key"`
+	URL      string           `json:"url,omitempty"`
+	State    IssueState       `json:"state"`
+	Comments []TrackerComment `json:"comments,omitempty"`
+}
+
+// TrackerComment is a comment on a tracker issue
+type TrackerComment struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewTrackerIssue is an issue to open in a tracker
+type NewTrackerIssue struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// TrackerEvent is a change reported by a tracker webhook. State is empty when the state did not change,
+// and Comment is set for new comments.
+type TrackerEvent struct {
+	Key     string
+	State   IssueState
+	Comment *TrackerComment
+}
+
+// LinkIssueRequest represents a request to open a tracker issue for an incident or action item
+type LinkIssueRequest struct {
+	// Tracker names a configured tracker; empty uses the first one
+	Tracker string `json:"tracker,omitempty"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// LogLevel is a normalized log severity
+type LogLevel string
+
+const (
+	LogLevelDebug LogLevel = "debug"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+	LogLevelFatal LogLevel = "fatal"
+)
+
+// Rank orders levels from least to most severe; unknown levels rank lowest
+func (l LogLevel) Rank() int {
+	switch l {
+	case LogLevelDebug:
+		return 1
+	case LogLevelInfo:
+		return 2
+	case LogLevelWarn:
+		return 3
+	case LogLevelError:
+		return 4
+	case LogLevelFatal:
+		return 5
+	}
+	return 0
+}
+
+// LogEntry is a log line parsed into structured fields
+type LogEntry struct {
+	Timestamp *time.Time        `json:"timestamp,omitempty"`
+	Level     LogLevel          `json:"level,omitempty"`
+	Source    string            `json:"source,omitempty"`
+	Message   string            `json:"message"`
+	Format    string            `json:"format"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+// CloudWatchEvent is one event from `aws logs filter-log-events` or `get-log-events` output
+type CloudWatchEvent struct {
+	Timestamp     int64  `json:"timestamp"`
+	Message       string `json:"message"`
+	LogStreamName string `json:"logStreamName,omitempty"`
+}
+
+// AppendLogsRequest represents a request to append logs to an incident.
+// CloudWatch output can be posted as-is since its events are read from "events".
+type AppendLogsRequest struct {
+	Lines  []string          `json:"lines,omitempty"`
+	Events []CloudWatchEvent `json:"events,omitempty"`
+	// Format is auto (default), text, json, klog, nginx or cloudwatch
+	Format string `json:"format,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+// AppendLogsResponse reports the entries parsed from an append request
+type AppendLogsResponse struct {
+	Appended int        `json:"appended"`
+	Total    int        `json:"total"`
+	Entries  []LogEntry `json:"entries"`
+}
+
+// LogEntryFilter selects an incident's log entries
+type LogEntryFilter struct {
+	// MinLevel keeps entries at or above this level
+	MinLevel LogLevel
+	Source   string
+}
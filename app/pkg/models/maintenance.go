@@ -0,0 +1,137 @@
+package models
+
+import (
+	"time"
+)
+
+// SuppressionAction is what a maintenance window or silence does to the incidents it matches
+type SuppressionAction string
+
+const (
+	// SuppressionSuppress drops matching incidents, recording only an audit entry
+	SuppressionSuppress SuppressionAction = "suppress"
+	// SuppressionDowngrade opens matching incidents at a lower severity without paging anyone
+	SuppressionDowngrade SuppressionAction = "downgrade"
+)
+
+// RecurrenceFrequency sets how often a maintenance window repeats
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily  RecurrenceFrequency = "daily"
+	RecurrenceWeekly RecurrenceFrequency = "weekly"
+)
+
+// Recurrence repeats a maintenance window at the same wall-clock time in the window's time zone
+type Recurrence struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	// Weekdays limits a weekly window to certain days; it defaults to the weekday of the first occurrence
+	Weekdays []string `json:"weekdays,omitempty"`
+	// Until is when the last occurrence may start; the window repeats indefinitely without it
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// MaintenanceWindow suppresses or downgrades incidents for planned work on services, tags or sources.
+// An incident matches when it matches every non-empty list, and any entry within a list.
+type MaintenanceWindow struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Services    []string `json:"services,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Sources     []string `json:"sources,omitempty"`
+	// StartsAt and EndsAt bound the first occurrence; recurring windows repeat it
+	StartsAt   time.Time         `json:"starts_at"`
+	EndsAt     time.Time         `json:"ends_at"`
+	TimeZone   string            `json:"time_zone,omitempty"`
+	Recurrence *Recurrence       `json:"recurrence,omitempty"`
+	Action     SuppressionAction `json:"action"`
+	// Severity is what downgraded incidents are lowered to; it defaults to low
+	Severity  Severity  `json:"severity,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Active and the current or next occurrence are computed when the window is read;
+	// the occurrence is empty once the window is over
+	Active          bool       `json:"active"`
+	OccurrenceStart *time.Time `json:"occurrence_start,omitempty"`
+	OccurrenceEnd   *time.Time `json:"occurrence_end,omitempty"`
+}
+
+// MatchOperator compares a silence matcher's value with an incident field
+type MatchOperator string
+
+const (
+	MatchEqual     MatchOperator = "="
+	MatchNotEqual  MatchOperator = "!="
+	MatchRegexp    MatchOperator = "=~"
+	MatchNotRegexp MatchOperator = "!~"
+)
+
+// Matcher tests one incident field: title, source, service, severity, tag, or otherwise a metadata key.
+// Comparisons are case-insensitive and regular expressions must match the whole value.
+type Matcher struct {
+	Name     string        `json:"name"`
+	Value    string        `json:"value"`
+	Operator MatchOperator `json:"operator,omitempty"`
+}
+
+// SilenceState is computed from a silence's start and end
+type SilenceState string
+
+const (
+	SilencePending SilenceState = "pending"
+	SilenceActive  SilenceState = "active"
+	SilenceExpired SilenceState = "expired"
+)
+
+// Silence suppresses or downgrades incidents matching all of its matchers until it expires
+type Silence struct {
+	ID        string            `json:"id"`
+	Matchers  []Matcher         `json:"matchers"`
+	Comment   string            `json:"comment"`
+	CreatedBy string            `json:"created_by"`
+	Action    SuppressionAction `json:"action"`
+	Severity  Severity          `json:"severity,omitempty"`
+	// StartsAt defaults to the time the silence is created; EndsAt is when it expires
+	StartsAt  time.Time    `json:"starts_at"`
+	EndsAt    time.Time    `json:"ends_at"`
+	State     SilenceState `json:"state"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// SuppressionMatchType identifies what kind of rule matched a suppressed or downgraded incident
+type SuppressionMatchType string
+
+const (
+	MatchedMaintenanceWindow SuppressionMatchType = "maintenance_window"
+	MatchedSilence           SuppressionMatchType = "silence"
+)
+
+// Suppression is the audit entry for an incident a maintenance window or silence suppressed or downgraded
+type Suppression struct {
+	ID     string            `json:"id"`
+	Action SuppressionAction `json:"action"`
+	// MatchedBy is the ID of the window or silence, and MatchedName its name or comment
+	MatchedType SuppressionMatchType `json:"matched_type"`
+	MatchedBy   string               `json:"matched_by"`
+	MatchedName string               `json:"matched_name,omitempty"`
+	Title       string               `json:"title"`
+	Source      string               `json:"source,omitempty"`
+	Service     string               `json:"service,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	// Severity is what the incident would have been opened at
+	Severity     Severity `json:"severity"`
+	DowngradedTo Severity `json:"downgraded_to,omitempty"`
+	// IncidentID is set for downgraded incidents, which are still opened
+	IncidentID string    `json:"incident_id,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// SuppressionFilter narrows the suppression audit log
+type SuppressionFilter struct {
+	// MatchedBy limits the log to one window or silence
+	MatchedBy string
+	Limit     int
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationStatus represents the delivery state of a notification
+type NotificationStatus string
+
+const (
+	NotificationPending   NotificationStatus = "pending"
+	NotificationDelivered NotificationStatus = "delivered"
+	// NotificationDeadLetter marks deliveries that failed every attempt; they can be retried by hand
+	NotificationDeadLetter NotificationStatus = "dead_letter"
+)
+
+// NotificationDelivery records sending one event to one notification channel
+type NotificationDelivery struct {
+	ID            string             `json:"id"`
+	IncidentID    string             `json:"incident_id"`
+	EventID       string             `json:"event_id"`
+	EventType     EventType          `json:"event_type"`
+	Channel       string             `json:"channel"`
+	Route         string             `json:"route"`
+	Title         string             `json:"title"`
+	Status        NotificationStatus `json:"status"`
+	Attempts      int                `json:"attempts"`
+	LastError     string             `json:"last_error,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	LastAttemptAt *time.Time         `json:"last_attempt_at,omitempty"`
+	DeliveredAt   *time.Time         `json:"delivered_at,omitempty"`
+}
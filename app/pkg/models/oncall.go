@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+)
+
+// RotationType sets how often a schedule layer hands off to the next user
+type RotationType string
+
+const (
+	RotationDaily  RotationType = "daily"
+	RotationWeekly RotationType = "weekly"
+	// RotationCustom hands off every ShiftDays days
+	RotationCustom RotationType = "custom"
+)
+
+// Schedule is an on-call rotation made of layers and overrides, evaluated in its time zone
+type Schedule struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// TimeZone is an IANA name such as Europe/London; handoffs follow its wall clock across DST changes
+	TimeZone string          `json:"time_zone"`
+	Layers   []ScheduleLayer `json:"layers"`
+	// Overrides replace whoever is on call for their period, whatever the layers say
+	Overrides []ScheduleOverride `json:"overrides,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// ScheduleLayer rotates through users; later layers take precedence over earlier ones while active
+type ScheduleLayer struct {
+	Name      string       `json:"name"`
+	Users     []string     `json:"users"`
+	Rotation  RotationType `json:"rotation"`
+	ShiftDays int          `json:"shift_days,omitempty"`
+	// StartDate (YYYY-MM-DD) and HandoffTime (HH:MM) place the first shift in the schedule's time zone
+	StartDate   string `json:"start_date"`
+	HandoffTime string `json:"handoff_time"`
+	// Restriction limits the layer to certain days and hours, e.g. a business-hours layer over a 24x7 one
+	Restriction *LayerRestriction `json:"restriction,omitempty"`
+}
+
+// LayerRestriction limits when a layer is on call; Start after End spans midnight
+type LayerRestriction struct {
+	Weekdays []string `json:"weekdays,omitempty"`
+	Start    string   `json:"start,omitempty"`
+	End      string   `json:"end,omitempty"`
+}
+
+// ScheduleOverride puts a user on call for a fixed period
+type ScheduleOverride struct {
+	ID        string    `json:"id"`
+	User      string    `json:"user"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateOverrideRequest represents a request to add a schedule override
+type CreateOverrideRequest struct {
+	User   string    `json:"user"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// OnCall is who is on call for a schedule at a point in time
+type OnCall struct {
+	Schedule string `json:"schedule"`
+	// User is empty when no layer or override covers the time
+	User  string `json:"user,omitempty"`
+	Layer string `json:"layer,omitempty"`
+	// OverrideID is set when an override rather than a layer put User on call
+	OverrideID string     `json:"override_id,omitempty"`
+	ShiftStart *time.Time `json:"shift_start,omitempty"`
+	ShiftEnd   *time.Time `json:"shift_end,omitempty"`
+}
+
+// EscalationTargetType identifies what an escalation target names
+type EscalationTargetType string
+
+const (
+	// EscalationTargetSchedule notifies whoever is on call for the named schedule
+	EscalationTargetSchedule EscalationTargetType = "schedule"
+	EscalationTargetUser     EscalationTargetType = "user"
+)
+
+// EscalationPolicy says who to notify about an incident and when to escalate if nobody acknowledges it
+type EscalationPolicy struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Levels      []EscalationLevel `json:"levels"`
+	// Repeat is how many more times to run through the levels after the last one goes unacknowledged
+	Repeat    int       `json:"repeat,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EscalationLevel is a set of targets notified together
+type EscalationLevel struct {
+	Targets []EscalationTarget `json:"targets"`
+	// DelayMinutes is how long to wait for an acknowledgement before escalating to the next level
+	DelayMinutes int `json:"delay_minutes"`
+}
+
+// EscalationTarget is a user or schedule notified at an escalation level
+type EscalationTarget struct {
+	Type EscalationTargetType `json:"type"`
+	Name string               `json:"name"`
+}
+
+// EscalationState tracks an incident's progress through its escalation policy
+type EscalationState struct {
+	Policy string `json:"policy"`
+	// Level is the 1-based level last notified; Cycle counts repeats of the whole policy from zero
+	Level            int        `json:"level"`
+	Cycle            int        `json:"cycle"`
+	NotifiedAt       time.Time  `json:"notified_at"`
+	NextEscalationAt *time.Time `json:"next_escalation_at,omitempty"`
+	// Exhausted is set once every level and repeat has been notified without an acknowledgement
+	Exhausted bool              `json:"exhausted,omitempty"`
+	History   []EscalationEvent `json:"history"`
+}
+
+// EscalationEvent records the users notified at one escalation step
+type EscalationEvent struct {
+	At    time.Time `json:"at"`
+	Level int       `json:"level"`
+	Cycle int       `json:"cycle"`
+	Users []string  `json:"users"`
+}
+
+// AcknowledgeRequest represents a responder acknowledging an incident
+type AcknowledgeRequest struct {
+	User string `json:"user"`
+}
+
+// OnCallConfig is the file format for loading schedules and escalation policies at startup
+type OnCallConfig struct {
+	Schedules          []Schedule         `json:"schedules"`
+	EscalationPolicies []EscalationPolicy `json:"escalation_policies"`
+}
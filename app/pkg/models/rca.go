@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+)
+
+// RCA document sections that can be edited, attributed and commented on
+const (
+	RCASectionTimeline            = "timeline"
+	RCASectionRootCause           = "root_cause"
+	RCASectionImpact              = "impact"
+	RCASectionImmediateResolution = "immediate_resolution"
+	RCASectionPreventiveMeasures  = "preventive_measures"
+	RCASectionLessonsLearned      = "lessons_learned"
+)
+
+// RCASections lists every RCA section in document order
+var RCASections = []string{
+	RCASectionTimeline,
+	RCASectionRootCause,
+	RCASectionImpact,
+	RCASectionImmediateResolution,
+	RCASectionPreventiveMeasures,
+	RCASectionLessonsLearned,
+}
+
+// ContentSource identifies whether content was machine-generated or written by a person
+type ContentSource string
+
+const (
+	ContentSourceAI    ContentSource = "ai"
+	ContentSourceHuman ContentSource = "human"
+)
+
+// SectionAttribution records who last wrote an RCA section
+type SectionAttribution struct {
+	Source    ContentSource `json:"source"`
+	Author    string        `json:"author"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// RCAStatus represents the review state of an incident's RCA document
+type RCAStatus string
+
+const (
+	RCAStatusDraft     RCAStatus = "draft"
+	RCAStatusInReview  RCAStatus = "in_review"
+	RCAStatusApproved  RCAStatus = "approved"
+	RCAStatusPublished RCAStatus = "published"
+)
+
+// RCAReview tracks the review workflow of an incident's RCA document
+type RCAReview struct {
+	Status    RCAStatus     `json:"status"`
+	Reviewers []string      `json:"reviewers"`
+	Approvals []RCAApproval `json:"approvals"`
+	Comments  []RCAComment  `json:"comments"`
+	// PublishedRevision is the RCA revision that was published
+	PublishedRevision int        `json:"published_revision,omitempty"`
+	PublishedBy       string     `json:"published_by,omitempty"`
+	PublishedAt       *time.Time `json:"published_at,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// RCAApproval records a reviewer approving a specific RCA revision
+type RCAApproval struct {
+	Reviewer   string    `json:"reviewer"`
+	Revision   int       `json:"revision"`
+	Comment    string    `json:"comment,omitempty"`
+	ApprovedAt time.Time `json:"approved_at"`
+}
+
+// RCAComment is a review comment anchored to an RCA section
+type RCAComment struct {
+	ID        string    `json:"id"`
+	Section   string    `json:"section"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Revision  int       `json:"revision"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RCAPatchRequest represents a human edit of one or more RCA sections; omitted sections are unchanged
+type RCAPatchRequest struct {
+	Author              string   `json:"author"`
+	Timeline            []string `json:"timeline,omitempty"`
+	RootCause           *string  `json:"root_cause,omitempty"`
+	Impact              *string  `json:"impact,omitempty"`
+	ImmediateResolution *string  `json:"immediate_resolution,omitempty"`
+	PreventiveMeasures  []string `json:"preventive_measures,omitempty"`
+	LessonsLearned      []string `json:"lessons_learned,omitempty"`
+}
+
+// RCAReviewersRequest represents a request to name the RCA reviewers
+type RCAReviewersRequest struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+// RCAStatusRequest represents a request to move the RCA to another review state
+type RCAStatusRequest struct {
+	Status RCAStatus `json:"status"`
+	Actor  string    `json:"actor"`
+}
+
+// RCAApprovalRequest represents a reviewer approving the current RCA revision
+type RCAApprovalRequest struct {
+	Reviewer string `json:"reviewer"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// RCACommentRequest represents a review comment on an RCA section
+type RCACommentRequest struct {
+	Section string `json:"section"`
+	Author  string `json:"author"`
+	Body    string `json:"body"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// AnalysisHistory lists every AI analysis generated for an incident, oldest first
+type AnalysisHistory struct {
+	IncidentID string       `json:"incident_id"`
+	Current    int          `json:"current"`
+	Revisions  []AIAnalysis `json:"revisions"`
+}
+
+// RCAHistory lists every RCA document generated for an incident, oldest first
+type RCAHistory struct {
+	IncidentID string        `json:"incident_id"`
+	Current    int           `json:"current"`
+	Revisions  []RCADocument `json:"revisions"`
+}
+
+// FieldChange describes how one field differs between two revisions.
+// Text fields report From and To; list fields report the items added and removed.
+type FieldChange struct {
+	Field   string   `json:"field"`
+	From    string   `json:"from,omitempty"`
+	To      string   `json:"to,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// RevisionDiff is a field-level comparison of two revisions
+type RevisionDiff struct {
+	IncidentID string        `json:"incident_id"`
+	From       int           `json:"from"`
+	To         int           `json:"to"`
+	Changes    []FieldChange `json:"changes"`
+}
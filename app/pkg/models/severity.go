@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+)
+
+// SeveritySource identifies what set an incident's effective severity
+type SeveritySource string
+
+const (
+	SeveritySourceUser SeveritySource = "user"
+	SeveritySourceRule SeveritySource = "rule"
+	SeveritySourceAI   SeveritySource = "ai"
+)
+
+// Valid reports whether s is one of the assignable severities; unknown is not assignable
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow:
+		return true
+	}
+	return false
+}
+
+// Rank orders severities from least to most severe; unknown ranks lowest
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	}
+	return 0
+}
+
+// ClassificationStatus represents the state of an asynchronous AI severity classification
+type ClassificationStatus string
+
+const (
+	ClassificationPending   ClassificationStatus = "pending"
+	ClassificationCompleted ClassificationStatus = "completed"
+	ClassificationFailed    ClassificationStatus = "failed"
+)
+
+// SeverityClassification records the AI severity suggestion made at creation time
+type SeverityClassification struct {
+	Status     ClassificationStatus `json:"status"`
+	Suggested  Severity             `json:"suggested,omitempty"`
+	Confidence float64              `json:"confidence,omitempty"`
+	Reasoning  string               `json:"reasoning,omitempty"`
+	// Fallback is the rules-engine severity kept when the AI call fails or times out
+	Fallback Severity `json:"fallback"`
+	// Applied reports whether the suggestion became the effective severity
+	Applied     bool              `json:"applied"`
+	Error       string            `json:"error,omitempty"`
+	Model       string            `json:"model,omitempty"`
+	Provider    string            `json:"provider,omitempty"`
+	RequestedAt time.Time         `json:"requested_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+	Feedback    *SeverityFeedback `json:"feedback,omitempty"`
+}
+
+// SeverityFeedback represents a responder's verdict on an AI severity suggestion
+type SeverityFeedback struct {
+	Accepted bool `json:"accepted"`
+	// Corrected is the severity responders settled on
+	Corrected Severity  `json:"corrected"`
+	Reviewer  string    `json:"reviewer,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SeverityFeedbackRequest represents a request to accept or reject an AI severity suggestion
+type SeverityFeedbackRequest struct {
+	Accepted bool `json:"accepted"`
+	// Severity is the correct severity when rejecting; omitted means revert to the rules severity
+	Severity *Severity `json:"severity,omitempty"`
+	Reviewer string    `json:"reviewer,omitempty"`
+	Comment  string    `json:"comment,omitempty"`
+}
+
+// SeverityAccuracyReport summarises responder feedback on AI severity suggestions
+type SeverityAccuracyReport struct {
+	Suggestions    int     `json:"suggestions"`
+	Failed         int     `json:"failed"`
+	Reviewed       int     `json:"reviewed"`
+	Accepted       int     `json:"accepted"`
+	Rejected       int     `json:"rejected"`
+	AcceptanceRate float64 `json:"acceptance_rate"`
+	// Confusion counts suggested severity -> corrected severity for reviewed suggestions
+	Confusion   map[Severity]map[Severity]int `json:"confusion"`
+	GeneratedAt time.Time                     `json:"generated_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// SLATarget names a response time target
+type SLATarget string
+
+const (
+	SLATargetAcknowledge SLATarget = "acknowledge"
+	SLATargetResolve     SLATarget = "resolve"
+)
+
+// SLAState is how an incident is doing against one SLA target
+type SLAState string
+
+const (
+	SLAOnTrack  SLAState = "on_track"
+	SLAAtRisk   SLAState = "at_risk"
+	SLABreached SLAState = "breached"
+	SLAMet      SLAState = "met"
+)
+
+// SLAPolicy sets the response time targets for incidents of one severity. Durations are strings
+// such as "15m" or "4h"; a policy needs at least one target.
+type SLAPolicy struct {
+	Severity          Severity `json:"severity"`
+	TimeToAcknowledge string   `json:"time_to_acknowledge,omitempty"`
+	TimeToResolve     string   `json:"time_to_resolve,omitempty"`
+	// WarnBefore is how long before a breach the incident is flagged at risk; it defaults to a fifth of each target
+	WarnBefore string    `json:"warn_before,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SLAConfig is the on-disk format for SLA policies
+type SLAConfig struct {
+	Policies []SLAPolicy `json:"policies"`
+}
+
+// SLATimer tracks one target from the incident's creation until it is acknowledged or resolved
+type SLATimer struct {
+	DueAt time.Time `json:"due_at"`
+	// RemainingSeconds is the time left before DueAt, negative once breached. Risk is the share of the
+	// allowed time used, so 1 or more means breached. Both are measured when the timer stopped, or now.
+	RemainingSeconds int64      `json:"remaining_seconds"`
+	Risk             float64    `json:"risk"`
+	State            SLAState   `json:"state"`
+	AtRiskAt         *time.Time `json:"at_risk_at,omitempty"`
+	BreachedAt       *time.Time `json:"breached_at,omitempty"`
+	// StoppedAt is when the incident was acknowledged or resolved
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+}
+
+// IncidentSLA holds an incident's SLA timers under the policy for its current severity
+type IncidentSLA struct {
+	Policy      Severity  `json:"policy,omitempty"`
+	Acknowledge *SLATimer `json:"acknowledge,omitempty"`
+	Resolve     *SLATimer `json:"resolve,omitempty"`
+	// Breached stays set once any target has been breached
+	Breached bool `json:"breached"`
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+)
+
+// StatusUpdateState is the stage an incident has reached as told to customers
+type StatusUpdateState string
+
+const (
+	StatusUpdateInvestigating StatusUpdateState = "investigating"
+	StatusUpdateIdentified    StatusUpdateState = "identified"
+	StatusUpdateMonitoring    StatusUpdateState = "monitoring"
+	StatusUpdateResolved      StatusUpdateState = "resolved"
+)
+
+// ComponentStatus is how a status page component is working
+type ComponentStatus string
+
+const (
+	ComponentOperational ComponentStatus = "operational"
+	ComponentDegraded    ComponentStatus = "degraded"
+	ComponentOutage      ComponentStatus = "outage"
+)
+
+// Component is a customer-facing part of the product shown on the status page
+type Component struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Services are the catalog services behind the component; incidents on them affect it
+	Services []string `json:"services,omitempty"`
+	// Status is derived from the latest status updates of unresolved incidents
+	Status    ComponentStatus `json:"status"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// UpdateComponentRequest represents a request to update a component; omitted fields are unchanged
+type UpdateComponentRequest struct {
+	Description *string  `json:"description,omitempty"`
+	Services    []string `json:"services,omitempty"`
+}
+
+// StatusUpdate is a customer-facing message about an incident, separate from internal comments
+type StatusUpdate struct {
+	ID         string            `json:"id"`
+	IncidentID string            `json:"incident_id"`
+	State      StatusUpdateState `json:"state"`
+	// Title is the public incident title as of this update
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	// Components maps each affected component to its status as of this update
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+	Author     string                     `json:"author,omitempty"`
+	CreatedAt  time.Time                  `json:"created_at"`
+}
+
+// CreateStatusUpdateRequest represents a request to publish a status update. Title is required on
+// an incident's first update; later updates keep the previous title and components when omitted.
+type CreateStatusUpdateRequest struct {
+	State      StatusUpdateState          `json:"state"`
+	Title      string                     `json:"title,omitempty"`
+	Message    string                     `json:"message"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+	Author     string                     `json:"author,omitempty"`
+}
+
+// DraftStatusUpdateRequest represents a request to draft the next status update
+type DraftStatusUpdateRequest struct {
+	// State is the state to announce; empty picks one from the incident
+	State StatusUpdateState `json:"state,omitempty"`
+}
+
+// StatusUpdateDraft is a proposed status update, ready to review and publish
+type StatusUpdateDraft struct {
+	State      StatusUpdateState          `json:"state"`
+	Title      string                     `json:"title"`
+	Message    string                     `json:"message"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+	// Source is "ai", or "template" when the AI provider is unavailable
+	Source string `json:"source"`
+}
+
+// StatusPage is the public, read-only view of component health and customer-facing incidents
+type StatusPage struct {
+	Title      string            `json:"title"`
+	URL        string            `json:"url,omitempty"`
+	Status     ComponentStatus   `json:"status"`
+	Components []PublicComponent `json:"components"`
+	// Incidents are unresolved; Recent are resolved within the last week
+	Incidents []PublicIncident `json:"incidents"`
+	Recent    []PublicIncident `json:"recent"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// PublicComponent is a component as shown on the status page
+type PublicComponent struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Status      ComponentStatus `json:"status"`
+}
+
+// PublicIncident is an incident as told through its status updates. ID is the ID of its first
+// status update, so internal incident IDs stay off the public page.
+type PublicIncident struct {
+	ID         string               `json:"id"`
+	Title      string               `json:"title"`
+	State      StatusUpdateState    `json:"state"`
+	Impact     ComponentStatus      `json:"impact"`
+	Components []string             `json:"components,omitempty"`
+	Updates    []PublicStatusUpdate `json:"updates"`
+	StartedAt  time.Time            `json:"started_at"`
+	ResolvedAt *time.Time           `json:"resolved_at,omitempty"`
+}
+
+// PublicStatusUpdate is a status update without its author, newest first in PublicIncident
+type PublicStatusUpdate struct {
+	ID         string                     `json:"id"`
+	State      StatusUpdateState          `json:"state"`
+	Message    string                     `json:"message"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+	CreatedAt  time.Time                  `json:"created_at"`
+}
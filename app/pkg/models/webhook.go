@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription registers a URL to receive signed incident events
+type WebhookSubscription struct {
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	// Events limits deliveries to these event types; empty means every event
+	Events []EventType `json:"events,omitempty"`
+	// Secret keys the HMAC signature; it is only returned when created or rotated
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateSubscriptionRequest represents a request to register a webhook; a secret is generated when omitted
+type CreateSubscriptionRequest struct {
+	URL         string      `json:"url"`
+	Description string      `json:"description,omitempty"`
+	Events      []EventType `json:"events,omitempty"`
+	Secret      string      `json:"secret,omitempty"`
+}
+
+// UpdateSubscriptionRequest represents a request to update a webhook; omitted fields are unchanged
+type UpdateSubscriptionRequest struct {
+	URL          *string     `json:"url,omitempty"`
+	Description  *string     `json:"description,omitempty"`
+	Events       []EventType `json:"events,omitempty"`
+	Active       *bool       `json:"active,omitempty"`
+	RotateSecret bool        `json:"rotate_secret,omitempty"`
+}
+
+// WebhookDeliveryStatus represents where a webhook delivery is in its retries
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending   WebhookDeliveryStatus = "pending"
+	WebhookSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery records sending one event to one subscription
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	SubscriptionID string                `json:"subscription_id"`
+	EventID        string                `json:"event_id"`
+	EventType      EventType             `json:"event_type"`
+	IncidentID     string                `json:"incident_id"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       []WebhookAttempt      `json:"attempts"`
+	// RedeliveryOf is the delivery this one repeats
+	RedeliveryOf string `json:"redelivery_of,omitempty"`
+	// Payload is the JSON body sent on every attempt
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	DeliveredAt *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// WebhookAttempt records one HTTP request for a delivery
+type WebhookAttempt struct {
+	At time.Time `json:"at"`
+	// StatusCode is zero when no response was received
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
@@ -0,0 +1,363 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// ChannelType selects how a channel delivers messages
+type ChannelType string
+
+const (
+	ChannelSlack   ChannelType = "slack"
+	ChannelTeams   ChannelType = "teams"
+	ChannelWebhook ChannelType = "webhook"
+	ChannelEmail   ChannelType = "email"
+)
+
+// Headers sent with generic webhook deliveries
+const (
+	HeaderEvent     = "X-Incident-Event"
+	HeaderTimestamp = "X-Incident-Timestamp"
+	HeaderSignature = "X-Incident-Signature"
+)
+
+// ChannelConfig describes one channel. Slack, Teams and webhook channels post to URL;
+// webhook deliveries are signed when Secret is set. Email channels send through SMTPHost.
+type ChannelConfig struct {
+	Name   string      `json:"name"`
+	Type   ChannelType `json:"type"`
+	URL    string      `json:"url,omitempty"`
+	Secret string      `json:"secret,omitempty"`
+
+	SMTPHost string   `json:"smtp_host,omitempty"`
+	SMTPPort int      `json:"smtp_port,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+}
+
+var (
+	// ErrInvalidSignature is returned by Verify when the signature does not match the body
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrStaleTimestamp is returned by Verify when the timestamp is outside the allowed tolerance
+	ErrStaleTimestamp = errors.New("timestamp outside tolerance")
+)
+
+// Message is a rendered notification for one event
+type Message struct {
+	Event models.Event
+	Title string
+	Body  string
+	// URL links to the incident when a base URL is configured
+	URL string
+}
+
+// Channel delivers messages to one destination
+type Channel interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Sign returns the signature for a webhook body sent at timestamp (Unix seconds):
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature made by Sign. It rejects timestamps more than tolerance from now
+// so a captured request cannot be replayed later.
+func Verify(secret, timestamp string, body []byte, signature string, tolerance time.Duration, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return ErrStaleTimestamp
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+func newChannel(cfg ChannelConfig) (Channel, error) {
+	switch cfg.Type {
+	case ChannelSlack, ChannelTeams, ChannelWebhook:
+		if !strings.HasPrefix(cfg.URL, "http://") && !strings.HasPrefix(cfg.URL, "https://") {
+			return nil, fmt.Errorf("an http(s) url is required")
+		}
+		switch cfg.Type {
+		case ChannelSlack:
+			return &slackChannel{url: cfg.URL}, nil
+		case ChannelTeams:
+			return &teamsChannel{url: cfg.URL}, nil
+		default:
+			return &webhookChannel{url: cfg.URL, secret: cfg.Secret}, nil
+		}
+	case ChannelEmail:
+		if cfg.SMTPHost == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("smtp_host, from and to are required")
+		}
+		port := cfg.SMTPPort
+		if port == 0 {
+			port = 587
+		}
+		return &emailChannel{
+			host:     cfg.SMTPHost,
+			addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)),
+			username: cfg.Username,
+			password: cfg.Password,
+			from:     cfg.From,
+			to:       cfg.To,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown channel type %q", cfg.Type)
+	}
+}
+
+// send makes one delivery attempt with a timeout
+func send(channel Channel, message Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	return channel.Send(ctx, message)
+}
+
+// slackChannel posts to a Slack incoming webhook
+type slackChannel struct {
+	url string
+}
+
+func (c *slackChannel) Send(ctx context.Context, message Message) error {
+	text := message.Body
+	if message.URL != "" {
+		text += "\n<" + message.URL + "|View incident>"
+	}
+	return postJSON(ctx, c.url, map[string]interface{}{
+		"text": message.Title,
+		"attachments": []map[string]interface{}{{
+			"color":    "#" + color(message.Event),
+			"fallback": message.Title,
+			"text":     text,
+		}},
+	}, nil)
+}
+
+// teamsChannel posts a MessageCard to a Microsoft Teams incoming webhook
+type teamsChannel struct {
+	url string
+}
+
+func (c *teamsChannel) Send(ctx context.Context, message Message) error {
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": color(message.Event),
+		"summary":    message.Title,
+		"title":      message.Title,
+		// Teams joins single newlines, so keep each line a paragraph
+		"text": strings.ReplaceAll(message.Body, "\n", "\n\n"),
+	}
+	if message.URL != "" {
+		card["potentialAction"] = []map[string]interface{}{{
+			"@type":   "OpenUri",
+			"name":    "View incident",
+			"targets": []map[string]string{{"os": "default", "uri": message.URL}},
+		}}
+	}
+	return postJSON(ctx, c.url, card, nil)
+}
+
+// webhookChannel posts the event as JSON, signed with the channel secret
+type webhookChannel struct {
+	url    string
+	secret string
+}
+
+// webhookPayload is the body of generic webhook deliveries
+type webhookPayload struct {
+	ID               string           `json:"id"`
+	Type             models.EventType `json:"type"`
+	At               time.Time        `json:"at"`
+	Title            string           `json:"title"`
+	Text             string           `json:"text"`
+	URL              string           `json:"url,omitempty"`
+	PreviousSeverity models.Severity  `json:"previous_severity,omitempty"`
+	Incident         *models.Incident `json:"incident"`
+}
+
+func (c *webhookChannel) Send(ctx context.Context, message Message) error {
+	body, err := json.Marshal(webhookPayload{
+		ID:               message.Event.ID,
+		Type:             message.Event.Type,
+		At:               message.Event.At,
+		Title:            message.Title,
+		Text:             message.Body,
+		URL:              message.URL,
+		PreviousSeverity: message.Event.PreviousSeverity,
+		Incident:         message.Event.Incident,
+	})
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		HeaderEvent:     string(message.Event.Type),
+		HeaderTimestamp: timestamp,
+	}
+	if c.secret != "" {
+		headers[HeaderSignature] = Sign(c.secret, timestamp, body)
+	}
+	return post(ctx, c.url, body, headers)
+}
+
+// emailChannel sends plain-text mail over SMTP, upgrading to TLS when the server offers STARTTLS
+type emailChannel struct {
+	host     string
+	addr     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func (c *emailChannel) Send(ctx context.Context, message Message) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.host}); err != nil {
+			return err
+		}
+	}
+	if c.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.from); err != nil {
+		return err
+	}
+	for _, to := range c.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(c.compose(message)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// compose builds the RFC 5322 message with CRLF line endings
+func (c *emailChannel) compose(message Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", c.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(c.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+
+	body := message.Body
+	if message.URL != "" {
+		body += "\n" + message.URL
+	}
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+func postJSON(ctx context.Context, url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return post(ctx, url, body, headers)
+}
+
+// post sends a JSON body and treats any non-2xx response as a failed delivery
+func post(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// color picks a hex colour for chat attachments: green once resolved, otherwise by severity
+func color(event models.Event) string {
+	if event.Type == models.EventIncidentResolved {
+		return "21ba45"
+	}
+	switch event.Incident.Severity {
+	case models.SeverityCritical:
+		return "d00000"
+	case models.SeverityHigh:
+		return "f2711c"
+	case models.SeverityMedium:
+		return "fbbd08"
+	default:
+		return "2185d0"
+	}
+}
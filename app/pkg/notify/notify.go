@@ -0,0 +1,452 @@
+// Package notify delivers incident events to chat, email and webhook channels.
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Defaults for delivery retries
+const (
+	DefaultMaxAttempts  = 3
+	DefaultRetryBackoff = 2 * time.Second
+)
+
+const (
+	// sendTimeout bounds a single delivery attempt
+	sendTimeout = 10 * time.Second
+	workers     = 4
+	queueSize   = 256
+	// maxHistory bounds the deliveries kept per incident; the oldest are dropped first
+	maxHistory = 100
+)
+
+// defaultEvents are routed when a route does not list any
+var defaultEvents = []models.EventType{
+	models.EventIncidentCreated,
+	models.EventIncidentSeverityChanged,
+	models.EventIncidentResolved,
+	models.EventIncidentSLAAtRisk,
+	models.EventIncidentSLABreached,
+}
+
+// Route sends matching events to channels. Empty filters match everything; within a filter any
+// value matches. Events defaults to incident.created, incident.severity_changed, incident.resolved
+// and the SLA warnings incident.sla_at_risk and incident.sla_breached.
+type Route struct {
+	Name       string             `json:"name"`
+	Events     []models.EventType `json:"events,omitempty"`
+	Severities []models.Severity  `json:"severities,omitempty"`
+	Services   []string           `json:"services,omitempty"`
+	Tags       []string           `json:"tags,omitempty"`
+	Channels   []string           `json:"channels"`
+}
+
+// Config describes channels, routes and message templates. Without routes every channel
+// receives the default events. RetryBackoff is a duration such as "2s" that doubles per attempt,
+// and BaseURL is used to link to incidents from messages.
+type Config struct {
+	Channels     []ChannelConfig               `json:"channels"`
+	Routes       []Route                       `json:"routes,omitempty"`
+	Templates    map[models.EventType]Template `json:"templates,omitempty"`
+	MaxAttempts  int                           `json:"max_attempts,omitempty"`
+	RetryBackoff string                        `json:"retry_backoff,omitempty"`
+	BaseURL      string                        `json:"base_url,omitempty"`
+}
+
+// LoadConfig reads a JSON notification configuration. Environment variables in channel URLs,
+// usernames, passwords and secrets are expanded so credentials can stay out of the file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read notification config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid notification config %s: %w", path, err)
+	}
+	for i := range cfg.Channels {
+		ch := &cfg.Channels[i]
+		ch.URL = os.ExpandEnv(ch.URL)
+		ch.Secret = os.ExpandEnv(ch.Secret)
+		ch.Username = os.ExpandEnv(ch.Username)
+		ch.Password = os.ExpandEnv(ch.Password)
+	}
+	return cfg, nil
+}
+
+// Option configures optional Dispatcher behaviour
+type Option func(*Dispatcher)
+
+// WithChannel adds a channel that is not described in the config, such as a custom integration
+func WithChannel(name string, channel Channel) Option {
+	return func(d *Dispatcher) {
+		d.channels[name] = channel
+	}
+}
+
+// job is one delivery waiting for a worker
+type job struct {
+	delivery *models.NotificationDelivery
+	channel  Channel
+	message  Message
+}
+
+// Dispatcher routes incident events to channels and delivers them in the background,
+// retrying failures and dead-lettering deliveries that never succeed
+type Dispatcher struct {
+	channels    map[string]Channel
+	routes      []Route
+	templates   *templates
+	maxAttempts int
+	backoff     time.Duration
+	baseURL     string
+	logger      *zap.Logger
+
+	queue chan *job
+	// done aborts retry backoff on Close
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	mu          sync.Mutex
+	closed      bool
+	counter     int64
+	history     map[string][]*models.NotificationDelivery
+	deadLetters []*job
+}
+
+// NewDispatcher validates cfg and starts the delivery workers; call Close to stop them
+func NewDispatcher(cfg Config, logger *zap.Logger, opts ...Option) (*Dispatcher, error) {
+	d := &Dispatcher{
+		channels:    make(map[string]Channel),
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultRetryBackoff,
+		baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+		logger:      logger,
+		queue:       make(chan *job, queueSize),
+		done:        make(chan struct{}),
+		history:     make(map[string][]*models.NotificationDelivery),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	for _, chCfg := range cfg.Channels {
+		if chCfg.Name == "" {
+			return nil, fmt.Errorf("channel name is required")
+		}
+		if _, ok := d.channels[chCfg.Name]; ok {
+			return nil, fmt.Errorf("duplicate channel %q", chCfg.Name)
+		}
+		channel, err := newChannel(chCfg)
+		if err != nil {
+			return nil, fmt.Errorf("channel %q: %w", chCfg.Name, err)
+		}
+		d.channels[chCfg.Name] = channel
+	}
+
+	if cfg.MaxAttempts < 0 {
+		return nil, fmt.Errorf("max_attempts must not be negative")
+	}
+	if cfg.MaxAttempts > 0 {
+		d.maxAttempts = cfg.MaxAttempts
+	}
+	if cfg.RetryBackoff != "" {
+		backoff, err := time.ParseDuration(cfg.RetryBackoff)
+		if err != nil || backoff < 0 {
+			return nil, fmt.Errorf("invalid retry_backoff %q", cfg.RetryBackoff)
+		}
+		d.backoff = backoff
+	}
+
+	d.routes = cfg.Routes
+	if len(d.routes) == 0 {
+		route := Route{Name: "default"}
+		for name := range d.channels {
+			route.Channels = append(route.Channels, name)
+		}
+		sort.Strings(route.Channels)
+		d.routes = []Route{route}
+	}
+	for i, route := range d.routes {
+		if route.Name == "" {
+			d.routes[i].Name = fmt.Sprintf("route-%d", i+1)
+		}
+		for _, event := range route.Events {
+			if !event.Valid() {
+				return nil, fmt.Errorf("route %q: unknown event type %q", d.routes[i].Name, event)
+			}
+		}
+		for _, name := range route.Channels {
+			if _, ok := d.channels[name]; !ok {
+				return nil, fmt.Errorf("route %q: unknown channel %q", d.routes[i].Name, name)
+			}
+		}
+	}
+
+	var err error
+	if d.templates, err = parseTemplates(cfg.Templates); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d, nil
+}
+
+// HandleEvent queues a delivery to every channel routed for the event. It never blocks:
+// when the queue is full the delivery is dead-lettered so it can be retried later.
+func (d *Dispatcher) HandleEvent(event models.Event) {
+	if event.Incident == nil {
+		return
+	}
+
+	var message *Message
+	seen := make(map[string]bool)
+	for _, route := range d.routes {
+		if !route.matches(event) {
+			continue
+		}
+		for _, name := range route.Channels {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if message == nil {
+				message = d.render(event)
+			}
+			d.enqueue(route.Name, name, *message)
+		}
+	}
+}
+
+// History returns the deliveries for an incident, oldest first
+func (d *Dispatcher) History(incidentID string) []models.NotificationDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deliveries := make([]models.NotificationDelivery, 0, len(d.history[incidentID]))
+	for _, delivery := range d.history[incidentID] {
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries
+}
+
+// DeadLetters returns deliveries that failed every attempt, oldest first
+func (d *Dispatcher) DeadLetters() []models.NotificationDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deliveries := make([]models.NotificationDelivery, 0, len(d.deadLetters))
+	for _, j := range d.deadLetters {
+		deliveries = append(deliveries, *j.delivery)
+	}
+	return deliveries
+}
+
+// Redeliver queues a dead-lettered delivery again with a fresh set of attempts.
+// It returns false when id is not on the dead-letter list.
+func (d *Dispatcher) Redeliver(id string) (*models.NotificationDelivery, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, j := range d.deadLetters {
+		if j.delivery.ID != id {
+			continue
+		}
+		if d.closed {
+			j.delivery.LastError = "dispatcher is closed"
+		} else {
+			select {
+			case d.queue <- j:
+				d.deadLetters = append(d.deadLetters[:i], d.deadLetters[i+1:]...)
+				j.delivery.Status = models.NotificationPending
+				j.delivery.Attempts = 0
+				j.delivery.LastError = ""
+			default:
+				j.delivery.LastError = "delivery queue is full"
+			}
+		}
+		delivery := *j.delivery
+		return &delivery, true
+	}
+	return nil, false
+}
+
+// Close stops accepting events, abandons pending retries and waits for in-flight deliveries.
+// Deliveries that did not succeed are dead-lettered.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	close(d.done)
+	close(d.queue)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// enqueue records a pending delivery and hands it to the workers
+func (d *Dispatcher) enqueue(route, channel string, message Message) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.counter++
+	incidentID := message.Event.Incident.ID
+	j := &job{
+		delivery: &models.NotificationDelivery{
+			ID:         fmt.Sprintf("NTF-%d", d.counter),
+			IncidentID: incidentID,
+			EventID:    message.Event.ID,
+			EventType:  message.Event.Type,
+			Channel:    channel,
+			Route:      route,
+			Title:      message.Title,
+			Status:     models.NotificationPending,
+			CreatedAt:  time.Now(),
+		},
+		channel: d.channels[channel],
+		message: message,
+	}
+
+	history := append(d.history[incidentID], j.delivery)
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+	d.history[incidentID] = history
+
+	if d.closed {
+		d.deadLetter(j, "dispatcher is closed")
+		return
+	}
+	select {
+	case d.queue <- j:
+	default:
+		d.deadLetter(j, "delivery queue is full")
+	}
+}
+
+// deadLetter gives up on a delivery; callers must hold d.mu
+func (d *Dispatcher) deadLetter(j *job, reason string) {
+	j.delivery.Status = models.NotificationDeadLetter
+	if reason != "" {
+		j.delivery.LastError = reason
+	}
+	d.deadLetters = append(d.deadLetters, j)
+	d.logger.Warn("notification dead-lettered",
+		zap.String("id", j.delivery.ID),
+		zap.String("incident", j.delivery.IncidentID),
+		zap.String("channel", j.delivery.Channel),
+		zap.String("error", j.delivery.LastError),
+	)
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for j := range d.queue {
+		d.deliver(j)
+	}
+}
+
+// deliver sends a job, backing off exponentially between attempts
+func (d *Dispatcher) deliver(j *job) {
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		err := send(j.channel, j.message)
+		now := time.Now()
+
+		d.mu.Lock()
+		j.delivery.Attempts++
+		j.delivery.LastAttemptAt = &now
+		if err == nil {
+			j.delivery.Status = models.NotificationDelivered
+			j.delivery.DeliveredAt = &now
+			j.delivery.LastError = ""
+			d.mu.Unlock()
+			return
+		}
+		j.delivery.LastError = err.Error()
+		d.mu.Unlock()
+
+		if attempt == d.maxAttempts {
+			break
+		}
+		select {
+		case <-time.After(d.backoff << (attempt - 1)):
+		case <-d.done:
+			// Shutting down: skip the remaining attempts
+			attempt = d.maxAttempts
+		}
+	}
+
+	d.mu.Lock()
+	d.deadLetter(j, "")
+	d.mu.Unlock()
+}
+
+// matches reports whether the route applies to the event
+func (r Route) matches(event models.Event) bool {
+	events := r.Events
+	if len(events) == 0 {
+		events = defaultEvents
+	}
+	if !containsEvent(events, event.Type) {
+		return false
+	}
+
+	incident := event.Incident
+	if len(r.Severities) > 0 && !containsSeverity(r.Severities, incident.Severity) {
+		return false
+	}
+	if len(r.Services) > 0 && !containsFold(r.Services, incident.Service) {
+		return false
+	}
+	if len(r.Tags) > 0 {
+		for _, tag := range incident.Tags {
+			if containsFold(r.Tags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsEvent(events []models.EventType, t models.EventType) bool {
+	for _, e := range events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSeverity(severities []models.Severity, s models.Severity) bool {
+	for _, sev := range severities {
+		if sev == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
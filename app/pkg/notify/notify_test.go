@@ -0,0 +1,398 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// capture records request bodies and headers, failing the first failures requests
+type capture struct {
+	mu       sync.Mutex
+	bodies   [][]byte
+	headers  []http.Header
+	failures int32
+}
+
+func (c *capture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	c.bodies = append(c.bodies, body)
+	c.headers = append(c.headers, r.Header.Clone())
+	c.mu.Unlock()
+	if atomic.AddInt32(&c.failures, -1) >= 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *capture) requests() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.bodies)
+}
+
+func newCapture(t *testing.T, failures int32) (*capture, string) {
+	t.Helper()
+	c := &capture{failures: failures}
+	server := httptest.NewServer(c)
+	t.Cleanup(server.Close)
+	return c, server.URL
+}
+
+// fakeSMTP accepts one connection at a time and records the DATA of every message
+type fakeSMTP struct {
+	mu       sync.Mutex
+	messages []string
+	rcpts    []string
+}
+
+func newFakeSMTP(t *testing.T) (*fakeSMTP, string, int) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	f := &fakeSMTP{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			f.serve(conn)
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+	return f, "127.0.0.1", addr.Port
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT"):
+			f.mu.Lock()
+			f.rcpts = append(f.rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+			f.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			f.mu.Lock()
+			f.messages = append(f.messages, data.String())
+			f.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func testEvent(eventType models.EventType, incident *models.Incident) models.Event {
+	return models.Event{ID: "EVT-1", Type: eventType, At: time.Now(), Incident: incident}
+}
+
+func testIncident() *models.Incident {
+	return &models.Incident{
+		ID:         "INC-1",
+		Title:      "Checkout errors",
+		Status:     models.StatusOpen,
+		Severity:   models.SeverityCritical,
+		Service:    "checkout",
+		AssignedTo: "alice",
+		Tags:       []string{"payments"},
+		AIAnalysis: &models.AIAnalysis{Summary: "Connection pool exhausted after deploy"},
+	}
+}
+
+func TestChannels(t *testing.T) {
+	slack, slackURL := newCapture(t, 0)
+	teams, teamsURL := newCapture(t, 0)
+	hook, hookURL := newCapture(t, 0)
+	mail, host, port := newFakeSMTP(t)
+
+	d, err := NewDispatcher(Config{
+		Channels: []ChannelConfig{
+			{Name: "slack", Type: ChannelSlack, URL: slackURL},
+			{Name: "teams", Type: ChannelTeams, URL: teamsURL},
+			{Name: "hook", Type: ChannelWebhook, URL: hookURL, Secret: "s3cret"},
+			{Name: "mail", Type: ChannelEmail, SMTPHost: host, SMTPPort: port, From: "incidents@example.com", To: []string{"oncall@example.com"}},
+		},
+		BaseURL: "https://incidents.example.com/",
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.HandleEvent(testEvent(models.EventIncidentCreated, testIncident()))
+	d.Close()
+
+	history := d.History("INC-1")
+	if len(history) != 4 {
+		t.Fatalf("expected 4 deliveries, got %+v", history)
+	}
+	for _, delivery := range history {
+		if delivery.Status != models.NotificationDelivered || delivery.Attempts != 1 || delivery.Route != "default" {
+			t.Errorf("expected %s to be delivered on the first attempt, got %+v", delivery.Channel, delivery)
+		}
+	}
+
+	var slackBody struct {
+		Text        string `json:"text"`
+		Attachments []struct {
+			Color string `json:"color"`
+			Text  string `json:"text"`
+		} `json:"attachments"`
+	}
+	json.Unmarshal(slack.bodies[0], &slackBody)
+	if slackBody.Text != "[critical] New incident INC-1: Checkout errors" || slackBody.Attachments[0].Color != "#d00000" {
+		t.Errorf("unexpected Slack title or colour: %+v", slackBody)
+	}
+	for _, want := range []string{"Service: checkout", "Assigned to: alice", "AI summary: Connection pool exhausted after deploy",
+		"<https://incidents.example.com/api/v1/incidents/INC-1|View incident>"} {
+		if !strings.Contains(slackBody.Attachments[0].Text, want) {
+			t.Errorf("expected Slack text to contain %q, got %q", want, slackBody.Attachments[0].Text)
+		}
+	}
+
+	var card map[string]interface{}
+	json.Unmarshal(teams.bodies[0], &card)
+	if card["@type"] != "MessageCard" || card["themeColor"] != "d00000" {
+		t.Errorf("unexpected Teams card: %v", card)
+	}
+
+	header := hook.headers[0]
+	if got, want := header.Get(HeaderSignature), Sign("s3cret", header.Get(HeaderTimestamp), hook.bodies[0]); got != want {
+		t.Errorf("expected signature %s, got %s", want, got)
+	}
+	if header.Get(HeaderEvent) != "incident.created" {
+		t.Errorf("expected the event header, got %q", header.Get(HeaderEvent))
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(hook.bodies[0], &payload); err != nil || payload.Incident.ID != "INC-1" || payload.Type != models.EventIncidentCreated {
+		t.Errorf("unexpected webhook payload %+v (%v)", payload, err)
+	}
+
+	if len(mail.messages) != 1 || mail.rcpts[0] != "<oncall@example.com>" {
+		t.Fatalf("expected one email to oncall, got %v to %v", mail.messages, mail.rcpts)
+	}
+	for _, want := range []string{"Subject: [critical] New incident INC-1: Checkout errors\r\n", "To: oncall@example.com\r\n", "AI summary: Connection pool"} {
+		if !strings.Contains(mail.messages[0], want) {
+			t.Errorf("expected the email to contain %q, got:\n%s", want, mail.messages[0])
+		}
+	}
+}
+
+func TestRouting(t *testing.T) {
+	critical, criticalURL := newCapture(t, 0)
+	payments, paymentsURL := newCapture(t, 0)
+	all, allURL := newCapture(t, 0)
+
+	d, err := NewDispatcher(Config{
+		Channels: []ChannelConfig{
+			{Name: "pager", Type: ChannelWebhook, URL: criticalURL},
+			{Name: "payments", Type: ChannelSlack, URL: paymentsURL},
+			{Name: "firehose", Type: ChannelWebhook, URL: allURL},
+		},
+		Routes: []Route{
+			{Name: "critical", Severities: []models.Severity{models.SeverityCritical}, Channels: []string{"pager", "firehose"}},
+			{Name: "payments", Services: []string{"Checkout"}, Tags: []string{"payments"}, Channels: []string{"payments"}},
+			{Name: "everything", Events: models.EventTypes, Channels: []string{"firehose"}},
+		},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	low := testIncident()
+	low.ID, low.Severity, low.Tags = "INC-2", models.SeverityLow, nil
+	d.HandleEvent(testEvent(models.EventIncidentCreated, testIncident()))
+	d.HandleEvent(testEvent(models.EventIncidentCreated, low))
+	d.HandleEvent(testEvent(models.EventAnalysisCompleted, testIncident()))
+	d.Close()
+
+	// critical: both incident.created events would match but only INC-1 is critical
+	// payments: INC-2 has no matching tag; analysis.completed is not a default event
+	// firehose: deduplicated across routes, so one delivery per event
+	if critical.requests() != 1 || payments.requests() != 1 || all.requests() != 3 {
+		t.Errorf("expected 1, 1 and 3 deliveries, got %d, %d and %d", critical.requests(), payments.requests(), all.requests())
+	}
+	for _, delivery := range d.History("INC-1") {
+		if delivery.Channel == "firehose" && delivery.EventType == models.EventIncidentCreated && delivery.Route != "critical" {
+			t.Errorf("expected the first matching route to be recorded, got %q", delivery.Route)
+		}
+	}
+}
+
+func TestRetriesAndDeadLetters(t *testing.T) {
+	flaky, flakyURL := newCapture(t, 2)
+	down, downURL := newCapture(t, 1000)
+
+	d, err := NewDispatcher(Config{
+		Channels: []ChannelConfig{
+			{Name: "flaky", Type: ChannelWebhook, URL: flakyURL},
+			{Name: "down", Type: ChannelWebhook, URL: downURL},
+		},
+		MaxAttempts:  3,
+		RetryBackoff: "1ms",
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer d.Close()
+
+	d.HandleEvent(testEvent(models.EventIncidentResolved, testIncident()))
+	deadline := time.Now().Add(5 * time.Second)
+	for len(d.DeadLetters()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	dead := d.DeadLetters()
+	if len(dead) != 1 || dead[0].Channel != "down" || dead[0].Attempts != 3 || dead[0].LastError != "unexpected status 503" {
+		t.Fatalf("expected the down channel to be dead-lettered after 3 attempts, got %+v", dead)
+	}
+	if flaky.requests() != 3 {
+		t.Errorf("expected the flaky channel to succeed on the third attempt, got %d requests", flaky.requests())
+	}
+
+	atomic.StoreInt32(&down.failures, 0)
+	redelivered, ok := d.Redeliver(dead[0].ID)
+	if !ok || redelivered.Status != models.NotificationPending || redelivered.Attempts != 0 {
+		t.Fatalf("expected the delivery to be queued again, got %+v", redelivered)
+	}
+	if _, ok := d.Redeliver(dead[0].ID); ok {
+		t.Error("expected a queued delivery not to be redelivered twice")
+	}
+	d.Close()
+
+	if len(d.DeadLetters()) != 0 {
+		t.Errorf("expected the dead-letter list to be empty, got %+v", d.DeadLetters())
+	}
+	for _, delivery := range d.History("INC-1") {
+		if delivery.Status != models.NotificationDelivered {
+			t.Errorf("expected %s to be delivered, got %+v", delivery.Channel, delivery)
+		}
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	hook, url := newCapture(t, 0)
+	d, err := NewDispatcher(Config{
+		Channels: []ChannelConfig{{Name: "hook", Type: ChannelWebhook, URL: url}},
+		Templates: map[models.EventType]Template{
+			models.EventIncidentSeverityChanged: {Title: "{{.Incident.ID}}: {{.PreviousSeverity}} -> {{.Incident.Severity}}"},
+		},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	incident := testIncident()
+	incident.AIAnalysis = nil
+	event := testEvent(models.EventIncidentSeverityChanged, incident)
+	event.PreviousSeverity = models.SeverityMedium
+	d.HandleEvent(event)
+	d.Close()
+
+	var payload webhookPayload
+	json.Unmarshal(hook.bodies[0], &payload)
+	if payload.Title != "INC-1: medium -> critical" {
+		t.Errorf("expected the custom title, got %q", payload.Title)
+	}
+	if want := "Checkout errors\nStatus: open | Severity: critical | Service: checkout | Assigned to: alice"; payload.Text != want {
+		t.Errorf("expected the default body without a summary, got %q", payload.Text)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	webhook := ChannelConfig{Name: "hook", Type: ChannelWebhook, URL: "http://127.0.0.1:1"}
+	configs := map[string]Config{
+		"unknown type":    {Channels: []ChannelConfig{{Name: "x", Type: "pager", URL: "http://x"}}},
+		"missing url":     {Channels: []ChannelConfig{{Name: "x", Type: ChannelSlack}}},
+		"email recipient": {Channels: []ChannelConfig{{Name: "x", Type: ChannelEmail, SMTPHost: "smtp", From: "a@example.com"}}},
+		"duplicate":       {Channels: []ChannelConfig{webhook, webhook}},
+		"unknown channel": {Channels: []ChannelConfig{webhook}, Routes: []Route{{Channels: []string{"slack"}}}},
+		"unknown event":   {Channels: []ChannelConfig{webhook}, Routes: []Route{{Events: []models.EventType{"incident.deleted"}, Channels: []string{"hook"}}}},
+		"bad template":    {Channels: []ChannelConfig{webhook}, Templates: map[models.EventType]Template{models.EventIncidentCreated: {Title: "{{.Incident"}}},
+		"bad backoff":     {Channels: []ChannelConfig{webhook}, RetryBackoff: "soon"},
+	}
+	for name, cfg := range configs {
+		if _, err := NewDispatcher(cfg, zap.NewNop()); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	t.Setenv("SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T/B/X")
+	path := filepath.Join(t.TempDir(), "notifications.json")
+	os.WriteFile(path, []byte(`{"channels": [{"name": "ops", "type": "slack", "url": "${SLACK_WEBHOOK_URL}"}], "max_attempts": 5}`), 0o644)
+	cfg, err := LoadConfig(path)
+	if err != nil || cfg.Channels[0].URL != "https://hooks.slack.com/services/T/B/X" || cfg.MaxAttempts != 5 {
+		t.Errorf("expected the URL to be expanded from the environment, got %+v (%v)", cfg, err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"EVT-1"}`)
+	signature := Sign("secret", strconv.Itoa(1700000000), body)
+	if !strings.HasPrefix(signature, "sha256=") || len(signature) != len("sha256=")+64 {
+		t.Fatalf("unexpected signature format %q", signature)
+	}
+	if signature == Sign("secret", "1700000001", body) || signature == Sign("other", "1700000000", body) {
+		t.Error("expected the signature to depend on the timestamp and secret")
+	}
+
+	now := time.Unix(1700000000, 0).Add(time.Minute)
+	if err := Verify("secret", "1700000000", body, signature, 5*time.Minute, now); err != nil {
+		t.Errorf("expected the signature to verify, got %v", err)
+	}
+	if err := Verify("secret", "1700000000", []byte(`{"id":"EVT-2"}`), signature, 5*time.Minute, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for a changed body, got %v", err)
+	}
+	if err := Verify("secret", "1700000000", body, signature, 5*time.Minute, now.Add(time.Hour)); !errors.Is(err, ErrStaleTimestamp) {
+		t.Errorf("expected ErrStaleTimestamp for a replayed request, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Template overrides the title and body rendered for an event type. Templates use text/template
+// with .Event, .Incident, .PreviousSeverity, .Summary (the AI analysis summary) and .URL.
+type Template struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// templateData is what message templates are executed with
+type templateData struct {
+	Event            models.Event
+	Incident         *models.Incident
+	PreviousSeverity models.Severity
+	Summary          string
+	URL              string
+}
+
+const defaultBody = `{{.Incident.Title}}
+Status: {{.Incident.Status}} | Severity: {{.Incident.Severity}}{{if .Incident.Service}} | Service: {{.Incident.Service}}{{end}}{{if .Incident.AssignedTo}} | Assigned to: {{.Incident.AssignedTo}}{{end}}
+{{- if .Summary}}
+AI summary: {{.Summary}}{{end}}`
+
+var defaultTitles = map[models.EventType]string{
+	models.EventIncidentCreated:         `[{{.Incident.Severity}}] New incident {{.Incident.ID}}: {{.Incident.Title}}`,
+	models.EventIncidentUpdated:         `Incident {{.Incident.ID}} updated: {{.Incident.Title}}`,
+	models.EventIncidentSeverityChanged: `Incident {{.Incident.ID}} severity changed from {{.PreviousSeverity}} to {{.Incident.Severity}}: {{.Incident.Title}}`,
+	models.EventIncidentResolved:        `Resolved: incident {{.Incident.ID}}: {{.Incident.Title}}`,
+	models.EventIncidentAcknowledged:    `Incident {{.Incident.ID}} acknowledged by {{.Incident.AcknowledgedBy}}: {{.Incident.Title}}`,
+	models.EventIncidentEscalated:       `[{{.Incident.Severity}}] Incident {{.Incident.ID}} escalated{{with .Incident.Escalation}} to level {{.Level}}{{end}}: {{.Incident.Title}}`,
+	models.EventIncidentSLAAtRisk:       `[{{.Incident.Severity}}] SLA at risk: incident {{.Incident.ID}} must be {{if eq .Event.SLATarget "acknowledge"}}acknowledged{{else}}resolved{{end}} soon: {{.Incident.Title}}`,
+	models.EventIncidentSLABreached:     `[{{.Incident.Severity}}] SLA breached: incident {{.Incident.ID}} was not {{if eq .Event.SLATarget "acknowledge"}}acknowledged{{else}}resolved{{end}} in time: {{.Incident.Title}}`,
+	models.EventAnalysisCompleted:       `AI analysis ready for incident {{.Incident.ID}}: {{.Incident.Title}}`,
+	models.EventRCAGenerated:            `RCA generated for incident {{.Incident.ID}}: {{.Incident.Title}}`,
+}
+
+// templates holds the parsed title and body template for every event type
+type templates struct {
+	titles map[models.EventType]*template.Template
+	bodies map[models.EventType]*template.Template
+}
+
+// parseTemplates parses the defaults with overrides applied on top
+func parseTemplates(overrides map[models.EventType]Template) (*templates, error) {
+	t := &templates{
+		titles: make(map[models.EventType]*template.Template),
+		bodies: make(map[models.EventType]*template.Template),
+	}
+	for event := range overrides {
+		if !event.Valid() {
+			return nil, fmt.Errorf("template for unknown event type %q", event)
+		}
+	}
+	for _, event := range models.EventTypes {
+		title, body := defaultTitles[event], defaultBody
+		if override, ok := overrides[event]; ok {
+			if override.Title != "" {
+				title = override.Title
+			}
+			if override.Body != "" {
+				body = override.Body
+			}
+		}
+
+		var err error
+		if t.titles[event], err = template.New(string(event)).Parse(title); err != nil {
+			return nil, fmt.Errorf("invalid title template for %s: %w", event, err)
+		}
+		if t.bodies[event], err = template.New(string(event)).Parse(body); err != nil {
+			return nil, fmt.Errorf("invalid body template for %s: %w", event, err)
+		}
+	}
+	return t, nil
+}
+
+// render builds the message for an event. A template that fails to execute falls back
+// to a plain title so the notification is still sent.
+func (d *Dispatcher) render(event models.Event) *Message {
+	data := templateData{
+		Event:            event,
+		Incident:         event.Incident,
+		PreviousSeverity: event.PreviousSeverity,
+	}
+	if event.Incident.AIAnalysis != nil {
+		data.Summary = event.Incident.AIAnalysis.Summary
+	}
+	if d.baseURL != "" {
+		data.URL = d.baseURL + "/api/v1/incidents/" + event.Incident.ID
+	}
+
+	message := &Message{Event: event, URL: data.URL}
+	var b strings.Builder
+	if err := d.templates.titles[event.Type].Execute(&b, data); err != nil {
+		d.logger.Warn("failed to render notification title", zap.String("event", string(event.Type)), zap.Error(err))
+		b.Reset()
+		fmt.Fprintf(&b, "Incident %s: %s", event.Incident.ID, event.Type)
+	}
+	message.Title = b.String()
+
+	b.Reset()
+	if err := d.templates.bodies[event.Type].Execute(&b, data); err != nil {
+		d.logger.Warn("failed to render notification body", zap.String("event", string(event.Type)), zap.Error(err))
+		b.Reset()
+		b.WriteString(event.Incident.Title)
+	}
+	message.Body = strings.TrimSpace(b.String())
+	return message
+}
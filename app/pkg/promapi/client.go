@@ -0,0 +1,130 @@
+// Package promapi queries a Prometheus-compatible HTTP API and summarizes the series
+// around the time an incident was opened.
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sample is a single point of a series
+type Sample struct {
+	At    time.Time
+	Value float64
+}
+
+// Series is one labelled time series of a range query result
+type Series struct {
+	Labels  map[string]string
+	Samples []Sample
+}
+
+// Client calls the Prometheus HTTP API. Thanos, Mimir, VictoriaMetrics and
+// Amazon Managed Prometheus (through a signing proxy) expose the same API.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the API at baseURL, e.g. http://prometheus:9090.
+// A non-empty token is sent as a bearer token.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// apiResponse is the envelope of every Prometheus API response
+type apiResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string    `json:"metric"`
+			Values [][2]json.RawMessage `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryRange evaluates query over [start, end] at the given step
+func (c *Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	params := url.Values{
+		"query": {query},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return nil, err
+	}
+	var result apiResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("Prometheus API error: %d - %s", resp.StatusCode, strings.TrimSpace(string(body[:min(len(body), 256)])))
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("Prometheus query failed: %s: %s", result.ErrorType, result.Error)
+	}
+	if result.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("unexpected result type %q", result.Data.ResultType)
+	}
+
+	series := make([]Series, 0, len(result.Data.Result))
+	for _, r := range result.Data.Result {
+		s := Series{Labels: r.Metric, Samples: make([]Sample, 0, len(r.Values))}
+		for _, v := range r.Values {
+			sample, ok := parseSample(v)
+			if ok {
+				s.Samples = append(s.Samples, sample)
+			}
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+// parseSample decodes a [unix seconds, "value"] pair, dropping NaN and infinite values
+func parseSample(v [2]json.RawMessage) (Sample, bool) {
+	var ts float64
+	var raw string
+	if json.Unmarshal(v[0], &ts) != nil || json.Unmarshal(v[1], &raw) != nil {
+		return Sample{}, false
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return Sample{}, false
+	}
+	sec, frac := math.Modf(ts)
+	return Sample{At: time.Unix(int64(sec), int64(frac*1e9)).UTC(), Value: value}, true
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
@@ -0,0 +1,249 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// EnricherName identifies metric snapshots on an incident
+const EnricherName = "prometheus"
+
+// Defaults for the query window around an incident's creation
+const (
+	DefaultBefore = 30 * time.Minute
+	DefaultAfter  = 15 * time.Minute
+)
+
+const (
+	// maxSeriesPerQuery bounds how many series of one query are summarized
+	maxSeriesPerQuery = 5
+	// targetPoints sets the query step so each series has about this many samples
+	targetPoints = 120
+	minStep      = 15 * time.Second
+	// metadataKey holds ad-hoc queries on an incident: a PromQL string or a list of them
+	metadataKey = "promql"
+)
+
+// Query is a named PromQL expression
+type Query struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// Config selects queries by incident source or tag. Before and After are durations
+// such as "30m" that set the window around the incident's creation.
+type Config struct {
+	Before  string             `json:"before,omitempty"`
+	After   string             `json:"after,omitempty"`
+	Sources map[string][]Query `json:"sources,omitempty"`
+	Tags    map[string][]Query `json:"tags,omitempty"`
+}
+
+// LoadConfig reads a JSON query configuration
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read Prometheus query file: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid Prometheus query file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Enricher attaches metric snapshots to incidents
+type Enricher struct {
+	client  *Client
+	before  time.Duration
+	after   time.Duration
+	sources map[string][]Query
+	tags    map[string][]Query
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewEnricher creates an enricher running the configured queries against client
+func NewEnricher(client *Client, cfg Config) (*Enricher, error) {
+	e := &Enricher{
+		client:  client,
+		before:  DefaultBefore,
+		after:   DefaultAfter,
+		sources: lowerKeys(cfg.Sources),
+		tags:    lowerKeys(cfg.Tags),
+		now:     time.Now,
+	}
+
+	var err error
+	if cfg.Before != "" {
+		if e.before, err = time.ParseDuration(cfg.Before); err != nil || e.before < 0 {
+			return nil, fmt.Errorf("invalid before duration %q", cfg.Before)
+		}
+	}
+	if cfg.After != "" {
+		if e.after, err = time.ParseDuration(cfg.After); err != nil || e.after < 0 {
+			return nil, fmt.Errorf("invalid after duration %q", cfg.After)
+		}
+	}
+	for _, group := range []map[string][]Query{e.sources, e.tags} {
+		for key, queries := range group {
+			for _, q := range queries {
+				if strings.TrimSpace(q.Query) == "" {
+					return nil, fmt.Errorf("query %q for %q is empty", q.Name, key)
+				}
+			}
+		}
+	}
+	return e, nil
+}
+
+// Name identifies the enricher
+func (e *Enricher) Name() string {
+	return EnricherName
+}
+
+// Enrich runs the queries that apply to the incident over the window around its creation.
+// It returns nil when no queries apply, and an error only when every query fails.
+func (e *Enricher) Enrich(ctx context.Context, incident *models.Incident) (*models.Enrichment, error) {
+	queries := e.QueriesFor(incident)
+	if len(queries) == 0 {
+		return nil, nil
+	}
+
+	start := incident.CreatedAt.Add(-e.before)
+	end := incident.CreatedAt.Add(e.after)
+	if now := e.now(); end.After(now) {
+		end = now
+	}
+	if !end.After(start) {
+		end = start.Add(minStep)
+	}
+	step := end.Sub(start) / targetPoints
+	if step < minStep {
+		step = minStep
+	}
+
+	var metrics []models.MetricSeries
+	var failures []string
+	var lastErr error
+	for _, q := range queries {
+		series, err := e.client.QueryRange(ctx, q.Query, start, end, step)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", q.Name, err))
+			lastErr = err
+			continue
+		}
+		sort.Slice(series, func(i, j int) bool {
+			return labelString(series[i].Labels) < labelString(series[j].Labels)
+		})
+		if len(series) > maxSeriesPerQuery {
+			series = series[:maxSeriesPerQuery]
+		}
+		for _, s := range series {
+			if len(s.Samples) > 0 {
+				metrics = append(metrics, Summarize(q.Name, q.Query, s))
+			}
+		}
+	}
+	if len(failures) == len(queries) {
+		return nil, fmt.Errorf("all %d Prometheus queries failed: %w", len(queries), lastErr)
+	}
+
+	return &models.Enrichment{
+		Enricher:  EnricherName,
+		Status:    models.EnrichmentOK,
+		Error:     strings.Join(failures, "; "),
+		FetchedAt: e.now(),
+		Summary:   SummaryText(metrics, incident.CreatedAt),
+		Metrics:   metrics,
+	}, nil
+}
+
+// QueriesFor lists the queries for the incident's source, its tags and its "promql" metadata,
+// without duplicate expressions
+func (e *Enricher) QueriesFor(incident *models.Incident) []Query {
+	var candidates []Query
+	candidates = append(candidates, e.sources[strings.ToLower(incident.Source)]...)
+	for _, tag := range incident.Tags {
+		candidates = append(candidates, e.tags[strings.ToLower(tag)]...)
+	}
+	candidates = append(candidates, metadataQueries(incident.Metadata[metadataKey])...)
+
+	seen := make(map[string]bool)
+	var queries []Query
+	for _, q := range candidates {
+		if q.Name == "" {
+			q.Name = q.Query
+		}
+		if seen[q.Query] {
+			continue
+		}
+		seen[q.Query] = true
+		queries = append(queries, q)
+	}
+	return queries
+}
+
+func metadataQueries(value interface{}) []Query {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return []Query{{Query: v}}
+		}
+	case []interface{}:
+		var queries []Query
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				queries = append(queries, Query{Query: s})
+			}
+		}
+		return queries
+	}
+	return nil
+}
+
+// SummaryText renders metric summaries as compact text for the analysis prompt
+func SummaryText(metrics []models.MetricSeries, createdAt time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Metrics around incident start (%s):", createdAt.UTC().Format(time.RFC3339))
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "\n- %s%s: avg %s, min %s, max %s, p95 %s, last %s",
+			m.Name, labelString(m.Labels), formatValue(m.Avg), formatValue(m.Min), formatValue(m.Max), formatValue(m.P95), formatValue(m.Last))
+		for _, cp := range m.ChangePoints {
+			fmt.Fprintf(&b, "\n  shifted %s -> %s at %s", formatValue(cp.Before), formatValue(cp.After), cp.At.UTC().Format(time.RFC3339))
+		}
+	}
+	return b.String()
+}
+
+func labelString(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(labels))
+	for k, v := range labels {
+		parts = append(parts, k+"="+strconv.Quote(v))
+	}
+	sort.Strings(parts)
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+func lowerKeys(m map[string][]Query) map[string][]Query {
+	lowered := make(map[string][]Query, len(m))
+	for k, v := range m {
+		lowered[strings.ToLower(k)] = append(lowered[strings.ToLower(k)], v...)
+	}
+	return lowered
+}
@@ -0,0 +1,183 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+var incidentStart = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+// fakePrometheus answers range queries with a step function: latency jumps from 0.2 to 1.5 at incident start
+type fakePrometheus struct {
+	queries []string
+	params  map[string]string
+	auth    string
+}
+
+func (f *fakePrometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	f.queries = append(f.queries, query.Get("query"))
+	f.params = map[string]string{"start": query.Get("start"), "end": query.Get("end"), "step": query.Get("step")}
+	f.auth = r.Header.Get("Authorization")
+
+	if r.URL.Path != "/api/v1/query_range" {
+		http.NotFound(w, r)
+		return
+	}
+	if strings.Contains(query.Get("query"), "bad(") {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error: unexpected \"(\""}`)
+		return
+	}
+
+	start, _ := strconv.ParseFloat(query.Get("start"), 64)
+	end, _ := strconv.ParseFloat(query.Get("end"), 64)
+	step, _ := strconv.ParseFloat(query.Get("step"), 64)
+	var values [][2]interface{}
+	for ts := start; ts <= end; ts += step {
+		value := "0.2"
+		if ts >= float64(incidentStart.Unix()) {
+			value = "1.5"
+		}
+		values = append(values, [2]interface{}{ts, value})
+	}
+	values = append(values, [2]interface{}{end, "NaN"})
+
+	result := []map[string]interface{}{
+		{"metric": map[string]string{"service": "payments"}, "values": values},
+		{"metric": map[string]string{"service": "checkout"}, "values": values[:4]},
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   map[string]interface{}{"resultType": "matrix", "result": result},
+	})
+}
+
+func newTestEnricher(t *testing.T, cfg Config) (*fakePrometheus, *Enricher) {
+	t.Helper()
+	fake := &fakePrometheus{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	enricher, err := NewEnricher(NewClient(server.URL+"/", "token"), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	enricher.now = func() time.Time { return incidentStart.Add(5 * time.Minute) }
+	return fake, enricher
+}
+
+func TestEnrich(t *testing.T) {
+	fake, enricher := newTestEnricher(t, Config{
+		Before:  "10m",
+		Sources: map[string][]Query{"Grafana": {{Name: "p99 latency", Query: "histogram_quantile(0.99, rate(http_request_duration_seconds_bucket[5m]))"}}},
+		Tags:    map[string][]Query{"payments": {{Name: "p99 latency", Query: "histogram_quantile(0.99, rate(http_request_duration_seconds_bucket[5m]))"}}},
+	})
+
+	enrichment, err := enricher.Enrich(context.Background(), &models.Incident{
+		Source:    "grafana",
+		Tags:      []string{"payments"},
+		Metadata:  map[string]interface{}{"promql": []interface{}{"up{job=\"payments\"}"}},
+		CreatedAt: incidentStart,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fake.queries) != 2 || fake.queries[1] != `up{job="payments"}` {
+		t.Errorf("expected duplicate queries to run once, got %q", fake.queries)
+	}
+	if fake.auth != "Bearer token" {
+		t.Errorf("expected bearer token, got %q", fake.auth)
+	}
+	// The window is clamped to now, so it spans 15 minutes at the minimum step
+	if fake.params["start"] != strconv.FormatInt(incidentStart.Add(-10*time.Minute).Unix(), 10) || fake.params["step"] != "15" {
+		t.Errorf("unexpected query window %v", fake.params)
+	}
+
+	if len(enrichment.Metrics) != 4 {
+		t.Fatalf("expected 2 series per query, got %+v", enrichment.Metrics)
+	}
+	m := enrichment.Metrics[1]
+	if m.Labels["service"] != "payments" || m.Min != 0.2 || m.Max != 1.5 || m.Last != 1.5 || m.Points != 61 {
+		t.Errorf("unexpected summary %+v", m)
+	}
+	if len(m.ChangePoints) != 1 || !m.ChangePoints[0].At.Equal(incidentStart) || math.Abs(m.ChangePoints[0].Before-0.2) > 1e-9 || m.ChangePoints[0].After != 1.5 {
+		t.Errorf("expected one change point at incident start, got %+v", m.ChangePoints)
+	}
+	if len(enrichment.Metrics[0].ChangePoints) != 0 {
+		t.Errorf("expected a flat series to have no change points, got %+v", enrichment.Metrics[0].ChangePoints)
+	}
+
+	for _, want := range []string{`p99 latency{service="payments"}: avg`, "shifted 0.2 -> 1.5 at 2024-05-01T10:00:00Z"} {
+		if !strings.Contains(enrichment.Summary, want) {
+			t.Errorf("expected summary to contain %q, got:\n%s", want, enrichment.Summary)
+		}
+	}
+}
+
+func TestEnrichQueryErrors(t *testing.T) {
+	_, enricher := newTestEnricher(t, Config{Tags: map[string][]Query{
+		"db": {{Name: "broken", Query: "bad("}, {Name: "connections", Query: "pg_stat_activity_count"}},
+	}})
+
+	enrichment, err := enricher.Enrich(context.Background(), &models.Incident{Tags: []string{"db"}, CreatedAt: incidentStart})
+	if err != nil {
+		t.Fatalf("expected partial success, got %v", err)
+	}
+	if !strings.Contains(enrichment.Error, "broken: Prometheus query failed: bad_data") || len(enrichment.Metrics) != 2 {
+		t.Errorf("unexpected enrichment %+v", enrichment)
+	}
+
+	_, err = enricher.Enrich(context.Background(), &models.Incident{Metadata: map[string]interface{}{"promql": "bad("}, CreatedAt: incidentStart})
+	if err == nil {
+		t.Error("expected an error when every query fails")
+	}
+
+	enrichment, err = enricher.Enrich(context.Background(), &models.Incident{Tags: []string{"web"}, CreatedAt: incidentStart})
+	if enrichment != nil || err != nil {
+		t.Errorf("expected no enrichment without queries, got %+v, %v", enrichment, err)
+	}
+}
+
+func TestNewEnricherValidation(t *testing.T) {
+	if _, err := NewEnricher(NewClient("http://prometheus", ""), Config{Before: "soon"}); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+	if _, err := NewEnricher(NewClient("http://prometheus", ""), Config{Tags: map[string][]Query{"db": {{Name: "empty"}}}}); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestChangePointsIgnoreNoise(t *testing.T) {
+	var samples []Sample
+	for i := 0; i < 40; i++ {
+		value := 100.0
+		if i%2 == 0 {
+			value = 140
+		}
+		samples = append(samples, Sample{At: incidentStart.Add(time.Duration(i) * time.Minute), Value: value})
+	}
+	if points := changePoints(samples); len(points) != 0 {
+		t.Errorf("expected no change points in alternating noise, got %+v", points)
+	}
+
+	// Two shifts: up at 10 and back down at 30
+	for i := 10; i < 30; i++ {
+		samples[i].Value = 400
+	}
+	points := changePoints(samples)
+	if len(points) != 2 || !points[0].At.Equal(samples[10].At) || !points[1].At.Equal(samples[30].At) {
+		t.Errorf("expected shifts at 10 and 30, got %+v", points)
+	}
+}
@@ -0,0 +1,131 @@
+package promapi
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+const (
+	// maxChangePoints bounds how many level shifts are reported per series
+	maxChangePoints = 3
+	// minSegment is the fewest samples on either side of a change point
+	minSegment = 3
+	// minShift is the smallest relative change in mean that counts as a change point
+	minShift = 0.2
+)
+
+// Summarize reduces a series to summary statistics and its change points
+func Summarize(name, query string, series Series) models.MetricSeries {
+	summary := models.MetricSeries{
+		Name:   name,
+		Query:  query,
+		Labels: series.Labels,
+		Points: len(series.Samples),
+	}
+	if len(series.Samples) == 0 {
+		return summary
+	}
+
+	values := make([]float64, len(series.Samples))
+	sum := 0.0
+	for i, s := range series.Samples {
+		values[i] = s.Value
+		sum += s.Value
+	}
+	summary.Start = series.Samples[0].At
+	summary.End = series.Samples[len(series.Samples)-1].At
+	summary.Avg = sum / float64(len(values))
+	summary.Last = values[len(values)-1]
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	summary.Min = sorted[0]
+	summary.Max = sorted[len(sorted)-1]
+	summary.P50 = percentile(sorted, 0.5)
+	summary.P95 = percentile(sorted, 0.95)
+
+	summary.ChangePoints = changePoints(series.Samples)
+	return summary
+}
+
+// percentile uses the nearest-rank method on sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// changePoints finds level shifts by binary segmentation: split where the means on either side
+// differ most, keep the split if the shift is large relative to both the level and the noise,
+// then search each side again
+func changePoints(samples []Sample) []models.ChangePoint {
+	sigma := noise(samples)
+	var points []models.ChangePoint
+	var search func(lo, hi int)
+	search = func(lo, hi int) {
+		if len(points) >= maxChangePoints || hi-lo < 2*minSegment {
+			return
+		}
+
+		// Weighting by segment sizes keeps a few outliers at either end from winning
+		best, bestScore := -1, 0.0
+		var before, after float64
+		for k := lo + minSegment; k <= hi-minSegment; k++ {
+			left, right := mean(samples[lo:k]), mean(samples[k:hi])
+			nl, nr := float64(k-lo), float64(hi-k)
+			if score := math.Abs(right-left) * math.Sqrt(nl*nr/(nl+nr)); score > bestScore {
+				best, bestScore, before, after = k, score, left, right
+			}
+		}
+		if best < 0 || !significant(before, after, best-lo, hi-best, sigma) {
+			return
+		}
+
+		points = append(points, models.ChangePoint{At: samples[best].At, Before: before, After: after})
+		search(lo, best)
+		search(best, hi)
+	}
+	search(0, len(samples))
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].At.Before(points[j].At)
+	})
+	return points
+}
+
+// significant requires a shift of at least minShift of the level and well outside the noise of the means
+func significant(before, after float64, nl, nr int, sigma float64) bool {
+	shift := math.Abs(after - before)
+	level := math.Max(math.Abs(before), math.Abs(after))
+	if level == 0 || shift/level < minShift {
+		return false
+	}
+	return shift > 4*sigma*math.Sqrt(1/float64(nl)+1/float64(nr))
+}
+
+// noise estimates the sample standard deviation from successive differences,
+// which level shifts barely affect
+func noise(samples []Sample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	diffs := make([]float64, 0, len(samples)-1)
+	for i := 1; i < len(samples); i++ {
+		diffs = append(diffs, math.Abs(samples[i].Value-samples[i-1].Value))
+	}
+	sort.Float64s(diffs)
+	// For normal noise the median absolute difference is about 0.954 standard deviations
+	return diffs[len(diffs)/2] / 0.954
+}
+
+func mean(samples []Sample) float64 {
+	sum := 0.0
+	for _, s := range samples {
+		sum += s.Value
+	}
+	return sum / float64(len(samples))
+}
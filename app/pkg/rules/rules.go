@@ -0,0 +1,387 @@
+// Package rules implements the configurable severity rules engine used to classify new incidents.
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// Fields a condition can match on
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldText        = "text" // title and description together
+	FieldSource      = "source"
+	FieldTags        = "tags"
+	FieldMetadataKey = "metadata_key"
+	FieldMetadata    = "metadata" // value of Key, or "key=value" pairs when Key is empty
+	FieldLogs        = "logs"
+)
+
+// ErrInvalidRule is returned when a rule fails validation
+var ErrInvalidRule = errors.New("invalid rule")
+
+// Condition is either a leaf matcher (Field + Pattern) or a boolean combination of conditions.
+// Patterns are regular expressions matched case-insensitively.
+type Condition struct {
+	Field   string `json:"field,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	Key     string `json:"key,omitempty"`
+
+	All []Condition `json:"all,omitempty"`
+	Any []Condition `json:"any,omitempty"`
+	Not *Condition  `json:"not,omitempty"`
+}
+
+// Rule assigns a severity, and optionally tags and an assignee, to incidents matching When
+type Rule struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Priority    int             `json:"priority"`
+	When        Condition       `json:"when"`
+	Severity    models.Severity `json:"severity"`
+	Tags        []string        `json:"tags,omitempty"`
+	AssignTo    string          `json:"assign_to,omitempty"`
+	Disabled    bool            `json:"disabled,omitempty"`
+}
+
+// Config is the on-disk rules file format
+type Config struct {
+	DefaultSeverity models.Severity `json:"default_severity,omitempty"`
+	Rules           []Rule          `json:"rules"`
+}
+
+// Input is the incident data rules are evaluated against
+type Input struct {
+	Title       string
+	Description string
+	Source      string
+	Tags        []string
+	Metadata    map[string]interface{}
+	Logs        []string
+}
+
+// Match explains why a leaf condition matched
+type Match struct {
+	Field   string `json:"field"`
+	Pattern string `json:"pattern"`
+	Text    string `json:"text"`
+}
+
+// Result is the outcome of evaluating the rules against an incident
+type Result struct {
+	Severity models.Severity `json:"severity"`
+	Rule     string          `json:"rule,omitempty"`
+	Tags     []string        `json:"tags,omitempty"`
+	AssignTo string          `json:"assign_to,omitempty"`
+	// Explanation states which rule fired and why, in evaluation order
+	Explanation []string `json:"explanation"`
+	Matches     []Match  `json:"matches,omitempty"`
+}
+
+type compiledCondition struct {
+	field string
+	key   string
+	re    *regexp.Regexp
+	all   []compiledCondition
+	any   []compiledCondition
+	not   *compiledCondition
+}
+
+type compiledRule struct {
+	Rule
+	when compiledCondition
+}
+
+// Engine evaluates rules in priority order; the first matching rule wins
+type Engine struct {
+	rules           []compiledRule
+	defaultSeverity models.Severity
+}
+
+// NewEngine validates and compiles the rules. Higher priorities are evaluated first and
+// rules with equal priority keep their configured order.
+func NewEngine(cfg Config) (*Engine, error) {
+	e := &Engine{defaultSeverity: cfg.DefaultSeverity}
+	if e.defaultSeverity == "" {
+		e.defaultSeverity = models.SeverityLow
+	}
+	if !e.defaultSeverity.Valid() {
+		return nil, fmt.Errorf("%w: default severity %q", ErrInvalidRule, e.defaultSeverity)
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range cfg.Rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("%w: rule %d has no name", ErrInvalidRule, i)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("%w: duplicate rule name %q", ErrInvalidRule, r.Name)
+		}
+		seen[r.Name] = true
+
+		if !r.Severity.Valid() {
+			return nil, fmt.Errorf("%w: rule %q has severity %q", ErrInvalidRule, r.Name, r.Severity)
+		}
+		when, err := compile(r.When)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %q: %v", ErrInvalidRule, r.Name, err)
+		}
+		e.rules = append(e.rules, compiledRule{Rule: r, when: when})
+	}
+
+	sort.SliceStable(e.rules, func(i, j int) bool {
+		return e.rules[i].Priority > e.rules[j].Priority
+	})
+	return e, nil
+}
+
+// LoadFile reads a JSON rules file
+func LoadFile(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	return NewEngine(cfg)
+}
+
+// Default returns the engine used when no rules file is configured
+func Default() *Engine {
+	e, err := NewEngine(DefaultConfig())
+	if err != nil {
+		panic(fmt.Sprintf("rules: invalid default rules: %v", err))
+	}
+	return e
+}
+
+// DefaultConfig mirrors the original keyword heuristic, now case-insensitive and word-bounded
+func DefaultConfig() Config {
+	return Config{
+		DefaultSeverity: models.SeverityLow,
+		Rules: []Rule{
+			{
+				Name:     "critical-keywords",
+				Priority: 300,
+				When:     Condition{Field: FieldText, Pattern: `\b(critical|production down|data loss|security breach)\b`},
+				Severity: models.SeverityCritical,
+			},
+			{
+				Name:     "high-keywords",
+				Priority: 200,
+				When:     Condition{Field: FieldText, Pattern: `\b(error|errors|failure|failing|down|unavailable)\b`},
+				Severity: models.SeverityHigh,
+			},
+			{
+				Name:     "medium-keywords",
+				Priority: 100,
+				When:     Condition{Field: FieldText, Pattern: `\b(warning|degraded|slow|high memory)\b`},
+				Severity: models.SeverityMedium,
+			},
+		},
+	}
+}
+
+// Rules returns the rules in evaluation order
+func (e *Engine) Rules() []Rule {
+	rules := make([]Rule, 0, len(e.rules))
+	for _, r := range e.rules {
+		rules = append(rules, r.Rule)
+	}
+	return rules
+}
+
+// Evaluate returns the result of the highest-priority matching rule, or the default severity
+func (e *Engine) Evaluate(in Input) Result {
+	var explanation []string
+
+	for _, r := range e.rules {
+		if r.Disabled {
+			explanation = append(explanation, fmt.Sprintf("rule %q skipped: disabled", r.Name))
+			continue
+		}
+
+		var matches []Match
+		if !r.when.eval(in, &matches) {
+			explanation = append(explanation, fmt.Sprintf("rule %q (priority %d) did not match", r.Name, r.Priority))
+			continue
+		}
+
+		reasons := make([]string, 0, len(matches))
+		for _, m := range matches {
+			reasons = append(reasons, fmt.Sprintf("%s matched /%s/ on %q", m.Field, m.Pattern, m.Text))
+		}
+		reason := "conditions matched"
+		if len(reasons) > 0 {
+			reason = strings.Join(reasons, "; ")
+		}
+		explanation = append(explanation, fmt.Sprintf("rule %q (priority %d) fired: %s -> severity %s", r.Name, r.Priority, reason, r.Severity))
+
+		return Result{
+			Severity:    r.Severity,
+			Rule:        r.Name,
+			Tags:        append([]string(nil), r.Tags...),
+			AssignTo:    r.AssignTo,
+			Explanation: explanation,
+			Matches:     matches,
+		}
+	}
+
+	explanation = append(explanation, fmt.Sprintf("no rule matched; using default severity %s", e.defaultSeverity))
+	return Result{
+		Severity:    e.defaultSeverity,
+		Explanation: explanation,
+	}
+}
+
+func compile(c Condition) (compiledCondition, error) {
+	leaf := c.Field != "" || c.Pattern != ""
+	combos := 0
+	if len(c.All) > 0 {
+		combos++
+	}
+	if len(c.Any) > 0 {
+		combos++
+	}
+	if c.Not != nil {
+		combos++
+	}
+
+	switch {
+	case leaf && combos > 0:
+		return compiledCondition{}, errors.New("a condition cannot combine field/pattern with all/any/not")
+	case !leaf && combos == 0:
+		return compiledCondition{}, errors.New("empty condition")
+	case combos > 1:
+		return compiledCondition{}, errors.New("use exactly one of all, any or not per condition")
+	}
+
+	if leaf {
+		switch c.Field {
+		case FieldTitle, FieldDescription, FieldText, FieldSource, FieldTags, FieldMetadataKey, FieldMetadata, FieldLogs:
+		default:
+			return compiledCondition{}, fmt.Errorf("unknown field %q", c.Field)
+		}
+		re, err := regexp.Compile("(?i)" + c.Pattern)
+		if err != nil {
+			return compiledCondition{}, fmt.Errorf("invalid pattern %q: %v", c.Pattern, err)
+		}
+		return compiledCondition{field: c.Field, key: c.Key, re: re}, nil
+	}
+
+	var out compiledCondition
+	for _, sub := range c.All {
+		cc, err := compile(sub)
+		if err != nil {
+			return compiledCondition{}, err
+		}
+		out.all = append(out.all, cc)
+	}
+	for _, sub := range c.Any {
+		cc, err := compile(sub)
+		if err != nil {
+			return compiledCondition{}, err
+		}
+		out.any = append(out.any, cc)
+	}
+	if c.Not != nil {
+		cc, err := compile(*c.Not)
+		if err != nil {
+			return compiledCondition{}, err
+		}
+		out.not = &cc
+	}
+	return out, nil
+}
+
+// eval reports whether the condition holds, appending the leaf matches that made it true
+func (c compiledCondition) eval(in Input, matches *[]Match) bool {
+	switch {
+	case c.re != nil:
+		for _, text := range c.values(in) {
+			if loc := c.re.FindStringIndex(text); loc != nil {
+				*matches = append(*matches, Match{Field: c.fieldLabel(), Pattern: strings.TrimPrefix(c.re.String(), "(?i)"), Text: text[loc[0]:loc[1]]})
+				return true
+			}
+		}
+		return false
+	case len(c.all) > 0:
+		var collected []Match
+		for _, sub := range c.all {
+			if !sub.eval(in, &collected) {
+				return false
+			}
+		}
+		*matches = append(*matches, collected...)
+		return true
+	case len(c.any) > 0:
+		for _, sub := range c.any {
+			if sub.eval(in, matches) {
+				return true
+			}
+		}
+		return false
+	case c.not != nil:
+		var ignored []Match
+		return !c.not.eval(in, &ignored)
+	}
+	return false
+}
+
+// values returns the strings a leaf condition is matched against
+func (c compiledCondition) values(in Input) []string {
+	switch c.field {
+	case FieldTitle:
+		return []string{in.Title}
+	case FieldDescription:
+		return []string{in.Description}
+	case FieldText:
+		return []string{in.Title + " " + in.Description}
+	case FieldSource:
+		return []string{in.Source}
+	case FieldTags:
+		return in.Tags
+	case FieldLogs:
+		return in.Logs
+	case FieldMetadataKey:
+		keys := make([]string, 0, len(in.Metadata))
+		for k := range in.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	case FieldMetadata:
+		if c.key != "" {
+			v, ok := in.Metadata[c.key]
+			if !ok {
+				return nil
+			}
+			return []string{fmt.Sprint(v)}
+		}
+		pairs := make([]string, 0, len(in.Metadata))
+		for k, v := range in.Metadata {
+			pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+		}
+		sort.Strings(pairs)
+		return pairs
+	}
+	return nil
+}
+
+func (c compiledCondition) fieldLabel() string {
+	if c.field == FieldMetadata && c.key != "" {
+		return "metadata." + c.key
+	}
+	return c.field
+}
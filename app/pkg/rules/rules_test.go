@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+func TestDefaultRulesAreCaseInsensitive(t *testing.T) {
+	engine := Default()
+
+	tests := []struct {
+		title    string
+		expected models.Severity
+	}{
+		{"CRITICAL: DB down", models.SeverityCritical},
+		{"Checkout Unavailable", models.SeverityHigh},
+		{"Search is SLOW", models.SeverityMedium},
+		{"Download page typo", models.SeverityLow},
+	}
+
+	for _, tt := range tests {
+		result := engine.Evaluate(Input{Title: tt.title})
+		if result.Severity != tt.expected {
+			t.Errorf("%q: expected %s, got %s (%s)", tt.title, tt.expected, result.Severity, strings.Join(result.Explanation, "; "))
+		}
+	}
+}
+
+func TestLoadFileEvaluatesPriorityAndCombinations(t *testing.T) {
+	engine, err := LoadFile("testdata/rules.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	in := Input{
+		Title:    "Critical checkout errors",
+		Source:   "payments-api",
+		Logs:     []string{"GET /charge 200", "POST /charge 503 upstream timeout"},
+		Metadata: map[string]interface{}{"environment": "production"},
+	}
+	result := engine.Evaluate(in)
+	if result.Rule != "payments-outage" || result.Severity != models.SeverityCritical {
+		t.Fatalf("expected payments-outage to fire, got %q", result.Rule)
+	}
+	if result.AssignTo != "payments-oncall" || len(result.Tags) != 1 {
+		t.Errorf("expected assignee and tags from rule, got %q %v", result.AssignTo, result.Tags)
+	}
+	last := result.Explanation[len(result.Explanation)-1]
+	if !strings.Contains(last, `rule "payments-outage"`) || !strings.Contains(last, "logs matched") {
+		t.Errorf("expected explanation of the fired rule, got %q", last)
+	}
+	if !strings.Contains(result.Explanation[0], "disabled") {
+		t.Errorf("expected disabled rule to be reported first, got %q", result.Explanation[0])
+	}
+
+	// The not-condition excludes staging, so the next rule by priority applies
+	in.Metadata["environment"] = "staging"
+	if result := engine.Evaluate(in); result.Rule != "critical-keywords" {
+		t.Errorf("expected critical-keywords for staging, got %q", result.Rule)
+	}
+
+	result = engine.Evaluate(Input{Title: "scanner finding", Metadata: map[string]interface{}{"cve_id": "CVE-2024-1"}})
+	if result.Rule != "security-scanner" || result.Severity != models.SeverityHigh {
+		t.Errorf("expected security-scanner to fire, got %q", result.Rule)
+	}
+
+	result = engine.Evaluate(Input{Title: "nothing to see"})
+	if result.Rule != "" || result.Severity != models.SeverityLow {
+		t.Errorf("expected default severity, got %q %s", result.Rule, result.Severity)
+	}
+}
+
+func TestNewEngineValidation(t *testing.T) {
+	tests := []Config{
+		{Rules: []Rule{{Name: "bad-regex", Severity: models.SeverityHigh, When: Condition{Field: FieldTitle, Pattern: "("}}}},
+		{Rules: []Rule{{Name: "bad-field", Severity: models.SeverityHigh, When: Condition{Field: "owner", Pattern: "x"}}}},
+		{Rules: []Rule{{Name: "bad-severity", Severity: "urgent", When: Condition{Field: FieldTitle, Pattern: "x"}}}},
+		{Rules: []Rule{{Name: "empty", Severity: models.SeverityHigh}}},
+	}
+
+	for _, cfg := range tests {
+		if _, err := NewEngine(cfg); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("%s: expected ErrInvalidRule, got %v", cfg.Rules[0].Name, err)
+		}
+	}
+}
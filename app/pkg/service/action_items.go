@@ -0,0 +1,325 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ErrActionItemNotFound is returned when an action item ID does not exist on the incident
+var ErrActionItemNotFound = errors.New("action item not found")
+
+// ErrInvalidActionItem is returned when an action item request fails validation
+var ErrInvalidActionItem = errors.New("invalid action item")
+
+// CreateActionItem adds a manually written action item to an incident
+func (s *IncidentService) CreateActionItem(incidentID string, req *models.CreateActionItemRequest) (*models.ActionItem, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidActionItem)
+	}
+	priority, err := actionItemPriority(req.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, ok := s.store.incidents[incidentID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+
+	item := s.newActionItem(incidentID, req.Title, priority)
+	item.Description = req.Description
+	item.Owner = req.Owner
+	item.DueDate = req.DueDate
+	item.TicketURL = req.TicketURL
+	item.Source = models.ContentSourceHuman
+	s.store.actionItems[incidentID] = append(s.store.actionItems[incidentID], item)
+
+	s.logger.Info("action item created", zap.String("incident_id", incidentID), zap.String("id", item.ID))
+	return withOverdue(item, time.Now()), nil
+}
+
+// PromoteActionItems turns preventive measures from the current RCA into action items.
+// Measures that already have an action item with the same title are skipped.
+func (s *IncidentService) PromoteActionItems(incidentID string, req *models.PromoteActionItemsRequest) ([]*models.ActionItem, error) {
+	priority, err := actionItemPriority(req.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[incidentID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	if incident.RCADocument == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRCANotFound, incidentID)
+	}
+
+	measures := incident.RCADocument.PreventiveMeasures
+	indexes := req.Measures
+	if len(indexes) == 0 {
+		for i := range measures {
+			indexes = append(indexes, i)
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, item := range s.store.actionItems[incidentID] {
+		existing[item.Title] = true
+	}
+
+	source := models.ContentSourceAI
+	if a, ok := incident.RCADocument.Attribution[models.RCASectionPreventiveMeasures]; ok {
+		source = a.Source
+	}
+
+	now := time.Now()
+	promoted := []*models.ActionItem{}
+	for _, i := range indexes {
+		if i < 0 || i >= len(measures) {
+			return nil, fmt.Errorf("%w: no preventive measure %d", ErrInvalidActionItem, i)
+		}
+		title := strings.TrimSpace(measures[i])
+		if title == "" || existing[title] {
+			continue
+		}
+		existing[title] = true
+
+		item := s.newActionItem(incidentID, title, priority)
+		item.Owner = req.Owner
+		item.DueDate = req.DueDate
+		item.Source = source
+		item.RCARevision = incident.RCADocument.Revision
+		s.store.actionItems[incidentID] = append(s.store.actionItems[incidentID], item)
+		promoted = append(promoted, withOverdue(item, now))
+	}
+
+	s.logger.Info("preventive measures promoted", zap.String("incident_id", incidentID), zap.Int("count", len(promoted)))
+	return promoted, nil
+}
+
+// ListIncidentActionItems returns an incident's action items in creation order
+func (s *IncidentService) ListIncidentActionItems(incidentID string) ([]*models.ActionItem, error) {
+	s.store.mu.RLock()
+	_, ok := s.store.incidents[incidentID]
+	s.store.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+
+	return s.ListActionItems(models.ActionItemFilter{IncidentID: incidentID}), nil
+}
+
+// ListActionItems returns action items across incidents, soonest due first
+func (s *IncidentService) ListActionItems(filter models.ActionItemFilter) []*models.ActionItem {
+	now := time.Now()
+	results := []*models.ActionItem{}
+
+	s.store.mu.RLock()
+	for incidentID, items := range s.store.actionItems {
+		if filter.IncidentID != "" && incidentID != filter.IncidentID {
+			continue
+		}
+		for _, item := range items {
+			if filter.Owner != "" && !strings.EqualFold(item.Owner, filter.Owner) {
+				continue
+			}
+			if filter.Status != nil && item.Status != *filter.Status {
+				continue
+			}
+			view := withOverdue(item, now)
+			if filter.Overdue != nil && view.Overdue != *filter.Overdue {
+				continue
+			}
+			results = append(results, view)
+		}
+	}
+	s.store.mu.RUnlock()
+
+	// Items without a due date sort last; ties keep creation order
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		switch {
+		case a.DueDate != nil && b.DueDate != nil && !a.DueDate.Equal(*b.DueDate):
+			return a.DueDate.Before(*b.DueDate)
+		case (a.DueDate == nil) != (b.DueDate == nil):
+			return a.DueDate != nil
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return results
+}
+
+// UpdateActionItem updates an action item, stamping CompletedAt when it is marked done
+func (s *IncidentService) UpdateActionItem(incidentID, itemID string, req *models.UpdateActionItemRequest) (*models.ActionItem, error) {
+	if req.Priority != nil {
+		if _, err := actionItemPriority(*req.Priority); err != nil {
+			return nil, err
+		}
+	}
+	if req.Status != nil && !validActionItemStatus(*req.Status) {
+		return nil, fmt.Errorf("%w: status %q", ErrInvalidActionItem, *req.Status)
+	}
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidActionItem)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	item, _, err := s.findActionItem(incidentID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
+	if req.Description != nil {
+		item.Description = *req.Description
+	}
+	if req.Owner != nil {
+		item.Owner = *req.Owner
+	}
+	if req.DueDate != nil {
+		item.DueDate = req.DueDate
+	}
+	if req.Priority != nil {
+		item.Priority = *req.Priority
+	}
+	if req.TicketURL != nil {
+		item.TicketURL = *req.TicketURL
+	}
+	if req.Status != nil {
+		item.Status = *req.Status
+		if *req.Status == models.ActionItemDone {
+			item.CompletedAt = &now
+		} else {
+			item.CompletedAt = nil
+		}
+	}
+	item.UpdatedAt = now
+	if req.Status != nil && item.Issue != nil {
+		s.queueIssueSync(incidentID, itemID)
+	}
+
+	s.logger.Info("action item updated", zap.String("incident_id", incidentID), zap.String("id", itemID))
+	return withOverdue(item, now), nil
+}
+
+// DeleteActionItem removes an action item from an incident
+func (s *IncidentService) DeleteActionItem(incidentID, itemID string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	_, index, err := s.findActionItem(incidentID, itemID)
+	if err != nil {
+		return err
+	}
+
+	items := s.store.actionItems[incidentID]
+	s.store.actionItems[incidentID] = append(items[:index:index], items[index+1:]...)
+
+	s.logger.Info("action item deleted", zap.String("incident_id", incidentID), zap.String("id", itemID))
+	return nil
+}
+
+// ActionItemCounts returns the number of open and overdue action items by priority
+func (s *IncidentService) ActionItemCounts() (open, overdue map[models.ActionItemPriority]int) {
+	open = make(map[models.ActionItemPriority]int)
+	overdue = make(map[models.ActionItemPriority]int)
+	now := time.Now()
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	for _, items := range s.store.actionItems {
+		for _, item := range items {
+			if !actionItemPending(item) {
+				continue
+			}
+			open[item.Priority]++
+			if isOverdue(item, now) {
+				overdue[item.Priority]++
+			}
+		}
+	}
+	return open, overdue
+}
+
+// newActionItem builds an open action item; callers must hold s.store.mu
+func (s *IncidentService) newActionItem(incidentID, title string, priority models.ActionItemPriority) *models.ActionItem {
+	s.store.counter++
+	now := time.Now()
+	return &models.ActionItem{
+		ID:         fmt.Sprintf("ACT-%d", s.store.counter),
+		IncidentID: incidentID,
+		Title:      title,
+		Priority:   priority,
+		Status:     models.ActionItemOpen,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// findActionItem looks up an item and its index; callers must hold s.store.mu
+func (s *IncidentService) findActionItem(incidentID, itemID string) (*models.ActionItem, int, error) {
+	if _, ok := s.store.incidents[incidentID]; !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	for i, item := range s.store.actionItems[incidentID] {
+		if item.ID == itemID {
+			return item, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("%w: %s", ErrActionItemNotFound, itemID)
+}
+
+// withOverdue returns a copy of item with Overdue computed as of now
+func withOverdue(item *models.ActionItem, now time.Time) *models.ActionItem {
+	view := *item
+	view.Overdue = isOverdue(item, now)
+	if item.Issue != nil {
+		issue := *item.Issue
+		issue.Conflicts = append([]models.IssueConflict(nil), item.Issue.Conflicts...)
+		view.Issue = &issue
+	}
+	return &view
+}
+
+func isOverdue(item *models.ActionItem, now time.Time) bool {
+	return actionItemPending(item) && item.DueDate != nil && now.After(*item.DueDate)
+}
+
+func actionItemPending(item *models.ActionItem) bool {
+	return item.Status == models.ActionItemOpen || item.Status == models.ActionItemInProgress
+}
+
+func actionItemPriority(p models.ActionItemPriority) (models.ActionItemPriority, error) {
+	switch p {
+	case "":
+		return models.ActionItemPriorityMedium, nil
+	case models.ActionItemPriorityHigh, models.ActionItemPriorityMedium, models.ActionItemPriorityLow:
+		return p, nil
+	}
+	return "", fmt.Errorf("%w: priority %q", ErrInvalidActionItem, p)
+}
+
+func validActionItemStatus(status models.ActionItemStatus) bool {
+	switch status {
+	case models.ActionItemOpen, models.ActionItemInProgress, models.ActionItemDone, models.ActionItemWontDo:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+func TestActionItemLifecycle(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+
+	if _, err := service.CreateActionItem(created.ID, &models.CreateActionItemRequest{Owner: "alice"}); !errors.Is(err, ErrInvalidActionItem) {
+		t.Errorf("expected missing title to fail, got %v", err)
+	}
+	if _, err := service.CreateActionItem(created.ID, &models.CreateActionItemRequest{Title: "x", Priority: "urgent"}); !errors.Is(err, ErrInvalidActionItem) {
+		t.Errorf("expected unknown priority to fail, got %v", err)
+	}
+	if _, err := service.CreateActionItem("INC-missing", &models.CreateActionItemRequest{Title: "x"}); !errors.Is(err, ErrIncidentNotFound) {
+		t.Errorf("expected unknown incident to fail, got %v", err)
+	}
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	item, err := service.CreateActionItem(created.ID, &models.CreateActionItemRequest{
+		Title:     "Add pool saturation alert",
+		Owner:     "alice",
+		DueDate:   &yesterday,
+		TicketURL: "https://jira.example.com/browse/OPS-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Priority != models.ActionItemPriorityMedium || item.Status != models.ActionItemOpen || !item.Overdue {
+		t.Errorf("expected open, medium, overdue item, got %+v", item)
+	}
+
+	done := models.ActionItemDone
+	updated, err := service.UpdateActionItem(created.ID, item.ID, &models.UpdateActionItemRequest{Status: &done})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.CompletedAt == nil || updated.Overdue {
+		t.Errorf("expected completed item to no longer be overdue, got %+v", updated)
+	}
+
+	if err := service.DeleteActionItem(created.ID, item.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteActionItem(created.ID, item.ID); !errors.Is(err, ErrActionItemNotFound) {
+		t.Errorf("expected deleted item to be gone, got %v", err)
+	}
+}
+
+func TestPromoteActionItems(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+
+	if _, err := service.PromoteActionItems(created.ID, &models.PromoteActionItemsRequest{}); !errors.Is(err, ErrRCANotFound) {
+		t.Errorf("expected promotion without an RCA to fail, got %v", err)
+	}
+
+	service.GenerateRCA(created.ID)
+	items, err := service.PromoteActionItems(created.ID, &models.PromoteActionItemsRequest{Owner: "bob"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Measure 1" || items[0].Source != models.ContentSourceAI || items[0].RCARevision != 1 {
+		t.Errorf("expected preventive measure promoted with AI source, got %+v", items)
+	}
+
+	// Promoting again does not duplicate items
+	items, _ = service.PromoteActionItems(created.ID, &models.PromoteActionItemsRequest{})
+	if len(items) != 0 {
+		t.Errorf("expected already promoted measure to be skipped, got %+v", items)
+	}
+	if _, err := service.PromoteActionItems(created.ID, &models.PromoteActionItemsRequest{Measures: []int{5}}); !errors.Is(err, ErrInvalidActionItem) {
+		t.Errorf("expected out of range measure to fail, got %v", err)
+	}
+}
+
+func TestListActionItemsAcrossIncidents(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	first, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "First", Description: "Test"})
+	second, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Second", Description: "Test"})
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(72 * time.Hour)
+	service.CreateActionItem(first.ID, &models.CreateActionItemRequest{Title: "No due date", Owner: "alice"})
+	service.CreateActionItem(second.ID, &models.CreateActionItemRequest{Title: "Upcoming", Owner: "alice", DueDate: &future})
+	service.CreateActionItem(second.ID, &models.CreateActionItemRequest{Title: "Late", Owner: "bob", DueDate: &past, Priority: models.ActionItemPriorityHigh})
+
+	all := service.ListActionItems(models.ActionItemFilter{})
+	if len(all) != 3 || all[0].Title != "Late" || all[2].Title != "No due date" {
+		t.Errorf("expected items ordered by due date, got %+v", all)
+	}
+
+	if items := service.ListActionItems(models.ActionItemFilter{Owner: "ALICE"}); len(items) != 2 {
+		t.Errorf("expected 2 items for alice, got %d", len(items))
+	}
+	overdue := true
+	if items := service.ListActionItems(models.ActionItemFilter{Overdue: &overdue}); len(items) != 1 || items[0].Owner != "bob" {
+		t.Errorf("expected only bob's item overdue, got %+v", items)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewMetricsCollector(service))
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gauges := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			gauges[family.GetName()+"/"+metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+	}
+	if gauges["incident_action_items_overdue/high"] != 1 || gauges["incident_action_items_open/medium"] != 2 {
+		t.Errorf("unexpected action item metrics %v", gauges)
+	}
+
+	service.DeleteIncident(second.ID)
+	if items := service.ListActionItems(models.ActionItemFilter{}); len(items) != 1 {
+		t.Errorf("expected deleting an incident to drop its items, got %d", len(items))
+	}
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/blob"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// DefaultAttachmentLimit is the largest attachment accepted unless WithAttachmentLimit overrides it
+const DefaultAttachmentLimit int64 = 100 << 20
+
+const (
+	// attachmentURLExpiry bounds how long presigned download URLs stay valid
+	attachmentURLExpiry = 15 * time.Minute
+	// attachmentLogBytes and attachmentLogLines cap how much of a text attachment is sent for analysis
+	attachmentLogBytes = 1 << 20
+	attachmentLogLines = 500
+)
+
+// ErrAttachmentNotFound is returned when an attachment ID does not exist on the incident
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// ErrAttachmentTooLarge is returned when an upload exceeds the configured size limit
+var ErrAttachmentTooLarge = errors.New("attachment too large")
+
+// ErrInvalidAttachment is returned for empty uploads
+var ErrInvalidAttachment = errors.New("invalid attachment")
+
+// ErrAttachmentsDisabled is returned when no blob store is configured
+var ErrAttachmentsDisabled = errors.New("attachment storage is not configured")
+
+// extensionTypes covers common incident artifacts missing from Go's built-in MIME table
+var extensionTypes = map[string]string{
+	".log":  "text/plain; charset=utf-8",
+	".txt":  "text/plain; charset=utf-8",
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+}
+
+// WithBlobStore stores attachment content in store
+func WithBlobStore(store blob.Store) ServiceOption {
+	return func(s *IncidentService) {
+		s.blobs = store
+	}
+}
+
+// WithAttachmentLimit caps the size of a single attachment in bytes
+func WithAttachmentLimit(maxBytes int64) ServiceOption {
+	return func(s *IncidentService) {
+		s.attachmentLimit = maxBytes
+	}
+}
+
+// AttachmentLimit returns the largest attachment the service accepts, in bytes
+func (s *IncidentService) AttachmentLimit() int64 {
+	return s.attachmentLimit
+}
+
+// UploadAttachment stores r as a new attachment on the incident.
+// The upload is spooled to a temporary file so its size, checksum and content type are known before it is stored.
+func (s *IncidentService) UploadAttachment(incidentID string, req *models.UploadAttachmentRequest, r io.Reader) (*models.Attachment, error) {
+	if s.blobs == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	if _, err := s.GetIncident(incidentID); err != nil {
+		return nil, err
+	}
+
+	spool, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, hash), io.LimitReader(r, s.attachmentLimit+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > s.attachmentLimit {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrAttachmentTooLarge, s.attachmentLimit)
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidAttachment)
+	}
+
+	head := make([]byte, 512)
+	n, _ := spool.ReadAt(head, 0)
+	filename := attachmentFilename(req.Filename)
+
+	s.store.mu.Lock()
+	s.store.counter++
+	id := fmt.Sprintf("ATT-%d", s.store.counter)
+	s.store.mu.Unlock()
+
+	attachment := &models.Attachment{
+		ID:                id,
+		IncidentID:        incidentID,
+		Filename:          filename,
+		ContentType:       sniffContentType(head[:n], filename),
+		Size:              size,
+		SHA256:            hex.EncodeToString(hash.Sum(nil)),
+		Uploader:          req.Uploader,
+		IncludeInAnalysis: req.IncludeInAnalysis,
+		CreatedAt:         time.Now(),
+		Key:               incidentID + "/" + id + "/" + filename,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := s.blobs.Put(ctx, attachment.Key, spool, size, attachment.ContentType); err != nil {
+		s.logger.Error("failed to store attachment", zap.String("incident_id", incidentID), zap.Error(err))
+		return nil, err
+	}
+
+	s.store.mu.Lock()
+	if _, ok := s.store.incidents[incidentID]; !ok {
+		// The incident was deleted while the upload was in flight
+		s.store.mu.Unlock()
+		s.blobs.Delete(ctx, attachment.Key)
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	s.store.attachments[incidentID] = append(s.store.attachments[incidentID], attachment)
+	s.store.mu.Unlock()
+
+	s.logger.Info("attachment uploaded",
+		zap.String("incident_id", incidentID),
+		zap.String("id", id),
+		zap.String("content_type", attachment.ContentType),
+		zap.Int64("size", size),
+	)
+	return s.attachmentView(ctx, attachment), nil
+}
+
+// ListAttachments returns an incident's attachments in upload order
+func (s *IncidentService) ListAttachments(incidentID string) ([]*models.Attachment, error) {
+	s.store.mu.RLock()
+	_, ok := s.store.incidents[incidentID]
+	attachments := append([]*models.Attachment(nil), s.store.attachments[incidentID]...)
+	s.store.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	results := make([]*models.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		results = append(results, s.attachmentView(ctx, attachment))
+	}
+	return results, nil
+}
+
+// GetAttachment returns an attachment's metadata
+func (s *IncidentService) GetAttachment(incidentID, attachmentID string) (*models.Attachment, error) {
+	s.store.mu.RLock()
+	attachment, _, err := s.findAttachment(incidentID, attachmentID)
+	s.store.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return s.attachmentView(ctx, attachment), nil
+}
+
+// OpenAttachment returns an attachment's metadata and content; the caller must close the content
+func (s *IncidentService) OpenAttachment(ctx context.Context, incidentID, attachmentID string) (*models.Attachment, io.ReadCloser, error) {
+	if s.blobs == nil {
+		return nil, nil, ErrAttachmentsDisabled
+	}
+
+	s.store.mu.RLock()
+	attachment, _, err := s.findAttachment(incidentID, attachmentID)
+	s.store.mu.RUnlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.blobs.Get(ctx, attachment.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment removes an attachment and its stored content
+func (s *IncidentService) DeleteAttachment(incidentID, attachmentID string) error {
+	s.store.mu.Lock()
+	attachment, index, err := s.findAttachment(incidentID, attachmentID)
+	if err != nil {
+		s.store.mu.Unlock()
+		return err
+	}
+	attachments := s.store.attachments[incidentID]
+	s.store.attachments[incidentID] = append(attachments[:index:index], attachments[index+1:]...)
+	s.store.mu.Unlock()
+
+	s.deleteBlobs([]string{attachment.Key})
+	s.logger.Info("attachment deleted", zap.String("incident_id", incidentID), zap.String("id", attachmentID))
+	return nil
+}
+
+// findAttachment looks up an attachment and its index; callers must hold s.store.mu
+func (s *IncidentService) findAttachment(incidentID, attachmentID string) (*models.Attachment, int, error) {
+	if _, ok := s.store.incidents[incidentID]; !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	for i, attachment := range s.store.attachments[incidentID] {
+		if attachment.ID == attachmentID {
+			return attachment, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachmentID)
+}
+
+// attachmentView copies an attachment and adds a presigned download URL when the store supports one
+func (s *IncidentService) attachmentView(ctx context.Context, attachment *models.Attachment) *models.Attachment {
+	view := *attachment
+	if s.blobs != nil {
+		url, err := s.blobs.URL(ctx, attachment.Key, attachmentURLExpiry)
+		if err != nil {
+			s.logger.Warn("failed to presign attachment URL", zap.String("id", attachment.ID), zap.Error(err))
+		}
+		view.DownloadURL = url
+	}
+	return &view
+}
+
+// deleteBlobs removes stored content, logging rather than failing on errors
+func (s *IncidentService) deleteBlobs(keys []string) {
+	if s.blobs == nil || len(keys) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			s.logger.Warn("failed to delete attachment content", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// analysisLogs returns the incident's parsed log entries, rendered with normalized timestamps and levels,
+// followed by lines from text attachments marked for analysis.
+// Attachment lines are prefixed with the file name so the model can cite them.
+func (s *IncidentService) analysisLogs(ctx context.Context, incident *models.Incident) []string {
+	s.store.mu.RLock()
+	logs := renderLogEntries(incident.LogEntries)
+	attachments := append([]*models.Attachment(nil), s.store.attachments[incident.ID]...)
+	s.store.mu.RUnlock()
+
+	if s.blobs == nil {
+		return logs
+	}
+	for _, attachment := range attachments {
+		if !attachment.IncludeInAnalysis || !isTextContent(attachment.ContentType) {
+			continue
+		}
+		content, err := s.blobs.Get(ctx, attachment.Key)
+		if err != nil {
+			s.logger.Warn("failed to read attachment for analysis", zap.String("id", attachment.ID), zap.Error(err))
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(content, attachmentLogBytes))
+		content.Close()
+		if err != nil {
+			s.logger.Warn("failed to read attachment for analysis", zap.String("id", attachment.ID), zap.Error(err))
+			continue
+		}
+
+		lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		if len(lines) > attachmentLogLines {
+			lines = lines[len(lines)-attachmentLogLines:]
+		}
+		for _, line := range lines {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				logs = append(logs, fmt.Sprintf("[%s] %s", attachment.Filename, line))
+			}
+		}
+	}
+	return logs
+}
+
+// attachmentFilename reduces a client-supplied name to a safe base name
+func attachmentFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." || name == "" {
+		return "attachment"
+	}
+	return name
+}
+
+// sniffContentType detects the type from the content, falling back to the file extension
+// for content that only sniffs as generic text or binary
+func sniffContentType(head []byte, filename string) string {
+	sniffed := http.DetectContentType(head)
+	if sniffed != "application/octet-stream" && sniffed != "text/plain; charset=utf-8" {
+		return sniffed
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	byExt, ok := extensionTypes[ext]
+	if !ok {
+		byExt = mime.TypeByExtension(ext)
+	}
+	// A text extension on binary content is not trusted
+	if byExt == "" || (isTextContent(byExt) && sniffed == "application/octet-stream") {
+		return sniffed
+	}
+	return byExt
+}
+
+// isTextContent reports whether a content type can be read as log lines
+func isTextContent(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json", "application/xml", "application/yaml", "application/x-yaml", "application/x-ndjson":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "text/")
+}
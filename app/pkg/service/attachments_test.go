@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/blob"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func newAttachmentService(t *testing.T, mockAI *MockAIClient, opts ...ServiceOption) *IncidentService {
+	t.Helper()
+	store, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop(), append([]ServiceOption{WithBlobStore(store)}, opts...)...)
+}
+
+func TestUploadAttachment(t *testing.T) {
+	service := newAttachmentService(t, &MockAIClient{}, WithAttachmentLimit(1024))
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+	attachment, err := service.UploadAttachment(created.ID, &models.UploadAttachmentRequest{Filename: "../../screenshot.txt"}, bytes.NewReader(png))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attachment.Filename != "screenshot.txt" || attachment.ContentType != "image/png" || attachment.Size != int64(len(png)) {
+		t.Errorf("expected sanitized name and sniffed type, got %+v", attachment)
+	}
+	if sum := sha256.Sum256(png); attachment.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected sha256 checksum, got %q", attachment.SHA256)
+	}
+
+	_, content, err := service.OpenAttachment(context.Background(), created.ID, attachment.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if !bytes.Equal(data, png) {
+		t.Error("expected downloaded content to match the upload")
+	}
+
+	if _, err := service.UploadAttachment(created.ID, &models.UploadAttachmentRequest{Filename: "big.log"}, strings.NewReader(strings.Repeat("x", 1025))); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("expected oversized upload to fail, got %v", err)
+	}
+	if _, err := service.UploadAttachment(created.ID, &models.UploadAttachmentRequest{Filename: "empty.log"}, strings.NewReader("")); !errors.Is(err, ErrInvalidAttachment) {
+		t.Errorf("expected empty upload to fail, got %v", err)
+	}
+
+	if err := service.DeleteAttachment(created.ID, attachment.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := service.OpenAttachment(context.Background(), created.ID, attachment.ID); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("expected deleted attachment to be gone, got %v", err)
+	}
+
+	disabled := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	if _, err := disabled.UploadAttachment(created.ID, &models.UploadAttachmentRequest{}, strings.NewReader("x")); !errors.Is(err, ErrAttachmentsDisabled) {
+		t.Errorf("expected uploads without a blob store to fail, got %v", err)
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	tests := []struct {
+		head     string
+		filename string
+		expected string
+	}{
+		{"level=error msg=timeout", "app.log", "text/plain; charset=utf-8"},
+		{`{"kind": "Pod"}`, "pod.json", "application/json"},
+		{"apiVersion: v1\nkind: Pod", "describe.yaml", "application/yaml"},
+		{"JAVA PROFILE 1.0.2\x00\x00\x00\x04", "heap.hprof", "application/octet-stream"},
+		{"\x00\x01\x02binary", "notes.txt", "application/octet-stream"},
+		{"<html><body>x</body></html>", "page.txt", "text/html; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		if got := sniffContentType([]byte(tt.head), tt.filename); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.filename, tt.expected, got)
+		}
+	}
+}
+
+func TestTextAttachmentsFeedAnalysis(t *testing.T) {
+	mockAI := &MockAIClient{}
+	service := newAttachmentService(t, mockAI)
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Test",
+		Description: "Test",
+		Logs:        []string{"inline log"},
+	})
+
+	service.UploadAttachment(created.ID, &models.UploadAttachmentRequest{Filename: "app.log", IncludeInAnalysis: true}, strings.NewReader("ERROR pool exhausted\r\n\nWARN retrying\n"))
+	service.UploadAttachment(created.ID, &models.UploadAttachmentRequest{Filename: "other.log"}, strings.NewReader("not analyzed\n"))
+
+	if _, err := service.AnalyzeIncident(created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"inline log", "[app.log] ERROR pool exhausted", "[app.log] WARN retrying"}
+	if strings.Join(mockAI.lastAnalysis.Logs, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected attachment lines after the incident logs, got %v", mockAI.lastAnalysis.Logs)
+	}
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ErrServiceNotFound is returned when a service is not in the catalog
+var ErrServiceNotFound = errors.New("service not found")
+
+// ErrServiceExists is returned when registering a service name that is already taken
+var ErrServiceExists = errors.New("service already exists")
+
+// ErrInvalidService is returned for catalog entries that fail validation and incidents linked to unknown services
+var ErrInvalidService = errors.New("invalid service")
+
+// maxServiceTier is the least critical tier; tier 1 is the most critical
+const maxServiceTier = 3
+
+// namePattern keeps service, schedule and escalation policy names safe to use in URL paths
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// severityLevels lists assignable severities indexed by Rank-1
+var severityLevels = []models.Severity{models.SeverityLow, models.SeverityMedium, models.SeverityHigh, models.SeverityCritical}
+
+// LoadServiceCatalog reads and validates a JSON service catalog file
+func LoadServiceCatalog(path string) ([]*models.Service, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service catalog: %w", err)
+	}
+
+	var catalog models.ServiceCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid service catalog %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(catalog.Services))
+	services := make([]*models.Service, 0, len(catalog.Services))
+	for i := range catalog.Services {
+		svc := &catalog.Services[i]
+		if err := validateService(svc); err != nil {
+			return nil, fmt.Errorf("service catalog %s: %w", path, err)
+		}
+		key := catalogKey(svc.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("service catalog %s: %w: %s", path, ErrServiceExists, svc.Name)
+		}
+		seen[key] = true
+		services = append(services, svc)
+	}
+	return services, nil
+}
+
+// WithServiceCatalog preloads the service catalog, typically from LoadServiceCatalog
+func WithServiceCatalog(services []*models.Service) ServiceOption {
+	return func(s *IncidentService) {
+		now := time.Now()
+		for _, svc := range services {
+			stored := copyService(svc)
+			stored.CreatedAt = now
+			stored.UpdatedAt = now
+			s.store.services[catalogKey(svc.Name)] = stored
+		}
+	}
+}
+
+// CreateService registers a service in the catalog
+func (s *IncidentService) CreateService(req *models.Service) (*models.Service, error) {
+	svc := copyService(req)
+	if err := validateService(svc); err != nil {
+		return nil, err
+	}
+	svc.CreatedAt = time.Now()
+	svc.UpdatedAt = svc.CreatedAt
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := catalogKey(svc.Name)
+	if _, ok := s.store.services[key]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrServiceExists, svc.Name)
+	}
+	s.store.services[key] = svc
+
+	s.logger.Info("service registered", zap.String("service", svc.Name), zap.String("team", svc.Team))
+	return copyService(svc), nil
+}
+
+// ListServices returns catalog entries sorted by name, optionally only those owned by team
+func (s *IncidentService) ListServices(team string) []*models.Service {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.Service, 0, len(s.store.services))
+	for _, svc := range s.store.services {
+		if team != "" && !strings.EqualFold(svc.Team, team) {
+			continue
+		}
+		result = append(result, copyService(svc))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// GetService returns a catalog entry by name, case-insensitively
+func (s *IncidentService) GetService(name string) (*models.Service, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	svc, ok := s.store.services[catalogKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+	return copyService(svc), nil
+}
+
+// UpdateService changes the provided fields of a catalog entry
+func (s *IncidentService) UpdateService(name string, req *models.UpdateServiceRequest) (*models.Service, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	existing, ok := s.store.services[catalogKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+
+	svc := copyService(existing)
+	if req.Team != nil {
+		svc.Team = *req.Team
+	}
+	if req.Description != nil {
+		svc.Description = *req.Description
+	}
+	if req.Tier != nil {
+		svc.Tier = *req.Tier
+	}
+	if req.OnCallSchedule != nil {
+		svc.OnCallSchedule = *req.OnCallSchedule
+	}
+	if req.EscalationPolicy != nil {
+		svc.EscalationPolicy = *req.EscalationPolicy
+	}
+	if req.Runbooks != nil {
+		svc.Runbooks = req.Runbooks
+	}
+	if req.Dependencies != nil {
+		svc.Dependencies = req.Dependencies
+	}
+	if req.SeverityFloor != nil {
+		svc.SeverityFloor = *req.SeverityFloor
+	}
+	if err := validateService(svc); err != nil {
+		return nil, err
+	}
+	svc.UpdatedAt = time.Now()
+	s.store.services[catalogKey(name)] = svc
+
+	s.logger.Info("service updated", zap.String("service", svc.Name))
+	return copyService(svc), nil
+}
+
+// DeleteService removes a service from the catalog; incidents keep their service name
+func (s *IncidentService) DeleteService(name string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := catalogKey(name)
+	if _, ok := s.store.services[key]; !ok {
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+	delete(s.store.services, key)
+
+	s.logger.Info("service deleted", zap.String("service", name))
+	return nil
+}
+
+// ServiceDependencies returns the services a service depends on and the services that depend on it,
+// following the graph transitively
+func (s *IncidentService) ServiceDependencies(name string) (*models.ServiceDependencies, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	svc, ok := s.store.services[catalogKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+
+	return &models.ServiceDependencies{
+		Service:    svc.Name,
+		Upstream:   s.walkDependencies(svc.Name, func(name string) []string { return s.serviceDependencies(name) }),
+		Downstream: s.dependentServices(svc.Name),
+	}, nil
+}
+
+// dependentServices returns the services that depend on name, directly or transitively; callers must hold s.store.mu
+func (s *IncidentService) dependentServices(name string) []models.DependencyNode {
+	dependents := make(map[string][]string)
+	for _, other := range s.store.services {
+		for _, dep := range other.Dependencies {
+			dependents[catalogKey(dep)] = append(dependents[catalogKey(dep)], other.Name)
+		}
+	}
+	return s.walkDependencies(name, func(name string) []string {
+		return dependents[catalogKey(name)]
+	})
+}
+
+// walkDependencies visits the graph breadth-first from start, so each service is reported at its shortest
+// distance and cycles terminate; callers must hold s.store.mu
+func (s *IncidentService) walkDependencies(start string, next func(string) []string) []models.DependencyNode {
+	visited := map[string]bool{catalogKey(start): true}
+	nodes := make([]models.DependencyNode, 0)
+	frontier := []string{start}
+	for depth := 1; len(frontier) > 0; depth++ {
+		var following []string
+		for _, name := range frontier {
+			for _, neighbour := range next(name) {
+				key := catalogKey(neighbour)
+				if visited[key] {
+					continue
+				}
+				visited[key] = true
+				node := models.DependencyNode{Name: neighbour, Depth: depth}
+				if svc, ok := s.store.services[key]; ok {
+					node.Name = svc.Name
+					node.Team = svc.Team
+					node.Tier = svc.Tier
+					node.Registered = true
+				}
+				nodes = append(nodes, node)
+				following = append(following, neighbour)
+			}
+		}
+		frontier = following
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Depth != nodes[j].Depth {
+			return nodes[i].Depth < nodes[j].Depth
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
+
+// serviceDependencies returns the direct dependencies of a registered service; callers must hold s.store.mu
+func (s *IncidentService) serviceDependencies(name string) []string {
+	if svc, ok := s.store.services[catalogKey(name)]; ok {
+		return svc.Dependencies
+	}
+	return nil
+}
+
+// linkService resolves the catalog entry for a new incident from the requested name or, failing that,
+// its service tags and metadata; callers must hold s.store.mu
+func (s *IncidentService) linkService(incident *models.Incident, requested string) (*models.Service, error) {
+	if requested != "" {
+		svc, ok := s.store.services[catalogKey(requested)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown service %q", ErrInvalidService, requested)
+		}
+		incident.Service = svc.Name
+		return copyService(svc), nil
+	}
+
+	// Metadata is more deliberate than tags, so it wins when both name a registered service
+	candidates := incidentServices(incident)
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		mi := strings.HasPrefix(candidates[names[i]], "metadata:")
+		mj := strings.HasPrefix(candidates[names[j]], "metadata:")
+		if mi != mj {
+			return mi
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		if svc, ok := s.store.services[name]; ok {
+			incident.Service = svc.Name
+			return copyService(svc), nil
+		}
+	}
+	return nil, nil
+}
+
+// serviceSeverity raises an automatically assigned severity one level for tier 1 services and up to the
+// service's severity floor, returning the severity and a note explaining any change
+func serviceSeverity(svc *models.Service, severity models.Severity) (models.Severity, string) {
+	if svc == nil {
+		return severity, ""
+	}
+
+	adjusted := severity
+	var reasons []string
+	if svc.Tier == 1 && adjusted.Valid() && adjusted != models.SeverityCritical {
+		adjusted = severityLevels[adjusted.Rank()]
+		reasons = append(reasons, "tier 1 service")
+	}
+	if svc.SeverityFloor.Valid() && adjusted.Rank() < svc.SeverityFloor.Rank() {
+		adjusted = svc.SeverityFloor
+		reasons = append(reasons, fmt.Sprintf("severity floor %s", svc.SeverityFloor))
+	}
+	if adjusted == severity {
+		return severity, ""
+	}
+	return adjusted, fmt.Sprintf("raised from %s to %s for %s (%s)", severity, adjusted, svc.Name, strings.Join(reasons, ", "))
+}
+
+// serviceContext describes the incident's service, its upstream dependencies and any trouble upstream
+// for the analysis prompt; callers must hold s.store.mu
+func (s *IncidentService) serviceContext(incident *models.Incident) string {
+	svc, ok := s.store.services[catalogKey(incident.Service)]
+	if !ok {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Service: %s (team %s", svc.Name, svc.Team)
+	if svc.Tier > 0 {
+		fmt.Fprintf(&b, ", tier %d", svc.Tier)
+	}
+	b.WriteString(")")
+	if svc.Description != "" {
+		fmt.Fprintf(&b, "\n%s", svc.Description)
+	}
+	for _, runbook := range svc.Runbooks {
+		fmt.Fprintf(&b, "\nRunbook: %s", runbook)
+	}
+
+	upstream := s.walkDependencies(svc.Name, s.serviceDependencies)
+	if len(upstream) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n\nUpstream dependencies (depth 1 is called directly):")
+	upstreamKeys := make(map[string]bool, len(upstream))
+	for _, node := range upstream {
+		upstreamKeys[catalogKey(node.Name)] = true
+		fmt.Fprintf(&b, "\n- %s (depth %d", node.Name, node.Depth)
+		if node.Team != "" {
+			fmt.Fprintf(&b, ", team %s", node.Team)
+		}
+		if node.Tier > 0 {
+			fmt.Fprintf(&b, ", tier %d", node.Tier)
+		}
+		b.WriteString(")")
+	}
+
+	var open []*models.Incident
+	for _, other := range s.store.incidents {
+		if other.ID == incident.ID || !upstreamKeys[catalogKey(other.Service)] {
+			continue
+		}
+		if other.Status == models.StatusOpen || other.Status == models.StatusInProgress {
+			open = append(open, other)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].CreatedAt.Before(open[j].CreatedAt) })
+	if len(open) > 0 {
+		b.WriteString("\n\nOpen incidents on upstream services:")
+		for _, other := range open {
+			fmt.Fprintf(&b, "\n- %s [%s] %s on %s, opened %s", other.ID, other.Severity, other.Title, other.Service,
+				other.CreatedAt.UTC().Format(time.RFC3339))
+		}
+	}
+
+	window := s.changeWindow
+	if window <= 0 {
+		window = DefaultChangeWindow
+	}
+	var changes []string
+	for _, change := range s.store.changes {
+		before := incident.CreatedAt.Sub(change.Timestamp)
+		if before < 0 || before > window || !upstreamKeys[catalogKey(change.Service)] {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s, %d minutes before the incident",
+			changeDescription(models.CorrelatedChange{Change: *change}), int(before.Minutes())))
+	}
+	if len(changes) > 0 {
+		b.WriteString("\n\nRecent changes to upstream services:")
+		for _, change := range changes {
+			fmt.Fprintf(&b, "\n- %s", change)
+		}
+	}
+
+	if len(open) > 0 || len(changes) > 0 {
+		b.WriteString("\n\nConsider whether the upstream incidents or changes above caused this incident.")
+	}
+	return b.String()
+}
+
+// validateService checks a catalog entry, normalising its dependency list
+func validateService(svc *models.Service) error {
+	svc.Name = strings.TrimSpace(svc.Name)
+	svc.Team = strings.TrimSpace(svc.Team)
+	if !namePattern.MatchString(svc.Name) {
+		return fmt.Errorf("%w: name must be letters, digits, '.', '_' or '-'", ErrInvalidService)
+	}
+	if svc.Team == "" {
+		return fmt.Errorf("%w: team is required for %s", ErrInvalidService, svc.Name)
+	}
+	if svc.Tier < 0 || svc.Tier > maxServiceTier {
+		return fmt.Errorf("%w: tier must be between 1 and %d", ErrInvalidService, maxServiceTier)
+	}
+	if svc.SeverityFloor != "" && !svc.SeverityFloor.Valid() {
+		return fmt.Errorf("%w: invalid severity floor %q", ErrInvalidService, svc.SeverityFloor)
+	}
+
+	deps := make([]string, 0, len(svc.Dependencies))
+	for _, dep := range svc.Dependencies {
+		dep = strings.TrimSpace(dep)
+		if dep == "" {
+			continue
+		}
+		if strings.EqualFold(dep, svc.Name) {
+			return fmt.Errorf("%w: %s cannot depend on itself", ErrInvalidService, svc.Name)
+		}
+		deps = appendUnique(deps, dep)
+	}
+	svc.Dependencies = deps
+	return nil
+}
+
+func catalogKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// copyService returns a copy safe to hand out after the store lock is released
+func copyService(svc *models.Service) *models.Service {
+	c := *svc
+	c.Runbooks = append([]string(nil), svc.Runbooks...)
+	c.Dependencies = append([]string(nil), svc.Dependencies...)
+	return &c
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func newCatalogService(t *testing.T, mockAI *MockAIClient) *IncidentService {
+	t.Helper()
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop())
+	for _, svc := range []models.Service{
+		{Name: "checkout", Team: "payments", Tier: 1, Runbooks: []string{"https://runbooks.example.com/checkout"}, Dependencies: []string{"payments-api", "cart"}},
+		{Name: "payments-api", Team: "payments", Tier: 1, Dependencies: []string{"ledger-db", "checkout"}},
+		{Name: "ledger-db", Team: "data", Tier: 2},
+		{Name: "cart", Team: "storefront", Tier: 3, SeverityFloor: models.SeverityMedium},
+	} {
+		svc := svc
+		if _, err := service.CreateService(&svc); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return service
+}
+
+func TestCreateIncidentAppliesServiceDefaults(t *testing.T) {
+	service := newCatalogService(t, &MockAIClient{})
+
+	// Inferred from a tag; a rules severity of high is bumped for tier 1 and the owning team is assigned
+	incident, err := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout errors",
+		Description: "5xx on /pay",
+		Tags:        []string{"service:Checkout"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if incident.Service != "checkout" || incident.AssignedTo != "payments" {
+		t.Errorf("expected checkout owned by payments, got service %q assigned to %q", incident.Service, incident.AssignedTo)
+	}
+	if incident.Severity != models.SeverityCritical || !strings.Contains(incident.SeverityAdjustment, "tier 1") {
+		t.Errorf("expected tier 1 bump to critical, got %s (%q)", incident.Severity, incident.SeverityAdjustment)
+	}
+
+	// The floor lifts the default low severity; an explicit assignee wins over the team
+	incident, _ = service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Cart badge stale",
+		Description: "count lags",
+		Service:     "cart",
+		AssignedTo:  "bob",
+	})
+	if incident.Severity != models.SeverityMedium || incident.AssignedTo != "bob" {
+		t.Errorf("expected floor medium assigned to bob, got %s assigned to %q", incident.Severity, incident.AssignedTo)
+	}
+
+	// A severity chosen by a person is never adjusted
+	low := models.SeverityLow
+	incident, _ = service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout typo",
+		Description: "copy",
+		Service:     "checkout",
+		Severity:    &low,
+	})
+	if incident.Severity != models.SeverityLow || incident.SeverityAdjustment != "" {
+		t.Errorf("expected user severity to stand, got %s (%q)", incident.Severity, incident.SeverityAdjustment)
+	}
+
+	if _, err := service.CreateIncident(&models.CreateIncidentRequest{Title: "x", Description: "y", Service: "search"}); !errors.Is(err, ErrInvalidService) {
+		t.Errorf("expected ErrInvalidService for an unknown service, got %v", err)
+	}
+}
+
+func TestServiceDependencies(t *testing.T) {
+	service := newCatalogService(t, &MockAIClient{})
+
+	deps, err := service.ServiceDependencies("checkout")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// payments-api depends back on checkout; the walk must still terminate
+	var upstream []string
+	for _, node := range deps.Upstream {
+		upstream = append(upstream, node.Name)
+	}
+	if strings.Join(upstream, ",") != "cart,payments-api,ledger-db" || deps.Upstream[2].Depth != 2 {
+		t.Errorf("unexpected upstream graph: %+v", deps.Upstream)
+	}
+	if len(deps.Downstream) != 1 || deps.Downstream[0].Name != "payments-api" {
+		t.Errorf("expected payments-api downstream, got %+v", deps.Downstream)
+	}
+
+	if _, err := service.ServiceDependencies("search"); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("expected ErrServiceNotFound, got %v", err)
+	}
+}
+
+func TestServiceValidation(t *testing.T) {
+	service := newCatalogService(t, &MockAIClient{})
+
+	tests := []struct {
+		name string
+		svc  models.Service
+		err  error
+	}{
+		{"duplicate", models.Service{Name: "Checkout", Team: "payments"}, ErrServiceExists},
+		{"no team", models.Service{Name: "search"}, ErrInvalidService},
+		{"bad name", models.Service{Name: "search/api", Team: "search"}, ErrInvalidService},
+		{"bad tier", models.Service{Name: "search", Team: "search", Tier: 4}, ErrInvalidService},
+		{"bad floor", models.Service{Name: "search", Team: "search", SeverityFloor: "sev1"}, ErrInvalidService},
+		{"self dependency", models.Service{Name: "search", Team: "search", Dependencies: []string{"search"}}, ErrInvalidService},
+	}
+	for _, tt := range tests {
+		if _, err := service.CreateService(&tt.svc); !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+
+	tier := 2
+	updated, err := service.UpdateService("checkout", &models.UpdateServiceRequest{Tier: &tier})
+	if err != nil || updated.Tier != 2 || updated.Team != "payments" {
+		t.Errorf("expected tier 2 with team unchanged, got %+v (%v)", updated, err)
+	}
+}
+
+func TestAnalyzeIncidentWithUpstreamContext(t *testing.T) {
+	mockAI := &MockAIClient{}
+	service := newCatalogService(t, mockAI)
+
+	upstream, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Ledger slow", Description: "p99 up", Service: "ledger-db"})
+	if _, err := service.RecordChange("api", &models.CreateChangeRequest{Service: "payments-api", Version: "v7"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx", Service: "checkout"})
+
+	if _, err := service.AnalyzeIncident(incident.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := mockAI.lastAnalysis.AdditionalContext["service"]
+	for _, want := range []string{
+		"Service: checkout (team payments, tier 1)",
+		"Runbook: https://runbooks.example.com/checkout",
+		"- ledger-db (depth 2, team data, tier 2)",
+		upstream.ID + " [medium] Ledger slow on ledger-db",
+		"deploy of payments-api v7",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected service context to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestLoadServiceCatalog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "services.json")
+	os.WriteFile(path, []byte(`{"services": [{"name": "checkout", "team": "payments", "tier": 1, "dependencies": ["cart", " cart "]}]}`), 0o644)
+
+	services, err := LoadServiceCatalog(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithServiceCatalog(services))
+	svc, err := service.GetService("CHECKOUT")
+	if err != nil || len(svc.Dependencies) != 1 || svc.CreatedAt.IsZero() {
+		t.Errorf("expected checkout loaded with one dependency, got %+v (%v)", svc, err)
+	}
+
+	os.WriteFile(path, []byte(`{"services": [{"name": "a", "team": "x"}, {"name": "A", "team": "y"}]}`), 0o644)
+	if _, err := LoadServiceCatalog(path); !errors.Is(err, ErrServiceExists) {
+		t.Errorf("expected duplicate names to be rejected, got %v", err)
+	}
+}
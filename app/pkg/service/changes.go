@@ -0,0 +1,327 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// DefaultChangeWindow is how long before an incident changes are correlated with it
+const DefaultChangeWindow = 6 * time.Hour
+
+// maxChanges bounds the change history kept in memory; the oldest changes are dropped first
+const maxChanges = 10000
+
+// ErrInvalidChange is returned for change events without a service or with an unknown kind
+var ErrInvalidChange = errors.New("invalid change")
+
+// Tag prefixes and metadata keys that name the service an incident is about
+var serviceKeys = []string{"service", "app", "deployment", "component"}
+
+// WithChangeWindow sets how long before an incident changes are correlated with it
+func WithChangeWindow(window time.Duration) ServiceOption {
+	return func(s *IncidentService) {
+		s.changeWindow = window
+	}
+}
+
+// RecordChange stores a change event reported through source, e.g. api or github
+func (s *IncidentService) RecordChange(source string, req *models.CreateChangeRequest) (*models.Change, error) {
+	if strings.TrimSpace(req.Service) == "" {
+		return nil, fmt.Errorf("%w: service is required", ErrInvalidChange)
+	}
+	kind := req.Kind
+	if kind == "" {
+		kind = models.ChangeDeploy
+	}
+	if !kind.Valid() {
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidChange, req.Kind)
+	}
+
+	now := time.Now()
+	change := &models.Change{
+		Service:     strings.TrimSpace(req.Service),
+		Version:     req.Version,
+		Commit:      req.Commit,
+		Author:      req.Author,
+		Environment: req.Environment,
+		Kind:        kind,
+		Description: req.Description,
+		URL:         req.URL,
+		Source:      source,
+		Timestamp:   now,
+		ReceivedAt:  now,
+	}
+	if req.Timestamp != nil {
+		change.Timestamp = *req.Timestamp
+	}
+
+	s.store.mu.Lock()
+	s.store.counter++
+	change.ID = fmt.Sprintf("CHG-%d", s.store.counter)
+	// Keep changes in time order; late webhooks are inserted where they belong
+	i := sort.Search(len(s.store.changes), func(i int) bool {
+		return s.store.changes[i].Timestamp.After(change.Timestamp)
+	})
+	s.store.changes = append(s.store.changes, nil)
+	copy(s.store.changes[i+1:], s.store.changes[i:])
+	s.store.changes[i] = change
+	if len(s.store.changes) > maxChanges {
+		s.store.changes = append([]*models.Change(nil), s.store.changes[len(s.store.changes)-maxChanges:]...)
+	}
+	s.store.mu.Unlock()
+
+	s.logger.Info("change recorded",
+		zap.String("id", change.ID),
+		zap.String("service", change.Service),
+		zap.String("source", source),
+	)
+	return change, nil
+}
+
+// ListChanges returns changes matching the filter, newest first
+func (s *IncidentService) ListChanges(filter models.ChangeFilter) []*models.Change {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.Change, 0)
+	for i := len(s.store.changes) - 1; i >= 0; i-- {
+		change := s.store.changes[i]
+		if filter.Until != nil && change.Timestamp.After(*filter.Until) {
+			continue
+		}
+		if filter.Since != nil && change.Timestamp.Before(*filter.Since) {
+			break
+		}
+		if filter.Service != "" && !strings.EqualFold(change.Service, filter.Service) {
+			continue
+		}
+		if filter.Environment != "" && !strings.EqualFold(change.Environment, filter.Environment) {
+			continue
+		}
+		if filter.Kind != "" && change.Kind != filter.Kind {
+			continue
+		}
+		result = append(result, change)
+	}
+	return result
+}
+
+// IncidentChanges returns the changes correlated with an incident, most suspicious first
+func (s *IncidentService) IncidentChanges(id string) ([]models.CorrelatedChange, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	return s.correlateChanges(incident), nil
+}
+
+// correlateChanges finds changes to the incident's services from the change window before it was
+// opened until it was resolved, and flags the likeliest cause; callers must hold s.store.mu
+func (s *IncidentService) correlateChanges(incident *models.Incident) []models.CorrelatedChange {
+	window := s.changeWindow
+	if window <= 0 {
+		window = DefaultChangeWindow
+	}
+	start := incident.CreatedAt.Add(-window)
+	end := incident.CreatedAt.Add(window)
+	if incident.ResolvedAt != nil && incident.ResolvedAt.Before(end) {
+		end = *incident.ResolvedAt
+	}
+
+	services := incidentServices(incident)
+	environment := incidentEnvironment(incident)
+	text := incident.Title + "\n" + incident.Description
+	// Many changes share a service, so each name is looked for in the text once
+	mentioned := make(map[string]bool)
+
+	correlated := make([]models.CorrelatedChange, 0)
+	first := sort.Search(len(s.store.changes), func(i int) bool {
+		return !s.store.changes[i].Timestamp.Before(start)
+	})
+	for _, change := range s.store.changes[first:] {
+		if change.Timestamp.After(end) {
+			break
+		}
+		if environment != "" && change.Environment != "" && !strings.EqualFold(environment, change.Environment) {
+			continue
+		}
+		matchedBy, weight := matchService(change.Service, services, text, mentioned)
+		if matchedBy == "" {
+			continue
+		}
+
+		before := incident.CreatedAt.Sub(change.Timestamp)
+		c := models.CorrelatedChange{
+			Change:        *change,
+			MatchedBy:     matchedBy,
+			MinutesBefore: int(math.Round(before.Minutes())),
+		}
+		// Only changes made before the incident can have caused it; recent changes are likelier causes
+		if before >= 0 {
+			recency := 1 - float64(before)/float64(window)
+			c.Score = math.Round(recency*weight*kindWeight(change.Kind)*100) / 100
+		}
+		correlated = append(correlated, c)
+	}
+
+	sort.SliceStable(correlated, func(i, j int) bool {
+		if correlated[i].Score != correlated[j].Score {
+			return correlated[i].Score > correlated[j].Score
+		}
+		return correlated[i].Change.Timestamp.After(correlated[j].Change.Timestamp)
+	})
+	if len(correlated) > 0 && correlated[0].Score > 0 {
+		correlated[0].Suspect = true
+	}
+	return correlated
+}
+
+// incidentServices collects service names from "service:x"-style tags and metadata, and bare tags,
+// mapped to how they were found
+func incidentServices(incident *models.Incident) map[string]string {
+	services := make(map[string]string)
+	for _, tag := range incident.Tags {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok {
+			services[strings.ToLower(tag)] = "tag:" + tag
+			continue
+		}
+		for _, k := range serviceKeys {
+			if strings.EqualFold(key, k) && value != "" {
+				services[strings.ToLower(value)] = "tag:" + tag
+			}
+		}
+	}
+	for _, k := range serviceKeys {
+		if value, ok := incident.Metadata[k].(string); ok && value != "" {
+			services[strings.ToLower(value)] = "metadata:" + k
+		}
+	}
+	return services
+}
+
+func incidentEnvironment(incident *models.Incident) string {
+	for _, key := range []string{"environment", "env"} {
+		if value, ok := incident.Metadata[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	for _, tag := range incident.Tags {
+		if key, value, ok := strings.Cut(tag, ":"); ok && (key == "environment" || key == "env") {
+			return value
+		}
+	}
+	return ""
+}
+
+// matchService ties a changed service to the incident, weighting explicit tags above a mention in the text.
+// mentioned caches whether each service name appears in text.
+func matchService(service string, services map[string]string, text string, mentioned map[string]bool) (string, float64) {
+	key := strings.ToLower(service)
+	if matchedBy, ok := services[key]; ok {
+		return matchedBy, 1
+	}
+	found, ok := mentioned[key]
+	if !ok {
+		found = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(service) + `\b`).MatchString(text)
+		mentioned[key] = found
+	}
+	if found {
+		return "mention", 0.6
+	}
+	return "", 0
+}
+
+// kindWeight reflects how often each kind of change causes incidents; rollbacks usually fix them
+func kindWeight(kind models.ChangeKind) float64 {
+	switch kind {
+	case models.ChangeRollback:
+		return 0.3
+	case models.ChangeConfig, models.ChangeFeatureFlag:
+		return 0.9
+	}
+	return 1
+}
+
+// recordedDeploys lists recorded changes since the given time as deployments for the list_recent_deploys tool
+func (s *IncidentService) recordedDeploys(service string, since time.Time) []models.Deployment {
+	changes := s.ListChanges(models.ChangeFilter{Service: service, Since: &since})
+	deploys := make([]models.Deployment, 0, len(changes))
+	for _, change := range changes {
+		deploys = append(deploys, models.Deployment{
+			Service:     change.Service,
+			Version:     change.Version,
+			Commit:      change.Commit,
+			Author:      change.Author,
+			Environment: change.Environment,
+			DeployedAt:  change.Timestamp,
+		})
+	}
+	return deploys
+}
+
+// changeDescription renders a change as one line for timelines and prompts
+func changeDescription(c models.CorrelatedChange) string {
+	change := c.Change
+	text := fmt.Sprintf("%s of %s", change.Kind, change.Service)
+	if change.Version != "" {
+		text += " " + change.Version
+	}
+	if change.Commit != "" {
+		text += fmt.Sprintf(" (commit %s)", shortCommit(change.Commit))
+	}
+	if change.Environment != "" {
+		text += " to " + change.Environment
+	}
+	if change.Author != "" {
+		text += " by " + change.Author
+	}
+	text += " at " + change.Timestamp.UTC().Format(time.RFC3339)
+	if c.Suspect {
+		text += " [suspect]"
+	}
+	return text
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+// changeTimelineEvents lists correlated changes as timeline events
+func changeTimelineEvents(changes []models.CorrelatedChange) []timelineEvent {
+	events := make([]timelineEvent, 0, len(changes))
+	for _, c := range changes {
+		events = append(events, timelineEvent{c.Change.Timestamp, "Change: " + changeDescription(c)})
+	}
+	return events
+}
+
+// changesContext renders correlated changes for the analysis prompt
+func changesContext(changes []models.CorrelatedChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Changes to related services (most likely cause first):")
+	for _, c := range changes {
+		fmt.Fprintf(&b, "\n- %s, %d minutes before the incident", changeDescription(c), c.MinutesBefore)
+		if c.Change.Description != "" {
+			fmt.Fprintf(&b, ": %s", c.Change.Description)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestCorrelateChanges(t *testing.T) {
+	mockAI := &MockAIClient{}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop())
+
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Cart and checkout errors",
+		Description: "5xx on /pay",
+		Tags:        []string{"service:checkout"},
+		Metadata:    map[string]interface{}{"environment": "production"},
+	})
+	at := func(offset time.Duration) *time.Time {
+		t := incident.CreatedAt.Add(offset)
+		return &t
+	}
+
+	for _, req := range []models.CreateChangeRequest{
+		{Service: "checkout", Version: "v1.4.2", Commit: "9f8e7d6c5b4a3f2e1d0c", Author: "alice", Environment: "production", Timestamp: at(-30 * time.Minute)},
+		{Service: "checkout", Kind: models.ChangeConfig, Timestamp: at(-3 * time.Hour)},
+		{Service: "cart", Version: "v9", Timestamp: at(-20 * time.Minute)},
+		{Service: "payments", Timestamp: at(-10 * time.Minute)},
+		{Service: "checkout", Timestamp: at(-7 * time.Hour)},
+		{Service: "checkout", Environment: "staging", Timestamp: at(-5 * time.Minute)},
+		{Service: "checkout", Kind: models.ChangeRollback, Version: "v1.4.1", Timestamp: at(10 * time.Minute)},
+	} {
+		req := req
+		if _, err := service.RecordChange("api", &req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	changes, err := service.IncidentChanges(incident.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 correlated changes, got %+v", changes)
+	}
+	suspect := changes[0]
+	if !suspect.Suspect || suspect.Change.Version != "v1.4.2" || suspect.MatchedBy != "tag:service:checkout" || suspect.MinutesBefore != 30 {
+		t.Errorf("expected the checkout deploy to be the suspect, got %+v", suspect)
+	}
+	if changes[1].Change.Service != "cart" || changes[1].MatchedBy != "mention" || changes[1].Suspect {
+		t.Errorf("expected the mentioned cart deploy second, got %+v", changes[1])
+	}
+	rollback := changes[3]
+	if rollback.Change.Kind != models.ChangeRollback || rollback.MinutesBefore != -10 || rollback.Score != 0 {
+		t.Errorf("expected the later rollback last with no score, got %+v", rollback)
+	}
+
+	if _, err := service.AnalyzeIncident(incident.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := mockAI.lastAnalysis.AdditionalContext["changes"]
+	if !strings.Contains(got, "\n- deploy of checkout v1.4.2 (commit 9f8e7d6c5b4a) to production by alice at") || !strings.Contains(got, "[suspect], 30 minutes before") {
+		t.Errorf("expected the suspect change first in the analysis context, got:\n%s", got)
+	}
+
+	if _, err := service.GenerateRCA(incident.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	timeline := strings.Join(mockAI.lastRCA.Timeline, "\n")
+	if !strings.Contains(timeline, "Change: config of checkout") || !strings.Contains(timeline, "Change: rollback of checkout v1.4.1") {
+		t.Errorf("expected changes in the timeline, got:\n%s", timeline)
+	}
+	if strings.Index(timeline, "config of checkout") > strings.Index(timeline, "Created:") {
+		t.Errorf("expected the timeline in time order, got:\n%s", timeline)
+	}
+}
+
+func TestListChanges(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	for i, name := range []string{"checkout", "search", "checkout"} {
+		at := base.Add(time.Duration(i) * time.Hour)
+		service.RecordChange("github", &models.CreateChangeRequest{Service: name, Timestamp: &at})
+	}
+	// A late webhook is placed by its timestamp, not its arrival
+	early := base.Add(-time.Hour)
+	service.RecordChange("api", &models.CreateChangeRequest{Service: "checkout", Kind: models.ChangeFeatureFlag, Timestamp: &early})
+
+	all := service.ListChanges(models.ChangeFilter{})
+	if len(all) != 4 || !all[0].Timestamp.Equal(base.Add(2*time.Hour)) || !all[3].Timestamp.Equal(early) {
+		t.Errorf("expected changes newest first, got %+v", all)
+	}
+
+	since := base
+	checkout := service.ListChanges(models.ChangeFilter{Service: "Checkout", Since: &since})
+	if len(checkout) != 2 || checkout[0].Kind != models.ChangeDeploy || checkout[0].Source != "github" {
+		t.Errorf("expected 2 checkout deploys since base, got %+v", checkout)
+	}
+
+	if deploys := service.recordedDeploys("search", early); len(deploys) != 1 || !deploys[0].DeployedAt.Equal(base.Add(time.Hour)) {
+		t.Errorf("expected recorded changes as deploys for the tool, got %+v", deploys)
+	}
+
+	for _, req := range []models.CreateChangeRequest{{}, {Service: "checkout", Kind: "restart"}} {
+		req := req
+		if _, err := service.RecordChange("api", &req); !errors.Is(err, ErrInvalidChange) {
+			t.Errorf("expected ErrInvalidChange for %+v, got %v", req, err)
+		}
+	}
+}
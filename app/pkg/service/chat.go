@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Chat grounds answers in the most recent log lines only, each cut to a bounded length, so a
+// log-heavy incident cannot crowd the conversation out of the model window
+const (
+	chatContextLogLines   = 200
+	chatContextLineLength = 500
+)
+
+// ChatIncident answers a follow-up question about an incident and persists the exchange
+func (s *IncidentService) ChatIncident(id string, req *models.ChatRequest) (*models.ChatResponse, error) {
+	incident, err := s.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+
+	question := models.ChatMessage{
+		Role:      models.ChatRoleUser,
+		Content:   req.Message,
+		Author:    req.Author,
+		CreatedAt: time.Now(),
+	}
+
+	s.store.mu.RLock()
+	history := s.conversationMessages(id)
+	chatReq := ai.ChatRequest{
+		Context:  buildChatContext(incident, s.store.comments[incident.ID], s.correlateChanges(incident)),
+		Messages: toAIChatMessages(append(history, question)),
+	}
+	s.store.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	resp, err := s.aiClient.Chat(ctx, chatReq)
+	if err != nil {
+		s.logger.Error("failed to chat about incident", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	reply := models.ChatMessage{
+		Role:      models.ChatRoleAssistant,
+		Content:   resp.Message,
+		Model:     s.aiClient.Model(),
+		Provider:  string(s.aiClient.Provider()),
+		CreatedAt: time.Now(),
+	}
+
+	// Only persist the exchange once the model has answered so a failed call can be retried cleanly
+	s.store.mu.Lock()
+	conv, ok := s.store.conversations[id]
+	if !ok {
+		conv = &models.Conversation{IncidentID: id}
+		s.store.conversations[id] = conv
+	}
+	conv.Messages = append(conv.Messages, question, reply)
+	conv.UpdatedAt = reply.CreatedAt
+	snapshot := copyConversation(conv)
+	s.store.mu.Unlock()
+
+	s.logger.Info("incident chat", zap.String("id", id), zap.Int("messages", len(snapshot.Messages)), zap.Int("trimmed", resp.TrimmedMessages))
+	return &models.ChatResponse{
+		Reply:           reply,
+		Conversation:    snapshot,
+		TrimmedMessages: resp.TrimmedMessages,
+	}, nil
+}
+
+// GetConversation returns the persisted chat history for an incident
+func (s *IncidentService) GetConversation(id string) (*models.Conversation, error) {
+	if _, err := s.GetIncident(id); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	conv, ok := s.store.conversations[id]
+	if !ok {
+		return &models.Conversation{IncidentID: id, Messages: []models.ChatMessage{}}, nil
+	}
+	return copyConversation(conv), nil
+}
+
+// conversationMessages returns a copy of the stored messages; callers must hold the store lock
+func (s *IncidentService) conversationMessages(id string) []models.ChatMessage {
+	conv, ok := s.store.conversations[id]
+	if !ok {
+		return nil
+	}
+	return append([]models.ChatMessage(nil), conv.Messages...)
+}
+
+// copyConversation returns a snapshot safe to hand out after the store lock is released
+func copyConversation(conv *models.Conversation) *models.Conversation {
+	return &models.Conversation{
+		IncidentID: conv.IncidentID,
+		Messages:   append([]models.ChatMessage{}, conv.Messages...),
+		UpdatedAt:  conv.UpdatedAt,
+	}
+}
+
+// toAIChatMessages converts persisted messages to the provider-neutral chat format
+func toAIChatMessages(messages []models.ChatMessage) []ai.ChatMessage {
+	result := make([]ai.ChatMessage, 0, len(messages))
+	for _, m := range messages {
+		role := ai.RoleUser
+		if m.Role == models.ChatRoleAssistant {
+			role = ai.RoleAssistant
+		}
+		result = append(result, ai.ChatMessage{Role: role, Content: m.Content})
+	}
+	return result
+}
+
+// buildChatContext renders the incident, its comments, related changes, logs, analysis and RCA as grounding context
+func buildChatContext(incident *models.Incident, comments []*models.Comment, changes []models.CorrelatedChange) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "ID: %s\n", incident.ID)
+	fmt.Fprintf(&b, "Title: %s\n", incident.Title)
+	fmt.Fprintf(&b, "Description: %s\n", incident.Description)
+	fmt.Fprintf(&b, "Status: %s\n", incident.Status)
+	fmt.Fprintf(&b, "Severity: %s\n", incident.Severity)
+	if incident.Source != "" {
+		fmt.Fprintf(&b, "Source: %s\n", incident.Source)
+	}
+	if incident.Service != "" {
+		fmt.Fprintf(&b, "Service: %s\n", incident.Service)
+	}
+	if len(incident.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n", strings.Join(incident.Tags, ", "))
+	}
+	if incident.AssignedTo != "" {
+		fmt.Fprintf(&b, "Assigned to: %s\n", incident.AssignedTo)
+	}
+
+	b.WriteString("\nTimeline:\n")
+	for _, entry := range buildTimeline(incident, comments, changes) {
+		fmt.Fprintf(&b, "- %s\n", entry)
+	}
+
+	if len(incident.Logs) > 0 {
+		b.WriteString("\nLogs:\n")
+		logs := incident.Logs
+		if omitted := len(logs) - chatContextLogLines; omitted > 0 {
+			fmt.Fprintf(&b, "(%d earlier lines omitted)\n", omitted)
+			logs = logs[omitted:]
+		}
+		for _, line := range logs {
+			b.WriteString(ai.TrimLongText(line, chatContextLineLength))
+			b.WriteString("\n")
+		}
+	}
+
+	if a := incident.AIAnalysis; a != nil {
+		b.WriteString("\nPrevious AI analysis:\n")
+		fmt.Fprintf(&b, "Summary: %s\n", a.Summary)
+		writeList(&b, "Findings", a.Findings)
+		writeList(&b, "Root causes", a.RootCauses)
+		writeList(&b, "Recommended actions", a.RecommendedActions)
+		fmt.Fprintf(&b, "Suggested severity: %s\n", a.SeveritySuggestion)
+	}
+
+	if rca := incident.RCADocument; rca != nil {
+		b.WriteString("\nRoot cause analysis:\n")
+		fmt.Fprintf(&b, "Root cause: %s\n", rca.RootCause)
+		fmt.Fprintf(&b, "Impact: %s\n", rca.Impact)
+		fmt.Fprintf(&b, "Immediate resolution: %s\n", rca.ImmediateResolution)
+		writeList(&b, "Preventive measures", rca.PreventiveMeasures)
+		writeList(&b, "Lessons learned", rca.LessonsLearned)
+	}
+
+	return b.String()
+}
+
+// writeList writes a labelled bullet list, skipping empty lists
+func writeList(b *strings.Builder, label string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", label)
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", item)
+	}
+}
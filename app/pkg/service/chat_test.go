@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestChatIncidentPersistsConversation(t *testing.T) {
+	store := NewIncidentStore()
+	mockAI := &MockAIClient{}
+	service := NewIncidentService(store, mockAI, zap.NewNop())
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "API latency",
+		Description: "p99 above 2s",
+		Logs:        []string{"pod api-7f restarted"},
+	})
+	if _, err := service.AnalyzeIncident(created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.ChatIncident(created.ID, &models.ChatRequest{Message: "which pod restarted first?"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := service.ChatIncident(created.ID, &models.ChatRequest{Message: "draft a customer update"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Reply.Content != "Chat reply" {
+		t.Errorf("expected reply 'Chat reply', got %q", resp.Reply.Content)
+	}
+	if len(resp.Conversation.Messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(resp.Conversation.Messages))
+	}
+
+	// The second call must carry the first exchange plus the new question
+	if len(mockAI.lastChat.Messages) != 3 {
+		t.Errorf("expected 3 messages sent to AI, got %d", len(mockAI.lastChat.Messages))
+	}
+	if mockAI.lastChat.Messages[1].Role != ai.RoleAssistant {
+		t.Errorf("expected assistant role, got %q", mockAI.lastChat.Messages[1].Role)
+	}
+	for _, want := range []string{"API latency", "pod api-7f restarted", "Test analysis summary"} {
+		if !strings.Contains(mockAI.lastChat.Context, want) {
+			t.Errorf("expected chat context to contain %q", want)
+		}
+	}
+}
+
+func TestChatIncidentErrorDoesNotPersist(t *testing.T) {
+	store := NewIncidentStore()
+	mockAI := &MockAIClient{chatErr: errors.New("provider down")}
+	service := NewIncidentService(store, mockAI, zap.NewNop())
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Test",
+		Description: "Test",
+	})
+
+	if _, err := service.ChatIncident(created.ID, &models.ChatRequest{Message: "hello"}); err == nil {
+		t.Fatal("expected error from AI client")
+	}
+
+	conv, err := service.GetConversation(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conv.Messages) != 0 {
+		t.Errorf("expected empty conversation, got %d messages", len(conv.Messages))
+	}
+}
+
+func TestChatIncidentNotFound(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+
+	_, err := service.ChatIncident("nonexistent", &models.ChatRequest{Message: "hello"})
+	if !errors.Is(err, ErrIncidentNotFound) {
+		t.Errorf("expected ErrIncidentNotFound, got %v", err)
+	}
+}
+
+func TestChatContextWithLargeLogs(t *testing.T) {
+	mockAI := &MockAIClient{}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop())
+
+	logs := make([]string, 20000)
+	for i := range logs {
+		logs[i] = fmt.Sprintf("ERROR line %d: %s", i, strings.Repeat("x", 200))
+	}
+	logs[len(logs)-1] = "FATAL " + strings.Repeat("y", 5000)
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Log storm", Description: "noisy", Logs: logs})
+	if _, err := service.ChatIncident(created.ID, &models.ChatRequest{Message: "what failed last?"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	grounding := mockAI.lastChat.Context
+	if !strings.Contains(grounding, "(19800 earlier lines omitted)") || strings.Contains(grounding, "ERROR line 19799:") ||
+		!strings.Contains(grounding, "ERROR line 19800:") || strings.Contains(grounding, strings.Repeat("y", 1000)) {
+		t.Errorf("expected only the last 200 log lines, each shortened, got %d characters", len(grounding))
+	}
+
+	// Whatever the context, the system prompt leaves at least half the window for history
+	model, maxTokens := "gpt-4", 1000
+	system := ai.ChatSystemPrompt(model, maxTokens, strings.Join(logs, "\n"))
+	if left := ai.ModelContextWindow(model) - maxTokens - ai.EstimateTokens(system); left < (ai.ModelContextWindow(model)-maxTokens)/2-200 {
+		t.Errorf("expected room for history after a capped context, got %d tokens", left)
+	}
+}
+
+func TestTrimChatHistory(t *testing.T) {
+	messages := []ai.ChatMessage{
+		{Role: ai.RoleUser, Content: strings.Repeat("a", 400)},
+		{Role: ai.RoleAssistant, Content: strings.Repeat("b", 400)},
+		{Role: ai.RoleUser, Content: "latest question"},
+	}
+
+	trimmed, dropped := ai.TrimChatHistory(messages, 150)
+	if len(trimmed) != 1 || dropped != 2 {
+		t.Fatalf("expected only the latest message to remain, got %d (dropped %d)", len(trimmed), dropped)
+	}
+	if trimmed[0].Content != "latest question" {
+		t.Errorf("expected latest question, got %q", trimmed[0].Content)
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ErrCommentNotFound is returned when a comment ID does not exist on the incident
+var ErrCommentNotFound = errors.New("comment not found")
+
+// ErrInvalidComment is returned when a comment request fails validation
+var ErrInvalidComment = errors.New("invalid comment")
+
+// mentionPattern matches @handles that are not part of an email address
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9][A-Za-z0-9._-]*[A-Za-z0-9]|[A-Za-z0-9])`)
+
+// CreateComment adds a comment, or a reply when ParentID is set, to an incident
+func (s *IncidentService) CreateComment(incidentID string, req *models.CreateCommentRequest) (*models.Comment, error) {
+	if strings.TrimSpace(req.Author) == "" {
+		return nil, fmt.Errorf("%w: author is required", ErrInvalidComment)
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, fmt.Errorf("%w: body is required", ErrInvalidComment)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[incidentID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	if req.ParentID != "" {
+		if _, err := s.findComment(incidentID, req.ParentID); err != nil {
+			return nil, fmt.Errorf("%w: parent %s does not exist", ErrInvalidComment, req.ParentID)
+		}
+	}
+
+	s.store.counter++
+	comment := &models.Comment{
+		ID:         fmt.Sprintf("CMT-%d", s.store.counter),
+		IncidentID: incidentID,
+		ParentID:   req.ParentID,
+		Author:     req.Author,
+		Body:       req.Body,
+		Mentions:   parseMentions(req.Body),
+		KeyFinding: req.KeyFinding,
+		CreatedAt:  time.Now(),
+	}
+	s.store.comments[incidentID] = append(s.store.comments[incidentID], comment)
+	if comment.KeyFinding {
+		s.refreshKeyFindings(incident)
+	}
+	if incident.Issue != nil {
+		s.queueIssueSync(incidentID, "")
+	}
+
+	s.logger.Info("comment added",
+		zap.String("incident_id", incidentID),
+		zap.String("id", comment.ID),
+		zap.Strings("mentions", comment.Mentions),
+	)
+	return copyComment(comment), nil
+}
+
+// ListComments returns an incident's comments as threads, oldest first
+func (s *IncidentService) ListComments(incidentID string) ([]*models.Comment, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	if _, ok := s.store.incidents[incidentID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	return commentThreads(s.store.comments[incidentID], ""), nil
+}
+
+// GetComment returns a single comment with its replies
+func (s *IncidentService) GetComment(incidentID, commentID string) (*models.Comment, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	comment, err := s.findComment(incidentID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	view := copyComment(comment)
+	view.Replies = commentThreads(s.store.comments[incidentID], commentID)
+	return view, nil
+}
+
+// UpdateComment edits a comment's body or marks it as a key finding
+func (s *IncidentService) UpdateComment(incidentID, commentID string, req *models.UpdateCommentRequest) (*models.Comment, error) {
+	if req.Body != nil && strings.TrimSpace(*req.Body) == "" {
+		return nil, fmt.Errorf("%w: body is required", ErrInvalidComment)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	comment, err := s.findComment(incidentID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Body != nil && *req.Body != comment.Body {
+		now := time.Now()
+		comment.Body = *req.Body
+		comment.Mentions = parseMentions(*req.Body)
+		comment.EditedAt = &now
+	}
+	if req.KeyFinding != nil {
+		comment.KeyFinding = *req.KeyFinding
+	}
+	s.refreshKeyFindings(s.store.incidents[incidentID])
+
+	s.logger.Info("comment updated", zap.String("incident_id", incidentID), zap.String("id", commentID))
+	return copyComment(comment), nil
+}
+
+// DeleteComment removes a comment together with its replies
+func (s *IncidentService) DeleteComment(incidentID, commentID string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, err := s.findComment(incidentID, commentID); err != nil {
+		return err
+	}
+
+	removed := map[string]bool{commentID: true}
+	kept := []*models.Comment{}
+	// Comments are stored in creation order, so a reply always follows its parent
+	for _, comment := range s.store.comments[incidentID] {
+		if removed[comment.ID] || removed[comment.ParentID] {
+			removed[comment.ID] = true
+			continue
+		}
+		kept = append(kept, comment)
+	}
+	s.store.comments[incidentID] = kept
+	s.refreshKeyFindings(s.store.incidents[incidentID])
+
+	s.logger.Info("comment deleted",
+		zap.String("incident_id", incidentID),
+		zap.String("id", commentID),
+		zap.Int("removed", len(removed)),
+	)
+	return nil
+}
+
+// findComment looks up a comment; callers must hold s.store.mu
+func (s *IncidentService) findComment(incidentID, commentID string) (*models.Comment, error) {
+	if _, ok := s.store.incidents[incidentID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	for _, comment := range s.store.comments[incidentID] {
+		if comment.ID == commentID {
+			return comment, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, commentID)
+}
+
+// refreshKeyFindings mirrors the incident's flagged comments onto the incident; callers must hold s.store.mu
+func (s *IncidentService) refreshKeyFindings(incident *models.Incident) {
+	var findings []models.KeyFinding
+	for _, comment := range s.store.comments[incident.ID] {
+		if comment.KeyFinding {
+			findings = append(findings, models.KeyFinding{
+				CommentID: comment.ID,
+				Author:    comment.Author,
+				Body:      comment.Body,
+				CreatedAt: comment.CreatedAt,
+			})
+		}
+	}
+	incident.KeyFindings = findings
+}
+
+// commentThreads returns copies of the comments under parentID with their replies nested
+func commentThreads(comments []*models.Comment, parentID string) []*models.Comment {
+	children := make(map[string][]*models.Comment)
+	for _, comment := range comments {
+		children[comment.ParentID] = append(children[comment.ParentID], comment)
+	}
+
+	var build func(parent string) []*models.Comment
+	build = func(parent string) []*models.Comment {
+		threads := []*models.Comment{}
+		for _, comment := range children[parent] {
+			view := copyComment(comment)
+			if replies := build(comment.ID); len(replies) > 0 {
+				view.Replies = replies
+			}
+			threads = append(threads, view)
+		}
+		return threads
+	}
+	return build(parentID)
+}
+
+func copyComment(comment *models.Comment) *models.Comment {
+	view := *comment
+	view.Mentions = append([]string(nil), comment.Mentions...)
+	view.Replies = nil
+	return &view
+}
+
+// parseMentions returns the unique @handles in a comment body, in order of appearance
+func parseMentions(body string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		handle := match[1]
+		if !seen[handle] {
+			seen[handle] = true
+			mentions = append(mentions, handle)
+		}
+	}
+	return mentions
+}
+
+// commentNotes renders comments as evidence for the RCA prompt, key findings first
+func commentNotes(comments []*models.Comment) []string {
+	var findings, notes []string
+	for _, comment := range comments {
+		if comment.KeyFinding {
+			findings = append(findings, fmt.Sprintf("[key finding] %s: %s", comment.Author, comment.Body))
+		} else {
+			notes = append(notes, fmt.Sprintf("%s: %s", comment.Author, comment.Body))
+		}
+	}
+	return append(findings, notes...)
+}
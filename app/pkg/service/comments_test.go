@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestCommentThreads(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+
+	if _, err := service.CreateComment(created.ID, &models.CreateCommentRequest{Author: "alice"}); !errors.Is(err, ErrInvalidComment) {
+		t.Errorf("expected empty body to fail, got %v", err)
+	}
+	if _, err := service.CreateComment(created.ID, &models.CreateCommentRequest{Author: "alice", Body: "x", ParentID: "CMT-999"}); !errors.Is(err, ErrInvalidComment) {
+		t.Errorf("expected unknown parent to fail, got %v", err)
+	}
+
+	root, err := service.CreateComment(created.ID, &models.CreateCommentRequest{
+		Author: "alice",
+		Body:   "Pool is exhausted on **db-primary**, cc @bob and @carol.sre (not ops@company.com)",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(root.Mentions, []string{"bob", "carol.sre"}) {
+		t.Errorf("unexpected mentions %v", root.Mentions)
+	}
+
+	reply, _ := service.CreateComment(created.ID, &models.CreateCommentRequest{Author: "bob", Body: "Confirmed", ParentID: root.ID})
+	service.CreateComment(created.ID, &models.CreateCommentRequest{Author: "carol", Body: "+1", ParentID: reply.ID})
+	service.CreateComment(created.ID, &models.CreateCommentRequest{Author: "dave", Body: "Unrelated note"})
+
+	threads, _ := service.ListComments(created.ID)
+	if len(threads) != 2 || len(threads[0].Replies) != 1 || len(threads[0].Replies[0].Replies) != 1 {
+		t.Fatalf("expected nested thread, got %+v", threads)
+	}
+
+	body := "Pool is exhausted on db-primary"
+	edited, err := service.UpdateComment(created.ID, root.ID, &models.UpdateCommentRequest{Body: &body})
+	if err != nil || edited.EditedAt == nil || len(edited.Mentions) != 0 {
+		t.Errorf("expected edit to stamp EditedAt and clear mentions, got %+v, %v", edited, err)
+	}
+
+	if err := service.DeleteComment(created.ID, root.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	threads, _ = service.ListComments(created.ID)
+	if len(threads) != 1 || threads[0].Author != "dave" {
+		t.Errorf("expected deleting a comment to remove its replies, got %+v", threads)
+	}
+	if _, err := service.GetComment(created.ID, reply.ID); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("expected reply to be gone, got %v", err)
+	}
+}
+
+func TestKeyFindingsFeedRCA(t *testing.T) {
+	mockAI := &MockAIClient{}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop())
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+
+	note, _ := service.CreateComment(created.ID, &models.CreateCommentRequest{Author: "alice", Body: "Rolled back at 10:05"})
+	finding, _ := service.CreateComment(created.ID, &models.CreateCommentRequest{Author: "bob", Body: "Deploy 42 halved the pool size"})
+
+	flag := true
+	service.UpdateComment(created.ID, finding.ID, &models.UpdateCommentRequest{KeyFinding: &flag})
+	incident, _ := service.GetIncident(created.ID)
+	if len(incident.KeyFindings) != 1 || incident.KeyFindings[0].CommentID != finding.ID {
+		t.Fatalf("expected key finding on the incident, got %+v", incident.KeyFindings)
+	}
+
+	if _, err := service.GenerateRCA(created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notes := mockAI.lastRCA.Notes
+	if len(notes) != 2 || notes[0] != "[key finding] bob: Deploy 42 halved the pool size" || notes[1] != "alice: Rolled back at 10:05" {
+		t.Errorf("expected key findings first in RCA notes, got %v", notes)
+	}
+	timeline := strings.Join(mockAI.lastRCA.Timeline, "\n")
+	if !strings.Contains(timeline, "Comment by alice") || !strings.Contains(timeline, "Key finding by bob") {
+		t.Errorf("expected comments in the timeline, got %q", timeline)
+	}
+
+	service.DeleteComment(created.ID, finding.ID)
+	service.DeleteComment(created.ID, note.ID)
+	if incident, _ := service.GetIncident(created.ID); len(incident.KeyFindings) != 0 {
+		t.Errorf("expected deleted key finding to disappear, got %+v", incident.KeyFindings)
+	}
+}
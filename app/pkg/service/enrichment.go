@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// enrichmentTimeout bounds a single enrichment run across all enrichers
+const enrichmentTimeout = 30 * time.Second
+
+// ErrEnrichmentDisabled is returned when no enrichers are configured
+var ErrEnrichmentDisabled = errors.New("incident enrichment is not configured")
+
+// Enricher gathers context about an incident from an external system.
+// Enrich returns nil when the incident carries nothing the enricher can look up.
+type Enricher interface {
+	Name() string
+	Enrich(ctx context.Context, incident *models.Incident) (*models.Enrichment, error)
+}
+
+// WithEnricher adds an enricher that runs in the background when incidents are created
+func WithEnricher(e Enricher) ServiceOption {
+	return func(s *IncidentService) {
+		s.enrichers = append(s.enrichers, e)
+	}
+}
+
+// RunEnrichment refreshes an incident's enrichments, e.g. after its tags changed
+func (s *IncidentService) RunEnrichment(id string) (*models.Incident, error) {
+	if len(s.enrichers) == 0 {
+		return nil, ErrEnrichmentDisabled
+	}
+	if err := s.enrich(id); err != nil {
+		return nil, err
+	}
+	return s.GetIncident(id)
+}
+
+// WaitForEnrichments blocks until background enrichments have finished
+func (s *IncidentService) WaitForEnrichments() {
+	s.enrichments.Wait()
+}
+
+func (s *IncidentService) enrichAsync(id string) {
+	defer s.enrichments.Done()
+	if err := s.enrich(id); err != nil && !errors.Is(err, ErrIncidentNotFound) {
+		s.logger.Warn("incident enrichment failed", zap.String("id", id), zap.Error(err))
+	}
+}
+
+// enrich runs every enricher against a snapshot of the incident and stores the results.
+// A failing enricher is recorded on the incident rather than returned.
+func (s *IncidentService) enrich(id string) error {
+	s.store.mu.RLock()
+	incident, ok := s.store.incidents[id]
+	var snapshot models.Incident
+	if ok {
+		snapshot = enrichmentSnapshot(incident)
+	}
+	s.store.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), enrichmentTimeout)
+	defer cancel()
+
+	results := make(map[string]*models.Enrichment, len(s.enrichers))
+	for _, enricher := range s.enrichers {
+		enrichment, err := enricher.Enrich(ctx, &snapshot)
+		if err != nil {
+			s.logger.Warn("enricher failed", zap.String("id", id), zap.String("enricher", enricher.Name()), zap.Error(err))
+			enrichment = &models.Enrichment{
+				Enricher:  enricher.Name(),
+				Status:    models.EnrichmentFailed,
+				Error:     err.Error(),
+				FetchedAt: time.Now(),
+			}
+		}
+		results[enricher.Name()] = enrichment
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok = s.store.incidents[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	enrichments := make(map[string]*models.Enrichment, len(incident.Enrichments)+len(results))
+	for name, enrichment := range incident.Enrichments {
+		enrichments[name] = enrichment
+	}
+	for name, enrichment := range results {
+		// A nil result means the incident no longer names anything to look up
+		if enrichment == nil {
+			delete(enrichments, name)
+			continue
+		}
+		enrichments[name] = enrichment
+	}
+	incident.Enrichments = enrichments
+	incident.UpdatedAt = time.Now()
+
+	s.logger.Info("incident enriched", zap.String("id", id), zap.Int("enrichments", len(enrichments)))
+	return nil
+}
+
+// enrichmentSnapshot copies the fields enrichers read so they can run without the lock;
+// callers must hold s.store.mu
+func enrichmentSnapshot(incident *models.Incident) models.Incident {
+	snapshot := *incident
+	snapshot.Tags = append([]string(nil), incident.Tags...)
+	snapshot.Metadata = make(map[string]interface{}, len(incident.Metadata))
+	for k, v := range incident.Metadata {
+		snapshot.Metadata[k] = v
+	}
+	return snapshot
+}
+
+// enrichmentContext returns successful enrichment summaries keyed by enricher for the analysis prompt;
+// callers must hold s.store.mu
+func enrichmentContext(incident *models.Incident) map[string]string {
+	summaries := make(map[string]string)
+	for name, enrichment := range incident.Enrichments {
+		if enrichment.Status == models.EnrichmentOK && enrichment.Summary != "" {
+			summaries[name] = enrichment.Summary
+		}
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+	return summaries
+}
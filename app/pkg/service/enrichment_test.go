@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/promapi"
+	"go.uber.org/zap"
+)
+
+// fakeEnricher returns a canned enrichment for incidents tagged "app:<name>"
+type fakeEnricher struct {
+	err   error
+	calls int
+}
+
+func (f *fakeEnricher) Name() string { return "fake" }
+
+func (f *fakeEnricher) Enrich(ctx context.Context, incident *models.Incident) (*models.Enrichment, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, tag := range incident.Tags {
+		if tag == "app:checkout" {
+			return &models.Enrichment{Enricher: "fake", Status: models.EnrichmentOK, Summary: "checkout: 1/3 pods ready", FetchedAt: time.Now()}, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestEnrichmentOnCreate(t *testing.T) {
+	mockAI := &MockAIClient{}
+	enricher := &fakeEnricher{}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop(), WithEnricher(enricher))
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout down", Description: "5xx", Tags: []string{"app:checkout"}})
+	service.WaitForEnrichments()
+
+	incident, _ := service.GetIncident(created.ID)
+	enrichment := incident.Enrichments["fake"]
+	if enrichment == nil || enrichment.Status != models.EnrichmentOK {
+		t.Fatalf("expected an enrichment, got %+v", incident.Enrichments)
+	}
+
+	if _, err := service.AnalyzeIncident(created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := mockAI.lastAnalysis.AdditionalContext["fake"]; got != "checkout: 1/3 pods ready" {
+		t.Errorf("expected enrichment summary in the analysis request, got %q", got)
+	}
+
+	// Dropping the tag and re-running removes the stale context
+	service.UpdateIncident(created.ID, &models.UpdateIncidentRequest{Tags: []string{}})
+	incident, err := service.RunEnrichment(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(incident.Enrichments) != 0 {
+		t.Errorf("expected stale enrichment to be removed, got %+v", incident.Enrichments)
+	}
+}
+
+func TestEnrichmentFailure(t *testing.T) {
+	mockAI := &MockAIClient{}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop(), WithEnricher(&fakeEnricher{err: errors.New("forbidden")}))
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	service.WaitForEnrichments()
+
+	incident, _ := service.GetIncident(created.ID)
+	if e := incident.Enrichments["fake"]; e == nil || e.Status != models.EnrichmentFailed || e.Error != "forbidden" {
+		t.Errorf("expected a failed enrichment, got %+v", e)
+	}
+
+	service.AnalyzeIncident(created.ID)
+	if mockAI.lastAnalysis.AdditionalContext != nil {
+		t.Errorf("failed enrichments must not reach the prompt, got %+v", mockAI.lastAnalysis.AdditionalContext)
+	}
+}
+
+func TestRunEnrichmentErrors(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	if _, err := service.RunEnrichment(created.ID); !errors.Is(err, ErrEnrichmentDisabled) {
+		t.Errorf("expected ErrEnrichmentDisabled, got %v", err)
+	}
+
+	service = NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithEnricher(&fakeEnricher{}))
+	if _, err := service.RunEnrichment("INC-missing"); !errors.Is(err, ErrIncidentNotFound) {
+		t.Errorf("expected ErrIncidentNotFound, got %v", err)
+	}
+}
+
+func TestAnalyzeIncidentWithPrometheusContext(t *testing.T) {
+	prom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"service":"checkout"},"values":[[1714557600,"0.2"],[1714557615,"0.2"],[1714557630,"2.4"]]}]}}`))
+	}))
+	defer prom.Close()
+
+	enricher, err := promapi.NewEnricher(promapi.NewClient(prom.URL, ""), promapi.Config{
+		Sources: map[string][]promapi.Query{"alertmanager": {{Name: "p99 latency", Query: "histogram_quantile(0.99, rate(http_request_duration_seconds_bucket[5m]))"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mockAI := &MockAIClient{}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop(), WithEnricher(enricher))
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout slow", Description: "p99 up", Source: "alertmanager"})
+	service.WaitForEnrichments()
+	if _, err := service.AnalyzeIncident(created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := mockAI.lastAnalysis.AdditionalContext["prometheus"]
+	if !strings.Contains(got, `p99 latency{service="checkout"}: avg 0.9333, min 0.2, max 2.4`) {
+		t.Errorf("expected metric summary in the analysis request, got %q", got)
+	}
+}
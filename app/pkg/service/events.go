@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// EventSubscriber receives incident events. HandleEvent is called with the store lock held, so it must
+// return quickly and must not call back into the service.
+type EventSubscriber interface {
+	HandleEvent(event models.Event)
+}
+
+// WithEventSubscriber registers a subscriber for incident events
+func WithEventSubscriber(subscriber EventSubscriber) ServiceOption {
+	return func(s *IncidentService) {
+		s.subscribers = append(s.subscribers, subscriber)
+	}
+}
+
+// publish sends an event with a snapshot of the incident to every subscriber and webhook subscription;
+// callers must hold s.store.mu
+func (s *IncidentService) publish(eventType models.EventType, incident *models.Incident, previous models.Severity) {
+	s.publishEvent(models.Event{Type: eventType, PreviousSeverity: previous}, incident)
+}
+
+// publishEvent numbers and timestamps an event, attaches a snapshot of the incident and delivers it;
+// callers must hold s.store.mu
+func (s *IncidentService) publishEvent(event models.Event, incident *models.Incident) {
+	// Status changes reach a linked issue before the next reconciliation
+	if (event.Type == models.EventIncidentUpdated || event.Type == models.EventIncidentAcknowledged) &&
+		incident.Issue != nil && incidentIssueState(incident.Status) != incident.Issue.SyncedState {
+		s.queueIssueSync(incident.ID, "")
+	}
+	if len(s.subscribers) == 0 && len(s.store.subscriptions) == 0 {
+		return
+	}
+	event.ID = fmt.Sprintf("EVT-%d", s.eventCounter.Add(1))
+	event.At = s.now()
+	event.Incident = snapshotIncident(incident)
+	for _, subscriber := range s.subscribers {
+		subscriber.HandleEvent(event)
+	}
+	s.queueWebhooks(event)
+}
+
+// publishUpdate publishes incident.updated, plus incident.severity_changed and incident.resolved when they
+// apply; callers must hold s.store.mu
+func (s *IncidentService) publishUpdate(incident *models.Incident, previousSeverity models.Severity, previousStatus models.IncidentStatus) {
+	s.publish(models.EventIncidentUpdated, incident, "")
+	if incident.Severity != previousSeverity {
+		s.publish(models.EventIncidentSeverityChanged, incident, previousSeverity)
+	}
+	if incident.Status == models.StatusResolved && previousStatus != models.StatusResolved {
+		s.publish(models.EventIncidentResolved, incident, "")
+	}
+}
+
+// snapshotIncident copies an incident so subscribers and API callers can read it after the store lock
+// is released. Maps and the nested state that background work updates in place are copied as well.
+func snapshotIncident(incident *models.Incident) *models.Incident {
+	c := *incident
+	if incident.Metadata != nil {
+		c.Metadata = make(map[string]interface{}, len(incident.Metadata))
+		for k, v := range incident.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	if incident.Enrichments != nil {
+		c.Enrichments = make(map[string]*models.Enrichment, len(incident.Enrichments))
+		for k, v := range incident.Enrichments {
+			c.Enrichments[k] = v
+		}
+	}
+	if incident.Issue != nil {
+		issue := *incident.Issue
+		issue.Conflicts = append([]models.IssueConflict(nil), incident.Issue.Conflicts...)
+		c.Issue = &issue
+	}
+	if incident.Escalation != nil {
+		escalation := *incident.Escalation
+		escalation.History = append([]models.EscalationEvent(nil), incident.Escalation.History...)
+		c.Escalation = &escalation
+	}
+	if incident.SLA != nil {
+		c.SLA = copyIncidentSLA(incident.SLA)
+	}
+	if incident.RCAReview != nil {
+		c.RCAReview = copyRCAReview(incident.RCAReview)
+	}
+	return &c
+}
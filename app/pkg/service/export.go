@@ -0,0 +1,42 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/export"
+)
+
+// WithRCARenderer replaces the built-in RCA export templates
+func WithRCARenderer(renderer *export.Renderer) ServiceOption {
+	return func(s *IncidentService) {
+		s.renderer = renderer
+	}
+}
+
+// ExportRCA renders the incident's current RCA document in the given format
+func (s *IncidentService) ExportRCA(id string, format export.Format) ([]byte, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if incident.RCADocument == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRCANotFound, id)
+	}
+
+	var buf bytes.Buffer
+	err := s.renderer.Render(&buf, format, export.Data{
+		Incident:    incident,
+		RCA:         incident.RCADocument,
+		Review:      incident.RCAReview,
+		GeneratedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render RCA: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -2,48 +2,172 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/blob"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/export"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/logparse"
 	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/rules"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/statuspage"
 	"go.uber.org/zap"
 )
 
+// ErrIncidentNotFound is returned when an incident ID does not exist
+var ErrIncidentNotFound = errors.New("incident not found")
+
 // IncidentStore provides thread-safe incident storage and retrieval
 type IncidentStore struct {
-	incidents map[string]*models.Incident
-	mu        sync.RWMutex
-	counter   int64
+	incidents     map[string]*models.Incident
+	conversations map[string]*models.Conversation
+	analyses      map[string][]*models.AIAnalysis
+	rcaRevisions  map[string][]*models.RCADocument
+	actionItems   map[string][]*models.ActionItem
+	comments      map[string][]*models.Comment
+	attachments   map[string][]*models.Attachment
+	services      map[string]*models.Service
+	mu            sync.RWMutex
+	counter       int64
+	// changes holds change events from all services in time order
+	changes []*models.Change
+	// schedules and escalationPolicies are keyed by lower-case name
+	schedules          map[string]*models.Schedule
+	escalationPolicies map[string]*models.EscalationPolicy
+	// webhookDeliveries holds each subscription's delivery log, oldest first. webhookCounter numbers
+	// subscriptions and deliveries apart from counter, so deliveries do not shift incident IDs.
+	subscriptions     map[string]*models.WebhookSubscription
+	webhookDeliveries map[string][]*models.WebhookDelivery
+	webhookCounter    int64
+	// components are keyed by lower-case name; statusUpdates hold each incident's public updates, oldest first
+	components    map[string]*models.Component
+	statusUpdates map[string][]*models.StatusUpdate
+	// suppressions is the audit log of suppressed and downgraded incidents, oldest first, numbered by
+	// suppressionCounter so alerts do not shift incident IDs; silencePatterns hold each silence's
+	// compiled matchers
+	maintenanceWindows map[string]*models.MaintenanceWindow
+	silences           map[string]*models.Silence
+	silencePatterns    map[string][]*regexp.Regexp
+	suppressions       []*models.Suppression
+	suppressionCounter int64
+	// slaPolicies hold the response time targets for each severity
+	slaPolicies map[models.Severity]*slaPolicy
 }
 
 // IncidentService provides business logic for incident management
 type IncidentService struct {
-	store    *IncidentStore
-	aiClient ai.Client
-	logger   *zap.Logger
+	store        *IncidentStore
+	aiClient     ai.Client
+	logger       *zap.Logger
+	deploySource DeploySource
+	toolLimits   ai.ToolLimits
+	rules        *rules.Engine
+	renderer     *export.Renderer
+	// promptVersion selects the analysis prompt; empty means ai.DefaultPromptVersion
+	promptVersion string
+	// aiSeverityTimeout enables AI severity classification at creation when non-zero
+	aiSeverityTimeout time.Duration
+	// classifications tracks in-flight background classifications
+	classifications sync.WaitGroup
+	// blobs holds attachment content; nil disables attachments
+	blobs           blob.Store
+	attachmentLimit int64
+	// enrichers gather external context for new incidents; enrichments tracks in-flight runs
+	enrichers   []Enricher
+	enrichments sync.WaitGroup
+	// changeWindow is how long before an incident changes are correlated; zero means DefaultChangeWindow
+	changeWindow time.Duration
+	// clock drives on-call evaluation and escalations; nil means time.Now
+	clock func() time.Time
+	// defaultEscalationPolicy pages incidents whose service has no escalation policy
+	defaultEscalationPolicy string
+	// subscribers receive incident events; eventCounter numbers them
+	subscribers  []EventSubscriber
+	eventCounter atomic.Int64
+	// notifier delivers notifications; nil disables notification history and redelivery
+	notifier Notifier
+	// webhooks tracks queued and in-flight webhook deliveries; workers start with the first delivery,
+	// and closing webhookStop abandons queued deliveries and pending retries
+	webhooks          sync.WaitGroup
+	webhookQueue      chan *models.WebhookDelivery
+	webhookStop       chan struct{}
+	startWebhooksOnce sync.Once
+	stopWebhooksOnce  sync.Once
+	webhookAttempts   int
+	webhookBackoff    time.Duration
+	// trackers hold linked issues; issueSync serializes calls to them so webhooks and syncs apply in
+	// order, and issueSyncs tracks syncs started by local changes
+	trackers   []IssueTracker
+	issueSync  sync.Mutex
+	issueSyncs sync.WaitGroup
+	// statusPage titles the public status page and its feeds
+	statusPage statuspage.Config
 }
 
+// ServiceOption configures optional IncidentService behaviour
+type ServiceOption func(*IncidentService)
+
 // NewIncidentStore creates a new incident store
 func NewIncidentStore() *IncidentStore {
 	return &IncidentStore{
-		incidents: make(map[string]*models.Incident),
-		counter:   0,
+		incidents:     make(map[string]*models.Incident),
+		conversations: make(map[string]*models.Conversation),
+		analyses:      make(map[string][]*models.AIAnalysis),
+		rcaRevisions:  make(map[string][]*models.RCADocument),
+		actionItems:   make(map[string][]*models.ActionItem),
+		comments:      make(map[string][]*models.Comment),
+		attachments:   make(map[string][]*models.Attachment),
+		services:      make(map[string]*models.Service),
+		counter:       0,
+
+		schedules:          make(map[string]*models.Schedule),
+		escalationPolicies: make(map[string]*models.EscalationPolicy),
+
+		subscriptions:     make(map[string]*models.WebhookSubscription),
+		webhookDeliveries: make(map[string][]*models.WebhookDelivery),
+
+		components:    make(map[string]*models.Component),
+		statusUpdates: make(map[string][]*models.StatusUpdate),
+
+		maintenanceWindows: make(map[string]*models.MaintenanceWindow),
+		silences:           make(map[string]*models.Silence),
+		silencePatterns:    make(map[string][]*regexp.Regexp),
+
+		slaPolicies: make(map[models.Severity]*slaPolicy),
 	}
 }
 
 // NewIncidentService creates a new incident service
-func NewIncidentService(store *IncidentStore, aiClient ai.Client, logger *zap.Logger) *IncidentService {
-	return &IncidentService{
-		store:    store,
-		aiClient: aiClient,
-		logger:   logger,
-	}
+func NewIncidentService(store *IncidentStore, aiClient ai.Client, logger *zap.Logger, opts ...ServiceOption) *IncidentService {
+	s := &IncidentService{
+		store:      store,
+		aiClient:   aiClient,
+		logger:     logger,
+		toolLimits: ai.DefaultToolLimits,
+		rules:      rules.Default(),
+		renderer:   export.Default(),
+
+		attachmentLimit: DefaultAttachmentLimit,
+		webhookQueue:    make(chan *models.WebhookDelivery, webhookQueueSize),
+		webhookStop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// CreateIncident creates a new incident with optional AI severity classification
+// CreateIncident creates a new incident, classifying its severity with the rules engine and,
+// when enabled, asynchronously with the AI client
 func (s *IncidentService) CreateIncident(req *models.CreateIncidentRequest) (*models.Incident, error) {
+	now := s.now()
 	incident := &models.Incident{
 		ID:          s.generateID(),
 		Title:       req.Title,
@@ -51,45 +175,154 @@ func (s *IncidentService) CreateIncident(req *models.CreateIncidentRequest) (*mo
 		Source:      req.Source,
 		Status:      models.StatusOpen,
 		Logs:        req.Logs,
+		LogEntries:  logparse.ParseLines(req.Logs, logparse.FormatAuto, req.Source),
 		Tags:        req.Tags,
 		Metadata:    req.Metadata,
 		AssignedTo:  req.AssignedTo,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
-	// Use provided severity or classify with AI
+	s.store.mu.RLock()
+	svc, err := s.linkService(incident, req.Service)
+	s.store.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rules always contribute tags and a default assignee; their severity only applies when none was provided
+	result := s.classifySeverity(incident)
 	if req.Severity != nil {
 		incident.Severity = *req.Severity
+		incident.SeveritySource = models.SeveritySourceUser
 	} else {
-		// Try to classify severity using AI
-		severity := s.classifySeverity(incident)
-		incident.Severity = severity
+		incident.Severity, incident.SeverityAdjustment = serviceSeverity(svc, result.Severity)
+		incident.SeverityRule = result.Rule
+		incident.SeveritySource = models.SeveritySourceRule
+	}
+	incident.Tags = appendUnique(incident.Tags, result.Tags...)
+
+	// Maintenance windows and silences are consulted once the severity is known and before anyone is paged
+	s.store.mu.RLock()
+	suppression := s.matchSuppression(incident, now)
+	s.store.mu.RUnlock()
+	if suppression != nil {
+		suppression.Title = incident.Title
+		suppression.Source = incident.Source
+		suppression.Service = incident.Service
+		suppression.Tags = append([]string(nil), incident.Tags...)
+		suppression.Severity = incident.Severity
+		suppression.At = now
+
+		if suppression.Action == models.SuppressionSuppress {
+			s.store.mu.Lock()
+			s.recordSuppression(suppression)
+			s.store.mu.Unlock()
+			s.logger.Info("incident suppressed", zap.String("suppression", suppression.ID), zap.String("matched_by", suppression.MatchedBy), zap.String("title", incident.Title))
+			return nil, fmt.Errorf("%w: %s by %s", ErrIncidentSuppressed, suppression.ID, describeSuppression(suppression))
+		}
+
+		if incident.Severity.Rank() > suppression.DowngradedTo.Rank() {
+			incident.SeverityAdjustment = fmt.Sprintf("lowered from %s to %s by %s", incident.Severity, suppression.DowngradedTo, describeSuppression(suppression))
+			incident.Severity = suppression.DowngradedTo
+		} else {
+			suppression.DowngradedTo = incident.Severity
+		}
+		suppression.IncidentID = incident.ID
+	}
+
+	// Downgraded incidents page nobody
+	var onCall string
+	if suppression == nil {
+		s.store.mu.RLock()
+		onCall = s.startEscalation(incident, svc)
+		s.store.mu.RUnlock()
+	}
+
+	// Whoever is on call comes first, then the owning team, then the rules' default assignee
+	if incident.AssignedTo == "" {
+		incident.AssignedTo = onCall
+	}
+	if incident.AssignedTo == "" && svc != nil {
+		incident.AssignedTo = svc.Team
+	}
+	if incident.AssignedTo == "" {
+		incident.AssignedTo = result.AssignTo
 	}
 
 	if incident.Metadata == nil {
 		incident.Metadata = make(map[string]interface{})
 	}
 
+	if s.aiSeverityTimeout > 0 && suppression == nil {
+		incident.SeverityClassification = &models.SeverityClassification{
+			Status:      models.ClassificationPending,
+			Fallback:    result.Severity,
+			RequestedAt: now,
+		}
+	}
+
 	// Store the incident
 	s.store.mu.Lock()
+	if suppression != nil {
+		s.recordSuppression(suppression)
+		incident.SuppressionID = suppression.ID
+	}
 	s.store.incidents[incident.ID] = incident
+	s.refreshSLA(incident, now)
+	s.publish(models.EventIncidentCreated, incident, "")
+	created := snapshotIncident(incident)
 	s.store.mu.Unlock()
 
-	s.logger.Info("incident created", zap.String("id", incident.ID), zap.String("title", incident.Title))
-	return incident, nil
+	if incident.SeverityClassification != nil {
+		s.classifications.Add(1)
+		go s.classifyWithAI(incident.ID, ai.SeverityRequest{
+			IncidentTitle: incident.Title,
+			IncidentDesc:  incident.Description,
+			Source:        incident.Source,
+			Logs:          incident.Logs,
+		})
+	}
+
+	if len(s.enrichers) > 0 {
+		s.enrichments.Add(1)
+		go s.enrichAsync(incident.ID)
+	}
+
+	s.logger.Info("incident created", zap.String("id", created.ID), zap.String("title", created.Title))
+	return created, nil
 }
 
-// GetIncident retrieves an incident by ID
+// GetIncident retrieves a copy of an incident by ID. Background classification, enrichment,
+// escalation and SLA evaluation keep writing to the stored incident, so callers never see it directly.
 func (s *IncidentService) GetIncident(id string) (*models.Incident, error) {
 	s.store.mu.RLock()
-	incident, ok := s.store.incidents[id]
-	s.store.mu.RUnlock()
+	defer s.store.mu.RUnlock()
 
+	incident, ok := s.store.incidents[id]
 	if !ok {
-		return nil, fmt.Errorf("incident not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	snapshot := snapshotIncident(incident)
+
+	// The remaining time is kept current between evaluator runs, which alone change SLA states
+	if snapshot.SLA != nil {
+		now := s.now()
+		for _, timer := range slaTimers(snapshot.SLA) {
+			if timer != nil {
+				measureSLATimer(timer, snapshot.CreatedAt, now)
+			}
+		}
 	}
+	return snapshot, nil
+}
 
+// storedIncident returns the incident itself for changes; callers must hold s.store.mu
+func (s *IncidentService) storedIncident(id string) (*models.Incident, error) {
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
 	return incident, nil
 }
 
@@ -109,7 +342,7 @@ func (s *IncidentService) ListIncidents(filterStatus *models.IncidentStatus, fil
 			continue
 		}
 
-		results = append(results, incident)
+		results = append(results, snapshotIncident(incident))
 	}
 	s.store.mu.RUnlock()
 
@@ -119,12 +352,25 @@ func (s *IncidentService) ListIncidents(filterStatus *models.IncidentStatus, fil
 // UpdateIncident updates an existing incident
 func (s *IncidentService) UpdateIncident(id string, req *models.UpdateIncidentRequest) (*models.Incident, error) {
 	s.store.mu.Lock()
-	incident, ok := s.store.incidents[id]
-	s.store.mu.Unlock()
+	defer s.store.mu.Unlock()
 
-	if !ok {
-		return nil, fmt.Errorf("incident not found: %s", id)
+	incident, err := s.storedIncident(id)
+	if err != nil {
+		return nil, err
 	}
+	now := s.now()
+
+	// Resolve the service first so an unknown name rejects the whole update; an empty name unlinks it
+	var serviceName string
+	if req.Service != nil && *req.Service != "" {
+		svc, ok := s.store.services[catalogKey(*req.Service)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown service %q", ErrInvalidService, *req.Service)
+		}
+		serviceName = svc.Name
+	}
+
+	previousSeverity, previousStatus := incident.Severity, incident.Status
 
 	// Update fields if provided
 	if req.Title != nil {
@@ -137,6 +383,12 @@ func (s *IncidentService) UpdateIncident(id string, req *models.UpdateIncidentRe
 
 	if req.Severity != nil {
 		incident.Severity = *req.Severity
+		incident.SeveritySource = models.SeveritySourceUser
+		incident.SeverityAdjustment = ""
+	}
+
+	if req.Service != nil {
+		incident.Service = serviceName
 	}
 
 	if req.Status != nil {
@@ -145,13 +397,15 @@ func (s *IncidentService) UpdateIncident(id string, req *models.UpdateIncidentRe
 
 		// Set resolved time when status changes to resolved
 		if *req.Status == models.StatusResolved && oldStatus != models.StatusResolved {
-			now := time.Now()
-			incident.ResolvedAt = &now
+			resolvedAt := now
+			incident.ResolvedAt = &resolvedAt
 		}
 	}
 
-	if len(req.Logs) > 0 {
+	// Logs replaces the whole log; an empty list clears it. POST /logs appends instead.
+	if req.Logs != nil {
 		incident.Logs = req.Logs
+		incident.LogEntries = logparse.ParseLines(req.Logs, logparse.FormatAuto, incident.Source)
 	}
 
 	if req.Tags != nil {
@@ -166,10 +420,13 @@ func (s *IncidentService) UpdateIncident(id string, req *models.UpdateIncidentRe
 		incident.AssignedTo = *req.AssignedTo
 	}
 
-	incident.UpdatedAt = time.Now()
+	incident.UpdatedAt = now
+
+	s.refreshSLA(incident, now)
+	s.publishUpdate(incident, previousSeverity, previousStatus)
 
 	s.logger.Info("incident updated", zap.String("id", incident.ID))
-	return incident, nil
+	return snapshotIncident(incident), nil
 }
 
 // DeleteIncident deletes an incident
@@ -177,12 +434,24 @@ func (s *IncidentService) DeleteIncident(id string) error {
 	s.store.mu.Lock()
 	if _, ok := s.store.incidents[id]; !ok {
 		s.store.mu.Unlock()
-		return fmt.Errorf("incident not found: %s", id)
+		return fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
 	}
 
 	delete(s.store.incidents, id)
+	delete(s.store.conversations, id)
+	delete(s.store.analyses, id)
+	delete(s.store.rcaRevisions, id)
+	delete(s.store.actionItems, id)
+	delete(s.store.comments, id)
+	delete(s.store.statusUpdates, id)
+	var blobKeys []string
+	for _, attachment := range s.store.attachments[id] {
+		blobKeys = append(blobKeys, attachment.Key)
+	}
+	delete(s.store.attachments, id)
 	s.store.mu.Unlock()
 
+	s.deleteBlobs(blobKeys)
 	s.logger.Info("incident deleted", zap.String("id", id))
 	return nil
 }
@@ -199,10 +468,28 @@ func (s *IncidentService) AnalyzeIncident(id string) (*models.Incident, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	s.store.mu.RLock()
+	additionalContext := enrichmentContext(incident)
+	if changes := changesContext(s.correlateChanges(incident)); changes != "" {
+		if additionalContext == nil {
+			additionalContext = make(map[string]string)
+		}
+		additionalContext["changes"] = changes
+	}
+	if svc := s.serviceContext(incident); svc != "" {
+		if additionalContext == nil {
+			additionalContext = make(map[string]string)
+		}
+		additionalContext["service"] = svc
+	}
+	s.store.mu.RUnlock()
+
 	analysisReq := ai.AnalysisRequest{
-		IncidentTitle: incident.Title,
-		IncidentDesc:  incident.Description,
-		Logs:          incident.Logs,
+		IncidentTitle:     incident.Title,
+		IncidentDesc:      incident.Description,
+		Logs:              s.analysisLogs(ctx, incident),
+		AdditionalContext: additionalContext,
+		PromptVersion:     s.analysisPromptVersion(),
 	}
 
 	analysis, err := s.aiClient.AnalyzeIncident(ctx, analysisReq)
@@ -213,21 +500,28 @@ func (s *IncidentService) AnalyzeIncident(id string) (*models.Incident, error) {
 
 	// Convert AI response to model
 	s.store.mu.Lock()
-	incident.AIAnalysis = &models.AIAnalysis{
+	defer s.store.mu.Unlock()
+	stored, err := s.storedIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	s.recordAnalysis(stored, &models.AIAnalysis{
 		Summary:            analysis.Summary,
 		Findings:           analysis.Findings,
 		RootCauses:         analysis.RootCauses,
 		RecommendedActions: analysis.RecommendedActions,
 		SeveritySuggestion: models.Severity(analysis.SuggestedSeverity),
-		GeneratedAt:        time.Now(),
+		GeneratedAt:        s.now(),
 		Model:              s.aiClient.Model(),
 		Provider:           string(s.aiClient.Provider()),
-	}
-	incident.UpdatedAt = time.Now()
-	s.store.mu.Unlock()
+		PromptVersion:      analysisReq.PromptVersion,
+		InputsHash:         inputsHash(analysisReq),
+	})
+	stored.UpdatedAt = s.now()
+	s.publish(models.EventAnalysisCompleted, stored, "")
 
 	s.logger.Info("incident analyzed", zap.String("id", id), zap.String("provider", string(s.aiClient.Provider())))
-	return incident, nil
+	return snapshotIncident(stored), nil
 }
 
 // GenerateRCA generates a root cause analysis document
@@ -238,6 +532,13 @@ func (s *IncidentService) GenerateRCA(id string) (*models.Incident, error) {
 		return nil, err
 	}
 
+	s.store.mu.RLock()
+	err = rcaEditable(incident)
+	s.store.mu.RUnlock()
+	if err != nil {
+		return incident, err
+	}
+
 	// Use existing analysis or create empty one
 	var analysis ai.AnalysisResponse
 	if incident.AIAnalysis != nil {
@@ -250,12 +551,16 @@ func (s *IncidentService) GenerateRCA(id string) (*models.Incident, error) {
 		}
 	}
 
+	s.store.mu.RLock()
+	comments := s.store.comments[id]
 	rcaReq := ai.RCARequest{
 		IncidentTitle: incident.Title,
 		IncidentDesc:  incident.Description,
 		Analysis:      analysis,
-		Timeline:      buildTimeline(incident),
+		Timeline:      buildTimeline(incident, comments, s.correlateChanges(incident)),
+		Notes:         commentNotes(comments),
 	}
+	s.store.mu.RUnlock()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -268,22 +573,43 @@ func (s *IncidentService) GenerateRCA(id string) (*models.Incident, error) {
 
 	// Convert AI response to model
 	s.store.mu.Lock()
-	incident.RCADocument = &models.RCADocument{
-		Timeline:            buildTimeline(incident),
+	defer s.store.mu.Unlock()
+	stored, err := s.storedIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := rcaEditable(stored); err != nil {
+		return snapshotIncident(stored), err
+	}
+
+	now := s.now()
+	attribution := make(map[string]models.SectionAttribution, len(models.RCASections))
+	for _, section := range models.RCASections {
+		attribution[section] = models.SectionAttribution{
+			Source:    models.ContentSourceAI,
+			Author:    s.aiClient.Model(),
+			UpdatedAt: now,
+		}
+	}
+	s.recordRCA(stored, &models.RCADocument{
+		Timeline:            buildTimeline(stored, s.store.comments[id], s.correlateChanges(stored)),
 		RootCause:           rca.RootCause,
 		Impact:              rca.Impact,
 		ImmediateResolution: rca.ImmediateResolution,
 		PreventiveMeasures:  rca.PreventiveMeasures,
 		LessonsLearned:      rca.LessonsLearned,
-		GeneratedAt:         time.Now(),
+		GeneratedAt:         now,
 		Model:               s.aiClient.Model(),
 		Provider:            string(s.aiClient.Provider()),
-	}
-	incident.UpdatedAt = time.Now()
-	s.store.mu.Unlock()
+		PromptVersion:       ai.RCAPromptVersion,
+		InputsHash:          inputsHash(rcaReq),
+		Attribution:         attribution,
+	})
+	stored.UpdatedAt = now
+	s.publish(models.EventRCAGenerated, stored, "")
 
 	s.logger.Info("RCA generated", zap.String("id", id), zap.String("provider", string(s.aiClient.Provider())))
-	return incident, nil
+	return snapshotIncident(stored), nil
 }
 
 // SummarizeLogs extracts insights from log collections
@@ -305,7 +631,7 @@ func (s *IncidentService) SummarizeLogs(logs []string) (*models.LogSummarizeResp
 		Summary:     summary.Summary,
 		KeyInsights: summary.KeyInsights,
 		Alerts:      summary.Alerts,
-		GeneratedAt: time.Now(),
+		GeneratedAt: s.now(),
 	}, nil
 }
 
@@ -319,53 +645,77 @@ func (s *IncidentService) generateID() string {
 	return fmt.Sprintf("INC-%d-%d", time.Now().Unix(), s.store.counter)
 }
 
-// classifySeverity classifies incident severity based on keywords
-func (s *IncidentService) classifySeverity(incident *models.Incident) models.Severity {
-	// Basic heuristics for severity classification
-	desc := incident.Title + " " + incident.Description
-
-	if hasKeyword(desc, "critical", "production down", "data loss", "security breach") {
-		return models.SeverityCritical
-	}
-
-	if hasKeyword(desc, "error", "failure", "down", "unavailable") {
-		return models.SeverityHigh
-	}
-
-	if hasKeyword(desc, "warning", "degraded", "slow", "high memory") {
-		return models.SeverityMedium
-	}
-
-	return models.SeverityLow
+// classifySeverity evaluates the severity rules against an incident
+func (s *IncidentService) classifySeverity(incident *models.Incident) rules.Result {
+	return s.rules.Evaluate(rules.Input{
+		Title:       incident.Title,
+		Description: incident.Description,
+		Source:      incident.Source,
+		Tags:        incident.Tags,
+		Metadata:    incident.Metadata,
+		Logs:        incident.Logs,
+	})
 }
 
 // Utility functions
 
-// hasKeyword checks if text contains any of the keywords (case-insensitive)
-func hasKeyword(text string, keywords ...string) bool {
-	for _, kw := range keywords {
-		if len(text) > 0 && len(kw) > 0 {
-			// Case-insensitive search
-			t := text
-			for i := 0; i < len(t)-len(kw)+1; i++ {
-				if t[i:i+len(kw)] == kw {
-					return true
-				}
+// appendUnique appends values that are not already present
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
 			}
 		}
+		if !found {
+			list = append(list, v)
+		}
 	}
-	return false
+	return list
 }
 
-// buildTimeline builds a timeline of incident events
-func buildTimeline(incident *models.Incident) []string {
-	timeline := []string{
-		fmt.Sprintf("Created: %s", incident.CreatedAt.Format(time.RFC3339)),
-	}
+// timelineEvent is a timestamped timeline line, sorted before rendering
+type timelineEvent struct {
+	at   time.Time
+	text string
+}
 
+// buildTimeline builds a timeline of incident events, including responder comments, log milestones
+// and changes to related services
+func buildTimeline(incident *models.Incident, comments []*models.Comment, changes []models.CorrelatedChange) []string {
+	events := []timelineEvent{{incident.CreatedAt, fmt.Sprintf("Created: %s", incident.CreatedAt.Format(time.RFC3339))}}
+	events = append(events, logTimelineEvents(incident.LogEntries)...)
+	events = append(events, changeTimelineEvents(changes)...)
+	events = append(events, escalationTimelineEvents(incident)...)
+	for _, comment := range comments {
+		label := "Comment"
+		if comment.KeyFinding {
+			label = "Key finding"
+		}
+		events = append(events, timelineEvent{comment.CreatedAt, fmt.Sprintf("%s by %s at %s: %s",
+			label, comment.Author, comment.CreatedAt.Format(time.RFC3339), firstLine(comment.Body))})
+	}
 	if incident.ResolvedAt != nil {
-		timeline = append(timeline, fmt.Sprintf("Resolved: %s", incident.ResolvedAt.Format(time.RFC3339)))
+		events = append(events, timelineEvent{*incident.ResolvedAt, fmt.Sprintf("Resolved: %s", incident.ResolvedAt.Format(time.RFC3339))})
 	}
 
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	timeline := make([]string, 0, len(events))
+	for _, e := range events {
+		timeline = append(timeline, e.text)
+	}
 	return timeline
 }
+
+// firstLine returns the first non-empty line of a Markdown body
+func firstLine(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
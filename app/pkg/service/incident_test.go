@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai/aifake"
 	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
 	"go.uber.org/zap"
 )
@@ -14,9 +16,20 @@ type MockAIClient struct {
 	analyzeErr    error
 	rcaErr        error
 	summarizeErr  error
+	chatErr       error
+	severity      string
+	severityErr   error
+	severityDelay time.Duration
 	lastAnalysis  ai.AnalysisRequest
 	lastRCA       ai.RCARequest
 	lastSummarize ai.SummarizeRequest
+	lastChat      ai.ChatRequest
+	// statusDraft, when set, is returned by DraftStatusUpdate
+	statusDraft     *ai.StatusUpdateResponse
+	statusDraftErr  error
+	lastStatusDraft ai.StatusUpdateRequest
+	// toolCalls are executed against the request registry by AnalyzeIncidentWithTools
+	toolCalls []ai.ToolCall
 }
 
 func (m *MockAIClient) AnalyzeIncident(ctx context.Context, req ai.AnalysisRequest) (*ai.AnalysisResponse, error) {
@@ -60,6 +73,69 @@ func (m *MockAIClient) SummarizeLogs(ctx context.Context, req ai.SummarizeReques
 	}, nil
 }
 
+func (m *MockAIClient) Chat(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+	m.lastChat = req
+	if m.chatErr != nil {
+		return nil, m.chatErr
+	}
+	return &ai.ChatResponse{
+		Message: "Chat reply",
+	}, nil
+}
+
+func (m *MockAIClient) ClassifySeverity(ctx context.Context, req ai.SeverityRequest) (*ai.SeverityResponse, error) {
+	select {
+	case <-time.After(m.severityDelay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if m.severityErr != nil {
+		return nil, m.severityErr
+	}
+	severity := m.severity
+	if severity == "" {
+		severity = "high"
+	}
+	return &ai.SeverityResponse{
+		Severity:   severity,
+		Confidence: 0.8,
+		Reasoning:  "Test reasoning",
+	}, nil
+}
+
+func (m *MockAIClient) AnalyzeIncidentWithTools(ctx context.Context, req ai.ToolAnalysisRequest) (*ai.ToolAnalysisResponse, error) {
+	if m.analyzeErr != nil {
+		return nil, m.analyzeErr
+	}
+	resp := &ai.ToolAnalysisResponse{StopReason: ai.StopReasonCompleted, Steps: 1}
+	for _, call := range m.toolCalls {
+		output, err := req.Tools.Execute(ctx, call)
+		entry := ai.ToolTraceEntry{Step: 1, Call: call, Output: output}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		resp.Trace = append(resp.Trace, entry)
+	}
+	analysis, _ := m.AnalyzeIncident(ctx, ai.AnalysisRequest{IncidentTitle: req.IncidentTitle})
+	resp.AnalysisResponse = *analysis
+	return resp, nil
+}
+
+func (m *MockAIClient) DraftStatusUpdate(ctx context.Context, req ai.StatusUpdateRequest) (*ai.StatusUpdateResponse, error) {
+	m.lastStatusDraft = req
+	if m.statusDraftErr != nil {
+		return nil, m.statusDraftErr
+	}
+	if m.statusDraft != nil {
+		return m.statusDraft, nil
+	}
+	return &ai.StatusUpdateResponse{
+		Title:   "Elevated errors",
+		State:   "investigating",
+		Message: "We are investigating elevated errors.",
+	}, nil
+}
+
 func (m *MockAIClient) Health(ctx context.Context) error {
 	return nil
 }
@@ -253,3 +329,58 @@ func TestSummarizeLogs(t *testing.T) {
 		t.Errorf("expected summary 'Log summary', got %q", summary.Summary)
 	}
 }
+
+func TestAnalyzeIncidentWithFakeProvider(t *testing.T) {
+	fake := aifake.NewServer()
+	defer fake.Close()
+	client, err := ai.NewClient(fake.Config(ai.ProviderAnthropic))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service := NewIncidentService(NewIncidentStore(), client, zap.NewNop())
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout errors",
+		Description: "5xx spike",
+		Logs:        []string{"ERROR upstream connect error"},
+	})
+
+	fake.Enqueue(aifake.AnthropicText("Here is the analysis:\n{\"summary\": \"Envoy cannot reach checkout\", \"findings\": [\"503s from envoy\"], \"suggested_severity\": \"high\"}"))
+	analyzed, err := service.AnalyzeIncident(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analyzed.AIAnalysis.Summary != "Envoy cannot reach checkout" || analyzed.AIAnalysis.SeveritySuggestion != models.SeverityHigh {
+		t.Errorf("unexpected analysis %+v", analyzed.AIAnalysis)
+	}
+	if analyzed.AIAnalysis.Provider != "anthropic" {
+		t.Errorf("expected provider 'anthropic', got %q", analyzed.AIAnalysis.Provider)
+	}
+
+	fake.Enqueue(aifake.Malformed())
+	if _, err := service.AnalyzeIncident(created.ID); err == nil {
+		t.Error("expected error for malformed provider response")
+	}
+	if analyzed.AIAnalysis.Summary != "Envoy cannot reach checkout" {
+		t.Error("a failed analysis must not overwrite the previous one")
+	}
+}
+
+func TestCreateIncidentClassifiesSeverityCaseInsensitively(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+
+	incident, err := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "CRITICAL: DB down",
+		Description: "Primary is not accepting connections",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if incident.Severity != models.SeverityCritical {
+		t.Errorf("expected severity 'critical', got %q", incident.Severity)
+	}
+	if incident.SeverityRule != "critical-keywords" {
+		t.Errorf("expected severity rule 'critical-keywords', got %q", incident.SeverityRule)
+	}
+}
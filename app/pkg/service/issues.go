@@ -0,0 +1,659 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// DefaultIssueSyncInterval is how often linked issues are reconciled when no interval is configured
+const DefaultIssueSyncInterval = 5 * time.Minute
+
+// issueTimeout bounds the tracker calls for one issue
+const issueTimeout = 30 * time.Second
+
+var (
+	// ErrIssueTrackingDisabled is returned by issue operations when no tracker is configured
+	ErrIssueTrackingDisabled = errors.New("issue tracking is not configured")
+	// ErrTrackerNotFound is returned when a request or webhook names a tracker that is not configured
+	ErrTrackerNotFound = errors.New("issue tracker not found")
+	// ErrIssueAlreadyLinked is returned when opening an issue for a record that already has one
+	ErrIssueAlreadyLinked = errors.New("issue already linked")
+	// ErrIssueNotLinked is returned when syncing a record without an issue, and for webhooks about
+	// issues that are not linked to anything
+	ErrIssueNotLinked = errors.New("no linked issue")
+	// ErrIssueTracker wraps failed calls to a tracker's API
+	ErrIssueTracker = errors.New("issue tracker request failed")
+)
+
+// IssueTracker opens, reads and updates issues in one tracker, and reads its webhooks
+type IssueTracker interface {
+	Name() string
+	CreateIssue(ctx context.Context, issue models.NewTrackerIssue) (*models.TrackerIssue, error)
+	GetIssue(ctx context.Context, key string) (*models.TrackerIssue, error)
+	SetState(ctx context.Context, key string, state models.IssueState) error
+	// Normalize returns the closest state the tracker can hold
+	Normalize(state models.IssueState) models.IssueState
+	AddComment(ctx context.Context, key, body string) (*models.TrackerComment, error)
+	ParseWebhook(header http.Header, body []byte) (*models.TrackerEvent, error)
+}
+
+// WithIssueTracker adds an issue tracker. The first one is used when a request does not name one.
+func WithIssueTracker(t IssueTracker) ServiceOption {
+	return func(s *IncidentService) {
+		s.trackers = append(s.trackers, t)
+	}
+}
+
+// CreateIncidentIssue opens a tracker issue for an incident and links it. The incident's comments
+// are copied to the issue and its state is pushed.
+func (s *IncidentService) CreateIncidentIssue(id string, req *models.LinkIssueRequest) (*models.Incident, error) {
+	t, err := s.tracker(req.Tracker)
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueSync.Lock()
+	defer s.issueSync.Unlock()
+
+	s.store.mu.RLock()
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		s.store.mu.RUnlock()
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if incident.Issue != nil {
+		s.store.mu.RUnlock()
+		return nil, fmt.Errorf("%w: %s is linked to %s", ErrIssueAlreadyLinked, id, incident.Issue.Key)
+	}
+	issue := models.NewTrackerIssue{
+		Title:  fmt.Sprintf("[%s] %s: %s", strings.ToUpper(string(incident.Severity)), incident.ID, incident.Title),
+		Body:   incidentIssueBody(incident),
+		Labels: []string{"incident", string(incident.Severity)},
+	}
+	s.store.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+	defer cancel()
+	created, err := t.CreateIssue(ctx, issue)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIssueTracker, err)
+	}
+
+	s.store.mu.Lock()
+	incident.Issue = &models.ExternalIssue{Tracker: t.Name(), Key: created.Key, URL: created.URL, SyncedState: created.State, SyncedAt: s.now()}
+	s.store.mu.Unlock()
+	s.logger.Info("incident issue created", zap.String("id", id), zap.String("tracker", t.Name()), zap.String("key", created.Key))
+
+	if err := s.syncIncidentIssue(ctx, id); err != nil {
+		s.logger.Warn("initial issue sync failed", zap.String("id", id), zap.Error(err))
+	}
+	return s.GetIncident(id)
+}
+
+// CreateActionItemIssue opens a tracker issue for an action item, links it and sets its ticket URL
+func (s *IncidentService) CreateActionItemIssue(incidentID, itemID string, req *models.LinkIssueRequest) (*models.ActionItem, error) {
+	t, err := s.tracker(req.Tracker)
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueSync.Lock()
+	defer s.issueSync.Unlock()
+
+	s.store.mu.RLock()
+	item, _, err := s.findActionItem(incidentID, itemID)
+	if err != nil {
+		s.store.mu.RUnlock()
+		return nil, err
+	}
+	if item.Issue != nil {
+		s.store.mu.RUnlock()
+		return nil, fmt.Errorf("%w: %s is linked to %s", ErrIssueAlreadyLinked, itemID, item.Issue.Key)
+	}
+	issue := models.NewTrackerIssue{
+		Title:  item.Title,
+		Body:   actionItemIssueBody(item, s.store.incidents[incidentID]),
+		Labels: []string{"action-item", string(item.Priority)},
+	}
+	s.store.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+	defer cancel()
+	created, err := t.CreateIssue(ctx, issue)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIssueTracker, err)
+	}
+
+	s.store.mu.Lock()
+	item.Issue = &models.ExternalIssue{Tracker: t.Name(), Key: created.Key, URL: created.URL, SyncedState: created.State, SyncedAt: s.now()}
+	item.TicketURL = created.URL
+	item.UpdatedAt = s.now()
+	s.store.mu.Unlock()
+	s.logger.Info("action item issue created", zap.String("incident_id", incidentID), zap.String("id", itemID),
+		zap.String("tracker", t.Name()), zap.String("key", created.Key))
+
+	if err := s.syncActionItemIssue(ctx, incidentID, itemID); err != nil {
+		s.logger.Warn("initial issue sync failed", zap.String("id", itemID), zap.Error(err))
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+	return withOverdue(item, time.Now()), nil
+}
+
+// SyncIncidentIssue reconciles an incident with its linked issue now
+func (s *IncidentService) SyncIncidentIssue(id string) (*models.Incident, error) {
+	if len(s.trackers) == 0 {
+		return nil, ErrIssueTrackingDisabled
+	}
+
+	s.issueSync.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+	err := s.syncIncidentIssue(ctx, id)
+	cancel()
+	s.issueSync.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return s.GetIncident(id)
+}
+
+// HandleIssueWebhook applies a state change or new comment reported by a tracker's webhook.
+// It returns ErrIssueNotLinked for issues that are not linked to an incident or action item.
+func (s *IncidentService) HandleIssueWebhook(trackerName string, header http.Header, body []byte) error {
+	t, err := s.tracker(trackerName)
+	if err != nil {
+		return err
+	}
+	event, err := t.ParseWebhook(header, body)
+	if err != nil {
+		return err
+	}
+
+	s.issueSync.Lock()
+	defer s.issueSync.Unlock()
+
+	s.store.mu.RLock()
+	incident, item := s.findIssue(t.Name(), event.Key)
+	s.store.mu.RUnlock()
+	if incident == nil && item == nil {
+		return fmt.Errorf("%w: %s", ErrIssueNotLinked, event.Key)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+	defer cancel()
+
+	if event.Comment != nil && incident != nil {
+		s.store.mu.Lock()
+		s.importIssueComments(incident.ID, t.Name(), []models.TrackerComment{*event.Comment})
+		s.store.mu.Unlock()
+	}
+	if event.State == "" {
+		return nil
+	}
+
+	s.store.mu.RLock()
+	var ref models.ExternalIssue
+	var local models.IssueState
+	if incident != nil {
+		ref, local = *incident.Issue, incidentIssueState(incident.Status)
+	} else {
+		ref, local = *item.Issue, actionItemIssueState(item.Status)
+	}
+	s.store.mu.RUnlock()
+
+	state, conflict, err := s.mergeIssueState(ctx, t, &ref, local, event.State)
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	if incident != nil {
+		s.finishIssueSync(incident.Issue, state, conflict, err)
+		if err == nil {
+			s.applyIncidentIssueState(incident, t, local, state)
+		}
+	} else {
+		s.finishIssueSync(item.Issue, state, conflict, err)
+		if err == nil {
+			s.applyActionItemIssueState(item, t, local, state)
+		}
+	}
+	return err
+}
+
+// ReconcileIssues syncs every linked incident and action item with its issue, catching changes whose
+// webhooks were missed, and returns how many synced successfully
+func (s *IncidentService) ReconcileIssues() int {
+	type linked struct{ incidentID, itemID string }
+	var records []linked
+
+	s.store.mu.RLock()
+	for id, incident := range s.store.incidents {
+		if incident.Issue != nil {
+			records = append(records, linked{incidentID: id})
+		}
+	}
+	for incidentID, items := range s.store.actionItems {
+		for _, item := range items {
+			if item.Issue != nil {
+				records = append(records, linked{incidentID: incidentID, itemID: item.ID})
+			}
+		}
+	}
+	s.store.mu.RUnlock()
+
+	synced := 0
+	for _, record := range records {
+		s.issueSync.Lock()
+		ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+		var err error
+		if record.itemID == "" {
+			err = s.syncIncidentIssue(ctx, record.incidentID)
+		} else {
+			err = s.syncActionItemIssue(ctx, record.incidentID, record.itemID)
+		}
+		cancel()
+		s.issueSync.Unlock()
+
+		if err != nil {
+			s.logger.Warn("issue sync failed", zap.String("incident_id", record.incidentID), zap.String("item_id", record.itemID), zap.Error(err))
+			continue
+		}
+		synced++
+	}
+	return synced
+}
+
+// RunIssueSync reconciles linked issues every interval until ctx is cancelled
+func (s *IncidentService) RunIssueSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := s.ReconcileIssues(); n > 0 {
+				s.logger.Debug("issues reconciled", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+// WaitForIssueSyncs blocks until syncs started by local changes have finished
+func (s *IncidentService) WaitForIssueSyncs() {
+	s.issueSyncs.Wait()
+}
+
+// queueIssueSync pushes a local change to a linked issue in the background. An empty itemID syncs the incident.
+func (s *IncidentService) queueIssueSync(incidentID, itemID string) {
+	s.issueSyncs.Add(1)
+	go func() {
+		defer s.issueSyncs.Done()
+		s.issueSync.Lock()
+		defer s.issueSync.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+		defer cancel()
+		var err error
+		if itemID == "" {
+			err = s.syncIncidentIssue(ctx, incidentID)
+		} else {
+			err = s.syncActionItemIssue(ctx, incidentID, itemID)
+		}
+		if err != nil && !errors.Is(err, ErrIncidentNotFound) && !errors.Is(err, ErrActionItemNotFound) {
+			s.logger.Warn("issue sync failed", zap.String("incident_id", incidentID), zap.String("item_id", itemID), zap.Error(err))
+		}
+	}()
+}
+
+// syncIncidentIssue merges the incident's state with its issue's, pushes comments the issue does not
+// have and imports the issue's new comments. Callers must hold s.issueSync.
+func (s *IncidentService) syncIncidentIssue(ctx context.Context, id string) error {
+	s.store.mu.RLock()
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		s.store.mu.RUnlock()
+		return fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if incident.Issue == nil {
+		s.store.mu.RUnlock()
+		return fmt.Errorf("%w: %s", ErrIssueNotLinked, id)
+	}
+	ref, local := *incident.Issue, incidentIssueState(incident.Status)
+	var pending []models.Comment
+	for _, comment := range s.store.comments[id] {
+		if comment.ExternalID == "" {
+			pending = append(pending, *comment)
+		}
+	}
+	s.store.mu.RUnlock()
+
+	t, err := s.tracker(ref.Tracker)
+	if err != nil {
+		return err
+	}
+	remote, err := t.GetIssue(ctx, ref.Key)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrIssueTracker, err)
+		s.store.mu.Lock()
+		s.finishIssueSync(incident.Issue, ref.SyncedState, nil, err)
+		s.store.mu.Unlock()
+		return err
+	}
+
+	state, conflict, err := s.mergeIssueState(ctx, t, &ref, local, remote.State)
+	pushed := make(map[string]string)
+	for _, comment := range pending {
+		if err != nil {
+			break
+		}
+		var created *models.TrackerComment
+		if created, err = t.AddComment(ctx, ref.Key, fmt.Sprintf("%s commented on %s:\n\n%s", comment.Author, id, comment.Body)); err != nil {
+			err = fmt.Errorf("%w: %v", ErrIssueTracker, err)
+			break
+		}
+		pushed[comment.ID] = externalCommentID(t.Name(), created.ID)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	for _, comment := range s.store.comments[id] {
+		if externalID, ok := pushed[comment.ID]; ok {
+			comment.ExternalID = externalID
+		}
+	}
+	s.importIssueComments(id, t.Name(), remote.Comments)
+	s.finishIssueSync(incident.Issue, state, conflict, err)
+	if state != "" {
+		s.applyIncidentIssueState(incident, t, local, state)
+	}
+	return err
+}
+
+// syncActionItemIssue merges an action item's state with its issue's. Callers must hold s.issueSync.
+func (s *IncidentService) syncActionItemIssue(ctx context.Context, incidentID, itemID string) error {
+	s.store.mu.RLock()
+	item, _, err := s.findActionItem(incidentID, itemID)
+	if err != nil {
+		s.store.mu.RUnlock()
+		return err
+	}
+	if item.Issue == nil {
+		s.store.mu.RUnlock()
+		return fmt.Errorf("%w: %s", ErrIssueNotLinked, itemID)
+	}
+	ref, local := *item.Issue, actionItemIssueState(item.Status)
+	s.store.mu.RUnlock()
+
+	t, err := s.tracker(ref.Tracker)
+	if err != nil {
+		return err
+	}
+	remote, err := t.GetIssue(ctx, ref.Key)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrIssueTracker, err)
+		s.store.mu.Lock()
+		s.finishIssueSync(item.Issue, ref.SyncedState, nil, err)
+		s.store.mu.Unlock()
+		return err
+	}
+	state, conflict, err := s.mergeIssueState(ctx, t, &ref, local, remote.State)
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.finishIssueSync(item.Issue, state, conflict, err)
+	if err == nil {
+		s.applyActionItemIssueState(item, t, local, state)
+	}
+	return err
+}
+
+// mergeIssueState decides the state both sides should have and pushes it to the tracker when the
+// issue differs. States are compared as the tracker holds them. A side that has not changed since
+// the last sync takes the other side's state. When both changed, the state furthest along
+// (done, then in progress, then open) wins and the conflict is returned.
+func (s *IncidentService) mergeIssueState(ctx context.Context, t IssueTracker, ref *models.ExternalIssue, local, remote models.IssueState) (models.IssueState, *models.IssueConflict, error) {
+	l, r, base := t.Normalize(local), t.Normalize(remote), t.Normalize(ref.SyncedState)
+
+	var resolved models.IssueState
+	var conflict *models.IssueConflict
+	switch {
+	case l == r, r == base:
+		resolved = l
+	case l == base:
+		resolved = r
+	default:
+		resolved = l
+		if issueStateRank(r) > issueStateRank(l) {
+			resolved = r
+		}
+		conflict = &models.IssueConflict{At: s.now(), Local: l, Remote: r, Resolved: resolved}
+		s.logger.Warn("issue state conflict", zap.String("key", ref.Key),
+			zap.String("local", string(l)), zap.String("remote", string(r)), zap.String("resolved", string(resolved)))
+	}
+
+	if resolved != r {
+		if err := t.SetState(ctx, ref.Key, resolved); err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrIssueTracker, err)
+		}
+	}
+	return resolved, conflict, nil
+}
+
+// finishIssueSync records the outcome of a sync on the link; callers must hold s.store.mu
+func (s *IncidentService) finishIssueSync(ref *models.ExternalIssue, state models.IssueState, conflict *models.IssueConflict, err error) {
+	if ref == nil {
+		return
+	}
+	if state != "" {
+		ref.SyncedState = state
+	}
+	if conflict != nil {
+		ref.Conflicts = append(ref.Conflicts, *conflict)
+	}
+	if err != nil {
+		ref.LastError = err.Error()
+		return
+	}
+	ref.LastError = ""
+	ref.SyncedAt = s.now()
+}
+
+// applyIncidentIssueState moves the incident to the merged state unless it already holds it as the
+// tracker sees it, or it changed from local while the tracker was being called. Callers must hold s.store.mu.
+func (s *IncidentService) applyIncidentIssueState(incident *models.Incident, t IssueTracker, local, state models.IssueState) {
+	current := incidentIssueState(incident.Status)
+	if current != local || t.Normalize(current) == state {
+		return
+	}
+
+	previous := incident.Status
+	now := s.now()
+	switch state {
+	case models.IssueDone:
+		incident.Status = models.StatusResolved
+		incident.ResolvedAt = &now
+	case models.IssueInProgress:
+		incident.Status = models.StatusInProgress
+	default:
+		incident.Status = models.StatusOpen
+	}
+	incident.UpdatedAt = now
+	s.logger.Info("incident status synced from issue", zap.String("id", incident.ID), zap.String("status", string(incident.Status)))
+	s.publishUpdate(incident, incident.Severity, previous)
+}
+
+// applyActionItemIssueState is applyIncidentIssueState for action items; callers must hold s.store.mu
+func (s *IncidentService) applyActionItemIssueState(item *models.ActionItem, t IssueTracker, local, state models.IssueState) {
+	current := actionItemIssueState(item.Status)
+	if current != local || t.Normalize(current) == state {
+		return
+	}
+
+	now := s.now()
+	item.CompletedAt = nil
+	switch state {
+	case models.IssueDone:
+		item.Status = models.ActionItemDone
+		item.CompletedAt = &now
+	case models.IssueInProgress:
+		item.Status = models.ActionItemInProgress
+	default:
+		item.Status = models.ActionItemOpen
+	}
+	item.UpdatedAt = now
+	s.logger.Info("action item status synced from issue", zap.String("id", item.ID), zap.String("status", string(item.Status)))
+}
+
+// importIssueComments adds issue comments the incident does not have yet; callers must hold s.store.mu
+func (s *IncidentService) importIssueComments(incidentID, trackerName string, comments []models.TrackerComment) {
+	known := make(map[string]bool)
+	for _, comment := range s.store.comments[incidentID] {
+		if comment.ExternalID != "" {
+			known[comment.ExternalID] = true
+		}
+	}
+	for _, remote := range comments {
+		externalID := externalCommentID(trackerName, remote.ID)
+		if known[externalID] {
+			continue
+		}
+		known[externalID] = true
+
+		author, createdAt := remote.Author, remote.CreatedAt
+		if author == "" {
+			author = trackerName
+		}
+		if createdAt.IsZero() {
+			createdAt = s.now()
+		}
+		s.store.counter++
+		s.store.comments[incidentID] = append(s.store.comments[incidentID], &models.Comment{
+			ID:         fmt.Sprintf("CMT-%d", s.store.counter),
+			IncidentID: incidentID,
+			Author:     author,
+			Body:       remote.Body,
+			Mentions:   parseMentions(remote.Body),
+			CreatedAt:  createdAt,
+			ExternalID: externalID,
+		})
+		s.logger.Info("comment imported from issue", zap.String("incident_id", incidentID), zap.String("external_id", externalID))
+	}
+}
+
+// findIssue returns the incident or action item linked to a tracker issue; callers must hold s.store.mu
+func (s *IncidentService) findIssue(trackerName, key string) (*models.Incident, *models.ActionItem) {
+	linked := func(ref *models.ExternalIssue) bool {
+		return ref != nil && ref.Tracker == trackerName && ref.Key == key
+	}
+	for _, incident := range s.store.incidents {
+		if linked(incident.Issue) {
+			return incident, nil
+		}
+	}
+	for _, items := range s.store.actionItems {
+		for _, item := range items {
+			if linked(item.Issue) {
+				return nil, item
+			}
+		}
+	}
+	return nil, nil
+}
+
+// tracker returns the named tracker, or the first one when name is empty
+func (s *IncidentService) tracker(name string) (IssueTracker, error) {
+	if len(s.trackers) == 0 {
+		return nil, ErrIssueTrackingDisabled
+	}
+	if name == "" {
+		return s.trackers[0], nil
+	}
+	for _, t := range s.trackers {
+		if t.Name() == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTrackerNotFound, name)
+}
+
+func incidentIssueState(status models.IncidentStatus) models.IssueState {
+	switch status {
+	case models.StatusResolved, models.StatusClosed:
+		return models.IssueDone
+	case models.StatusInProgress:
+		return models.IssueInProgress
+	default:
+		return models.IssueOpen
+	}
+}
+
+func actionItemIssueState(status models.ActionItemStatus) models.IssueState {
+	switch status {
+	case models.ActionItemDone, models.ActionItemWontDo:
+		return models.IssueDone
+	case models.ActionItemInProgress:
+		return models.IssueInProgress
+	default:
+		return models.IssueOpen
+	}
+}
+
+func issueStateRank(state models.IssueState) int {
+	switch state {
+	case models.IssueDone:
+		return 2
+	case models.IssueInProgress:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func externalCommentID(trackerName, id string) string {
+	return trackerName + ":" + id
+}
+
+// incidentIssueBody describes an incident for its tracker issue
+func incidentIssueBody(incident *models.Incident) string {
+	var b strings.Builder
+	if incident.Description != "" {
+		b.WriteString(incident.Description + "\n\n")
+	}
+	fmt.Fprintf(&b, "Incident: %s\nSeverity: %s\nStatus: %s\n", incident.ID, incident.Severity, incident.Status)
+	if incident.Service != "" {
+		fmt.Fprintf(&b, "Service: %s\n", incident.Service)
+	}
+	if incident.AIAnalysis != nil && incident.AIAnalysis.Summary != "" {
+		fmt.Fprintf(&b, "\nAI summary: %s\n", incident.AIAnalysis.Summary)
+	}
+	return b.String()
+}
+
+// actionItemIssueBody describes an action item and the incident it follows up
+func actionItemIssueBody(item *models.ActionItem, incident *models.Incident) string {
+	var b strings.Builder
+	if item.Description != "" {
+		b.WriteString(item.Description + "\n\n")
+	}
+	fmt.Fprintf(&b, "Follow-up to %s", item.IncidentID)
+	if incident != nil {
+		fmt.Fprintf(&b, ": %s", incident.Title)
+	}
+	fmt.Fprintf(&b, "\nPriority: %s\n", item.Priority)
+	if item.Owner != "" {
+		fmt.Fprintf(&b, "Owner: %s\n", item.Owner)
+	}
+	if item.DueDate != nil {
+		fmt.Fprintf(&b, "Due: %s\n", item.DueDate.Format("2006-01-02"))
+	}
+	return b.String()
+}
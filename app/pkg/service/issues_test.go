@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+var errFakeSignature = errors.New("bad signature")
+
+// fakeTracker keeps issues in memory. Webhook bodies are TrackerEvents, accepted when X-Fake-Token is "ok".
+type fakeTracker struct {
+	mu         sync.Mutex
+	foldStates bool
+	issues     map[string]*models.TrackerIssue
+	created    []models.NewTrackerIssue
+	setStates  []models.IssueState
+}
+
+func newFakeTracker() *fakeTracker {
+	return &fakeTracker{issues: make(map[string]*models.TrackerIssue)}
+}
+
+func (f *fakeTracker) Name() string { return "fake" }
+
+func (f *fakeTracker) CreateIssue(ctx context.Context, issue models.NewTrackerIssue) (*models.TrackerIssue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := fmt.Sprintf("FAKE-%d", len(f.issues)+1)
+	f.issues[key] = &models.TrackerIssue{Key: key, URL: "https://tracker.example.com/" + key, State: models.IssueOpen}
+	f.created = append(f.created, issue)
+	return &models.TrackerIssue{Key: key, URL: f.issues[key].URL, State: models.IssueOpen}, nil
+}
+
+func (f *fakeTracker) GetIssue(ctx context.Context, key string) (*models.TrackerIssue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue, ok := f.issues[key]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", key)
+	}
+	copied := *issue
+	copied.Comments = append([]models.TrackerComment(nil), issue.Comments...)
+	return &copied, nil
+}
+
+func (f *fakeTracker) SetState(ctx context.Context, key string, state models.IssueState) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues[key].State = f.Normalize(state)
+	f.setStates = append(f.setStates, state)
+	return nil
+}
+
+func (f *fakeTracker) Normalize(state models.IssueState) models.IssueState {
+	if f.foldStates && state == models.IssueInProgress {
+		return models.IssueOpen
+	}
+	return state
+}
+
+func (f *fakeTracker) AddComment(ctx context.Context, key, body string) (*models.TrackerComment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.comment(key, "incident-bot", body), nil
+}
+
+func (f *fakeTracker) ParseWebhook(header http.Header, body []byte) (*models.TrackerEvent, error) {
+	if header.Get("X-Fake-Token") != "ok" {
+		return nil, errFakeSignature
+	}
+	var event models.TrackerEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// comment adds a comment as it would appear on the tracker; callers must hold f.mu
+func (f *fakeTracker) comment(key, author, body string) *models.TrackerComment {
+	issue := f.issues[key]
+	comment := models.TrackerComment{ID: fmt.Sprintf("c%d", len(issue.Comments)+1), Author: author, Body: body}
+	issue.Comments = append(issue.Comments, comment)
+	return &comment
+}
+
+// remoteChange edits an issue as a tracker user would
+func (f *fakeTracker) remoteChange(key string, state models.IssueState, comment string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if state != "" {
+		f.issues[key].State = state
+	}
+	if comment != "" {
+		f.comment(key, "dana", comment)
+	}
+}
+
+func (f *fakeTracker) issue(key string) models.TrackerIssue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.issues[key]
+}
+
+func externalComments(comments []*models.Comment) int {
+	n := 0
+	for _, comment := range comments {
+		if comment.ExternalID != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestIncidentIssueSync(t *testing.T) {
+	disabled := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	if _, err := disabled.CreateIncidentIssue("INC-1", &models.LinkIssueRequest{}); !errors.Is(err, ErrIssueTrackingDisabled) {
+		t.Fatalf("expected ErrIssueTrackingDisabled, got %v", err)
+	}
+
+	fake := newFakeTracker()
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithIssueTracker(fake))
+	high := models.SeverityHigh
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx", Severity: &high})
+	service.CreateComment(incident.ID, &models.CreateCommentRequest{Author: "alice", Body: "Looking at the load balancer"})
+
+	if _, err := service.CreateIncidentIssue(incident.ID, &models.LinkIssueRequest{Tracker: "linear"}); !errors.Is(err, ErrTrackerNotFound) {
+		t.Errorf("expected ErrTrackerNotFound, got %v", err)
+	}
+	linked, err := service.CreateIncidentIssue(incident.ID, &models.LinkIssueRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if linked.Issue == nil || linked.Issue.Key != "FAKE-1" || linked.Issue.SyncedState != models.IssueOpen {
+		t.Fatalf("expected FAKE-1 to be linked, got %+v", linked.Issue)
+	}
+	if title := fake.created[0].Title; !strings.Contains(title, incident.ID) || !strings.HasPrefix(title, "[HIGH]") {
+		t.Errorf("unexpected issue title %q", title)
+	}
+	if comments := fake.issue("FAKE-1").Comments; len(comments) != 1 || !strings.HasPrefix(comments[0].Body, "alice commented on "+incident.ID) {
+		t.Errorf("expected the existing comment to be pushed, got %+v", comments)
+	}
+	if _, err := service.CreateIncidentIssue(incident.ID, &models.LinkIssueRequest{}); !errors.Is(err, ErrIssueAlreadyLinked) {
+		t.Errorf("expected ErrIssueAlreadyLinked, got %v", err)
+	}
+
+	// Local changes are pushed in the background
+	inProgress := models.StatusInProgress
+	service.UpdateIncident(incident.ID, &models.UpdateIncidentRequest{Status: &inProgress})
+	service.CreateComment(incident.ID, &models.CreateCommentRequest{Author: "bob", Body: "Rolled back"})
+	service.WaitForIssueSyncs()
+	if issue := fake.issue("FAKE-1"); issue.State != models.IssueInProgress || len(issue.Comments) != 2 {
+		t.Errorf("expected the status and comment to be pushed, got %+v", issue)
+	}
+
+	// Remote changes are pulled on sync, and pushed comments are not imported back
+	fake.remoteChange("FAKE-1", models.IssueDone, "Confirmed fixed")
+	synced, err := service.SyncIncidentIssue(incident.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if synced.Status != models.StatusResolved || synced.ResolvedAt == nil {
+		t.Errorf("expected the incident to be resolved from the issue, got %s", synced.Status)
+	}
+	service.SyncIncidentIssue(incident.ID)
+	comments, _ := service.ListComments(incident.ID)
+	if len(comments) != 3 || externalComments(comments) != 3 {
+		t.Fatalf("expected 3 comments all linked to the issue, got %d (%d linked)", len(comments), externalComments(comments))
+	}
+	if imported := comments[2]; imported.Author != "dana" || imported.ExternalID != "fake:c3" {
+		t.Errorf("unexpected imported comment %+v", imported)
+	}
+	service.WaitForIssueSyncs()
+	if issue := fake.issue("FAKE-1"); len(issue.Comments) != 3 {
+		t.Errorf("expected no comments to be echoed to the issue, got %d", len(issue.Comments))
+	}
+}
+
+func TestMergeIssueState(t *testing.T) {
+	tests := []struct {
+		name     string
+		fold     bool
+		base     models.IssueState
+		local    models.IssueState
+		remote   models.IssueState
+		resolved models.IssueState
+		conflict bool
+		pushed   bool
+	}{
+		{"unchanged", false, models.IssueOpen, models.IssueOpen, models.IssueOpen, models.IssueOpen, false, false},
+		{"local change", false, models.IssueOpen, models.IssueInProgress, models.IssueOpen, models.IssueInProgress, false, true},
+		{"remote change", false, models.IssueOpen, models.IssueOpen, models.IssueDone, models.IssueDone, false, false},
+		{"same change", false, models.IssueOpen, models.IssueDone, models.IssueDone, models.IssueDone, false, false},
+		{"both changed", false, models.IssueOpen, models.IssueInProgress, models.IssueDone, models.IssueDone, true, false},
+		{"local further along", false, models.IssueInProgress, models.IssueDone, models.IssueOpen, models.IssueDone, true, true},
+		{"folded in progress", true, models.IssueOpen, models.IssueInProgress, models.IssueOpen, models.IssueOpen, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeTracker()
+			fake.foldStates = tt.fold
+			fake.issues["FAKE-1"] = &models.TrackerIssue{Key: "FAKE-1", State: tt.remote}
+			service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithIssueTracker(fake))
+
+			ref := &models.ExternalIssue{Tracker: "fake", Key: "FAKE-1", SyncedState: tt.base}
+			resolved, conflict, err := service.mergeIssueState(context.Background(), fake, ref, tt.local, tt.remote)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resolved != tt.resolved {
+				t.Errorf("expected %s, got %s", tt.resolved, resolved)
+			}
+			if (conflict != nil) != tt.conflict {
+				t.Errorf("expected conflict %v, got %+v", tt.conflict, conflict)
+			}
+			if pushed := len(fake.setStates) > 0; pushed != tt.pushed {
+				t.Errorf("expected pushed %v, got %v", tt.pushed, fake.setStates)
+			}
+		})
+	}
+}
+
+func TestHandleIssueWebhook(t *testing.T) {
+	fake := newFakeTracker()
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithIssueTracker(fake))
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+	service.CreateIncidentIssue(incident.ID, &models.LinkIssueRequest{})
+	header := http.Header{"X-Fake-Token": {"ok"}}
+
+	if err := service.HandleIssueWebhook("fake", http.Header{}, []byte(`{"key":"FAKE-1","state":"done"}`)); !errors.Is(err, errFakeSignature) {
+		t.Errorf("expected the tracker's signature error, got %v", err)
+	}
+	if err := service.HandleIssueWebhook("linear", header, []byte(`{}`)); !errors.Is(err, ErrTrackerNotFound) {
+		t.Errorf("expected ErrTrackerNotFound, got %v", err)
+	}
+	if err := service.HandleIssueWebhook("fake", header, []byte(`{"key":"FAKE-9","state":"done"}`)); !errors.Is(err, ErrIssueNotLinked) {
+		t.Errorf("expected ErrIssueNotLinked, got %v", err)
+	}
+
+	comment := `{"key":"FAKE-1","comment":{"id":"c7","author":"dana","body":"Seeing this in eu-west too"}}`
+	for i := 0; i < 2; i++ {
+		if err := service.HandleIssueWebhook("fake", header, []byte(comment)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if comments, _ := service.ListComments(incident.ID); len(comments) != 1 || comments[0].ExternalID != "fake:c7" {
+		t.Errorf("expected the comment to be imported once, got %d", len(comments))
+	}
+
+	fake.remoteChange("FAKE-1", models.IssueDone, "")
+	if err := service.HandleIssueWebhook("fake", header, []byte(`{"key":"FAKE-1","state":"done"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resolved, _ := service.GetIncident(incident.ID)
+	if resolved.Status != models.StatusResolved || resolved.Issue.SyncedState != models.IssueDone {
+		t.Errorf("expected the incident to be resolved, got %s (synced %s)", resolved.Status, resolved.Issue.SyncedState)
+	}
+	service.WaitForIssueSyncs()
+	if len(fake.setStates) != 0 {
+		t.Errorf("expected no state to be pushed back, got %v", fake.setStates)
+	}
+}
+
+func TestActionItemIssueSync(t *testing.T) {
+	fake := newFakeTracker()
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithIssueTracker(fake))
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+	item, _ := service.CreateActionItem(incident.ID, &models.CreateActionItemRequest{Title: "Add connection pool alerts", Owner: "alice"})
+
+	if _, err := service.CreateActionItemIssue(incident.ID, "ACT-404", &models.LinkIssueRequest{}); !errors.Is(err, ErrActionItemNotFound) {
+		t.Errorf("expected ErrActionItemNotFound, got %v", err)
+	}
+	linked, err := service.CreateActionItemIssue(incident.ID, item.ID, &models.LinkIssueRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if linked.Issue == nil || linked.TicketURL != "https://tracker.example.com/FAKE-1" {
+		t.Fatalf("expected the issue to be linked as the ticket, got %+v", linked)
+	}
+	if body := fake.created[0].Body; !strings.Contains(body, "Follow-up to "+incident.ID) || !strings.Contains(body, "Owner: alice") {
+		t.Errorf("unexpected issue body %q", body)
+	}
+
+	done := models.ActionItemDone
+	service.UpdateActionItem(incident.ID, item.ID, &models.UpdateActionItemRequest{Status: &done})
+	service.WaitForIssueSyncs()
+	if state := fake.issue("FAKE-1").State; state != models.IssueDone {
+		t.Errorf("expected the issue to be done, got %s", state)
+	}
+
+	// A missed webhook is caught by reconciliation
+	fake.remoteChange("FAKE-1", models.IssueOpen, "")
+	if n := service.ReconcileIssues(); n != 1 {
+		t.Errorf("expected 1 record to sync, got %d", n)
+	}
+	items := service.ListActionItems(models.ActionItemFilter{IncidentID: incident.ID})
+	if items[0].Status != models.ActionItemOpen || items[0].CompletedAt != nil {
+		t.Errorf("expected the action item to be reopened, got %s", items[0].Status)
+	}
+}
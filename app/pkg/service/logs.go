@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/logparse"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidLogs is returned when an append request has no entries or names an unknown format
+var ErrInvalidLogs = errors.New("invalid logs")
+
+// AppendLogs parses and appends log lines or CloudWatch events to an incident without touching existing logs
+func (s *IncidentService) AppendLogs(id string, req *models.AppendLogsRequest) (*models.AppendLogsResponse, error) {
+	format, err := logparse.ParseFormat(req.Format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogs, err)
+	}
+
+	var raw []string
+	var entries []models.LogEntry
+	for _, line := range req.Lines {
+		if strings.TrimSpace(line) != "" {
+			raw = append(raw, strings.TrimRight(line, "\r\n"))
+		}
+	}
+	entries = logparse.ParseLines(raw, format, req.Source)
+	if len(req.Events) > 0 {
+		events := logparse.ParseCloudWatch(req.Events, req.Source)
+		for _, event := range req.Events {
+			if strings.TrimSpace(event.Message) != "" {
+				raw = append(raw, strings.TrimRight(event.Message, "\r\n"))
+			}
+		}
+		entries = append(entries, events...)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no log lines or events", ErrInvalidLogs)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	incident.Logs = append(incident.Logs, raw...)
+	incident.LogEntries = append(incident.LogEntries, entries...)
+	incident.UpdatedAt = time.Now()
+
+	s.logger.Info("logs appended",
+		zap.String("id", id),
+		zap.String("format", string(format)),
+		zap.Int("entries", len(entries)),
+	)
+	return &models.AppendLogsResponse{
+		Appended: len(entries),
+		Total:    len(incident.LogEntries),
+		Entries:  entries,
+	}, nil
+}
+
+// ListLogEntries returns an incident's structured log entries in arrival order
+func (s *IncidentService) ListLogEntries(id string, filter models.LogEntryFilter) ([]models.LogEntry, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+
+	results := []models.LogEntry{}
+	for _, entry := range incident.LogEntries {
+		if filter.MinLevel != "" && entry.Level.Rank() < filter.MinLevel.Rank() {
+			continue
+		}
+		if filter.Source != "" && entry.Source != filter.Source {
+			continue
+		}
+		results = append(results, entry)
+	}
+	return results, nil
+}
+
+// renderLogEntries formats entries as normalized lines so prompts see consistent timestamps and levels
+func renderLogEntries(entries []models.LogEntry) []string {
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, logparse.Render(entry))
+	}
+	return lines
+}
+
+// logTimelineEvents marks when logging started and ended and when the first error was logged
+func logTimelineEvents(entries []models.LogEntry) []timelineEvent {
+	var first, last, firstError *models.LogEntry
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Timestamp == nil {
+			continue
+		}
+		if first == nil || entry.Timestamp.Before(*first.Timestamp) {
+			first = entry
+		}
+		if last == nil || entry.Timestamp.After(*last.Timestamp) {
+			last = entry
+		}
+		if entry.Level.Rank() >= models.LogLevelError.Rank() && (firstError == nil || entry.Timestamp.Before(*firstError.Timestamp)) {
+			firstError = entry
+		}
+	}
+	if first == nil {
+		return nil
+	}
+
+	events := []timelineEvent{{*first.Timestamp, fmt.Sprintf("First log entry: %s", first.Timestamp.Format(time.RFC3339))}}
+	if firstError != nil {
+		events = append(events, timelineEvent{*firstError.Timestamp, fmt.Sprintf("First error logged at %s: %s",
+			firstError.Timestamp.Format(time.RFC3339), firstLine(firstError.Message))})
+	}
+	if last != first {
+		events = append(events, timelineEvent{*last.Timestamp, fmt.Sprintf("Last log entry: %s", last.Timestamp.Format(time.RFC3339))})
+	}
+	return events
+}
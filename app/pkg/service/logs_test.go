@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestAppendLogs(t *testing.T) {
+	mockAI := &MockAIClient{}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop())
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Test",
+		Description: "Test",
+		Logs:        []string{"2024-01-01T10:00:00Z INFO deploy 42 started"},
+	})
+
+	resp, err := service.AppendLogs(created.ID, &models.AppendLogsRequest{
+		Lines:  []string{`{"level":"error","time":"2024-01-01T10:02:00Z","msg":"pool exhausted"}`, ""},
+		Source: "payments",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Appended != 1 || resp.Total != 2 || resp.Entries[0].Source != "payments" {
+		t.Errorf("unexpected append response %+v", resp)
+	}
+
+	resp, _ = service.AppendLogs(created.ID, &models.AppendLogsRequest{
+		Events: []models.CloudWatchEvent{{Timestamp: 1704103380000, Message: "Task timed out after 3.00 seconds"}},
+	})
+	if resp.Total != 3 || resp.Entries[0].Format != "cloudwatch" {
+		t.Errorf("expected CloudWatch event appended, got %+v", resp)
+	}
+
+	if _, err := service.AppendLogs(created.ID, &models.AppendLogsRequest{Lines: []string{"x"}, Format: "syslog"}); !errors.Is(err, ErrInvalidLogs) {
+		t.Errorf("expected unknown format to fail, got %v", err)
+	}
+	if _, err := service.AppendLogs(created.ID, &models.AppendLogsRequest{Lines: []string{" "}}); !errors.Is(err, ErrInvalidLogs) {
+		t.Errorf("expected empty append to fail, got %v", err)
+	}
+
+	errorsOnly, _ := service.ListLogEntries(created.ID, models.LogEntryFilter{MinLevel: models.LogLevelWarn})
+	if len(errorsOnly) != 1 || errorsOnly[0].Message != "pool exhausted" {
+		t.Errorf("expected only the error entry, got %+v", errorsOnly)
+	}
+
+	service.AnalyzeIncident(created.ID)
+	logs := strings.Join(mockAI.lastAnalysis.Logs, "\n")
+	if !strings.Contains(logs, "2024-01-01T10:02:00Z ERROR [payments] pool exhausted") {
+		t.Errorf("expected normalized entries in the analysis prompt, got %q", logs)
+	}
+
+	service.GenerateRCA(created.ID)
+	timeline := strings.Join(mockAI.lastRCA.Timeline, "\n")
+	if !strings.Contains(timeline, "First log entry: 2024-01-01T10:00:00Z") || !strings.Contains(timeline, "First error logged at 2024-01-01T10:02:00Z: pool exhausted") {
+		t.Errorf("expected log milestones in the timeline, got %q", timeline)
+	}
+
+	// An empty list in an update clears the logs
+	service.UpdateIncident(created.ID, &models.UpdateIncidentRequest{Logs: []string{}})
+	if incident, _ := service.GetIncident(created.ID); len(incident.Logs) != 0 || len(incident.LogEntries) != 0 {
+		t.Errorf("expected logs cleared, got %d lines", len(incident.Logs))
+	}
+}
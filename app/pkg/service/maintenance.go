@@ -0,0 +1,622 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSuppressionLimit is how many audit entries ListSuppressions returns when no limit is given
+	DefaultSuppressionLimit = 100
+	// maxSuppressions bounds the suppression audit log; the oldest entries are dropped first
+	maxSuppressions = 1000
+)
+
+var (
+	// ErrMaintenanceWindowNotFound is returned when a maintenance window does not exist
+	ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+	// ErrInvalidMaintenanceWindow is returned for maintenance windows that fail validation
+	ErrInvalidMaintenanceWindow = errors.New("invalid maintenance window")
+	// ErrSilenceNotFound is returned when a silence does not exist
+	ErrSilenceNotFound = errors.New("silence not found")
+	// ErrInvalidSilence is returned for silences that fail validation
+	ErrInvalidSilence = errors.New("invalid silence")
+	// ErrSilenceExpired is returned when expiring a silence that has already expired
+	ErrSilenceExpired = errors.New("silence already expired")
+	// ErrIncidentSuppressed is returned by CreateIncident when a maintenance window or silence drops the incident
+	ErrIncidentSuppressed = errors.New("incident suppressed")
+)
+
+// CreateMaintenanceWindow adds a maintenance window
+func (s *IncidentService) CreateMaintenanceWindow(req *models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	window := copyMaintenanceWindow(req)
+	if err := validateMaintenanceWindow(window); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	window.CreatedAt = now
+	window.UpdatedAt = now
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	s.store.counter++
+	window.ID = fmt.Sprintf("MW-%d", s.store.counter)
+	s.store.maintenanceWindows[window.ID] = window
+
+	s.logger.Info("maintenance window created", zap.String("id", window.ID), zap.String("name", window.Name), zap.String("action", string(window.Action)))
+	return maintenanceWindowView(window, now), nil
+}
+
+// ListMaintenanceWindows returns maintenance windows by start time; activeOnly limits them to those in effect now
+func (s *IncidentService) ListMaintenanceWindows(activeOnly bool) []*models.MaintenanceWindow {
+	now := s.now()
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.MaintenanceWindow, 0, len(s.store.maintenanceWindows))
+	for _, window := range s.store.maintenanceWindows {
+		if view := maintenanceWindowView(window, now); view.Active || !activeOnly {
+			result = append(result, view)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].StartsAt.Equal(result[j].StartsAt) {
+			return result[i].StartsAt.Before(result[j].StartsAt)
+		}
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// GetMaintenanceWindow returns a maintenance window by ID
+func (s *IncidentService) GetMaintenanceWindow(id string) (*models.MaintenanceWindow, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	window, ok := s.store.maintenanceWindows[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMaintenanceWindowNotFound, id)
+	}
+	return maintenanceWindowView(window, s.now()), nil
+}
+
+// UpdateMaintenanceWindow replaces a maintenance window's definition, keeping its ID and creator
+func (s *IncidentService) UpdateMaintenanceWindow(id string, req *models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	window := copyMaintenanceWindow(req)
+	if err := validateMaintenanceWindow(window); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	existing, ok := s.store.maintenanceWindows[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMaintenanceWindowNotFound, id)
+	}
+	window.ID = existing.ID
+	window.CreatedBy = existing.CreatedBy
+	window.CreatedAt = existing.CreatedAt
+	window.UpdatedAt = s.now()
+	s.store.maintenanceWindows[id] = window
+
+	s.logger.Info("maintenance window updated", zap.String("id", id))
+	return maintenanceWindowView(window, window.UpdatedAt), nil
+}
+
+// DeleteMaintenanceWindow removes a maintenance window; its audit entries are kept
+func (s *IncidentService) DeleteMaintenanceWindow(id string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, ok := s.store.maintenanceWindows[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrMaintenanceWindowNotFound, id)
+	}
+	delete(s.store.maintenanceWindows, id)
+
+	s.logger.Info("maintenance window deleted", zap.String("id", id))
+	return nil
+}
+
+// CreateSilence adds a silence, starting now unless a start time is given
+func (s *IncidentService) CreateSilence(req *models.Silence) (*models.Silence, error) {
+	now := s.now()
+	silence := copySilence(req)
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	patterns, err := validateSilence(silence)
+	if err != nil {
+		return nil, err
+	}
+	if !silence.EndsAt.After(now) {
+		return nil, fmt.Errorf("%w: ends_at is in the past", ErrInvalidSilence)
+	}
+	silence.CreatedAt = now
+	silence.UpdatedAt = now
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	s.store.counter++
+	silence.ID = fmt.Sprintf("SIL-%d", s.store.counter)
+	s.store.silences[silence.ID] = silence
+	s.store.silencePatterns[silence.ID] = patterns
+
+	s.logger.Info("silence created", zap.String("id", silence.ID), zap.String("created_by", silence.CreatedBy), zap.Time("ends_at", silence.EndsAt))
+	return silenceView(silence, now), nil
+}
+
+// ListSilences returns silences newest first, optionally only those in one state
+func (s *IncidentService) ListSilences(state *models.SilenceState) []*models.Silence {
+	now := s.now()
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.Silence, 0, len(s.store.silences))
+	for _, silence := range s.store.silences {
+		if view := silenceView(silence, now); state == nil || view.State == *state {
+			result = append(result, view)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result
+}
+
+// GetSilence returns a silence by ID
+func (s *IncidentService) GetSilence(id string) (*models.Silence, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	silence, ok := s.store.silences[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSilenceNotFound, id)
+	}
+	return silenceView(silence, s.now()), nil
+}
+
+// ExpireSilence ends a silence now. Expired silences are kept so the audit log can refer to them.
+func (s *IncidentService) ExpireSilence(id string) (*models.Silence, error) {
+	now := s.now()
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	silence, ok := s.store.silences[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSilenceNotFound, id)
+	}
+	if silenceState(silence, now) == models.SilenceExpired {
+		return nil, fmt.Errorf("%w: %s", ErrSilenceExpired, id)
+	}
+	if silence.StartsAt.After(now) {
+		silence.StartsAt = now
+	}
+	silence.EndsAt = now
+	silence.UpdatedAt = now
+
+	s.logger.Info("silence expired", zap.String("id", id))
+	return silenceView(silence, now), nil
+}
+
+// ListSuppressions returns the audit log of suppressed and downgraded incidents, newest first
+func (s *IncidentService) ListSuppressions(filter models.SuppressionFilter) []*models.Suppression {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultSuppressionLimit
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.Suppression, 0)
+	for i := len(s.store.suppressions) - 1; i >= 0 && len(result) < limit; i-- {
+		entry := s.store.suppressions[i]
+		if filter.MatchedBy != "" && entry.MatchedBy != filter.MatchedBy {
+			continue
+		}
+		c := *entry
+		c.Tags = append([]string(nil), entry.Tags...)
+		result = append(result, &c)
+	}
+	return result
+}
+
+// matchSuppression finds the active silence or maintenance window covering a new incident. Suppressing
+// beats downgrading, and silences are checked before windows. Callers must hold s.store.mu.
+func (s *IncidentService) matchSuppression(incident *models.Incident, now time.Time) *models.Suppression {
+	silences := make([]*models.Silence, 0, len(s.store.silences))
+	for _, silence := range s.store.silences {
+		if silenceState(silence, now) == models.SilenceActive && silenceMatches(silence, s.store.silencePatterns[silence.ID], incident) {
+			silences = append(silences, silence)
+		}
+	}
+	sort.Slice(silences, func(i, j int) bool { return silences[i].CreatedAt.Before(silences[j].CreatedAt) })
+
+	windows := make([]*models.MaintenanceWindow, 0, len(s.store.maintenanceWindows))
+	for _, window := range s.store.maintenanceWindows {
+		if start, _, ok := windowOccurrence(window, now); ok && !now.Before(start) && windowMatches(window, incident) {
+			windows = append(windows, window)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].CreatedAt.Before(windows[j].CreatedAt) })
+
+	var matches []*models.Suppression
+	for _, silence := range silences {
+		matches = append(matches, &models.Suppression{
+			Action: silence.Action, MatchedType: models.MatchedSilence, MatchedBy: silence.ID, MatchedName: silence.Comment, DowngradedTo: silence.Severity,
+		})
+	}
+	for _, window := range windows {
+		matches = append(matches, &models.Suppression{
+			Action: window.Action, MatchedType: models.MatchedMaintenanceWindow, MatchedBy: window.ID, MatchedName: window.Name, DowngradedTo: window.Severity,
+		})
+	}
+
+	var downgrade *models.Suppression
+	for _, match := range matches {
+		if match.Action == models.SuppressionSuppress {
+			match.DowngradedTo = ""
+			return match
+		}
+		if downgrade == nil {
+			downgrade = match
+		}
+	}
+	return downgrade
+}
+
+// recordSuppression numbers an audit entry and appends it to the log; callers must hold s.store.mu
+func (s *IncidentService) recordSuppression(entry *models.Suppression) {
+	s.store.suppressionCounter++
+	entry.ID = fmt.Sprintf("SUP-%d", s.store.suppressionCounter)
+	s.store.suppressions = append(s.store.suppressions, entry)
+	if len(s.store.suppressions) > maxSuppressions {
+		s.store.suppressions = s.store.suppressions[len(s.store.suppressions)-maxSuppressions:]
+	}
+}
+
+// describeSuppression names the window or silence behind an audit entry, e.g. "maintenance window MW-3"
+func describeSuppression(entry *models.Suppression) string {
+	return fmt.Sprintf("%s %s", strings.ReplaceAll(string(entry.MatchedType), "_", " "), entry.MatchedBy)
+}
+
+// windowOccurrence returns the occurrence of a window that is running at the given time, or failing that the
+// next one; ok is false once the window is over. Recurring windows follow the wall clock of their time zone.
+func windowOccurrence(window *models.MaintenanceWindow, at time.Time) (start, end time.Time, ok bool) {
+	r := window.Recurrence
+	if r == nil {
+		return window.StartsAt, window.EndsAt, at.Before(window.EndsAt)
+	}
+
+	loc, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	first := window.StartsAt.In(loc)
+	duration := window.EndsAt.Sub(window.StartsAt)
+	from := at
+	if from.Before(window.StartsAt) {
+		from = window.StartsAt
+	}
+	local := from.In(loc)
+
+	// An occurrence still running started at most duration ago, and the next one is at most a week away
+	for day := -int(duration/(24*time.Hour)) - 1; day <= 7; day++ {
+		start = time.Date(local.Year(), local.Month(), local.Day()+day, first.Hour(), first.Minute(), first.Second(), first.Nanosecond(), loc)
+		if start.Before(window.StartsAt) || !recursOn(r, first, start) {
+			continue
+		}
+		if r.Until != nil && start.After(*r.Until) {
+			break
+		}
+		if end = start.Add(duration); at.Before(end) {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// recursOn reports whether a recurring window has an occurrence starting on start's day
+func recursOn(r *models.Recurrence, first, start time.Time) bool {
+	if r.Frequency != models.RecurrenceWeekly {
+		return true
+	}
+	if len(r.Weekdays) == 0 {
+		return start.Weekday() == first.Weekday()
+	}
+	for _, day := range r.Weekdays {
+		if weekdays[weekdayKey(day)] == start.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// windowMatches reports whether an incident matches every non-empty list of a window
+func windowMatches(window *models.MaintenanceWindow, incident *models.Incident) bool {
+	return matchesAny(window.Services, incident.Service) &&
+		matchesAny(window.Sources, incident.Source) &&
+		(len(window.Tags) == 0 || matchesAny(window.Tags, incident.Tags...))
+}
+
+// matchesAny reports whether any value equals an entry of list case-insensitively; an empty list matches anything
+func matchesAny(list []string, values ...string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, want := range list {
+		for _, value := range values {
+			if strings.EqualFold(want, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// silenceMatches reports whether an incident matches all of a silence's matchers, given the
+// patterns compiled for them by validateSilence
+func silenceMatches(silence *models.Silence, patterns []*regexp.Regexp, incident *models.Incident) bool {
+	for i, m := range silence.Matchers {
+		if !matcherMatches(m, patterns[i], incidentField(incident, m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matcherMatches tests a matcher against a field's values, using re for regular expression operators;
+// a missing field has the empty value, and negative operators match when no value matches
+func matcherMatches(m models.Matcher, re *regexp.Regexp, values []string) bool {
+	if len(values) == 0 {
+		values = []string{""}
+	}
+
+	matched := false
+	switch m.Operator {
+	case models.MatchRegexp, models.MatchNotRegexp:
+		for _, v := range values {
+			matched = matched || re.MatchString(v)
+		}
+	default:
+		for _, v := range values {
+			matched = matched || strings.EqualFold(v, m.Value)
+		}
+	}
+
+	if m.Operator == models.MatchNotEqual || m.Operator == models.MatchNotRegexp {
+		return !matched
+	}
+	return matched
+}
+
+// compileMatcher anchors a matcher's regular expression so it must match the whole value
+func compileMatcher(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)^(?:" + pattern + ")$")
+}
+
+// incidentField returns the values a matcher name refers to; unknown names are looked up in metadata
+func incidentField(incident *models.Incident, name string) []string {
+	switch strings.ToLower(name) {
+	case "title":
+		return []string{incident.Title}
+	case "source":
+		return []string{incident.Source}
+	case "service":
+		return []string{incident.Service}
+	case "severity":
+		return []string{string(incident.Severity)}
+	case "tag":
+		return incident.Tags
+	}
+	if v, ok := incident.Metadata[name]; ok && v != nil {
+		return []string{fmt.Sprint(v)}
+	}
+	return nil
+}
+
+// silenceState computes whether a silence is pending, active or expired
+func silenceState(silence *models.Silence, now time.Time) models.SilenceState {
+	switch {
+	case now.Before(silence.StartsAt):
+		return models.SilencePending
+	case now.Before(silence.EndsAt):
+		return models.SilenceActive
+	}
+	return models.SilenceExpired
+}
+
+// validateSuppressionAction defaults a window's or silence's action to suppress and a downgrade's severity to low
+func validateSuppressionAction(action *models.SuppressionAction, severity *models.Severity) error {
+	if *action == "" {
+		*action = models.SuppressionSuppress
+	}
+	switch *action {
+	case models.SuppressionSuppress:
+		*severity = ""
+	case models.SuppressionDowngrade:
+		if *severity == "" {
+			*severity = models.SeverityLow
+		}
+		if !severity.Valid() {
+			return fmt.Errorf("unknown severity %q", *severity)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", *action)
+	}
+	return nil
+}
+
+func validateMaintenanceWindow(window *models.MaintenanceWindow) error {
+	window.Name = strings.TrimSpace(window.Name)
+	if window.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidMaintenanceWindow)
+	}
+	window.Services = trimNonEmpty(window.Services)
+	window.Tags = trimNonEmpty(window.Tags)
+	window.Sources = trimNonEmpty(window.Sources)
+	if len(window.Services)+len(window.Tags)+len(window.Sources) == 0 {
+		return fmt.Errorf("%w: %s needs at least one service, tag or source", ErrInvalidMaintenanceWindow, window.Name)
+	}
+	if window.StartsAt.IsZero() || window.EndsAt.IsZero() {
+		return fmt.Errorf("%w: starts_at and ends_at are required", ErrInvalidMaintenanceWindow)
+	}
+	if !window.EndsAt.After(window.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidMaintenanceWindow)
+	}
+	if err := validateSuppressionAction(&window.Action, &window.Severity); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMaintenanceWindow, err)
+	}
+	if window.TimeZone == "" {
+		window.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(window.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidMaintenanceWindow, window.TimeZone)
+	}
+
+	r := window.Recurrence
+	if r == nil {
+		return nil
+	}
+	period := 24 * time.Hour
+	switch r.Frequency {
+	case models.RecurrenceDaily:
+		if len(r.Weekdays) > 0 {
+			return fmt.Errorf("%w: weekdays only apply to weekly recurrence", ErrInvalidMaintenanceWindow)
+		}
+	case models.RecurrenceWeekly:
+		period = 7 * 24 * time.Hour
+		for _, day := range r.Weekdays {
+			if _, ok := weekdays[weekdayKey(day)]; !ok {
+				return fmt.Errorf("%w: unknown weekday %q", ErrInvalidMaintenanceWindow, day)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown recurrence frequency %q", ErrInvalidMaintenanceWindow, r.Frequency)
+	}
+	if window.EndsAt.Sub(window.StartsAt) > period {
+		return fmt.Errorf("%w: a %s window cannot last longer than %s", ErrInvalidMaintenanceWindow, r.Frequency, period)
+	}
+	if r.Until != nil && r.Until.Before(window.StartsAt) {
+		return fmt.Errorf("%w: recurrence ends before the window starts", ErrInvalidMaintenanceWindow)
+	}
+	return nil
+}
+
+// validateSilence normalizes a silence and compiles its matchers. The patterns line up with the
+// matchers and are nil for equality operators.
+func validateSilence(silence *models.Silence) ([]*regexp.Regexp, error) {
+	silence.Comment = strings.TrimSpace(silence.Comment)
+	silence.CreatedBy = strings.TrimSpace(silence.CreatedBy)
+	if silence.Comment == "" || silence.CreatedBy == "" {
+		return nil, fmt.Errorf("%w: comment and created_by are required", ErrInvalidSilence)
+	}
+	if len(silence.Matchers) == 0 {
+		return nil, fmt.Errorf("%w: at least one matcher is required", ErrInvalidSilence)
+	}
+
+	// A silence whose every matcher also matches an empty field would silence nearly everything
+	selective := false
+	patterns := make([]*regexp.Regexp, len(silence.Matchers))
+	for i := range silence.Matchers {
+		m := &silence.Matchers[i]
+		m.Name = strings.TrimSpace(m.Name)
+		if m.Name == "" {
+			return nil, fmt.Errorf("%w: matcher %d has no name", ErrInvalidSilence, i)
+		}
+		if m.Operator == "" {
+			m.Operator = models.MatchEqual
+		}
+		switch m.Operator {
+		case models.MatchEqual, models.MatchNotEqual:
+		case models.MatchRegexp, models.MatchNotRegexp:
+			re, err := compileMatcher(m.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: matcher %s: %v", ErrInvalidSilence, m.Name, err)
+			}
+			patterns[i] = re
+		default:
+			return nil, fmt.Errorf("%w: matcher %s has unknown operator %q", ErrInvalidSilence, m.Name, m.Operator)
+		}
+		if !matcherMatches(*m, patterns[i], nil) {
+			selective = true
+		}
+	}
+	if !selective {
+		return nil, fmt.Errorf("%w: at least one matcher must not match an empty value", ErrInvalidSilence)
+	}
+
+	if err := validateSuppressionAction(&silence.Action, &silence.Severity); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSilence, err)
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSilence)
+	}
+	return patterns, nil
+}
+
+// trimNonEmpty trims each value and drops empty ones
+func trimNonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// maintenanceWindowView copies a window and fills in whether it is active and its current or next occurrence
+func maintenanceWindowView(window *models.MaintenanceWindow, now time.Time) *models.MaintenanceWindow {
+	c := copyMaintenanceWindow(window)
+	if start, end, ok := windowOccurrence(window, now); ok {
+		c.Active = !now.Before(start)
+		c.OccurrenceStart = &start
+		c.OccurrenceEnd = &end
+	}
+	return c
+}
+
+func silenceView(silence *models.Silence, now time.Time) *models.Silence {
+	c := copySilence(silence)
+	c.State = silenceState(silence, now)
+	return c
+}
+
+func copyMaintenanceWindow(window *models.MaintenanceWindow) *models.MaintenanceWindow {
+	c := *window
+	c.Services = append([]string(nil), window.Services...)
+	c.Tags = append([]string(nil), window.Tags...)
+	c.Sources = append([]string(nil), window.Sources...)
+	c.Active = false
+	c.OccurrenceStart = nil
+	c.OccurrenceEnd = nil
+	if window.Recurrence != nil {
+		r := *window.Recurrence
+		r.Weekdays = append([]string(nil), r.Weekdays...)
+		if r.Until != nil {
+			until := *r.Until
+			r.Until = &until
+		}
+		c.Recurrence = &r
+	}
+	return &c
+}
+
+func copySilence(silence *models.Silence) *models.Silence {
+	c := *silence
+	c.Matchers = append([]models.Matcher(nil), silence.Matchers...)
+	c.State = ""
+	return &c
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestWindowOccurrence(t *testing.T) {
+	// Tuesdays and Thursdays 22:00-00:30 London time; British Summer Time starts on 31 March 2024
+	until := mustTime(t, "2024-04-30T00:00:00Z")
+	window := &models.MaintenanceWindow{
+		StartsAt:   mustTime(t, "2024-03-19T22:00:00Z"),
+		EndsAt:     mustTime(t, "2024-03-20T00:30:00Z"),
+		TimeZone:   "Europe/London",
+		Recurrence: &models.Recurrence{Frequency: models.RecurrenceWeekly, Weekdays: []string{"tue", "Thursday"}, Until: &until},
+	}
+
+	tests := []struct {
+		at     string
+		start  string
+		active bool
+	}{
+		{"2024-03-01T00:00:00Z", "2024-03-19T22:00:00Z", false},
+		{"2024-03-19T21:00:00Z", "2024-03-19T22:00:00Z", false},
+		{"2024-03-20T00:00:00Z", "2024-03-19T22:00:00Z", true},
+		{"2024-03-20T01:00:00Z", "2024-03-21T22:00:00Z", false},
+		{"2024-04-02T21:30:00Z", "2024-04-02T21:00:00Z", true},
+		{"2024-04-26T12:00:00Z", "", false},
+	}
+	for _, tt := range tests {
+		at := mustTime(t, tt.at)
+		start, end, ok := windowOccurrence(window, at)
+		if tt.start == "" {
+			if ok {
+				t.Errorf("%s: expected the window to be over, got %s", tt.at, start)
+			}
+			continue
+		}
+		if !ok || !start.Equal(mustTime(t, tt.start)) || end.Sub(start) != 150*time.Minute || !at.Before(start) != tt.active {
+			t.Errorf("%s: expected occurrence at %s (active %v), got %s-%s (%v)", tt.at, tt.start, tt.active, start, end, ok)
+		}
+	}
+
+	once := &models.MaintenanceWindow{StartsAt: window.StartsAt, EndsAt: window.EndsAt}
+	if _, _, ok := windowOccurrence(once, mustTime(t, "2024-03-21T00:00:00Z")); ok {
+		t.Error("expected a one-off window to be over after it ends")
+	}
+}
+
+func TestMaintenanceWindowSuppression(t *testing.T) {
+	now := mustTime(t, "2024-03-19T22:30:00Z")
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithClock(func() time.Time { return now }))
+	service.CreateService(&models.Service{Name: "ledger-db", Team: "data"})
+
+	invalid := []models.MaintenanceWindow{
+		{Name: "db upgrade", StartsAt: now, EndsAt: now.Add(time.Hour)},
+		{Name: "db upgrade", Services: []string{"ledger-db"}, StartsAt: now, EndsAt: now},
+		{Name: "db upgrade", Services: []string{"ledger-db"}, StartsAt: now, EndsAt: now.Add(time.Hour), Action: "ignore"},
+		{Name: "db upgrade", Services: []string{"ledger-db"}, StartsAt: now, EndsAt: now.Add(25 * time.Hour), Recurrence: &models.Recurrence{Frequency: models.RecurrenceDaily}},
+		{Name: "db upgrade", Services: []string{"ledger-db"}, StartsAt: now, EndsAt: now.Add(time.Hour), Recurrence: &models.Recurrence{Frequency: models.RecurrenceWeekly, Weekdays: []string{"someday"}}},
+	}
+	for _, req := range invalid {
+		req := req
+		if _, err := service.CreateMaintenanceWindow(&req); !errors.Is(err, ErrInvalidMaintenanceWindow) {
+			t.Errorf("%+v: expected ErrInvalidMaintenanceWindow, got %v", req, err)
+		}
+	}
+
+	window, err := service.CreateMaintenanceWindow(&models.MaintenanceWindow{
+		Name: "db upgrade", Services: []string{"ledger-db"}, Sources: []string{"alertmanager", "cloudwatch"},
+		StartsAt: now.Add(-30 * time.Minute), EndsAt: now.Add(time.Hour), CreatedBy: "priya",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if window.Action != models.SuppressionSuppress || !window.Active || !window.OccurrenceEnd.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected window %+v", window)
+	}
+
+	_, err = service.CreateIncident(&models.CreateIncidentRequest{Title: "Replication lag", Description: "lag > 30s", Source: "Alertmanager", Service: "ledger-db"})
+	if !errors.Is(err, ErrIncidentSuppressed) || !strings.Contains(err.Error(), "by maintenance window "+window.ID) {
+		t.Fatalf("expected the incident to be suppressed, got %v", err)
+	}
+	if incidents, _ := service.ListIncidents(nil, nil); len(incidents) != 0 {
+		t.Errorf("expected no incidents, got %d", len(incidents))
+	}
+
+	// Manually opened incidents and other services are not covered
+	if _, err := service.CreateIncident(&models.CreateIncidentRequest{Title: "Replication lag", Description: "seen by hand", Service: "ledger-db"}); err != nil {
+		t.Errorf("expected an incident without a matching source, got %v", err)
+	}
+	if _, err := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx", Source: "alertmanager"}); err != nil {
+		t.Errorf("expected an incident without a matching service, got %v", err)
+	}
+
+	entries := service.ListSuppressions(models.SuppressionFilter{MatchedBy: window.ID})
+	if len(entries) != 1 {
+		t.Fatalf("expected one audit entry, got %+v", entries)
+	}
+	if e := entries[0]; e.Action != models.SuppressionSuppress || e.MatchedType != models.MatchedMaintenanceWindow || e.MatchedName != "db upgrade" ||
+		e.Service != "ledger-db" || e.Source != "Alertmanager" || e.IncidentID != "" || !e.At.Equal(now) {
+		t.Errorf("unexpected audit entry %+v", e)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if active := service.ListMaintenanceWindows(true); len(active) != 0 {
+		t.Errorf("expected no active windows, got %+v", active)
+	}
+	if _, err := service.CreateIncident(&models.CreateIncidentRequest{Title: "Replication lag", Description: "lag > 30s", Source: "alertmanager", Service: "ledger-db"}); err != nil {
+		t.Errorf("expected an incident after the window, got %v", err)
+	}
+	if err := service.DeleteMaintenanceWindow(window.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.GetMaintenanceWindow(window.ID); !errors.Is(err, ErrMaintenanceWindowNotFound) {
+		t.Errorf("expected ErrMaintenanceWindowNotFound, got %v", err)
+	}
+}
+
+func TestMaintenanceWindowDowngrade(t *testing.T) {
+	now := mustTime(t, "2024-03-19T22:30:00Z")
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(),
+		WithClock(func() time.Time { return now }), WithDefaultEscalationPolicy("default"))
+	service.CreateEscalationPolicy(&models.EscalationPolicy{
+		Name:   "default",
+		Levels: []models.EscalationLevel{{Targets: []models.EscalationTarget{{Type: models.EscalationTargetUser, Name: "carol"}}, DelayMinutes: 10}},
+	})
+
+	window, _ := service.CreateMaintenanceWindow(&models.MaintenanceWindow{
+		Name: "nightly batch", Tags: []string{"batch"}, Action: models.SuppressionDowngrade, Severity: models.SeverityMedium,
+		StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Recurrence: &models.Recurrence{Frequency: models.RecurrenceDaily},
+	})
+
+	critical := models.SeverityCritical
+	incident, err := service.CreateIncident(&models.CreateIncidentRequest{Title: "Batch job failed", Description: "exit 1", Tags: []string{"Batch"}, Severity: &critical})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if incident.Severity != models.SeverityMedium || incident.Escalation != nil || incident.AssignedTo != "" || incident.SuppressionID == "" ||
+		incident.SeverityAdjustment != "lowered from critical to medium by maintenance window "+window.ID {
+		t.Errorf("expected a medium incident paging nobody, got %+v", incident)
+	}
+
+	low := models.SeverityLow
+	quiet, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Batch job slow", Description: "late", Tags: []string{"batch"}, Severity: &low})
+	if quiet.Severity != models.SeverityLow || quiet.SeverityAdjustment != "" {
+		t.Errorf("expected a low incident to stay low, got %+v", quiet)
+	}
+
+	// Silences are checked first, and suppressing beats downgrading
+	silence, _ := service.CreateSilence(&models.Silence{
+		Matchers: []models.Matcher{{Name: "tag", Value: "batch"}, {Name: "title", Value: "disk.*", Operator: models.MatchRegexp}},
+		Comment:  "known disk issue", CreatedBy: "priya", EndsAt: now.Add(time.Hour),
+	})
+	if _, err := service.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "/var", Tags: []string{"batch"}}); !errors.Is(err, ErrIncidentSuppressed) {
+		t.Errorf("expected the silence to suppress the incident, got %v", err)
+	}
+
+	entries := service.ListSuppressions(models.SuppressionFilter{})
+	if len(entries) != 3 || entries[0].MatchedBy != silence.ID || entries[2].IncidentID != incident.ID || entries[2].DowngradedTo != models.SeverityMedium ||
+		entries[2].Severity != models.SeverityCritical || entries[1].DowngradedTo != models.SeverityLow {
+		t.Errorf("unexpected audit log %+v", entries)
+	}
+	if limited := service.ListSuppressions(models.SuppressionFilter{Limit: 1}); len(limited) != 1 || limited[0].MatchedBy != silence.ID {
+		t.Errorf("expected the newest entry, got %+v", limited)
+	}
+
+	// Audit entries are numbered on their own, so the downgrade does not shift incident IDs
+	if entries[0].ID != "SUP-3" || entries[2].ID != "SUP-1" || incident.SuppressionID != "SUP-1" {
+		t.Errorf("expected audit entries SUP-1 to SUP-3, got %s, %s and %s", entries[2].ID, entries[1].ID, entries[0].ID)
+	}
+	number := func(id string) int {
+		n, _ := strconv.Atoi(id[strings.LastIndex(id, "-")+1:])
+		return n
+	}
+	if number(quiet.ID) != number(incident.ID)+1 {
+		t.Errorf("expected consecutive incident IDs, got %s and %s", incident.ID, quiet.ID)
+	}
+}
+
+func TestSilences(t *testing.T) {
+	now := mustTime(t, "2024-03-19T22:30:00Z")
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithClock(func() time.Time { return now }))
+
+	invalid := []models.Silence{
+		{Comment: "noisy", CreatedBy: "priya", EndsAt: now.Add(time.Hour)},
+		{Matchers: []models.Matcher{{Name: "alertname", Value: "HighCPU"}}, CreatedBy: "priya", EndsAt: now.Add(time.Hour)},
+		{Matchers: []models.Matcher{{Name: "severity", Value: "critical", Operator: models.MatchNotEqual}}, Comment: "noisy", CreatedBy: "priya", EndsAt: now.Add(time.Hour)},
+		{Matchers: []models.Matcher{{Name: "title", Value: ".*", Operator: models.MatchRegexp}}, Comment: "noisy", CreatedBy: "priya", EndsAt: now.Add(time.Hour)},
+		{Matchers: []models.Matcher{{Name: "title", Value: "(", Operator: models.MatchRegexp}}, Comment: "noisy", CreatedBy: "priya", EndsAt: now.Add(time.Hour)},
+		{Matchers: []models.Matcher{{Name: "title", Value: "cpu", Operator: "~"}}, Comment: "noisy", CreatedBy: "priya", EndsAt: now.Add(time.Hour)},
+		{Matchers: []models.Matcher{{Name: "alertname", Value: "HighCPU"}}, Comment: "noisy", CreatedBy: "priya", EndsAt: now.Add(-time.Minute)},
+	}
+	for _, req := range invalid {
+		req := req
+		if _, err := service.CreateSilence(&req); !errors.Is(err, ErrInvalidSilence) {
+			t.Errorf("%+v: expected ErrInvalidSilence, got %v", req, err)
+		}
+	}
+
+	silence, err := service.CreateSilence(&models.Silence{
+		Matchers: []models.Matcher{{Name: "alertname", Value: "highcpu"}, {Name: "severity", Value: "critical", Operator: models.MatchNotEqual}},
+		Comment:  "noisy CPU alert", CreatedBy: "priya", EndsAt: now.Add(2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if silence.State != models.SilenceActive || !silence.StartsAt.Equal(now) || silence.Matchers[0].Operator != models.MatchEqual {
+		t.Errorf("unexpected silence %+v", silence)
+	}
+	pending, _ := service.CreateSilence(&models.Silence{
+		Matchers: []models.Matcher{{Name: "source", Value: "cloudwatch"}}, Comment: "tomorrow's migration", CreatedBy: "sam",
+		StartsAt: now.Add(24 * time.Hour), EndsAt: now.Add(26 * time.Hour),
+	})
+
+	high := models.SeverityHigh
+	critical := models.SeverityCritical
+	alert := func(severity *models.Severity) error {
+		_, err := service.CreateIncident(&models.CreateIncidentRequest{Title: "CPU high", Description: "95%", Source: "cloudwatch",
+			Severity: severity, Metadata: map[string]interface{}{"alertname": "HighCPU"}})
+		return err
+	}
+	if err := alert(&high); !errors.Is(err, ErrIncidentSuppressed) {
+		t.Errorf("expected the silence to suppress a high alert, got %v", err)
+	}
+	if err := alert(&critical); err != nil {
+		t.Errorf("expected a critical alert to get through, got %v", err)
+	}
+
+	active := models.SilenceActive
+	if silences := service.ListSilences(&active); len(silences) != 1 || silences[0].ID != silence.ID {
+		t.Errorf("expected only the first silence to be active, got %+v", silences)
+	}
+
+	expired, err := service.ExpireSilence(silence.ID)
+	if err != nil || expired.State != models.SilenceExpired || !expired.EndsAt.Equal(now) {
+		t.Fatalf("unexpected expired silence %+v (%v)", expired, err)
+	}
+	if _, err := service.ExpireSilence(silence.ID); !errors.Is(err, ErrSilenceExpired) {
+		t.Errorf("expected ErrSilenceExpired, got %v", err)
+	}
+	if err := alert(&high); err != nil {
+		t.Errorf("expected alerts to get through once the silence expired, got %v", err)
+	}
+	if got, _ := service.GetSilence(pending.ID); got.State != models.SilencePending {
+		t.Errorf("expected the second silence to be pending, got %+v", got)
+	}
+	if _, err := service.GetSilence("SIL-0"); !errors.Is(err, ErrSilenceNotFound) {
+		t.Errorf("expected ErrSilenceNotFound, got %v", err)
+	}
+}
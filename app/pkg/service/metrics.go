@@ -0,0 +1,65 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	actionItemsOpenDesc = prometheus.NewDesc(
+		"incident_action_items_open",
+		"Number of open or in-progress incident action items",
+		[]string{"priority"}, nil,
+	)
+	actionItemsOverdueDesc = prometheus.NewDesc(
+		"incident_action_items_overdue",
+		"Number of open incident action items past their due date",
+		[]string{"priority"}, nil,
+	)
+	slaBreachedDesc = prometheus.NewDesc(
+		"incident_sla_breached",
+		"Number of incidents that breached an SLA target",
+		[]string{"target", "severity"}, nil,
+	)
+	slaAtRiskDesc = prometheus.NewDesc(
+		"incident_sla_at_risk",
+		"Number of incidents at risk of breaching an SLA target",
+		[]string{"target", "severity"}, nil,
+	)
+)
+
+// MetricsCollector exposes incident data as Prometheus metrics, computed at scrape time
+type MetricsCollector struct {
+	service *IncidentService
+}
+
+// NewMetricsCollector creates a collector for the service's incident metrics
+func NewMetricsCollector(service *IncidentService) *MetricsCollector {
+	return &MetricsCollector{service: service}
+}
+
+// Describe implements prometheus.Collector
+func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- actionItemsOpenDesc
+	ch <- actionItemsOverdueDesc
+	ch <- slaBreachedDesc
+	ch <- slaAtRiskDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *MetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	open, overdue := c.service.ActionItemCounts()
+	for priority, n := range open {
+		ch <- prometheus.MustNewConstMetric(actionItemsOpenDesc, prometheus.GaugeValue, float64(n), string(priority))
+	}
+	for priority, n := range overdue {
+		ch <- prometheus.MustNewConstMetric(actionItemsOverdueDesc, prometheus.GaugeValue, float64(n), string(priority))
+	}
+
+	breached, atRisk := c.service.SLACounts()
+	for _, count := range breached {
+		ch <- prometheus.MustNewConstMetric(slaBreachedDesc, prometheus.GaugeValue, float64(count.Count), string(count.Target), string(count.Severity))
+	}
+	for _, count := range atRisk {
+		ch <- prometheus.MustNewConstMetric(slaAtRiskDesc, prometheus.GaugeValue, float64(count.Count), string(count.Target), string(count.Severity))
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+var (
+	// ErrNotificationsDisabled is returned when no notifier is configured
+	ErrNotificationsDisabled = errors.New("notifications are not configured")
+	// ErrNotificationNotFound is returned when a dead-lettered notification ID does not exist
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// Notifier delivers incident events to outbound channels and keeps a record of every delivery
+type Notifier interface {
+	EventSubscriber
+	// History returns the deliveries for an incident, oldest first
+	History(incidentID string) []models.NotificationDelivery
+	// DeadLetters returns deliveries that exhausted their retries
+	DeadLetters() []models.NotificationDelivery
+	// Redeliver queues a dead-lettered delivery again; false means the ID is not dead-lettered
+	Redeliver(id string) (*models.NotificationDelivery, bool)
+	// Close stops accepting events and waits for queued deliveries to finish
+	Close()
+}
+
+// WithNotifier sends incident events to notifier
+func WithNotifier(notifier Notifier) ServiceOption {
+	return func(s *IncidentService) {
+		s.notifier = notifier
+		s.subscribers = append(s.subscribers, notifier)
+	}
+}
+
+// NotificationHistory returns the notifications sent for an incident
+func (s *IncidentService) NotificationHistory(id string) ([]models.NotificationDelivery, error) {
+	if s.notifier == nil {
+		return nil, ErrNotificationsDisabled
+	}
+	if _, err := s.GetIncident(id); err != nil {
+		return nil, err
+	}
+	return s.notifier.History(id), nil
+}
+
+// DeadLetterNotifications returns notifications that could not be delivered
+func (s *IncidentService) DeadLetterNotifications() ([]models.NotificationDelivery, error) {
+	if s.notifier == nil {
+		return nil, ErrNotificationsDisabled
+	}
+	return s.notifier.DeadLetters(), nil
+}
+
+// RedeliverNotification retries a dead-lettered notification
+func (s *IncidentService) RedeliverNotification(id string) (*models.NotificationDelivery, error) {
+	if s.notifier == nil {
+		return nil, ErrNotificationsDisabled
+	}
+	delivery, ok := s.notifier.Redeliver(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationNotFound, id)
+	}
+	return delivery, nil
+}
+
+// CloseNotifications flushes queued notifications; call it on shutdown
+func (s *IncidentService) CloseNotifications() {
+	if s.notifier != nil {
+		s.notifier.Close()
+	}
+}
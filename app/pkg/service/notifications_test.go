@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// recordingNotifier keeps events in memory and dead-letters nothing
+type recordingNotifier struct {
+	events []models.Event
+	closed bool
+}
+
+func (n *recordingNotifier) HandleEvent(event models.Event) {
+	n.events = append(n.events, event)
+}
+
+func (n *recordingNotifier) History(incidentID string) []models.NotificationDelivery {
+	var deliveries []models.NotificationDelivery
+	for _, event := range n.events {
+		if event.Incident.ID == incidentID {
+			deliveries = append(deliveries, models.NotificationDelivery{EventID: event.ID, EventType: event.Type})
+		}
+	}
+	return deliveries
+}
+
+func (n *recordingNotifier) DeadLetters() []models.NotificationDelivery { return nil }
+
+func (n *recordingNotifier) Redeliver(id string) (*models.NotificationDelivery, bool) {
+	return nil, false
+}
+
+func (n *recordingNotifier) Close() { n.closed = true }
+
+func (n *recordingNotifier) types() []models.EventType {
+	var types []models.EventType
+	for _, event := range n.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestEventPublication(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithNotifier(notifier))
+
+	low := models.SeverityLow
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx", Severity: &low})
+
+	critical, resolved := models.SeverityCritical, models.StatusResolved
+	title := "Checkout down"
+	service.UpdateIncident(incident.ID, &models.UpdateIncidentRequest{Severity: &critical})
+	service.UpdateIncident(incident.ID, &models.UpdateIncidentRequest{Title: &title, Status: &resolved})
+	service.UpdateIncident(incident.ID, &models.UpdateIncidentRequest{Status: &resolved})
+	service.AcknowledgeIncident(incident.ID, &models.AcknowledgeRequest{User: "alice"})
+	service.AnalyzeIncident(incident.ID)
+
+	expected := []models.EventType{
+		models.EventIncidentCreated,
+		models.EventIncidentUpdated, models.EventIncidentSeverityChanged,
+		models.EventIncidentUpdated, models.EventIncidentResolved,
+		models.EventIncidentUpdated,
+		models.EventIncidentAcknowledged,
+		models.EventAnalysisCompleted,
+	}
+	got := notifier.types()
+	if len(got) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("event %d: expected %s, got %s", i, expected[i], got[i])
+		}
+	}
+
+	changed := notifier.events[2]
+	if changed.PreviousSeverity != models.SeverityLow || changed.Incident.Severity != models.SeverityCritical || changed.ID != "EVT-3" {
+		t.Errorf("expected a low to critical change, got %+v", changed)
+	}
+	// Events carry snapshots, so later updates do not rewrite what subscribers saw
+	if notifier.events[0].Incident == incident || notifier.events[0].Incident.Title != "Checkout errors" {
+		t.Errorf("expected the created event to keep the original title, got %q", notifier.events[0].Incident.Title)
+	}
+	if summary := notifier.events[len(notifier.events)-1].Incident.AIAnalysis; summary == nil || summary.Summary != "Test analysis summary" {
+		t.Errorf("expected analysis.completed to include the analysis, got %+v", summary)
+	}
+
+	history, err := service.NotificationHistory(incident.ID)
+	if err != nil || len(history) != len(expected) {
+		t.Errorf("expected the notifier's history, got %d deliveries (%v)", len(history), err)
+	}
+	if _, err := service.NotificationHistory("INC-missing"); !errors.Is(err, ErrIncidentNotFound) {
+		t.Errorf("expected ErrIncidentNotFound, got %v", err)
+	}
+	if _, err := service.RedeliverNotification("NTF-1"); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected ErrNotificationNotFound, got %v", err)
+	}
+	service.CloseNotifications()
+	if !notifier.closed {
+		t.Error("expected CloseNotifications to close the notifier")
+	}
+}
+
+func TestNotificationsDisabled(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "db-1"})
+
+	if _, err := service.NotificationHistory(incident.ID); !errors.Is(err, ErrNotificationsDisabled) {
+		t.Errorf("expected ErrNotificationsDisabled, got %v", err)
+	}
+	if _, err := service.DeadLetterNotifications(); !errors.Is(err, ErrNotificationsDisabled) {
+		t.Errorf("expected ErrNotificationsDisabled, got %v", err)
+	}
+	service.CloseNotifications()
+}
@@ -0,0 +1,884 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// DefaultEscalationInterval is how often the scheduler checks for incidents due to escalate
+const DefaultEscalationInterval = 30 * time.Second
+
+// maxEscalationRepeat bounds how many times a policy can run through its levels
+const maxEscalationRepeat = 10
+
+var (
+	// ErrScheduleNotFound is returned when an on-call schedule does not exist
+	ErrScheduleNotFound = errors.New("schedule not found")
+	// ErrScheduleExists is returned when creating a schedule whose name is taken
+	ErrScheduleExists = errors.New("schedule already exists")
+	// ErrInvalidSchedule is returned for schedules and overrides that fail validation
+	ErrInvalidSchedule = errors.New("invalid schedule")
+	// ErrOverrideNotFound is returned when a schedule override does not exist
+	ErrOverrideNotFound = errors.New("override not found")
+	// ErrEscalationPolicyNotFound is returned when an escalation policy does not exist
+	ErrEscalationPolicyNotFound = errors.New("escalation policy not found")
+	// ErrEscalationPolicyExists is returned when creating a policy whose name is taken
+	ErrEscalationPolicyExists = errors.New("escalation policy already exists")
+	// ErrInvalidEscalationPolicy is returned for escalation policies that fail validation
+	ErrInvalidEscalationPolicy = errors.New("invalid escalation policy")
+	// ErrInvalidAcknowledgement is returned when an acknowledgement does not name a user
+	ErrInvalidAcknowledgement = errors.New("invalid acknowledgement")
+	// ErrNotEscalatable is returned when escalating an incident that is resolved, has no policy or has paged every level
+	ErrNotEscalatable = errors.New("incident cannot be escalated")
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// WithClock replaces time.Now for on-call evaluation and escalations, so tests can control time
+func WithClock(clock func() time.Time) ServiceOption {
+	return func(s *IncidentService) {
+		s.clock = clock
+	}
+}
+
+// WithDefaultEscalationPolicy pages incidents whose service has no escalation policy, or no service
+func WithDefaultEscalationPolicy(name string) ServiceOption {
+	return func(s *IncidentService) {
+		s.defaultEscalationPolicy = name
+	}
+}
+
+// LoadOnCallConfig reads and validates a JSON file of schedules and escalation policies
+func LoadOnCallConfig(path string) (*models.OnCallConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read on-call config: %w", err)
+	}
+
+	var cfg models.OnCallConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid on-call config %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range cfg.Schedules {
+		schedule := &cfg.Schedules[i]
+		if err := validateSchedule(schedule); err != nil {
+			return nil, fmt.Errorf("on-call config %s: %w", path, err)
+		}
+		if seen[catalogKey(schedule.Name)] {
+			return nil, fmt.Errorf("on-call config %s: %w: %s", path, ErrScheduleExists, schedule.Name)
+		}
+		seen[catalogKey(schedule.Name)] = true
+	}
+	seen = make(map[string]bool)
+	for i := range cfg.EscalationPolicies {
+		policy := &cfg.EscalationPolicies[i]
+		if err := validateEscalationPolicy(policy); err != nil {
+			return nil, fmt.Errorf("on-call config %s: %w", path, err)
+		}
+		if seen[catalogKey(policy.Name)] {
+			return nil, fmt.Errorf("on-call config %s: %w: %s", path, ErrEscalationPolicyExists, policy.Name)
+		}
+		seen[catalogKey(policy.Name)] = true
+	}
+	return &cfg, nil
+}
+
+// WithOnCallConfig preloads schedules and escalation policies, typically from LoadOnCallConfig
+func WithOnCallConfig(cfg *models.OnCallConfig) ServiceOption {
+	return func(s *IncidentService) {
+		now := time.Now()
+		for i := range cfg.Schedules {
+			schedule := copySchedule(&cfg.Schedules[i])
+			schedule.CreatedAt = now
+			schedule.UpdatedAt = now
+			s.assignOverrideIDs(schedule)
+			s.store.schedules[catalogKey(schedule.Name)] = schedule
+		}
+		for i := range cfg.EscalationPolicies {
+			policy := copyEscalationPolicy(&cfg.EscalationPolicies[i])
+			policy.CreatedAt = now
+			policy.UpdatedAt = now
+			s.store.escalationPolicies[catalogKey(policy.Name)] = policy
+		}
+	}
+}
+
+// CreateSchedule adds an on-call schedule
+func (s *IncidentService) CreateSchedule(req *models.Schedule) (*models.Schedule, error) {
+	schedule := copySchedule(req)
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = schedule.CreatedAt
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := catalogKey(schedule.Name)
+	if _, ok := s.store.schedules[key]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrScheduleExists, schedule.Name)
+	}
+	s.assignOverrideIDs(schedule)
+	s.store.schedules[key] = schedule
+
+	s.logger.Info("schedule created", zap.String("schedule", schedule.Name), zap.Int("layers", len(schedule.Layers)))
+	return copySchedule(schedule), nil
+}
+
+// ListSchedules returns all schedules sorted by name
+func (s *IncidentService) ListSchedules() []*models.Schedule {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.Schedule, 0, len(s.store.schedules))
+	for _, schedule := range s.store.schedules {
+		result = append(result, copySchedule(schedule))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// GetSchedule returns a schedule by name, case-insensitively
+func (s *IncidentService) GetSchedule(name string) (*models.Schedule, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	schedule, ok := s.store.schedules[catalogKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+	return copySchedule(schedule), nil
+}
+
+// UpdateSchedule replaces a schedule's definition; existing overrides are kept unless req lists overrides
+func (s *IncidentService) UpdateSchedule(name string, req *models.Schedule) (*models.Schedule, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	existing, ok := s.store.schedules[catalogKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+
+	schedule := copySchedule(req)
+	schedule.Name = existing.Name
+	if req.Overrides == nil {
+		schedule.Overrides = append([]models.ScheduleOverride(nil), existing.Overrides...)
+	}
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	schedule.CreatedAt = existing.CreatedAt
+	schedule.UpdatedAt = time.Now()
+	s.assignOverrideIDs(schedule)
+	s.store.schedules[catalogKey(name)] = schedule
+
+	s.logger.Info("schedule updated", zap.String("schedule", schedule.Name))
+	return copySchedule(schedule), nil
+}
+
+// DeleteSchedule removes a schedule; policies and services that name it no longer resolve anyone from it
+func (s *IncidentService) DeleteSchedule(name string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := catalogKey(name)
+	if _, ok := s.store.schedules[key]; !ok {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+	delete(s.store.schedules, key)
+
+	s.logger.Info("schedule deleted", zap.String("schedule", name))
+	return nil
+}
+
+// AddOverride puts a user on call for a schedule over a fixed period
+func (s *IncidentService) AddOverride(name string, req *models.CreateOverrideRequest) (*models.ScheduleOverride, error) {
+	override := models.ScheduleOverride{
+		User:   strings.TrimSpace(req.User),
+		Start:  req.Start,
+		End:    req.End,
+		Reason: req.Reason,
+	}
+	if err := validateOverride(override); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	schedule, ok := s.store.schedules[catalogKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+	s.store.counter++
+	override.ID = fmt.Sprintf("OVR-%d", s.store.counter)
+	override.CreatedAt = time.Now()
+	schedule.Overrides = append(schedule.Overrides, override)
+	schedule.UpdatedAt = override.CreatedAt
+
+	s.logger.Info("schedule override added", zap.String("schedule", schedule.Name), zap.String("user", override.User))
+	return &override, nil
+}
+
+// DeleteOverride removes an override from a schedule
+func (s *IncidentService) DeleteOverride(name, overrideID string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	schedule, ok := s.store.schedules[catalogKey(name)]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+	for i, override := range schedule.Overrides {
+		if override.ID == overrideID {
+			schedule.Overrides = append(schedule.Overrides[:i:i], schedule.Overrides[i+1:]...)
+			schedule.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrOverrideNotFound, overrideID)
+}
+
+// OnCallNow reports who is on call at the given time, or now when at is zero, for one schedule or,
+// when name is empty, all of them
+func (s *IncidentService) OnCallNow(name string, at time.Time) ([]models.OnCall, error) {
+	if at.IsZero() {
+		at = s.now()
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	if name != "" {
+		schedule, ok := s.store.schedules[catalogKey(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+		}
+		return []models.OnCall{onCallAt(schedule, at)}, nil
+	}
+
+	result := make([]models.OnCall, 0, len(s.store.schedules))
+	for _, schedule := range s.store.schedules {
+		result = append(result, onCallAt(schedule, at))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Schedule < result[j].Schedule })
+	return result, nil
+}
+
+// CreateEscalationPolicy adds an escalation policy
+func (s *IncidentService) CreateEscalationPolicy(req *models.EscalationPolicy) (*models.EscalationPolicy, error) {
+	policy := copyEscalationPolicy(req)
+	if err := validateEscalationPolicy(policy); err != nil {
+		return nil, err
+	}
+	policy.CreatedAt = time.Now()
+	policy.UpdatedAt = policy.CreatedAt
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := catalogKey(policy.Name)
+	if _, ok := s.store.escalationPolicies[key]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrEscalationPolicyExists, policy.Name)
+	}
+	s.store.escalationPolicies[key] = policy
+
+	s.logger.Info("escalation policy created", zap.String("policy", policy.Name), zap.Int("levels", len(policy.Levels)))
+	return copyEscalationPolicy(policy), nil
+}
+
+// ListEscalationPolicies returns all escalation policies sorted by name
+func (s *IncidentService) ListEscalationPolicies() []*models.EscalationPolicy {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.EscalationPolicy, 0, len(s.store.escalationPolicies))
+	for _, policy := range s.store.escalationPolicies {
+		result = append(result, copyEscalationPolicy(policy))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// GetEscalationPolicy returns an escalation policy by name, case-insensitively
+func (s *IncidentService) GetEscalationPolicy(name string) (*models.EscalationPolicy, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	policy, ok := s.store.escalationPolicies[catalogKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEscalationPolicyNotFound, name)
+	}
+	return copyEscalationPolicy(policy), nil
+}
+
+// UpdateEscalationPolicy replaces a policy's levels; incidents already escalating continue from their
+// current level
+func (s *IncidentService) UpdateEscalationPolicy(name string, req *models.EscalationPolicy) (*models.EscalationPolicy, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	existing, ok := s.store.escalationPolicies[catalogKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEscalationPolicyNotFound, name)
+	}
+
+	policy := copyEscalationPolicy(req)
+	policy.Name = existing.Name
+	if err := validateEscalationPolicy(policy); err != nil {
+		return nil, err
+	}
+	policy.CreatedAt = existing.CreatedAt
+	policy.UpdatedAt = time.Now()
+	s.store.escalationPolicies[catalogKey(name)] = policy
+
+	s.logger.Info("escalation policy updated", zap.String("policy", policy.Name))
+	return copyEscalationPolicy(policy), nil
+}
+
+// DeleteEscalationPolicy removes a policy; incidents escalating through it stop escalating
+func (s *IncidentService) DeleteEscalationPolicy(name string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := catalogKey(name)
+	if _, ok := s.store.escalationPolicies[key]; !ok {
+		return fmt.Errorf("%w: %s", ErrEscalationPolicyNotFound, name)
+	}
+	delete(s.store.escalationPolicies, key)
+
+	s.logger.Info("escalation policy deleted", zap.String("policy", name))
+	return nil
+}
+
+// AcknowledgeIncident records a responder taking an incident, which stops its escalation.
+// Acknowledging an acknowledged incident leaves the first acknowledgement in place.
+func (s *IncidentService) AcknowledgeIncident(id string, req *models.AcknowledgeRequest) (*models.Incident, error) {
+	user := strings.TrimSpace(req.User)
+	if user == "" {
+		return nil, fmt.Errorf("%w: user is required", ErrInvalidAcknowledgement)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if incident.AcknowledgedAt != nil {
+		return snapshotIncident(incident), nil
+	}
+
+	now := s.now()
+	incident.AcknowledgedAt = &now
+	incident.AcknowledgedBy = user
+	if incident.Status == models.StatusOpen {
+		incident.Status = models.StatusInProgress
+	}
+	if incident.Escalation != nil {
+		incident.Escalation.NextEscalationAt = nil
+	}
+	incident.UpdatedAt = now
+	s.refreshSLA(incident, now)
+	s.publish(models.EventIncidentAcknowledged, incident, "")
+
+	s.logger.Info("incident acknowledged", zap.String("id", id), zap.String("user", user))
+	return snapshotIncident(incident), nil
+}
+
+// EvaluateEscalations notifies the next level for every unacknowledged incident whose escalation delay
+// has passed, and returns how many incidents were escalated
+func (s *IncidentService) EvaluateEscalations() int {
+	now := s.now()
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	escalated := 0
+	for _, incident := range s.store.incidents {
+		state := incident.Escalation
+		if state == nil || state.NextEscalationAt == nil || incident.AcknowledgedAt != nil || now.Before(*state.NextEscalationAt) {
+			continue
+		}
+		if incident.Status == models.StatusResolved || incident.Status == models.StatusClosed {
+			state.NextEscalationAt = nil
+			continue
+		}
+
+		policy, ok := s.store.escalationPolicies[catalogKey(state.Policy)]
+		if !ok {
+			s.logger.Warn("escalation policy missing, escalation stopped", zap.String("id", incident.ID), zap.String("policy", state.Policy))
+			state.NextEscalationAt = nil
+			continue
+		}
+
+		if s.escalate(incident, policy, now) {
+			escalated++
+		}
+	}
+	return escalated
+}
+
+// EscalateIncident pages the next level of an incident's escalation policy now, without waiting
+// for the delay. It works on acknowledged incidents too, for responders who want more help.
+func (s *IncidentService) EscalateIncident(id string) (*models.Incident, error) {
+	now := s.now()
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if incident.Status == models.StatusResolved || incident.Status == models.StatusClosed {
+		return nil, fmt.Errorf("%w: %s is %s", ErrNotEscalatable, id, incident.Status)
+	}
+	if incident.Escalation == nil {
+		return nil, fmt.Errorf("%w: %s has no escalation policy", ErrNotEscalatable, id)
+	}
+	policy, ok := s.store.escalationPolicies[catalogKey(incident.Escalation.Policy)]
+	if !ok {
+		return nil, fmt.Errorf("%w: escalation policy %q no longer exists", ErrNotEscalatable, incident.Escalation.Policy)
+	}
+	if !s.escalate(incident, policy, now) {
+		return nil, fmt.Errorf("%w: every level of %s has been paged", ErrNotEscalatable, policy.Name)
+	}
+	return snapshotIncident(incident), nil
+}
+
+// escalate pages the level after the last one notified, starting the policy again while repeats remain.
+// It returns false once the policy is exhausted. Callers must hold s.store.mu.
+func (s *IncidentService) escalate(incident *models.Incident, policy *models.EscalationPolicy, now time.Time) bool {
+	state := incident.Escalation
+	level, cycle := state.Level+1, state.Cycle
+	if level > len(policy.Levels) {
+		if cycle >= policy.Repeat {
+			state.Exhausted = true
+			state.NextEscalationAt = nil
+			s.logger.Warn("escalation exhausted without acknowledgement", zap.String("id", incident.ID), zap.String("policy", policy.Name))
+			return false
+		}
+		level, cycle = 1, cycle+1
+	}
+
+	if users := s.notifyLevel(incident, policy, level, cycle, now); len(users) > 0 {
+		incident.AssignedTo = users[0]
+	}
+	incident.UpdatedAt = now
+	s.publish(models.EventIncidentEscalated, incident, "")
+	return true
+}
+
+// RunEscalations evaluates escalations every interval until ctx is cancelled
+func (s *IncidentService) RunEscalations(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := s.EvaluateEscalations(); n > 0 {
+				s.logger.Info("incidents escalated", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+func (s *IncidentService) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
+// startEscalation pages the first level of the incident's escalation policy and returns who to assign it to.
+// Without a policy the incident goes to whoever is on call for its service's schedule. Callers must hold s.store.mu.
+func (s *IncidentService) startEscalation(incident *models.Incident, svc *models.Service) string {
+	name := s.defaultEscalationPolicy
+	if svc != nil && svc.EscalationPolicy != "" {
+		name = svc.EscalationPolicy
+	}
+	if policy, ok := s.store.escalationPolicies[catalogKey(name)]; ok && name != "" {
+		incident.Escalation = &models.EscalationState{Policy: policy.Name}
+		if users := s.notifyLevel(incident, policy, 1, 0, s.now()); len(users) > 0 {
+			return users[0]
+		}
+		return ""
+	}
+
+	if svc != nil && svc.OnCallSchedule != "" {
+		if schedule, ok := s.store.schedules[catalogKey(svc.OnCallSchedule)]; ok {
+			return onCallAt(schedule, s.now()).User
+		}
+	}
+	return ""
+}
+
+// notifyLevel records paging the users at a 1-based policy level and schedules the next escalation;
+// callers must hold s.store.mu
+func (s *IncidentService) notifyLevel(incident *models.Incident, policy *models.EscalationPolicy, level, cycle int, now time.Time) []string {
+	l := policy.Levels[level-1]
+	users := make([]string, 0, len(l.Targets))
+	for _, target := range l.Targets {
+		switch target.Type {
+		case models.EscalationTargetUser:
+			users = appendUnique(users, target.Name)
+		case models.EscalationTargetSchedule:
+			schedule, ok := s.store.schedules[catalogKey(target.Name)]
+			if !ok {
+				s.logger.Warn("escalation schedule missing", zap.String("policy", policy.Name), zap.String("schedule", target.Name))
+				continue
+			}
+			if onCall := onCallAt(schedule, now); onCall.User != "" {
+				users = appendUnique(users, onCall.User)
+			}
+		}
+	}
+
+	next := now.Add(time.Duration(l.DelayMinutes) * time.Minute)
+	state := incident.Escalation
+	state.Level = level
+	state.Cycle = cycle
+	state.NotifiedAt = now
+	state.NextEscalationAt = &next
+	state.History = append(state.History, models.EscalationEvent{At: now, Level: level, Cycle: cycle, Users: users})
+
+	s.logger.Info("incident paged",
+		zap.String("id", incident.ID),
+		zap.String("policy", policy.Name),
+		zap.Int("level", level),
+		zap.Strings("users", users),
+	)
+	return users
+}
+
+// assignOverrideIDs gives overrides loaded from config or a schedule body an ID; callers must hold
+// s.store.mu or own the service exclusively
+func (s *IncidentService) assignOverrideIDs(schedule *models.Schedule) {
+	for i := range schedule.Overrides {
+		if schedule.Overrides[i].ID == "" {
+			s.store.counter++
+			schedule.Overrides[i].ID = fmt.Sprintf("OVR-%d", s.store.counter)
+		}
+		if schedule.Overrides[i].CreatedAt.IsZero() {
+			schedule.Overrides[i].CreatedAt = schedule.UpdatedAt
+		}
+	}
+}
+
+// onCallAt evaluates a schedule at a point in time. The latest override covering the time wins, then the
+// last layer that is active.
+func onCallAt(schedule *models.Schedule, at time.Time) models.OnCall {
+	result := models.OnCall{Schedule: schedule.Name}
+
+	for i := len(schedule.Overrides) - 1; i >= 0; i-- {
+		override := schedule.Overrides[i]
+		if !at.Before(override.Start) && at.Before(override.End) {
+			result.User = override.User
+			result.OverrideID = override.ID
+			result.ShiftStart = &override.Start
+			result.ShiftEnd = &override.End
+			return result
+		}
+	}
+
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	for i := len(schedule.Layers) - 1; i >= 0; i-- {
+		layer := schedule.Layers[i]
+		if !restrictionActive(layer.Restriction, at.In(loc)) {
+			continue
+		}
+		user, start, end, ok := layerShift(layer, loc, at)
+		if !ok {
+			continue
+		}
+		result.User = user
+		result.Layer = layer.Name
+		result.ShiftStart = &start
+		result.ShiftEnd = &end
+		return result
+	}
+	return result
+}
+
+// layerShift finds the layer's shift covering at. Shifts are counted in calendar days so handoffs keep
+// their wall-clock time across DST changes.
+func layerShift(layer models.ScheduleLayer, loc *time.Location, at time.Time) (string, time.Time, time.Time, bool) {
+	date, err := time.ParseInLocation("2006-01-02", layer.StartDate, loc)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, false
+	}
+	hour, minute, err := parseClock(layer.HandoffTime)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, false
+	}
+	first := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc)
+	if at.Before(first) {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	local := at.In(loc)
+	days := civilDays(first, local)
+	if local.Before(time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)) {
+		days--
+	}
+	length := shiftDays(layer)
+	shift := days / length
+	start := first.AddDate(0, 0, shift*length)
+	end := first.AddDate(0, 0, (shift+1)*length)
+	return layer.Users[shift%len(layer.Users)], start, end, true
+}
+
+// civilDays counts calendar days from a's date to b's date
+func civilDays(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+func shiftDays(layer models.ScheduleLayer) int {
+	switch layer.Rotation {
+	case models.RotationDaily:
+		return 1
+	case models.RotationWeekly:
+		return 7
+	}
+	return layer.ShiftDays
+}
+
+// restrictionActive reports whether a layer restriction allows the local time
+func restrictionActive(r *models.LayerRestriction, local time.Time) bool {
+	if r == nil {
+		return true
+	}
+	if len(r.Weekdays) > 0 {
+		found := false
+		for _, day := range r.Weekdays {
+			if weekdays[weekdayKey(day)] == local.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Start == "" || r.End == "" {
+		return true
+	}
+	sh, sm, _ := parseClock(r.Start)
+	eh, em, _ := parseClock(r.End)
+	start, end := sh*60+sm, eh*60+em
+	now := local.Hour()*60 + local.Minute()
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// parseClock parses an HH:MM wall-clock time
+func parseClock(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q: expected HH:MM", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// weekdayKey accepts full or abbreviated day names in any case
+func weekdayKey(day string) string {
+	day = strings.ToLower(strings.TrimSpace(day))
+	if len(day) > 3 {
+		day = day[:3]
+	}
+	return day
+}
+
+// validateSchedule checks a schedule, filling in defaults for the time zone and handoff time
+func validateSchedule(schedule *models.Schedule) error {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if !namePattern.MatchString(schedule.Name) {
+		return fmt.Errorf("%w: name must be letters, digits, '.', '_' or '-'", ErrInvalidSchedule)
+	}
+	if schedule.TimeZone == "" {
+		schedule.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidSchedule, schedule.TimeZone)
+	}
+	if len(schedule.Layers) == 0 {
+		return fmt.Errorf("%w: %s needs at least one layer", ErrInvalidSchedule, schedule.Name)
+	}
+
+	for i := range schedule.Layers {
+		layer := &schedule.Layers[i]
+		if layer.Name == "" {
+			layer.Name = fmt.Sprintf("layer-%d", i+1)
+		}
+		users := make([]string, 0, len(layer.Users))
+		for _, user := range layer.Users {
+			if user = strings.TrimSpace(user); user != "" {
+				users = append(users, user)
+			}
+		}
+		if len(users) == 0 {
+			return fmt.Errorf("%w: layer %s has no users", ErrInvalidSchedule, layer.Name)
+		}
+		layer.Users = users
+
+		switch layer.Rotation {
+		case models.RotationDaily, models.RotationWeekly:
+		case models.RotationCustom:
+			if layer.ShiftDays < 1 {
+				return fmt.Errorf("%w: layer %s needs shift_days for a custom rotation", ErrInvalidSchedule, layer.Name)
+			}
+		default:
+			return fmt.Errorf("%w: layer %s has unknown rotation %q", ErrInvalidSchedule, layer.Name, layer.Rotation)
+		}
+		if _, err := time.Parse("2006-01-02", layer.StartDate); err != nil {
+			return fmt.Errorf("%w: layer %s start_date must be YYYY-MM-DD", ErrInvalidSchedule, layer.Name)
+		}
+		if layer.HandoffTime == "" {
+			layer.HandoffTime = "00:00"
+		}
+		if _, _, err := parseClock(layer.HandoffTime); err != nil {
+			return fmt.Errorf("%w: layer %s handoff_time: %v", ErrInvalidSchedule, layer.Name, err)
+		}
+
+		if r := layer.Restriction; r != nil {
+			for _, day := range r.Weekdays {
+				if _, ok := weekdays[weekdayKey(day)]; !ok {
+					return fmt.Errorf("%w: layer %s has unknown weekday %q", ErrInvalidSchedule, layer.Name, day)
+				}
+			}
+			if (r.Start == "") != (r.End == "") {
+				return fmt.Errorf("%w: layer %s restriction needs both start and end", ErrInvalidSchedule, layer.Name)
+			}
+			for _, value := range []string{r.Start, r.End} {
+				if _, _, err := parseClock(value); value != "" && err != nil {
+					return fmt.Errorf("%w: layer %s restriction: %v", ErrInvalidSchedule, layer.Name, err)
+				}
+			}
+		}
+	}
+
+	for i := range schedule.Overrides {
+		schedule.Overrides[i].User = strings.TrimSpace(schedule.Overrides[i].User)
+		if err := validateOverride(schedule.Overrides[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateOverride(override models.ScheduleOverride) error {
+	if override.User == "" {
+		return fmt.Errorf("%w: override user is required", ErrInvalidSchedule)
+	}
+	if !override.End.After(override.Start) {
+		return fmt.Errorf("%w: override must end after it starts", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// validateEscalationPolicy checks a policy's levels and targets
+func validateEscalationPolicy(policy *models.EscalationPolicy) error {
+	policy.Name = strings.TrimSpace(policy.Name)
+	if !namePattern.MatchString(policy.Name) {
+		return fmt.Errorf("%w: name must be letters, digits, '.', '_' or '-'", ErrInvalidEscalationPolicy)
+	}
+	if len(policy.Levels) == 0 {
+		return fmt.Errorf("%w: %s needs at least one level", ErrInvalidEscalationPolicy, policy.Name)
+	}
+	if policy.Repeat < 0 || policy.Repeat > maxEscalationRepeat {
+		return fmt.Errorf("%w: repeat must be between 0 and %d", ErrInvalidEscalationPolicy, maxEscalationRepeat)
+	}
+	for i, level := range policy.Levels {
+		if len(level.Targets) == 0 {
+			return fmt.Errorf("%w: level %d has no targets", ErrInvalidEscalationPolicy, i+1)
+		}
+		if level.DelayMinutes < 1 {
+			return fmt.Errorf("%w: level %d delay_minutes must be at least 1", ErrInvalidEscalationPolicy, i+1)
+		}
+		for _, target := range level.Targets {
+			if target.Type != models.EscalationTargetUser && target.Type != models.EscalationTargetSchedule {
+				return fmt.Errorf("%w: level %d has unknown target type %q", ErrInvalidEscalationPolicy, i+1, target.Type)
+			}
+			if strings.TrimSpace(target.Name) == "" {
+				return fmt.Errorf("%w: level %d has a target without a name", ErrInvalidEscalationPolicy, i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// copySchedule returns a copy safe to hand out after the store lock is released
+func copySchedule(schedule *models.Schedule) *models.Schedule {
+	c := *schedule
+	c.Layers = make([]models.ScheduleLayer, len(schedule.Layers))
+	for i, layer := range schedule.Layers {
+		layer.Users = append([]string(nil), layer.Users...)
+		if layer.Restriction != nil {
+			r := *layer.Restriction
+			r.Weekdays = append([]string(nil), r.Weekdays...)
+			layer.Restriction = &r
+		}
+		c.Layers[i] = layer
+	}
+	c.Overrides = append([]models.ScheduleOverride(nil), schedule.Overrides...)
+	return &c
+}
+
+// copyEscalationPolicy returns a copy safe to hand out after the store lock is released
+func copyEscalationPolicy(policy *models.EscalationPolicy) *models.EscalationPolicy {
+	c := *policy
+	c.Levels = make([]models.EscalationLevel, len(policy.Levels))
+	for i, level := range policy.Levels {
+		level.Targets = append([]models.EscalationTarget(nil), level.Targets...)
+		c.Levels[i] = level
+	}
+	return &c
+}
+
+// escalationTimelineEvents lists pages and the acknowledgement as timeline events
+func escalationTimelineEvents(incident *models.Incident) []timelineEvent {
+	var events []timelineEvent
+	if state := incident.Escalation; state != nil {
+		for _, e := range state.History {
+			who := "nobody on call"
+			if len(e.Users) > 0 {
+				who = strings.Join(e.Users, ", ")
+			}
+			events = append(events, timelineEvent{e.At, fmt.Sprintf("Paged %s (escalation level %d) at %s",
+				who, e.Level, e.At.Format(time.RFC3339))})
+		}
+	}
+	if incident.AcknowledgedAt != nil {
+		events = append(events, timelineEvent{*incident.AcknowledgedAt, fmt.Sprintf("Acknowledged by %s at %s",
+			incident.AcknowledgedBy, incident.AcknowledgedAt.Format(time.RFC3339))})
+	}
+	return events
+}
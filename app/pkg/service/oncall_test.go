@@ -0,0 +1,231 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func mustTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("bad test time %q: %v", value, err)
+	}
+	return parsed
+}
+
+func TestOnCallAt(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	_, err := service.CreateSchedule(&models.Schedule{
+		Name:     "payments-primary",
+		TimeZone: "America/New_York",
+		Layers: []models.ScheduleLayer{
+			{Name: "primary", Users: []string{"alice", "bob", "carol"}, Rotation: models.RotationWeekly, StartDate: "2024-03-04", HandoffTime: "09:00"},
+			{
+				Name: "daytime", Users: []string{"dave", "erin"}, Rotation: models.RotationDaily, StartDate: "2024-03-04", HandoffTime: "09:00",
+				Restriction: &models.LayerRestriction{Weekdays: []string{"Mon", "tuesday", "wed", "thu", "fri"}, Start: "09:00", End: "17:00"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.AddOverride("payments-primary", &models.CreateOverrideRequest{
+		User:  "frank",
+		Start: mustTime(t, "2024-03-13T00:00:00Z"),
+		End:   mustTime(t, "2024-03-14T00:00:00Z"),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		at    string
+		user  string
+		layer string
+	}{
+		{"before the first handoff", "2024-03-04T08:59:00-05:00", "", ""},
+		{"weeknight", "2024-03-06T20:00:00-05:00", "alice", "primary"},
+		{"weekend outside the daytime layer", "2024-03-09T12:00:00-05:00", "alice", "primary"},
+		// DST starts on 10 March; the handoff stays at 09:00 local, now 13:00 UTC
+		{"just before the handoff after DST", "2024-03-11T12:59:00Z", "alice", "primary"},
+		{"daytime layer wins in business hours", "2024-03-11T13:30:00Z", "erin", "daytime"},
+		{"after the handoff after DST", "2024-03-11T22:00:00Z", "bob", "primary"},
+		{"override", "2024-03-13T15:00:00Z", "frank", ""},
+	}
+	for _, tt := range tests {
+		onCall, err := service.OnCallNow("PAYMENTS-PRIMARY", mustTime(t, tt.at))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got := onCall[0]; got.User != tt.user || got.Layer != tt.layer {
+			t.Errorf("%s: expected %q from layer %q, got %+v", tt.name, tt.user, tt.layer, got)
+		}
+	}
+
+	onCall, _ := service.OnCallNow("", mustTime(t, "2024-03-11T22:00:00Z"))
+	if start := onCall[0].ShiftStart; start == nil || !start.Equal(mustTime(t, "2024-03-11T13:00:00Z")) {
+		t.Errorf("expected bob's shift to start at 09:00 EDT, got %v", start)
+	}
+	if _, err := service.OnCallNow("search", time.Time{}); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("expected ErrScheduleNotFound, got %v", err)
+	}
+}
+
+func TestEscalation(t *testing.T) {
+	now := mustTime(t, "2024-01-01T10:00:00Z")
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(),
+		WithClock(func() time.Time { return now }))
+
+	if _, err := service.CreateSchedule(&models.Schedule{
+		Name:   "payments-primary",
+		Layers: []models.ScheduleLayer{{Users: []string{"alice", "bob"}, Rotation: models.RotationDaily, StartDate: "2024-01-01"}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.CreateEscalationPolicy(&models.EscalationPolicy{
+		Name: "payments",
+		Levels: []models.EscalationLevel{
+			{Targets: []models.EscalationTarget{{Type: models.EscalationTargetSchedule, Name: "payments-primary"}}, DelayMinutes: 10},
+			{Targets: []models.EscalationTarget{{Type: models.EscalationTargetUser, Name: "carol"}}, DelayMinutes: 15},
+		},
+		Repeat: 1,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.CreateService(&models.Service{Name: "checkout", Team: "payments", EscalationPolicy: "payments"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx", Service: "checkout"})
+	acked, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout slow", Description: "p99", Service: "checkout"})
+	if incident.AssignedTo != "alice" || incident.Escalation == nil || incident.Escalation.Level != 1 {
+		t.Fatalf("expected level 1 to page alice, got %q %+v", incident.AssignedTo, incident.Escalation)
+	}
+
+	steps := []struct {
+		after     time.Duration
+		escalated int
+		assigned  string
+		level     int
+		cycle     int
+	}{
+		{5 * time.Minute, 0, "alice", 1, 0},
+		{10 * time.Minute, 1, "carol", 2, 0},
+		{25 * time.Minute, 1, "alice", 1, 1},
+		{35 * time.Minute, 1, "carol", 2, 1},
+		{50 * time.Minute, 0, "carol", 2, 1},
+	}
+	for i, step := range steps {
+		now = mustTime(t, "2024-01-01T10:00:00Z").Add(step.after)
+		if i == 0 {
+			if _, err := service.AcknowledgeIncident(acked.ID, &models.AcknowledgeRequest{User: "bob"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if got := service.EvaluateEscalations(); got != step.escalated {
+			t.Errorf("after %s: expected %d escalations, got %d", step.after, step.escalated, got)
+		}
+		incident, _ = service.GetIncident(incident.ID)
+		state := incident.Escalation
+		if incident.AssignedTo != step.assigned || state.Level != step.level || state.Cycle != step.cycle {
+			t.Errorf("after %s: expected %s at level %d cycle %d, got %s at level %d cycle %d",
+				step.after, step.assigned, step.level, step.cycle, incident.AssignedTo, state.Level, state.Cycle)
+		}
+	}
+	if !incident.Escalation.Exhausted || len(incident.Escalation.History) != 4 {
+		t.Errorf("expected the policy to be exhausted after 4 pages, got %+v", incident.Escalation)
+	}
+
+	acked, _ = service.GetIncident(acked.ID)
+	if acked.AcknowledgedBy != "bob" || acked.Status != models.StatusInProgress || len(acked.Escalation.History) != 1 {
+		t.Errorf("expected the acknowledged incident to stop at level 1, got %+v", acked.Escalation)
+	}
+	timeline := strings.Join(buildTimeline(acked, nil, nil), "\n")
+	if !strings.Contains(timeline, "Paged alice (escalation level 1)") || !strings.Contains(timeline, "Acknowledged by bob") {
+		t.Errorf("expected pages and acknowledgement in the timeline, got:\n%s", timeline)
+	}
+
+	// Manual escalation pages the next level even after acknowledgement, but not past the policy
+	if escalated, err := service.EscalateIncident(acked.ID); err != nil || escalated.AssignedTo != "carol" || escalated.Escalation.Level != 2 {
+		t.Errorf("expected a manual escalation to page carol, got %+v (%v)", escalated, err)
+	}
+	if _, err := service.EscalateIncident(incident.ID); !errors.Is(err, ErrNotEscalatable) {
+		t.Errorf("expected ErrNotEscalatable for an exhausted policy, got %v", err)
+	}
+}
+
+func TestOnCallValidation(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	layer := models.ScheduleLayer{Users: []string{"alice"}, Rotation: models.RotationWeekly, StartDate: "2024-01-01"}
+
+	schedules := []models.Schedule{
+		{Name: "tz", TimeZone: "Mars/Olympus", Layers: []models.ScheduleLayer{layer}},
+		{Name: "empty"},
+		{Name: "custom", Layers: []models.ScheduleLayer{{Users: []string{"alice"}, Rotation: models.RotationCustom, StartDate: "2024-01-01"}}},
+		{Name: "handoff", Layers: []models.ScheduleLayer{{Users: []string{"alice"}, Rotation: models.RotationDaily, StartDate: "2024-01-01", HandoffTime: "9am"}}},
+	}
+	for _, schedule := range schedules {
+		schedule := schedule
+		if _, err := service.CreateSchedule(&schedule); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("%s: expected ErrInvalidSchedule, got %v", schedule.Name, err)
+		}
+	}
+
+	target := []models.EscalationTarget{{Type: models.EscalationTargetUser, Name: "alice"}}
+	policies := []models.EscalationPolicy{
+		{Name: "no-levels"},
+		{Name: "no-delay", Levels: []models.EscalationLevel{{Targets: target}}},
+		{Name: "bad-target", Levels: []models.EscalationLevel{{Targets: []models.EscalationTarget{{Type: "team", Name: "x"}}, DelayMinutes: 5}}},
+		{Name: "repeat", Levels: []models.EscalationLevel{{Targets: target, DelayMinutes: 5}}, Repeat: 99},
+	}
+	for _, policy := range policies {
+		policy := policy
+		if _, err := service.CreateEscalationPolicy(&policy); !errors.Is(err, ErrInvalidEscalationPolicy) {
+			t.Errorf("%s: expected ErrInvalidEscalationPolicy, got %v", policy.Name, err)
+		}
+	}
+
+	if _, err := service.AcknowledgeIncident("INC-missing", &models.AcknowledgeRequest{User: "alice"}); !errors.Is(err, ErrIncidentNotFound) {
+		t.Errorf("expected ErrIncidentNotFound, got %v", err)
+	}
+	unpaged, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "db-1"})
+	if _, err := service.EscalateIncident(unpaged.ID); !errors.Is(err, ErrNotEscalatable) {
+		t.Errorf("expected ErrNotEscalatable without a policy, got %v", err)
+	}
+}
+
+func TestLoadOnCallConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "oncall.json")
+	os.WriteFile(path, []byte(`{
+		"schedules": [{"name": "primary", "layers": [{"users": ["alice"], "rotation": "weekly", "start_date": "2024-01-01"}],
+			"overrides": [{"user": "bob", "start": "2024-01-02T00:00:00Z", "end": "2024-01-03T00:00:00Z"}]}],
+		"escalation_policies": [{"name": "default", "levels": [{"targets": [{"type": "schedule", "name": "primary"}], "delay_minutes": 5}]}]
+	}`), 0o644)
+
+	cfg, err := LoadOnCallConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := mustTime(t, "2024-01-02T12:00:00Z")
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(),
+		WithOnCallConfig(cfg), WithDefaultEscalationPolicy("default"), WithClock(func() time.Time { return now }))
+
+	schedule, err := service.GetSchedule("primary")
+	if err != nil || schedule.Overrides[0].ID == "" || schedule.Layers[0].HandoffTime != "00:00" {
+		t.Errorf("expected the override to get an ID and the handoff to default, got %+v (%v)", schedule, err)
+	}
+
+	// Without a service, the default policy pages whoever is on call
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "db-1"})
+	if incident.AssignedTo != "bob" || incident.Escalation == nil || incident.Escalation.Policy != "default" {
+		t.Errorf("expected the default policy to page bob, got %q %+v", incident.AssignedTo, incident.Escalation)
+	}
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ErrRCANotFound is returned when an incident has no RCA document yet
+var ErrRCANotFound = errors.New("RCA document not found")
+
+// ErrRCALocked is returned when an approved or published RCA is modified
+var ErrRCALocked = errors.New("RCA document is locked")
+
+// ErrInvalidTransition is returned for review state changes the workflow does not allow
+var ErrInvalidTransition = errors.New("invalid RCA status transition")
+
+// ErrInvalidRCARequest is returned when an RCA edit or review request is incomplete
+var ErrInvalidRCARequest = errors.New("invalid RCA request")
+
+// rcaTransitions lists the review states reachable from each state
+var rcaTransitions = map[models.RCAStatus][]models.RCAStatus{
+	models.RCAStatusDraft:    {models.RCAStatusInReview},
+	models.RCAStatusInReview: {models.RCAStatusDraft, models.RCAStatusApproved},
+	models.RCAStatusApproved: {models.RCAStatusDraft, models.RCAStatusPublished},
+}
+
+// EditRCA applies a human edit as a new RCA revision, attributing the changed sections to the author.
+// An incident without an RCA starts from an empty document. Editing during review discards approvals.
+func (s *IncidentService) EditRCA(id string, req *models.RCAPatchRequest) (*models.Incident, error) {
+	if strings.TrimSpace(req.Author) == "" {
+		return nil, fmt.Errorf("%w: author is required", ErrInvalidRCARequest)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if err := rcaEditable(incident); err != nil {
+		return nil, err
+	}
+
+	doc := &models.RCADocument{}
+	if incident.RCADocument != nil {
+		*doc = *incident.RCADocument
+	}
+	now := s.now()
+	attribution := make(map[string]models.SectionAttribution, len(models.RCASections))
+	for section, a := range doc.Attribution {
+		attribution[section] = a
+	}
+
+	var changed []string
+	if req.Timeline != nil {
+		doc.Timeline = req.Timeline
+		changed = append(changed, models.RCASectionTimeline)
+	}
+	if req.RootCause != nil {
+		doc.RootCause = *req.RootCause
+		changed = append(changed, models.RCASectionRootCause)
+	}
+	if req.Impact != nil {
+		doc.Impact = *req.Impact
+		changed = append(changed, models.RCASectionImpact)
+	}
+	if req.ImmediateResolution != nil {
+		doc.ImmediateResolution = *req.ImmediateResolution
+		changed = append(changed, models.RCASectionImmediateResolution)
+	}
+	if req.PreventiveMeasures != nil {
+		doc.PreventiveMeasures = req.PreventiveMeasures
+		changed = append(changed, models.RCASectionPreventiveMeasures)
+	}
+	if req.LessonsLearned != nil {
+		doc.LessonsLearned = req.LessonsLearned
+		changed = append(changed, models.RCASectionLessonsLearned)
+	}
+	if len(changed) == 0 {
+		return nil, fmt.Errorf("%w: no sections to update", ErrInvalidRCARequest)
+	}
+
+	for _, section := range changed {
+		attribution[section] = models.SectionAttribution{
+			Source:    models.ContentSourceHuman,
+			Author:    req.Author,
+			UpdatedAt: now,
+		}
+	}
+	doc.Attribution = attribution
+	doc.EditedBy = req.Author
+	doc.GeneratedAt = now
+
+	s.recordRCA(incident, doc)
+	incident.UpdatedAt = now
+
+	s.logger.Info("RCA edited", zap.String("id", id), zap.String("author", req.Author), zap.Strings("sections", changed))
+	return snapshotIncident(incident), nil
+}
+
+// GetRCAReview returns the review state of an incident's RCA
+func (s *IncidentService) GetRCAReview(id string) (*models.RCAReview, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if incident.RCAReview == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRCANotFound, id)
+	}
+	return copyRCAReview(incident.RCAReview), nil
+}
+
+// SetRCAReviewers names the people who must approve the RCA before it can be published
+func (s *IncidentService) SetRCAReviewers(id string, req *models.RCAReviewersRequest) (*models.RCAReview, error) {
+	var reviewers []string
+	for _, r := range req.Reviewers {
+		if r = strings.TrimSpace(r); r != "" {
+			reviewers = appendUnique(reviewers, r)
+		}
+	}
+	if len(reviewers) == 0 {
+		return nil, fmt.Errorf("%w: at least one reviewer is required", ErrInvalidRCARequest)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, err := s.reviewableRCA(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := rcaEditable(incident); err != nil {
+		return nil, err
+	}
+
+	review := incident.RCAReview
+	review.Reviewers = reviewers
+	// Approvals from people who are no longer reviewers no longer count
+	kept := []models.RCAApproval{}
+	for _, a := range review.Approvals {
+		if containsString(reviewers, a.Reviewer) {
+			kept = append(kept, a)
+		}
+	}
+	review.Approvals = kept
+	review.UpdatedAt = s.now()
+	return copyRCAReview(review), nil
+}
+
+// TransitionRCA moves the RCA through draft, in_review, approved and published
+func (s *IncidentService) TransitionRCA(id string, req *models.RCAStatusRequest) (*models.RCAReview, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, err := s.reviewableRCA(id)
+	if err != nil {
+		return nil, err
+	}
+
+	review := incident.RCAReview
+	if !containsStatus(rcaTransitions[review.Status], req.Status) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, review.Status, req.Status)
+	}
+
+	now := s.now()
+	switch req.Status {
+	case models.RCAStatusInReview:
+		if len(review.Reviewers) == 0 {
+			return nil, fmt.Errorf("%w: name reviewers before requesting review", ErrInvalidTransition)
+		}
+	case models.RCAStatusApproved:
+		if pending := pendingApprovals(review, incident.RCADocument.Revision); len(pending) > 0 {
+			return nil, fmt.Errorf("%w: awaiting approval from %s", ErrInvalidTransition, strings.Join(pending, ", "))
+		}
+	case models.RCAStatusDraft:
+		review.Approvals = nil
+	case models.RCAStatusPublished:
+		review.PublishedRevision = incident.RCADocument.Revision
+		review.PublishedBy = req.Actor
+		review.PublishedAt = &now
+	}
+
+	s.logger.Info("RCA status changed",
+		zap.String("id", id),
+		zap.String("from", string(review.Status)),
+		zap.String("to", string(req.Status)),
+		zap.String("actor", req.Actor),
+	)
+	review.Status = req.Status
+	review.UpdatedAt = now
+	return copyRCAReview(review), nil
+}
+
+// ApproveRCA records a named reviewer's approval of the current revision.
+// The RCA becomes approved once every reviewer has approved it.
+func (s *IncidentService) ApproveRCA(id string, req *models.RCAApprovalRequest) (*models.RCAReview, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, err := s.reviewableRCA(id)
+	if err != nil {
+		return nil, err
+	}
+
+	review := incident.RCAReview
+	if review.Status != models.RCAStatusInReview {
+		return nil, fmt.Errorf("%w: RCA is %s, not in_review", ErrInvalidTransition, review.Status)
+	}
+	if !containsString(review.Reviewers, req.Reviewer) {
+		return nil, fmt.Errorf("%w: %q is not a reviewer", ErrInvalidRCARequest, req.Reviewer)
+	}
+
+	now := s.now()
+	revision := incident.RCADocument.Revision
+	approvals := make([]models.RCAApproval, 0, len(review.Approvals)+1)
+	for _, a := range review.Approvals {
+		if a.Reviewer != req.Reviewer {
+			approvals = append(approvals, a)
+		}
+	}
+	review.Approvals = append(approvals, models.RCAApproval{
+		Reviewer:   req.Reviewer,
+		Revision:   revision,
+		Comment:    req.Comment,
+		ApprovedAt: now,
+	})
+
+	if len(pendingApprovals(review, revision)) == 0 {
+		review.Status = models.RCAStatusApproved
+	}
+	review.UpdatedAt = now
+
+	s.logger.Info("RCA approved", zap.String("id", id), zap.String("reviewer", req.Reviewer), zap.Int("revision", revision))
+	return copyRCAReview(review), nil
+}
+
+// CommentOnRCA adds a review comment anchored to a section of the current revision
+func (s *IncidentService) CommentOnRCA(id string, req *models.RCACommentRequest) (*models.RCAComment, error) {
+	if !containsString(models.RCASections, req.Section) {
+		return nil, fmt.Errorf("%w: unknown section %q", ErrInvalidRCARequest, req.Section)
+	}
+	if strings.TrimSpace(req.Author) == "" || strings.TrimSpace(req.Body) == "" {
+		return nil, fmt.Errorf("%w: author and body are required", ErrInvalidRCARequest)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, err := s.reviewableRCA(id)
+	if err != nil {
+		return nil, err
+	}
+
+	review := incident.RCAReview
+	comment := models.RCAComment{
+		ID:        fmt.Sprintf("c-%d", len(review.Comments)+1),
+		Section:   req.Section,
+		Author:    req.Author,
+		Body:      req.Body,
+		Revision:  incident.RCADocument.Revision,
+		CreatedAt: s.now(),
+	}
+	review.Comments = append(review.Comments, comment)
+	review.UpdatedAt = comment.CreatedAt
+	return &comment, nil
+}
+
+// reviewableRCA returns an incident that has an RCA under review; callers must hold s.store.mu
+func (s *IncidentService) reviewableRCA(id string) (*models.Incident, error) {
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if incident.RCADocument == nil || incident.RCAReview == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRCANotFound, id)
+	}
+	return incident, nil
+}
+
+// rcaEditable reports ErrRCALocked once the RCA has been approved or published
+func rcaEditable(incident *models.Incident) error {
+	if incident.RCAReview == nil {
+		return nil
+	}
+	switch incident.RCAReview.Status {
+	case models.RCAStatusApproved, models.RCAStatusPublished:
+		return fmt.Errorf("%w: %s is %s", ErrRCALocked, incident.ID, incident.RCAReview.Status)
+	}
+	return nil
+}
+
+// copyRCAReview returns a copy that shares nothing with the stored review, so it can be read
+// after s.store.mu is released
+func copyRCAReview(review *models.RCAReview) *models.RCAReview {
+	c := *review
+	c.Reviewers = append(make([]string, 0, len(review.Reviewers)), review.Reviewers...)
+	c.Approvals = append(make([]models.RCAApproval, 0, len(review.Approvals)), review.Approvals...)
+	c.Comments = append(make([]models.RCAComment, 0, len(review.Comments)), review.Comments...)
+	if review.PublishedAt != nil {
+		publishedAt := *review.PublishedAt
+		c.PublishedAt = &publishedAt
+	}
+	return &c
+}
+
+// pendingApprovals returns the reviewers who have not approved the given revision
+func pendingApprovals(review *models.RCAReview, revision int) []string {
+	var pending []string
+	for _, reviewer := range review.Reviewers {
+		approved := false
+		for _, a := range review.Approvals {
+			if a.Reviewer == reviewer && a.Revision == revision {
+				approved = true
+				break
+			}
+		}
+		if !approved {
+			pending = append(pending, reviewer)
+		}
+	}
+	return pending
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStatus(list []models.RCAStatus, v models.RCAStatus) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
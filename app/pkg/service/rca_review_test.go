@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestRCAReviewWorkflow(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	service.GenerateRCA(created.ID)
+
+	rootCause := "Connection pool sized for the old replica count"
+	incident, err := service.EditRCA(created.ID, &models.RCAPatchRequest{
+		Author:             "ic@company.com",
+		RootCause:          &rootCause,
+		PreventiveMeasures: []string{"Alert on pool saturation"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	doc := incident.RCADocument
+	if doc.Revision != 2 || doc.RootCause != rootCause || doc.Impact != "Impact details" {
+		t.Errorf("expected edit on top of the AI revision, got %+v", doc)
+	}
+	if a := doc.Attribution[models.RCASectionRootCause]; a.Source != models.ContentSourceHuman || a.Author != "ic@company.com" {
+		t.Errorf("expected root cause attributed to the editor, got %+v", a)
+	}
+	if a := doc.Attribution[models.RCASectionImpact]; a.Source != models.ContentSourceAI {
+		t.Errorf("expected impact to stay attributed to AI, got %+v", a)
+	}
+
+	if _, err := service.TransitionRCA(created.ID, &models.RCAStatusRequest{Status: models.RCAStatusInReview}); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected review without reviewers to fail, got %v", err)
+	}
+	service.SetRCAReviewers(created.ID, &models.RCAReviewersRequest{Reviewers: []string{"alice", "bob"}})
+	if _, err := service.TransitionRCA(created.ID, &models.RCAStatusRequest{Status: models.RCAStatusInReview}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	comment, err := service.CommentOnRCA(created.ID, &models.RCACommentRequest{Section: models.RCASectionImpact, Author: "alice", Body: "Quantify affected users"})
+	if err != nil || comment.Revision != 2 {
+		t.Fatalf("expected comment on revision 2, got %+v, %v", comment, err)
+	}
+	if _, err := service.CommentOnRCA(created.ID, &models.RCACommentRequest{Section: "appendix", Author: "alice", Body: "x"}); !errors.Is(err, ErrInvalidRCARequest) {
+		t.Errorf("expected unknown section to fail, got %v", err)
+	}
+
+	service.ApproveRCA(created.ID, &models.RCAApprovalRequest{Reviewer: "alice"})
+
+	// An edit during review invalidates approvals given so far
+	impact := "1,200 checkouts failed"
+	service.EditRCA(created.ID, &models.RCAPatchRequest{Author: "ic@company.com", Impact: &impact})
+	review, _ := service.ApproveRCA(created.ID, &models.RCAApprovalRequest{Reviewer: "bob"})
+	if review.Status != models.RCAStatusInReview {
+		t.Errorf("expected alice's stale approval not to count, got status %s", review.Status)
+	}
+	if _, err := service.ApproveRCA(created.ID, &models.RCAApprovalRequest{Reviewer: "mallory"}); !errors.Is(err, ErrInvalidRCARequest) {
+		t.Errorf("expected approval from a non-reviewer to fail, got %v", err)
+	}
+	review, _ = service.ApproveRCA(created.ID, &models.RCAApprovalRequest{Reviewer: "alice"})
+	if review.Status != models.RCAStatusApproved {
+		t.Fatalf("expected approved once all reviewers approved, got %s", review.Status)
+	}
+
+	if _, err := service.EditRCA(created.ID, &models.RCAPatchRequest{Author: "ic@company.com", Impact: &impact}); !errors.Is(err, ErrRCALocked) {
+		t.Errorf("expected approved RCA to be locked, got %v", err)
+	}
+	if _, err := service.GenerateRCA(created.ID); !errors.Is(err, ErrRCALocked) {
+		t.Errorf("expected regeneration of an approved RCA to fail, got %v", err)
+	}
+
+	review, err = service.TransitionRCA(created.ID, &models.RCAStatusRequest{Status: models.RCAStatusPublished, Actor: "ic@company.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if review.PublishedRevision != 3 || review.PublishedAt == nil {
+		t.Errorf("expected revision 3 published, got %+v", review)
+	}
+	if _, err := service.TransitionRCA(created.ID, &models.RCAStatusRequest{Status: models.RCAStatusDraft}); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected published RCA to be final, got %v", err)
+	}
+}
+
+func TestRCAReviewCopies(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithClock(func() time.Time { return now }))
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+	service.GenerateRCA(created.ID)
+	service.SetRCAReviewers(created.ID, &models.RCAReviewersRequest{Reviewers: []string{"alice", "bob", "carol"}})
+	service.TransitionRCA(created.ID, &models.RCAStatusRequest{Status: models.RCAStatusInReview})
+
+	first, _ := service.ApproveRCA(created.ID, &models.RCAApprovalRequest{Reviewer: "alice"})
+	service.ApproveRCA(created.ID, &models.RCAApprovalRequest{Reviewer: "bob"})
+	snapshot, _ := service.GetIncident(created.ID)
+
+	// Dropping alice filters the approvals; copies handed out earlier must not change
+	review, err := service.SetRCAReviewers(created.ID, &models.RCAReviewersRequest{Reviewers: []string{"bob", "carol"}})
+	if err != nil || len(review.Approvals) != 1 || review.Approvals[0].Reviewer != "bob" {
+		t.Fatalf("expected only bob's approval to remain, got %+v (%v)", review, err)
+	}
+	if approvals := snapshot.RCAReview.Approvals; len(approvals) != 2 || approvals[0].Reviewer != "alice" || approvals[1].Reviewer != "bob" {
+		t.Errorf("expected the incident snapshot to keep both approvals, got %+v", approvals)
+	}
+	if len(first.Approvals) != 1 || first.Approvals[0].Reviewer != "alice" || len(first.Reviewers) != 3 {
+		t.Errorf("expected the first approval's review to be unchanged, got %+v", first)
+	}
+	if !review.UpdatedAt.Equal(now) || !first.Approvals[0].ApprovedAt.Equal(now) {
+		t.Errorf("expected review timestamps from the clock, got %v and %v", review.UpdatedAt, first.Approvals[0].ApprovedAt)
+	}
+}
+
+func TestEditRCAWithoutAI(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+
+	if _, err := service.EditRCA(created.ID, &models.RCAPatchRequest{LessonsLearned: []string{"x"}}); !errors.Is(err, ErrInvalidRCARequest) {
+		t.Errorf("expected missing author to fail, got %v", err)
+	}
+
+	incident, err := service.EditRCA(created.ID, &models.RCAPatchRequest{Author: "ic", LessonsLearned: []string{"Page the database team sooner"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if incident.RCADocument.Revision != 1 || incident.RCAReview.Status != models.RCAStatusDraft {
+		t.Errorf("expected a first human revision in draft, got revision %d", incident.RCADocument.Revision)
+	}
+}
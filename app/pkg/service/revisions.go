@@ -0,0 +1,271 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ErrRevisionNotFound is returned when an analysis or RCA revision does not exist
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// WithPromptVersion selects the analysis prompt version used by AnalyzeIncident
+func WithPromptVersion(version string) ServiceOption {
+	return func(s *IncidentService) {
+		s.promptVersion = version
+	}
+}
+
+func (s *IncidentService) analysisPromptVersion() string {
+	if s.promptVersion == "" {
+		return ai.DefaultPromptVersion
+	}
+	return s.promptVersion
+}
+
+// recordAnalysis appends analysis as the incident's newest revision and makes it current.
+// Callers must hold s.store.mu.
+func (s *IncidentService) recordAnalysis(incident *models.Incident, analysis *models.AIAnalysis) {
+	analysis.Revision = len(s.store.analyses[incident.ID]) + 1
+	s.store.analyses[incident.ID] = append(s.store.analyses[incident.ID], analysis)
+	incident.AIAnalysis = analysis
+}
+
+// recordRCA appends doc as the incident's newest RCA revision and makes it current,
+// starting the review workflow on the first revision. Callers must hold s.store.mu.
+func (s *IncidentService) recordRCA(incident *models.Incident, doc *models.RCADocument) {
+	doc.Revision = len(s.store.rcaRevisions[incident.ID]) + 1
+	s.store.rcaRevisions[incident.ID] = append(s.store.rcaRevisions[incident.ID], doc)
+	s.setCurrentRCA(incident, doc)
+}
+
+// setCurrentRCA makes doc the current RCA; approvals of other revisions no longer apply.
+// Callers must hold s.store.mu.
+func (s *IncidentService) setCurrentRCA(incident *models.Incident, doc *models.RCADocument) {
+	incident.RCADocument = doc
+	if incident.RCAReview == nil {
+		incident.RCAReview = &models.RCAReview{
+			Status:    models.RCAStatusDraft,
+			Reviewers: []string{},
+			Approvals: []models.RCAApproval{},
+			Comments:  []models.RCAComment{},
+		}
+	}
+	incident.RCAReview.Approvals = []models.RCAApproval{}
+	incident.RCAReview.UpdatedAt = time.Now()
+}
+
+// ListAnalyses returns every analysis generated for an incident, oldest first
+func (s *IncidentService) ListAnalyses(id string) (*models.AnalysisHistory, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+
+	history := &models.AnalysisHistory{
+		IncidentID: id,
+		Revisions:  make([]models.AIAnalysis, 0, len(s.store.analyses[id])),
+	}
+	for _, a := range s.store.analyses[id] {
+		history.Revisions = append(history.Revisions, *a)
+	}
+	if incident.AIAnalysis != nil {
+		history.Current = incident.AIAnalysis.Revision
+	}
+	return history, nil
+}
+
+// ListRCARevisions returns every RCA document generated for an incident, oldest first
+func (s *IncidentService) ListRCARevisions(id string) (*models.RCAHistory, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+
+	history := &models.RCAHistory{
+		IncidentID: id,
+		Revisions:  make([]models.RCADocument, 0, len(s.store.rcaRevisions[id])),
+	}
+	for _, d := range s.store.rcaRevisions[id] {
+		history.Revisions = append(history.Revisions, *d)
+	}
+	if incident.RCADocument != nil {
+		history.Current = incident.RCADocument.Revision
+	}
+	return history, nil
+}
+
+// PinAnalysis makes an earlier analysis revision the incident's current analysis
+func (s *IncidentService) PinAnalysis(id string, revision int) (*models.Incident, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	analysis, err := s.analysisRevision(id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	incident.AIAnalysis = analysis
+	s.logger.Info("analysis revision pinned", zap.String("id", id), zap.Int("revision", revision))
+	return snapshotIncident(incident), nil
+}
+
+// PinRCARevision makes an earlier RCA revision the incident's current RCA document
+func (s *IncidentService) PinRCARevision(id string, revision int) (*models.Incident, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if err := rcaEditable(incident); err != nil {
+		return nil, err
+	}
+	doc, err := s.rcaRevision(id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setCurrentRCA(incident, doc)
+	s.logger.Info("RCA revision pinned", zap.String("id", id), zap.Int("revision", revision))
+	return snapshotIncident(incident), nil
+}
+
+// DiffAnalyses compares two analysis revisions field by field
+func (s *IncidentService) DiffAnalyses(id string, from, to int) (*models.RevisionDiff, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	if _, ok := s.store.incidents[id]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	a, err := s.analysisRevision(id, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.analysisRevision(id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.RevisionDiff{IncidentID: id, From: from, To: to, Changes: []models.FieldChange{}}
+	diff.Changes = diffText(diff.Changes, "summary", a.Summary, b.Summary)
+	diff.Changes = diffList(diff.Changes, "findings", a.Findings, b.Findings)
+	diff.Changes = diffList(diff.Changes, "root_causes", a.RootCauses, b.RootCauses)
+	diff.Changes = diffList(diff.Changes, "recommended_actions", a.RecommendedActions, b.RecommendedActions)
+	diff.Changes = diffText(diff.Changes, "severity_suggestion", string(a.SeveritySuggestion), string(b.SeveritySuggestion))
+	diff.Changes = diffText(diff.Changes, "model", a.Model, b.Model)
+	diff.Changes = diffText(diff.Changes, "provider", a.Provider, b.Provider)
+	diff.Changes = diffText(diff.Changes, "prompt_version", a.PromptVersion, b.PromptVersion)
+	diff.Changes = diffText(diff.Changes, "inputs_hash", a.InputsHash, b.InputsHash)
+	return diff, nil
+}
+
+// DiffRCARevisions compares two RCA revisions field by field
+func (s *IncidentService) DiffRCARevisions(id string, from, to int) (*models.RevisionDiff, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	if _, ok := s.store.incidents[id]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	a, err := s.rcaRevision(id, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.rcaRevision(id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.RevisionDiff{IncidentID: id, From: from, To: to, Changes: []models.FieldChange{}}
+	diff.Changes = diffList(diff.Changes, "timeline", a.Timeline, b.Timeline)
+	diff.Changes = diffText(diff.Changes, "root_cause", a.RootCause, b.RootCause)
+	diff.Changes = diffText(diff.Changes, "impact", a.Impact, b.Impact)
+	diff.Changes = diffText(diff.Changes, "immediate_resolution", a.ImmediateResolution, b.ImmediateResolution)
+	diff.Changes = diffList(diff.Changes, "preventive_measures", a.PreventiveMeasures, b.PreventiveMeasures)
+	diff.Changes = diffList(diff.Changes, "lessons_learned", a.LessonsLearned, b.LessonsLearned)
+	diff.Changes = diffText(diff.Changes, "model", a.Model, b.Model)
+	diff.Changes = diffText(diff.Changes, "provider", a.Provider, b.Provider)
+	diff.Changes = diffText(diff.Changes, "prompt_version", a.PromptVersion, b.PromptVersion)
+	diff.Changes = diffText(diff.Changes, "inputs_hash", a.InputsHash, b.InputsHash)
+	return diff, nil
+}
+
+// analysisRevision looks up a revision by number; callers must hold s.store.mu
+func (s *IncidentService) analysisRevision(id string, revision int) (*models.AIAnalysis, error) {
+	revisions := s.store.analyses[id]
+	if revision < 1 || revision > len(revisions) {
+		return nil, fmt.Errorf("%w: analysis %d of %s", ErrRevisionNotFound, revision, id)
+	}
+	return revisions[revision-1], nil
+}
+
+// rcaRevision looks up a revision by number; callers must hold s.store.mu
+func (s *IncidentService) rcaRevision(id string, revision int) (*models.RCADocument, error) {
+	revisions := s.store.rcaRevisions[id]
+	if revision < 1 || revision > len(revisions) {
+		return nil, fmt.Errorf("%w: RCA %d of %s", ErrRevisionNotFound, revision, id)
+	}
+	return revisions[revision-1], nil
+}
+
+// inputsHash fingerprints the request sent to the model so revisions built from the same inputs can be recognised
+func inputsHash(req interface{}) string {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func diffText(changes []models.FieldChange, field, from, to string) []models.FieldChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, models.FieldChange{Field: field, From: from, To: to})
+}
+
+func diffList(changes []models.FieldChange, field string, from, to []string) []models.FieldChange {
+	added := missingFrom(to, from)
+	removed := missingFrom(from, to)
+	if len(added) == 0 && len(removed) == 0 {
+		return changes
+	}
+	return append(changes, models.FieldChange{Field: field, Added: added, Removed: removed})
+}
+
+// missingFrom returns the items of list that do not appear in other
+func missingFrom(list, other []string) []string {
+	present := make(map[string]bool, len(other))
+	for _, item := range other {
+		present[item] = true
+	}
+
+	var missing []string
+	for _, item := range list {
+		if !present[item] {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai/aifake"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestAnalysisRevisions(t *testing.T) {
+	fake := aifake.NewServer()
+	defer fake.Close()
+	client, err := ai.NewClient(fake.Config(ai.ProviderOpenAI))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service := NewIncidentService(NewIncidentStore(), client, zap.NewNop(), WithPromptVersion("v2"))
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout errors",
+		Description: "5xx spike",
+	})
+
+	fake.Enqueue(aifake.OpenAIText(`{"summary": "Checkout failing", "findings": ["503s"], "root_causes": ["bad deploy"], "suggested_severity": "high"}`))
+	service.AnalyzeIncident(created.ID)
+
+	service.UpdateIncident(created.ID, &models.UpdateIncidentRequest{Logs: []string{"ERROR pool exhausted"}})
+	fake.Enqueue(aifake.OpenAIText(`{"summary": "Checkout failing", "findings": ["503s", "pool exhausted"], "root_causes": ["connection pool exhausted"], "suggested_severity": "critical"}`))
+	incident, _ := service.AnalyzeIncident(created.ID)
+
+	if incident.AIAnalysis.Revision != 2 || incident.AIAnalysis.PromptVersion != "v2" {
+		t.Errorf("expected revision 2 with prompt v2 to be current, got %d %q", incident.AIAnalysis.Revision, incident.AIAnalysis.PromptVersion)
+	}
+
+	history, err := service.ListAnalyses(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history.Revisions) != 2 || history.Current != 2 {
+		t.Fatalf("expected 2 revisions with 2 current, got %d with %d current", len(history.Revisions), history.Current)
+	}
+	if history.Revisions[0].InputsHash == history.Revisions[1].InputsHash {
+		t.Error("expected different inputs hashes after logs changed")
+	}
+
+	diff, err := service.DiffAnalyses(created.ID, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes := make(map[string]models.FieldChange)
+	for _, c := range diff.Changes {
+		changes[c.Field] = c
+	}
+	if _, ok := changes["summary"]; ok {
+		t.Error("unchanged summary should not be reported")
+	}
+	if c := changes["findings"]; len(c.Added) != 1 || c.Added[0] != "pool exhausted" || len(c.Removed) != 0 {
+		t.Errorf("unexpected findings change %+v", c)
+	}
+	if c := changes["severity_suggestion"]; c.From != "high" || c.To != "critical" {
+		t.Errorf("unexpected severity change %+v", c)
+	}
+
+	pinned, err := service.PinAnalysis(created.ID, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pinned.AIAnalysis.Revision != 1 || pinned.AIAnalysis.Summary != "Checkout failing" {
+		t.Errorf("expected revision 1 to be pinned, got %d", pinned.AIAnalysis.Revision)
+	}
+
+	if _, err := service.PinAnalysis(created.ID, 3); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
+
+func TestRCARevisions(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Test",
+		Description: "Test",
+	})
+	service.GenerateRCA(created.ID)
+	service.GenerateRCA(created.ID)
+
+	history, err := service.ListRCARevisions(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history.Revisions) != 2 || history.Current != 2 {
+		t.Fatalf("expected 2 revisions with 2 current, got %d with %d current", len(history.Revisions), history.Current)
+	}
+	if history.Revisions[0].PromptVersion != ai.RCAPromptVersion {
+		t.Errorf("expected prompt version %q, got %q", ai.RCAPromptVersion, history.Revisions[0].PromptVersion)
+	}
+
+	diff, _ := service.DiffRCARevisions(created.ID, 1, 2)
+	if len(diff.Changes) != 0 {
+		t.Errorf("expected identical revisions, got %+v", diff.Changes)
+	}
+
+	if _, err := service.PinRCARevision(created.ID, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	incident, _ := service.GetIncident(created.ID)
+	if incident.RCADocument.Revision != 1 {
+		t.Errorf("expected revision 1 to be current, got %d", incident.RCADocument.Revision)
+	}
+}
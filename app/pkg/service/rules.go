@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/rules"
+)
+
+// WithRulesEngine replaces the default severity rules
+func WithRulesEngine(engine *rules.Engine) ServiceOption {
+	return func(s *IncidentService) {
+		s.rules = engine
+	}
+}
+
+// SeverityRules returns the configured rules in evaluation order
+func (s *IncidentService) SeverityRules() []rules.Rule {
+	return s.rules.Rules()
+}
+
+// TestRules evaluates the severity rules against a sample incident without storing it
+func (s *IncidentService) TestRules(req *models.CreateIncidentRequest) rules.Result {
+	return s.classifySeverity(&models.Incident{
+		Title:       req.Title,
+		Description: req.Description,
+		Source:      req.Source,
+		Tags:        req.Tags,
+		Metadata:    req.Metadata,
+		Logs:        req.Logs,
+	})
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ErrNoSeveritySuggestion is returned when feedback is given on an incident without a completed AI suggestion
+var ErrNoSeveritySuggestion = errors.New("no AI severity suggestion to review")
+
+// ErrInvalidSeverity is returned for severities outside critical, high, medium and low
+var ErrInvalidSeverity = errors.New("invalid severity")
+
+// WithAISeverity enables asynchronous AI severity classification at creation time.
+// The rules engine severity stays in effect if the AI call fails or exceeds timeout.
+func WithAISeverity(timeout time.Duration) ServiceOption {
+	return func(s *IncidentService) {
+		s.aiSeverityTimeout = timeout
+	}
+}
+
+// classifyWithAI asks the AI client for a severity and applies it unless someone has set one since
+func (s *IncidentService) classifyWithAI(id string, req ai.SeverityRequest) {
+	defer s.classifications.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.aiSeverityTimeout)
+	defer cancel()
+
+	resp, err := s.aiClient.ClassifySeverity(ctx, req)
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok || incident.SeverityClassification == nil {
+		return
+	}
+
+	now := s.now()
+	classification := *incident.SeverityClassification
+	classification.CompletedAt = &now
+	classification.Model = s.aiClient.Model()
+	classification.Provider = string(s.aiClient.Provider())
+
+	if err != nil {
+		classification.Status = models.ClassificationFailed
+		classification.Error = err.Error()
+		incident.SeverityClassification = &classification
+		s.logger.Warn("AI severity classification failed, keeping rules severity", zap.String("id", id), zap.Error(err))
+		return
+	}
+
+	classification.Status = models.ClassificationCompleted
+	classification.Suggested = models.Severity(resp.Severity)
+	classification.Confidence = resp.Confidence
+	classification.Reasoning = resp.Reasoning
+
+	// Never override a severity chosen by a person
+	previous := incident.Severity
+	if incident.SeveritySource == models.SeveritySourceRule {
+		incident.Severity, incident.SeverityAdjustment = serviceSeverity(s.store.services[catalogKey(incident.Service)], classification.Suggested)
+		incident.SeveritySource = models.SeveritySourceAI
+		classification.Applied = true
+	}
+	incident.SeverityClassification = &classification
+	incident.UpdatedAt = now
+	if incident.Severity != previous {
+		// The new severity's SLA policy applies straight away
+		s.refreshSLA(incident, now)
+		s.publish(models.EventIncidentSeverityChanged, incident, previous)
+	}
+
+	s.logger.Info("incident severity classified",
+		zap.String("id", id),
+		zap.String("suggested", resp.Severity),
+		zap.Bool("applied", classification.Applied),
+	)
+}
+
+// WaitForClassifications blocks until background severity classifications have finished
+func (s *IncidentService) WaitForClassifications() {
+	s.classifications.Wait()
+}
+
+// SubmitSeverityFeedback records a responder accepting or rejecting the AI severity suggestion.
+// Accepting applies the suggestion; rejecting applies the given severity, or reverts to the rules severity.
+func (s *IncidentService) SubmitSeverityFeedback(id string, req *models.SeverityFeedbackRequest) (*models.Incident, error) {
+	if req.Severity != nil && !req.Severity.Valid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSeverity, *req.Severity)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	if incident.SeverityClassification == nil || incident.SeverityClassification.Status != models.ClassificationCompleted {
+		return nil, fmt.Errorf("%w: %s", ErrNoSeveritySuggestion, id)
+	}
+
+	now := s.now()
+	classification := *incident.SeverityClassification
+	feedback := &models.SeverityFeedback{
+		Accepted:  req.Accepted,
+		Reviewer:  req.Reviewer,
+		Comment:   req.Comment,
+		CreatedAt: now,
+	}
+
+	// A responder's decision stands as given; only the reverted rules severity is adjusted for the service
+	previous := incident.Severity
+	incident.SeverityAdjustment = ""
+	switch {
+	case req.Accepted:
+		incident.Severity = classification.Suggested
+		incident.SeveritySource = models.SeveritySourceAI
+		classification.Applied = true
+	case req.Severity != nil:
+		incident.Severity = *req.Severity
+		incident.SeveritySource = models.SeveritySourceUser
+		classification.Applied = false
+	default:
+		incident.Severity, incident.SeverityAdjustment = serviceSeverity(s.store.services[catalogKey(incident.Service)], classification.Fallback)
+		incident.SeveritySource = models.SeveritySourceRule
+		classification.Applied = false
+	}
+	feedback.Corrected = incident.Severity
+	classification.Feedback = feedback
+
+	incident.SeverityClassification = &classification
+	incident.UpdatedAt = now
+	if incident.Severity != previous {
+		s.refreshSLA(incident, now)
+		s.publish(models.EventIncidentSeverityChanged, incident, previous)
+	}
+
+	s.logger.Info("severity feedback recorded", zap.String("id", id), zap.Bool("accepted", req.Accepted))
+	return snapshotIncident(incident), nil
+}
+
+// SeverityAccuracy reports how often responders accepted AI severity suggestions
+func (s *IncidentService) SeverityAccuracy() *models.SeverityAccuracyReport {
+	report := &models.SeverityAccuracyReport{
+		Confusion:   make(map[models.Severity]map[models.Severity]int),
+		GeneratedAt: time.Now(),
+	}
+
+	s.store.mu.RLock()
+	for _, incident := range s.store.incidents {
+		c := incident.SeverityClassification
+		if c == nil {
+			continue
+		}
+		switch c.Status {
+		case models.ClassificationFailed:
+			report.Failed++
+			continue
+		case models.ClassificationPending:
+			continue
+		}
+
+		report.Suggestions++
+		if c.Feedback == nil {
+			continue
+		}
+		report.Reviewed++
+		if c.Feedback.Accepted {
+			report.Accepted++
+		} else {
+			report.Rejected++
+		}
+		if report.Confusion[c.Suggested] == nil {
+			report.Confusion[c.Suggested] = make(map[models.Severity]int)
+		}
+		report.Confusion[c.Suggested][c.Feedback.Corrected]++
+	}
+	s.store.mu.RUnlock()
+
+	if report.Reviewed > 0 {
+		report.AcceptanceRate = float64(report.Accepted) / float64(report.Reviewed)
+	}
+	return report
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestAISeverityClassificationApplied(t *testing.T) {
+	mockAI := &MockAIClient{severity: "critical"}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop(), WithAISeverity(time.Second))
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout slow",
+		Description: "p99 latency doubled",
+	})
+	service.WaitForClassifications()
+
+	incident, _ := service.GetIncident(created.ID)
+	c := incident.SeverityClassification
+	if c == nil || c.Status != models.ClassificationCompleted {
+		t.Fatalf("expected completed classification, got %+v", c)
+	}
+	if incident.Severity != models.SeverityCritical || incident.SeveritySource != models.SeveritySourceAI {
+		t.Errorf("expected AI severity to apply, got %s from %s", incident.Severity, incident.SeveritySource)
+	}
+	if c.Fallback != models.SeverityMedium || !c.Applied {
+		t.Errorf("expected rules fallback 'medium' and applied suggestion, got %+v", c)
+	}
+}
+
+func TestAISeverityClassificationLeavesReturnedIncident(t *testing.T) {
+	mockAI := &MockAIClient{severity: "critical"}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop(), WithAISeverity(time.Second))
+
+	// Encoding the response while the classifier runs must not race with it
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout slow", Description: "p99 latency doubled"})
+	if _, err := json.Marshal(created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.WaitForClassifications()
+
+	if created.Severity != models.SeverityMedium || created.SeverityClassification.Status != models.ClassificationPending {
+		t.Errorf("expected the returned incident to keep the rules severity, got %s %+v", created.Severity, created.SeverityClassification)
+	}
+	if incident, _ := service.GetIncident(created.ID); incident.Severity != models.SeverityCritical {
+		t.Errorf("expected the stored incident to be classified, got %s", incident.Severity)
+	}
+}
+
+func TestAISeverityDoesNotOverrideUser(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{severity: "critical"}, zap.NewNop(), WithAISeverity(time.Second))
+
+	low := models.SeverityLow
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout slow",
+		Description: "Test",
+		Severity:    &low,
+	})
+	service.WaitForClassifications()
+
+	incident, _ := service.GetIncident(created.ID)
+	if incident.Severity != models.SeverityLow || incident.SeveritySource != models.SeveritySourceUser {
+		t.Errorf("expected user severity to stand, got %s from %s", incident.Severity, incident.SeveritySource)
+	}
+	if incident.SeverityClassification.Suggested != models.SeverityCritical || incident.SeverityClassification.Applied {
+		t.Errorf("expected suggestion recorded but not applied, got %+v", incident.SeverityClassification)
+	}
+}
+
+func TestAISeverityTimeoutFallsBackToRules(t *testing.T) {
+	mockAI := &MockAIClient{severityDelay: time.Second}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop(), WithAISeverity(20*time.Millisecond))
+
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout slow",
+		Description: "Test",
+	})
+	service.WaitForClassifications()
+
+	incident, _ := service.GetIncident(created.ID)
+	if incident.SeverityClassification.Status != models.ClassificationFailed {
+		t.Errorf("expected failed classification, got %s", incident.SeverityClassification.Status)
+	}
+	if incident.Severity != models.SeverityMedium || incident.SeveritySource != models.SeveritySourceRule {
+		t.Errorf("expected rules severity, got %s from %s", incident.Severity, incident.SeveritySource)
+	}
+
+	if _, err := service.SubmitSeverityFeedback(created.ID, &models.SeverityFeedbackRequest{Accepted: true}); !errors.Is(err, ErrNoSeveritySuggestion) {
+		t.Errorf("expected ErrNoSeveritySuggestion, got %v", err)
+	}
+	if report := service.SeverityAccuracy(); report.Failed != 1 || report.Suggestions != 0 {
+		t.Errorf("expected one failed classification, got %+v", report)
+	}
+}
+
+func TestSeverityFeedbackAndAccuracy(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{severity: "high"}, zap.NewNop(), WithAISeverity(time.Second))
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Test", Description: "Test"})
+		ids = append(ids, created.ID)
+	}
+	service.WaitForClassifications()
+
+	if _, err := service.SubmitSeverityFeedback(ids[0], &models.SeverityFeedbackRequest{Accepted: true, Reviewer: "alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	critical := models.SeverityCritical
+	incident, err := service.SubmitSeverityFeedback(ids[1], &models.SeverityFeedbackRequest{Severity: &critical, Comment: "customers affected"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if incident.Severity != models.SeverityCritical || incident.SeveritySource != models.SeveritySourceUser {
+		t.Errorf("expected rejected suggestion to apply the user severity, got %s from %s", incident.Severity, incident.SeveritySource)
+	}
+
+	incident, _ = service.SubmitSeverityFeedback(ids[2], &models.SeverityFeedbackRequest{})
+	if incident.Severity != models.SeverityLow || incident.SeveritySource != models.SeveritySourceRule {
+		t.Errorf("expected rejection without severity to revert to rules, got %s from %s", incident.Severity, incident.SeveritySource)
+	}
+
+	bogus := models.Severity("sev1")
+	if _, err := service.SubmitSeverityFeedback(ids[2], &models.SeverityFeedbackRequest{Severity: &bogus}); !errors.Is(err, ErrInvalidSeverity) {
+		t.Errorf("expected ErrInvalidSeverity, got %v", err)
+	}
+
+	report := service.SeverityAccuracy()
+	if report.Suggestions != 3 || report.Reviewed != 3 || report.Accepted != 1 || report.Rejected != 2 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if report.Confusion[models.SeverityHigh][models.SeverityCritical] != 1 || report.Confusion[models.SeverityHigh][models.SeverityLow] != 1 {
+		t.Errorf("unexpected confusion matrix %v", report.Confusion)
+	}
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// DefaultSLAInterval is how often the evaluator checks incidents against their SLA policies
+const DefaultSLAInterval = 30 * time.Second
+
+var (
+	// ErrSLAPolicyNotFound is returned when no SLA policy is set for a severity
+	ErrSLAPolicyNotFound = errors.New("SLA policy not found")
+	// ErrInvalidSLAPolicy is returned for SLA policies that fail validation
+	ErrInvalidSLAPolicy = errors.New("invalid SLA policy")
+)
+
+// slaPolicy is an SLAPolicy with its durations parsed; a zero target is not tracked
+type slaPolicy struct {
+	models.SLAPolicy
+	acknowledge time.Duration
+	resolve     time.Duration
+	warnBefore  time.Duration
+}
+
+// warnAt returns when a timer due at due with the given target should be flagged at risk
+func (p *slaPolicy) warnAt(due time.Time, target time.Duration) time.Time {
+	if p.warnBefore > 0 {
+		return due.Add(-p.warnBefore)
+	}
+	return due.Add(-target / 5)
+}
+
+// SLACount is the number of incidents in an SLA state for one severity and target
+type SLACount struct {
+	Severity models.Severity
+	Target   models.SLATarget
+	Count    int
+}
+
+// LoadSLAConfig reads and validates a JSON file of SLA policies
+func LoadSLAConfig(path string) (*models.SLAConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SLA config: %w", err)
+	}
+
+	var cfg models.SLAConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid SLA config %s: %w", path, err)
+	}
+
+	seen := make(map[models.Severity]bool)
+	for _, policy := range cfg.Policies {
+		if _, err := parseSLAPolicy(policy); err != nil {
+			return nil, fmt.Errorf("SLA config %s: %w", path, err)
+		}
+		if seen[policy.Severity] {
+			return nil, fmt.Errorf("SLA config %s: %w: duplicate policy for %s", path, ErrInvalidSLAPolicy, policy.Severity)
+		}
+		seen[policy.Severity] = true
+	}
+	return &cfg, nil
+}
+
+// WithSLAConfig preloads SLA policies, typically from LoadSLAConfig
+func WithSLAConfig(cfg *models.SLAConfig) ServiceOption {
+	return func(s *IncidentService) {
+		now := time.Now()
+		for _, policy := range cfg.Policies {
+			parsed, err := parseSLAPolicy(policy)
+			if err != nil {
+				s.logger.Warn("skipping invalid SLA policy", zap.String("severity", string(policy.Severity)), zap.Error(err))
+				continue
+			}
+			parsed.UpdatedAt = now
+			s.store.slaPolicies[parsed.Severity] = parsed
+		}
+	}
+}
+
+// SetSLAPolicy creates or replaces the SLA policy for a severity. Open incidents move to the new
+// targets when they are next evaluated; targets already met or breached stay as they were.
+func (s *IncidentService) SetSLAPolicy(severity models.Severity, req *models.SLAPolicy) (*models.SLAPolicy, error) {
+	policy := *req
+	policy.Severity = severity
+	parsed, err := parseSLAPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	parsed.UpdatedAt = s.now()
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.store.slaPolicies[severity] = parsed
+
+	s.logger.Info("SLA policy set", zap.String("severity", string(severity)),
+		zap.String("time_to_acknowledge", parsed.TimeToAcknowledge), zap.String("time_to_resolve", parsed.TimeToResolve))
+	result := parsed.SLAPolicy
+	return &result, nil
+}
+
+// ListSLAPolicies returns the SLA policies from most to least severe
+func (s *IncidentService) ListSLAPolicies() []*models.SLAPolicy {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.SLAPolicy, 0, len(s.store.slaPolicies))
+	for _, policy := range s.store.slaPolicies {
+		p := policy.SLAPolicy
+		result = append(result, &p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Severity.Rank() > result[j].Severity.Rank() })
+	return result
+}
+
+// GetSLAPolicy returns the SLA policy for a severity
+func (s *IncidentService) GetSLAPolicy(severity models.Severity) (*models.SLAPolicy, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	policy, ok := s.store.slaPolicies[severity]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSLAPolicyNotFound, severity)
+	}
+	result := policy.SLAPolicy
+	return &result, nil
+}
+
+// DeleteSLAPolicy removes the SLA policy for a severity; open incidents stop tracking targets they have not met
+func (s *IncidentService) DeleteSLAPolicy(severity models.Severity) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, ok := s.store.slaPolicies[severity]; !ok {
+		return fmt.Errorf("%w: %s", ErrSLAPolicyNotFound, severity)
+	}
+	delete(s.store.slaPolicies, severity)
+
+	s.logger.Info("SLA policy deleted", zap.String("severity", string(severity)))
+	return nil
+}
+
+// EvaluateSLAs refreshes every incident's SLA timers, flagging incidents at risk and marking breaches.
+// It returns how many targets became at risk and how many were breached.
+func (s *IncidentService) EvaluateSLAs() (atRisk, breached int) {
+	now := s.now()
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	for _, incident := range s.store.incidents {
+		r, b := s.refreshSLA(incident, now)
+		atRisk += r
+		breached += b
+	}
+	return atRisk, breached
+}
+
+// RunSLAEvaluator evaluates SLAs every interval until ctx is cancelled
+func (s *IncidentService) RunSLAEvaluator(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if atRisk, breached := s.EvaluateSLAs(); atRisk+breached > 0 {
+				s.logger.Info("SLA targets evaluated", zap.Int("at_risk", atRisk), zap.Int("breached", breached))
+			}
+		}
+	}
+}
+
+// SLACounts returns how many incidents breached each target, and how many open ones are at risk of
+// breaching it, by severity
+func (s *IncidentService) SLACounts() (breached, atRisk []SLACount) {
+	type key struct {
+		severity models.Severity
+		target   models.SLATarget
+	}
+	breaches := make(map[key]int)
+	risks := make(map[key]int)
+
+	s.store.mu.RLock()
+	for _, incident := range s.store.incidents {
+		if incident.SLA == nil {
+			continue
+		}
+		for target, timer := range slaTimers(incident.SLA) {
+			switch {
+			case timer == nil:
+			case timer.BreachedAt != nil:
+				breaches[key{incident.Severity, target}]++
+			case timer.State == models.SLAAtRisk:
+				risks[key{incident.Severity, target}]++
+			}
+		}
+	}
+	s.store.mu.RUnlock()
+
+	for k, n := range breaches {
+		breached = append(breached, SLACount{Severity: k.severity, Target: k.target, Count: n})
+	}
+	for k, n := range risks {
+		atRisk = append(atRisk, SLACount{Severity: k.severity, Target: k.target, Count: n})
+	}
+	return breached, atRisk
+}
+
+// refreshSLA brings an incident's timers up to date with the policy for its severity, publishing
+// incident.sla_at_risk and incident.sla_breached as targets cross those points. It returns how many
+// targets became at risk and breached. Callers must hold s.store.mu for writing.
+func (s *IncidentService) refreshSLA(incident *models.Incident, now time.Time) (atRisk, breached int) {
+	policy := s.store.slaPolicies[incident.Severity]
+	if policy == nil && incident.SLA == nil {
+		return 0, 0
+	}
+	if incident.SLA == nil {
+		incident.SLA = &models.IncidentSLA{}
+	}
+	sla := incident.SLA
+	sla.Policy = ""
+	var ackTarget, resolveTarget time.Duration
+	if policy != nil {
+		sla.Policy = policy.Severity
+		ackTarget, resolveTarget = policy.acknowledge, policy.resolve
+	}
+
+	// Resolving an incident before anyone acknowledged it also stops the acknowledgement timer
+	var resolvedAt *time.Time
+	if incident.Status == models.StatusResolved || incident.Status == models.StatusClosed {
+		resolvedAt = &now
+		if incident.ResolvedAt != nil {
+			resolvedAt = incident.ResolvedAt
+		}
+	}
+	acknowledgedAt := incident.AcknowledgedAt
+	if acknowledgedAt == nil {
+		acknowledgedAt = resolvedAt
+	}
+
+	timers := []struct {
+		target  models.SLATarget
+		timer   **models.SLATimer
+		limit   time.Duration
+		stopped *time.Time
+	}{
+		{models.SLATargetAcknowledge, &sla.Acknowledge, ackTarget, acknowledgedAt},
+		{models.SLATargetResolve, &sla.Resolve, resolveTarget, resolvedAt},
+	}
+	for _, t := range timers {
+		timer := *t.timer
+		finished := timer != nil && (timer.StoppedAt != nil || timer.BreachedAt != nil)
+		if !finished {
+			// Unfinished timers follow the current policy, and stop being tracked without a target
+			if t.limit == 0 {
+				*t.timer = nil
+				continue
+			}
+			if timer == nil {
+				timer = &models.SLATimer{}
+				*t.timer = timer
+			}
+			timer.DueAt = incident.CreatedAt.Add(t.limit)
+		}
+		if timer.StoppedAt == nil && t.stopped != nil {
+			stopped := *t.stopped
+			timer.StoppedAt = &stopped
+		}
+
+		end := now
+		if timer.StoppedAt != nil {
+			end = *timer.StoppedAt
+		}
+		switch {
+		case timer.BreachedAt == nil && !end.Before(timer.DueAt):
+			due := timer.DueAt
+			timer.BreachedAt = &due
+			sla.Breached = true
+			breached++
+			s.logger.Warn("SLA breached", zap.String("id", incident.ID), zap.String("target", string(t.target)), zap.Time("due_at", due))
+			s.publishEvent(models.Event{Type: models.EventIncidentSLABreached, SLATarget: t.target}, incident)
+		case timer.BreachedAt == nil && timer.StoppedAt == nil && timer.AtRiskAt == nil && policy != nil &&
+			!now.Before(policy.warnAt(timer.DueAt, t.limit)):
+			at := now
+			timer.AtRiskAt = &at
+			atRisk++
+			s.publishEvent(models.Event{Type: models.EventIncidentSLAAtRisk, SLATarget: t.target}, incident)
+		}
+
+		measureSLATimer(timer, incident.CreatedAt, now)
+		switch {
+		case timer.BreachedAt != nil:
+			timer.State = models.SLABreached
+		case timer.StoppedAt != nil:
+			timer.State = models.SLAMet
+		case timer.AtRiskAt != nil:
+			timer.State = models.SLAAtRisk
+		default:
+			timer.State = models.SLAOnTrack
+		}
+	}
+
+	if sla.Acknowledge == nil && sla.Resolve == nil && !sla.Breached {
+		incident.SLA = nil
+	}
+	return atRisk, breached
+}
+
+// measureSLATimer sets a timer's remaining time and the share of its target used up, as of now or
+// when the timer stopped. Unlike refreshSLA it changes neither the timer's state nor the incident.
+func measureSLATimer(timer *models.SLATimer, createdAt, now time.Time) {
+	end := now
+	if timer.StoppedAt != nil {
+		end = *timer.StoppedAt
+	}
+	timer.RemainingSeconds = int64(timer.DueAt.Sub(end) / time.Second)
+	if allowed := timer.DueAt.Sub(createdAt); allowed > 0 {
+		timer.Risk = math.Round(float64(end.Sub(createdAt))/float64(allowed)*100) / 100
+	}
+}
+
+// slaTimers returns an incident's timers by target; either may be nil
+func slaTimers(sla *models.IncidentSLA) map[models.SLATarget]*models.SLATimer {
+	return map[models.SLATarget]*models.SLATimer{
+		models.SLATargetAcknowledge: sla.Acknowledge,
+		models.SLATargetResolve:     sla.Resolve,
+	}
+}
+
+// parseSLAPolicy validates a policy and parses its durations
+func parseSLAPolicy(policy models.SLAPolicy) (*slaPolicy, error) {
+	if !policy.Severity.Valid() {
+		return nil, fmt.Errorf("%w: unknown severity %q", ErrInvalidSLAPolicy, policy.Severity)
+	}
+	parsed := &slaPolicy{SLAPolicy: policy}
+
+	fields := []struct {
+		name  string
+		value string
+		into  *time.Duration
+	}{
+		{"time_to_acknowledge", policy.TimeToAcknowledge, &parsed.acknowledge},
+		{"time_to_resolve", policy.TimeToResolve, &parsed.resolve},
+		{"warn_before", policy.WarnBefore, &parsed.warnBefore},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(f.value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: %s must be a positive duration such as \"15m\", got %q", ErrInvalidSLAPolicy, f.name, f.value)
+		}
+		*f.into = d
+	}
+	if parsed.acknowledge == 0 && parsed.resolve == 0 {
+		return nil, fmt.Errorf("%w: %s needs time_to_acknowledge, time_to_resolve or both", ErrInvalidSLAPolicy, policy.Severity)
+	}
+	if parsed.acknowledge > 0 && parsed.resolve > 0 && parsed.resolve < parsed.acknowledge {
+		return nil, fmt.Errorf("%w: time_to_resolve is shorter than time_to_acknowledge", ErrInvalidSLAPolicy)
+	}
+	return parsed, nil
+}
+
+func copyIncidentSLA(sla *models.IncidentSLA) *models.IncidentSLA {
+	c := *sla
+	if sla.Acknowledge != nil {
+		timer := *sla.Acknowledge
+		c.Acknowledge = &timer
+	}
+	if sla.Resolve != nil {
+		timer := *sla.Resolve
+		c.Resolve = &timer
+	}
+	return &c
+}
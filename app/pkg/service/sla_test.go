@@ -0,0 +1,229 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+func TestSLATimers(t *testing.T) {
+	now := time.Now()
+	events := &recordingNotifier{}
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(),
+		WithClock(func() time.Time { return now }), WithEventSubscriber(events),
+		WithSLAConfig(&models.SLAConfig{Policies: []models.SLAPolicy{
+			{Severity: models.SeverityCritical, TimeToAcknowledge: "15m", TimeToResolve: "4h"},
+		}}))
+
+	critical, low := models.SeverityCritical, models.SeverityLow
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout down", Description: "5xx", Severity: &critical})
+	quiet, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Disk 70%", Description: "db-1", Severity: &low})
+	if incident.SLA == nil || incident.SLA.Acknowledge.State != models.SLAOnTrack || incident.SLA.Resolve.DueAt.Sub(incident.CreatedAt) != 4*time.Hour {
+		t.Fatalf("expected on-track timers, got %+v", incident.SLA)
+	}
+	if quiet.SLA != nil {
+		t.Errorf("expected no SLA without a policy for low, got %+v", quiet.SLA)
+	}
+
+	// The acknowledgement warning starts a fifth of the target before the breach
+	now = now.Add(13 * time.Minute)
+	published := len(events.events)
+	if got, _ := service.GetIncident(incident.ID); got.SLA.Acknowledge.State != models.SLAOnTrack || got.SLA.Acknowledge.RemainingSeconds != 120 ||
+		len(events.events) != published {
+		t.Errorf("expected reads to update the remaining time only, got %+v and %d new events", got.SLA.Acknowledge, len(events.events)-published)
+	}
+	if atRisk, breached := service.EvaluateSLAs(); atRisk != 1 || breached != 0 {
+		t.Errorf("expected 1 at risk and 0 breached, got %d and %d", atRisk, breached)
+	}
+	if atRisk, _ := service.EvaluateSLAs(); atRisk != 0 {
+		t.Errorf("expected the at-risk event only once, got %d", atRisk)
+	}
+	now = now.Add(3 * time.Minute)
+	if _, breached := service.EvaluateSLAs(); breached != 1 {
+		t.Errorf("expected the acknowledgement target breached, got %d", breached)
+	}
+
+	got, _ := service.GetIncident(incident.ID)
+	if !got.SLA.Breached || got.SLA.Acknowledge.State != models.SLABreached || got.SLA.Acknowledge.RemainingSeconds >= 0 {
+		t.Errorf("expected a breached acknowledgement, got %+v", got.SLA.Acknowledge)
+	}
+	if got.SLA.Resolve.State != models.SLAOnTrack || got.SLA.Resolve.Risk <= 0 || got.SLA.Resolve.Risk >= 1 {
+		t.Errorf("expected resolution on track, got %+v", got.SLA.Resolve)
+	}
+
+	var slaEvents []models.Event
+	for _, event := range events.events {
+		if event.Type == models.EventIncidentSLAAtRisk || event.Type == models.EventIncidentSLABreached {
+			slaEvents = append(slaEvents, event)
+		}
+	}
+	if len(slaEvents) != 2 || slaEvents[0].Type != models.EventIncidentSLAAtRisk || slaEvents[1].Type != models.EventIncidentSLABreached ||
+		slaEvents[1].SLATarget != models.SLATargetAcknowledge || slaEvents[1].Incident.SLA.Acknowledge.BreachedAt == nil {
+		t.Errorf("unexpected SLA events %+v", slaEvents)
+	}
+
+	// Acknowledging stops the timer but keeps the breach on record
+	acked, _ := service.AcknowledgeIncident(incident.ID, &models.AcknowledgeRequest{User: "alice"})
+	if acked.SLA.Acknowledge.StoppedAt == nil || acked.SLA.Acknowledge.State != models.SLABreached {
+		t.Errorf("expected a stopped, breached acknowledgement timer, got %+v", acked.SLA.Acknowledge)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewMetricsCollector(service))
+	families, _ := registry.Gather()
+	gauges := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range metric.GetLabel() {
+				key += "/" + label.GetValue()
+			}
+			gauges[key] = metric.GetGauge().GetValue()
+		}
+	}
+	if gauges["incident_sla_breached/critical/acknowledge"] != 1 {
+		t.Errorf("unexpected SLA metrics %v", gauges)
+	}
+
+	// Resolving in time meets the resolution target
+	resolved := models.StatusResolved
+	service.UpdateIncident(incident.ID, &models.UpdateIncidentRequest{Status: &resolved})
+	now = now.Add(5 * time.Hour)
+	service.EvaluateSLAs()
+	got, _ = service.GetIncident(incident.ID)
+	if got.SLA.Resolve.State != models.SLAMet || got.SLA.Resolve.BreachedAt != nil {
+		t.Errorf("expected the resolution target met, got %+v", got.SLA.Resolve)
+	}
+}
+
+// TestSLAClock runs on a clock far from wall time, so any timestamp taken from time.Now skews the timers
+func TestSLAClock(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(),
+		WithClock(func() time.Time { return now }),
+		WithSLAConfig(&models.SLAConfig{Policies: []models.SLAPolicy{
+			{Severity: models.SeverityCritical, TimeToAcknowledge: "15m", TimeToResolve: "4h"},
+		}}))
+
+	critical := models.SeverityCritical
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout down", Description: "5xx", Severity: &critical})
+	if !incident.CreatedAt.Equal(now) || !incident.UpdatedAt.Equal(now) {
+		t.Errorf("expected timestamps from the clock, got created %v and updated %v", incident.CreatedAt, incident.UpdatedAt)
+	}
+	if sla := incident.SLA; sla == nil || !sla.Acknowledge.DueAt.Equal(now.Add(15*time.Minute)) || sla.Acknowledge.RemainingSeconds != 900 {
+		t.Fatalf("expected 15 minutes to acknowledge, got %+v", incident.SLA)
+	}
+
+	now = now.Add(time.Hour)
+	resolved := models.StatusResolved
+	updated, _ := service.UpdateIncident(incident.ID, &models.UpdateIncidentRequest{Status: &resolved})
+	if updated.ResolvedAt == nil || !updated.ResolvedAt.Equal(now) || !updated.UpdatedAt.Equal(now) {
+		t.Errorf("expected resolution at %v, got resolved %v and updated %v", now, updated.ResolvedAt, updated.UpdatedAt)
+	}
+	if updated.SLA.Resolve.State != models.SLAMet || updated.SLA.Acknowledge.State != models.SLABreached {
+		t.Errorf("expected resolution met and acknowledgement breached, got %+v", updated.SLA)
+	}
+
+	component, _ := service.CreateComponent(&models.Component{Name: "Checkout"})
+	if !component.CreatedAt.Equal(now) || !component.UpdatedAt.Equal(now) {
+		t.Errorf("expected component timestamps from the clock, got %+v", component)
+	}
+	now = now.Add(time.Minute)
+	description := "Cart and payment"
+	if component, _ = service.UpdateComponent("Checkout", &models.UpdateComponentRequest{Description: &description}); !component.UpdatedAt.Equal(now) {
+		t.Errorf("expected the component update at %v, got %v", now, component.UpdatedAt)
+	}
+}
+
+func TestSLASeverityChanges(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{severity: "critical"}, zap.NewNop(),
+		WithClock(func() time.Time { return now }), WithAISeverity(time.Second),
+		WithSLAConfig(&models.SLAConfig{Policies: []models.SLAPolicy{
+			{Severity: models.SeverityCritical, TimeToAcknowledge: "15m"},
+			{Severity: models.SeverityMedium, TimeToAcknowledge: "1h"},
+		}}))
+
+	// The AI classification raises the rules severity, and the timers follow without an evaluator run
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout slow", Description: "p99 latency doubled"})
+	service.WaitForClassifications()
+	got, _ := service.GetIncident(created.ID)
+	if got.SLA == nil || got.SLA.Policy != models.SeverityCritical || !got.SLA.Acknowledge.DueAt.Equal(now.Add(15*time.Minute)) {
+		t.Fatalf("expected the critical targets after classification, got %+v", got.SLA)
+	}
+
+	reverted, err := service.SubmitSeverityFeedback(created.ID, &models.SeverityFeedbackRequest{Accepted: false, Reviewer: "alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reverted.SLA.Policy != models.SeverityMedium || !reverted.SLA.Acknowledge.DueAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected the medium targets after feedback, got %+v", reverted.SLA)
+	}
+}
+
+func TestSLAPolicies(t *testing.T) {
+	now := time.Now()
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithClock(func() time.Time { return now }))
+
+	high := models.SeverityHigh
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Latency", Description: "p99", Severity: &high})
+
+	invalid := []models.SLAPolicy{
+		{},
+		{TimeToAcknowledge: "soon"},
+		{TimeToAcknowledge: "-5m"},
+		{TimeToAcknowledge: "1h", TimeToResolve: "30m"},
+	}
+	for _, req := range invalid {
+		if _, err := service.SetSLAPolicy(high, &req); !errors.Is(err, ErrInvalidSLAPolicy) {
+			t.Errorf("expected %+v to be invalid, got %v", req, err)
+		}
+	}
+	if _, err := service.SetSLAPolicy("urgent", &models.SLAPolicy{TimeToAcknowledge: "5m"}); !errors.Is(err, ErrInvalidSLAPolicy) {
+		t.Errorf("expected an unknown severity to be invalid, got %v", err)
+	}
+
+	// Existing incidents pick up a new policy
+	if _, err := service.SetSLAPolicy(high, &models.SLAPolicy{TimeToResolve: "8h", WarnBefore: "1h"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now = now.Add(7*time.Hour + 30*time.Minute)
+	service.EvaluateSLAs()
+	got, _ := service.GetIncident(incident.ID)
+	if got.SLA == nil || got.SLA.Acknowledge != nil || got.SLA.Resolve.State != models.SLAAtRisk {
+		t.Errorf("expected the resolution target at risk, got %+v", got.SLA)
+	}
+
+	if err := service.DeleteSLAPolicy(high); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteSLAPolicy(high); !errors.Is(err, ErrSLAPolicyNotFound) {
+		t.Errorf("expected ErrSLAPolicyNotFound, got %v", err)
+	}
+	service.EvaluateSLAs()
+	if got, _ := service.GetIncident(incident.ID); got.SLA != nil {
+		t.Errorf("expected the timers dropped with the policy, got %+v", got.SLA)
+	}
+}
+
+func TestLoadSLAConfig(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "sla.json")
+	os.WriteFile(valid, []byte(`{"policies": [{"severity": "critical", "time_to_acknowledge": "15m", "time_to_resolve": "4h"}]}`), 0o600)
+	duplicate := filepath.Join(dir, "duplicate.json")
+	os.WriteFile(duplicate, []byte(`{"policies": [{"severity": "high", "time_to_resolve": "8h"}, {"severity": "high", "time_to_resolve": "12h"}]}`), 0o600)
+
+	cfg, err := LoadSLAConfig(valid)
+	if err != nil || len(cfg.Policies) != 1 {
+		t.Fatalf("expected one policy, got %+v, %v", cfg, err)
+	}
+	if _, err := LoadSLAConfig(duplicate); !errors.Is(err, ErrInvalidSLAPolicy) {
+		t.Errorf("expected ErrInvalidSLAPolicy, got %v", err)
+	}
+}
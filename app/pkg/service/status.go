@@ -0,0 +1,619 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/statuspage"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrComponentNotFound is returned when a component name does not exist
+	ErrComponentNotFound = errors.New("component not found")
+	// ErrComponentExists is returned when creating a component whose name is taken
+	ErrComponentExists = errors.New("component already exists")
+	// ErrInvalidComponent is returned when a component fails validation
+	ErrInvalidComponent = errors.New("invalid component")
+	// ErrInvalidStatusUpdate is returned when a status update fails validation
+	ErrInvalidStatusUpdate = errors.New("invalid status update")
+)
+
+const (
+	// statusHistoryWindow is how long resolved incidents stay on the status page
+	statusHistoryWindow = 7 * 24 * time.Hour
+	// statusFeedLimit bounds the number of updates in the RSS and Atom feeds
+	statusFeedLimit = 50
+	// statusDraftTimeout bounds the AI call behind a drafted update
+	statusDraftTimeout = 30 * time.Second
+)
+
+// WithStatusPage sets the status page title and URL and preloads its components, typically from
+// statuspage.LoadConfig. Invalid components are skipped.
+func WithStatusPage(cfg *statuspage.Config) ServiceOption {
+	return func(s *IncidentService) {
+		s.statusPage = statuspage.Config{Title: cfg.Title, URL: cfg.URL}
+		now := time.Now()
+		for _, c := range cfg.Components {
+			component := copyComponent(&c)
+			if err := validateComponent(component); err != nil {
+				s.logger.Warn("skipping status page component", zap.String("component", c.Name), zap.Error(err))
+				continue
+			}
+			component.CreatedAt = now
+			component.UpdatedAt = now
+			s.store.components[componentKey(component.Name)] = component
+		}
+	}
+}
+
+// CreateComponent adds a component to the status page
+func (s *IncidentService) CreateComponent(req *models.Component) (*models.Component, error) {
+	component := copyComponent(req)
+	if err := validateComponent(component); err != nil {
+		return nil, err
+	}
+	component.CreatedAt = s.now()
+	component.UpdatedAt = component.CreatedAt
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := componentKey(component.Name)
+	if _, ok := s.store.components[key]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrComponentExists, component.Name)
+	}
+	s.store.components[key] = component
+
+	s.logger.Info("status page component created", zap.String("component", component.Name))
+	return s.componentWithStatus(component), nil
+}
+
+// ListComponents returns the components sorted by name, with their current status
+func (s *IncidentService) ListComponents() []*models.Component {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	result := make([]*models.Component, 0, len(s.store.components))
+	for _, component := range s.store.components {
+		result = append(result, s.componentWithStatus(component))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// GetComponent returns a component by name, case-insensitively
+func (s *IncidentService) GetComponent(name string) (*models.Component, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	component, ok := s.store.components[componentKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrComponentNotFound, name)
+	}
+	return s.componentWithStatus(component), nil
+}
+
+// UpdateComponent changes the provided fields of a component
+func (s *IncidentService) UpdateComponent(name string, req *models.UpdateComponentRequest) (*models.Component, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	component, ok := s.store.components[componentKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrComponentNotFound, name)
+	}
+
+	updated := copyComponent(component)
+	if req.Description != nil {
+		updated.Description = *req.Description
+	}
+	if req.Services != nil {
+		updated.Services = append([]string(nil), req.Services...)
+	}
+	if err := validateComponent(updated); err != nil {
+		return nil, err
+	}
+	updated.UpdatedAt = s.now()
+	s.store.components[componentKey(name)] = updated
+
+	s.logger.Info("status page component updated", zap.String("component", updated.Name))
+	return s.componentWithStatus(updated), nil
+}
+
+// DeleteComponent removes a component. Published updates keep naming it but the page no longer shows it.
+func (s *IncidentService) DeleteComponent(name string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := componentKey(name)
+	if _, ok := s.store.components[key]; !ok {
+		return fmt.Errorf("%w: %s", ErrComponentNotFound, name)
+	}
+	delete(s.store.components, key)
+
+	s.logger.Info("status page component deleted", zap.String("component", name))
+	return nil
+}
+
+// PublishStatusUpdate posts a customer-facing update on an incident. Without components, the first
+// update marks the components tied to the incident's service and its dependents, and later updates
+// keep the previous ones. A resolved update returns its components to operational.
+func (s *IncidentService) PublishStatusUpdate(incidentID string, req *models.CreateStatusUpdateRequest) (*models.StatusUpdate, error) {
+	if !validStatusUpdateState(req.State) {
+		return nil, fmt.Errorf("%w: state must be investigating, identified, monitoring or resolved", ErrInvalidStatusUpdate)
+	}
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		return nil, fmt.Errorf("%w: message is required", ErrInvalidStatusUpdate)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	incident, ok := s.store.incidents[incidentID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	updates := s.store.statusUpdates[incidentID]
+	var previous *models.StatusUpdate
+	if len(updates) > 0 {
+		previous = updates[len(updates)-1]
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" && previous != nil {
+		title = previous.Title
+	}
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required on the first update", ErrInvalidStatusUpdate)
+	}
+
+	var components map[string]models.ComponentStatus
+	switch {
+	case len(req.Components) > 0:
+		components = make(map[string]models.ComponentStatus, len(req.Components))
+		for name, status := range req.Components {
+			component, ok := s.store.components[componentKey(name)]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrComponentNotFound, name)
+			}
+			if !validComponentStatus(status) {
+				return nil, fmt.Errorf("%w: component status must be operational, degraded or outage", ErrInvalidStatusUpdate)
+			}
+			components[component.Name] = status
+		}
+	case previous != nil:
+		components = copyComponentStatuses(previous.Components)
+	default:
+		components = s.affectedComponents(incident)
+	}
+	if req.State == models.StatusUpdateResolved {
+		for name := range components {
+			components[name] = models.ComponentOperational
+		}
+	}
+
+	s.store.counter++
+	update := &models.StatusUpdate{
+		ID:         fmt.Sprintf("SU-%d", s.store.counter),
+		IncidentID: incidentID,
+		State:      req.State,
+		Title:      title,
+		Message:    message,
+		Components: components,
+		Author:     strings.TrimSpace(req.Author),
+		CreatedAt:  s.now(),
+	}
+	s.store.statusUpdates[incidentID] = append(updates, update)
+
+	s.logger.Info("status update published",
+		zap.String("incident_id", incidentID),
+		zap.String("id", update.ID),
+		zap.String("state", string(update.State)))
+	return copyStatusUpdate(update), nil
+}
+
+// ListStatusUpdates returns an incident's status updates, oldest first
+func (s *IncidentService) ListStatusUpdates(incidentID string) ([]*models.StatusUpdate, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	if _, ok := s.store.incidents[incidentID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	updates := make([]*models.StatusUpdate, 0, len(s.store.statusUpdates[incidentID]))
+	for _, update := range s.store.statusUpdates[incidentID] {
+		updates = append(updates, copyStatusUpdate(update))
+	}
+	return updates, nil
+}
+
+// DraftStatusUpdate proposes the next status update for an incident without publishing it. The AI
+// provider only sees the incident's title, description, severity and analysis summary, and the
+// draft is scrubbed of addresses, hostnames, people and internal service names. When the provider
+// is unavailable the draft comes from a template.
+func (s *IncidentService) DraftStatusUpdate(incidentID string, req *models.DraftStatusUpdateRequest) (*models.StatusUpdateDraft, error) {
+	if req.State != "" && !validStatusUpdateState(req.State) {
+		return nil, fmt.Errorf("%w: state must be investigating, identified, monitoring or resolved", ErrInvalidStatusUpdate)
+	}
+
+	s.store.mu.RLock()
+	incident, ok := s.store.incidents[incidentID]
+	if !ok {
+		s.store.mu.RUnlock()
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, incidentID)
+	}
+	updates := s.store.statusUpdates[incidentID]
+	var previousTitle string
+	var previousState models.StatusUpdateState
+	var components map[string]models.ComponentStatus
+	var previousMessages []string
+	for _, update := range updates {
+		previousMessages = append(previousMessages, update.Message)
+	}
+	if len(updates) > 0 {
+		last := updates[len(updates)-1]
+		previousTitle, previousState = last.Title, last.State
+		components = copyComponentStatuses(last.Components)
+	} else {
+		components = s.affectedComponents(incident)
+	}
+
+	state := req.State
+	switch {
+	case state != "":
+	case incident.Status == models.StatusResolved || incident.Status == models.StatusClosed:
+		state = models.StatusUpdateResolved
+	case previousState != "" && previousState != models.StatusUpdateResolved:
+		state = previousState
+	default:
+		state = models.StatusUpdateInvestigating
+	}
+
+	aiReq := ai.StatusUpdateRequest{
+		IncidentTitle:   incident.Title,
+		IncidentDesc:    incident.Description,
+		Severity:        string(incident.Severity),
+		Components:      sortedComponentNames(components),
+		State:           string(state),
+		PreviousUpdates: previousMessages,
+	}
+	if incident.AIAnalysis != nil {
+		aiReq.Summary = incident.AIAnalysis.Summary
+	}
+	internal := s.internalTerms(incident)
+	s.store.mu.RUnlock()
+
+	draft := &models.StatusUpdateDraft{State: state, Components: components, Source: "ai"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusDraftTimeout)
+	defer cancel()
+	resp, err := s.aiClient.DraftStatusUpdate(ctx, aiReq)
+	if err != nil {
+		s.logger.Warn("AI status update draft failed, using template", zap.String("incident_id", incidentID), zap.Error(err))
+		draft.Source = "template"
+		draft.Title, draft.Message = templateStatusUpdate(state, aiReq.Components)
+	} else {
+		draft.Title, draft.Message = resp.Title, resp.Message
+		if req.State == "" {
+			draft.State = models.StatusUpdateState(resp.State)
+		}
+	}
+	if previousTitle != "" {
+		draft.Title = previousTitle
+	}
+	if draft.State == models.StatusUpdateResolved {
+		for name := range draft.Components {
+			draft.Components[name] = models.ComponentOperational
+		}
+	}
+	draft.Title = statuspage.Scrub(draft.Title, internal...)
+	draft.Message = statuspage.Scrub(draft.Message, internal...)
+	return draft, nil
+}
+
+// PublicStatus returns the status page: each component's status, unresolved incidents and those
+// resolved in the last week. Only status updates and component names are shown.
+func (s *IncidentService) PublicStatus() *models.StatusPage {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	now := s.now()
+	page := &models.StatusPage{
+		Title:      s.statusPage.Title,
+		URL:        s.statusPage.URL,
+		Status:     models.ComponentOperational,
+		Components: make([]models.PublicComponent, 0, len(s.store.components)),
+		Incidents:  make([]models.PublicIncident, 0),
+		Recent:     make([]models.PublicIncident, 0),
+	}
+	if page.Title == "" {
+		page.Title = statuspage.DefaultTitle
+	}
+
+	for _, updates := range s.store.statusUpdates {
+		if len(updates) == 0 {
+			continue
+		}
+		incident := publicIncident(updates)
+		if last := updates[len(updates)-1].CreatedAt; last.After(page.UpdatedAt) {
+			page.UpdatedAt = last
+		}
+		switch {
+		case incident.State != models.StatusUpdateResolved:
+			page.Incidents = append(page.Incidents, incident)
+			page.Status = worseComponentStatus(page.Status, incident.Impact)
+		case now.Sub(*incident.ResolvedAt) <= statusHistoryWindow:
+			page.Recent = append(page.Recent, incident)
+		}
+	}
+	newestFirst := func(incidents []models.PublicIncident) {
+		sort.Slice(incidents, func(i, j int) bool { return incidents[i].StartedAt.After(incidents[j].StartedAt) })
+	}
+	newestFirst(page.Incidents)
+	newestFirst(page.Recent)
+
+	for _, component := range s.store.components {
+		status := s.componentStatus(component.Name)
+		page.Components = append(page.Components, models.PublicComponent{Name: component.Name, Description: component.Description, Status: status})
+		page.Status = worseComponentStatus(page.Status, status)
+		if component.UpdatedAt.After(page.UpdatedAt) {
+			page.UpdatedAt = component.UpdatedAt
+		}
+	}
+	sort.Slice(page.Components, func(i, j int) bool { return page.Components[i].Name < page.Components[j].Name })
+
+	if page.UpdatedAt.IsZero() {
+		page.UpdatedAt = now
+	}
+	return page
+}
+
+// StatusFeed renders the latest status updates across all incidents as an RSS or Atom feed
+func (s *IncidentService) StatusFeed(format statuspage.Format) ([]byte, error) {
+	s.store.mu.RLock()
+	var entries []statuspage.Entry
+	for _, updates := range s.store.statusUpdates {
+		for _, update := range updates {
+			entries = append(entries, statuspage.Entry{
+				ID:         update.ID,
+				IncidentID: updates[0].ID,
+				Title:      update.Title,
+				State:      update.State,
+				Message:    update.Message,
+				At:         update.CreatedAt,
+			})
+		}
+	}
+	cfg := s.statusPage
+	s.store.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].At.After(entries[j].At) })
+	if len(entries) > statusFeedLimit {
+		entries = entries[:statusFeedLimit]
+	}
+
+	var buf bytes.Buffer
+	if err := statuspage.RenderFeed(&buf, format, cfg, entries, s.now()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// affectedComponents maps the components tied to the incident's service to a status from its
+// severity, and those tied to services depending on it to degraded; callers must hold s.store.mu
+func (s *IncidentService) affectedComponents(incident *models.Incident) map[string]models.ComponentStatus {
+	components := make(map[string]models.ComponentStatus)
+	if incident.Service == "" {
+		return components
+	}
+	impact := make(map[string]models.ComponentStatus)
+	impact[catalogKey(incident.Service)] = models.ComponentDegraded
+	if incident.Severity == models.SeverityCritical {
+		impact[catalogKey(incident.Service)] = models.ComponentOutage
+	}
+	for _, dependent := range s.dependentServices(incident.Service) {
+		impact[catalogKey(dependent.Name)] = models.ComponentDegraded
+	}
+
+	for _, component := range s.store.components {
+		for _, svc := range component.Services {
+			if status, ok := impact[catalogKey(svc)]; ok {
+				components[component.Name] = worseComponentStatus(components[component.Name], status)
+			}
+		}
+	}
+	return components
+}
+
+// componentStatus is the worst status given to a component by the latest update of each unresolved
+// incident; callers must hold s.store.mu
+func (s *IncidentService) componentStatus(name string) models.ComponentStatus {
+	status := models.ComponentOperational
+	for _, updates := range s.store.statusUpdates {
+		if len(updates) == 0 {
+			continue
+		}
+		latest := updates[len(updates)-1]
+		if latest.State == models.StatusUpdateResolved {
+			continue
+		}
+		for component, componentStatus := range latest.Components {
+			if strings.EqualFold(component, name) {
+				status = worseComponentStatus(status, componentStatus)
+			}
+		}
+	}
+	return status
+}
+
+// componentWithStatus copies a component and fills in its status; callers must hold s.store.mu
+func (s *IncidentService) componentWithStatus(component *models.Component) *models.Component {
+	result := copyComponent(component)
+	result.Status = s.componentStatus(component.Name)
+	return result
+}
+
+// internalTerms lists the names a public update must not mention: the incident ID, the people on it,
+// and catalog services and teams. Component names are always allowed. Callers must hold s.store.mu.
+func (s *IncidentService) internalTerms(incident *models.Incident) []string {
+	candidates := []string{incident.ID, incident.AssignedTo, incident.AcknowledgedBy, incident.Service}
+	for _, comment := range s.store.comments[incident.ID] {
+		candidates = append(candidates, comment.Author)
+	}
+	for _, svc := range s.store.services {
+		candidates = append(candidates, svc.Name, svc.Team)
+	}
+
+	var terms []string
+	for _, term := range candidates {
+		if _, public := s.store.components[componentKey(term)]; term != "" && !public {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// publicIncident tells an incident through its status updates
+func publicIncident(updates []*models.StatusUpdate) models.PublicIncident {
+	first, latest := updates[0], updates[len(updates)-1]
+	incident := models.PublicIncident{
+		ID:         first.ID,
+		Title:      latest.Title,
+		State:      latest.State,
+		Impact:     models.ComponentOperational,
+		Components: sortedComponentNames(latest.Components),
+		Updates:    make([]models.PublicStatusUpdate, 0, len(updates)),
+		StartedAt:  first.CreatedAt,
+	}
+	for i := len(updates) - 1; i >= 0; i-- {
+		update := updates[i]
+		incident.Updates = append(incident.Updates, models.PublicStatusUpdate{
+			ID:         update.ID,
+			State:      update.State,
+			Message:    update.Message,
+			Components: copyComponentStatuses(update.Components),
+			CreatedAt:  update.CreatedAt,
+		})
+		for _, status := range update.Components {
+			incident.Impact = worseComponentStatus(incident.Impact, status)
+		}
+	}
+	if latest.State == models.StatusUpdateResolved {
+		resolvedAt := latest.CreatedAt
+		incident.ResolvedAt = &resolvedAt
+	}
+	return incident
+}
+
+// templateStatusUpdate drafts an update without AI
+func templateStatusUpdate(state models.StatusUpdateState, components []string) (string, string) {
+	affected := "some of our services"
+	title := "Service disruption"
+	if len(components) > 0 {
+		affected = strings.Join(components, ", ")
+		title = "Degraded service: " + affected
+	}
+	switch state {
+	case models.StatusUpdateIdentified:
+		return title, fmt.Sprintf("We have identified the issue affecting %s and are working on a fix.", affected)
+	case models.StatusUpdateMonitoring:
+		return title, fmt.Sprintf("A fix has been applied for the issue affecting %s. We are monitoring the results.", affected)
+	case models.StatusUpdateResolved:
+		return title, "This incident has been resolved and all services are operating normally."
+	default:
+		return title, fmt.Sprintf("We are investigating reports of issues affecting %s. We will post an update as soon as we know more.", affected)
+	}
+}
+
+func validStatusUpdateState(state models.StatusUpdateState) bool {
+	switch state {
+	case models.StatusUpdateInvestigating, models.StatusUpdateIdentified, models.StatusUpdateMonitoring, models.StatusUpdateResolved:
+		return true
+	}
+	return false
+}
+
+func validComponentStatus(status models.ComponentStatus) bool {
+	switch status {
+	case models.ComponentOperational, models.ComponentDegraded, models.ComponentOutage:
+		return true
+	}
+	return false
+}
+
+func componentStatusRank(status models.ComponentStatus) int {
+	switch status {
+	case models.ComponentOutage:
+		return 2
+	case models.ComponentDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func worseComponentStatus(a, b models.ComponentStatus) models.ComponentStatus {
+	if componentStatusRank(b) > componentStatusRank(a) || a == "" {
+		return b
+	}
+	return a
+}
+
+func sortedComponentNames(components map[string]models.ComponentStatus) []string {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateComponent(component *models.Component) error {
+	component.Name = strings.TrimSpace(component.Name)
+	if component.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidComponent)
+	}
+	if len(component.Name) > 100 {
+		return fmt.Errorf("%w: name must be at most 100 characters", ErrInvalidComponent)
+	}
+	for _, svc := range component.Services {
+		if !namePattern.MatchString(svc) {
+			return fmt.Errorf("%w: invalid service name %q", ErrInvalidComponent, svc)
+		}
+	}
+	return nil
+}
+
+func componentKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func copyComponent(component *models.Component) *models.Component {
+	c := *component
+	c.Services = append([]string(nil), component.Services...)
+	c.Status = ""
+	return &c
+}
+
+func copyComponentStatuses(components map[string]models.ComponentStatus) map[string]models.ComponentStatus {
+	result := make(map[string]models.ComponentStatus, len(components))
+	for name, status := range components {
+		result[name] = status
+	}
+	return result
+}
+
+func copyStatusUpdate(update *models.StatusUpdate) *models.StatusUpdate {
+	u := *update
+	u.Components = copyComponentStatuses(update.Components)
+	return &u
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/statuspage"
+)
+
+// newStatusPageService adds components on top of the catalog from newCatalogService. Reporting runs on
+// ledger-db, which payments-api and, through it, checkout depend on.
+func newStatusPageService(t *testing.T, mockAI *MockAIClient) *IncidentService {
+	t.Helper()
+	service := newCatalogService(t, mockAI)
+	for _, c := range []models.Component{
+		{Name: "Checkout", Services: []string{"checkout", "cart"}},
+		{Name: "Payments", Services: []string{"payments-api"}},
+		{Name: "Reporting", Services: []string{"ledger-db"}},
+		{Name: "Search"},
+	} {
+		c := c
+		if _, err := service.CreateComponent(&c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return service
+}
+
+func componentStatuses(page *models.StatusPage) map[string]models.ComponentStatus {
+	result := make(map[string]models.ComponentStatus)
+	for _, c := range page.Components {
+		result[c.Name] = c.Status
+	}
+	return result
+}
+
+func TestStatusPageComponents(t *testing.T) {
+	service := newStatusPageService(t, &MockAIClient{})
+
+	if _, err := service.CreateComponent(&models.Component{Name: " "}); !errors.Is(err, ErrInvalidComponent) {
+		t.Errorf("expected ErrInvalidComponent, got %v", err)
+	}
+	if _, err := service.CreateComponent(&models.Component{Name: "checkout"}); !errors.Is(err, ErrComponentExists) {
+		t.Errorf("expected ErrComponentExists, got %v", err)
+	}
+	if _, err := service.CreateComponent(&models.Component{Name: "API", Services: []string{"bad name"}}); !errors.Is(err, ErrInvalidComponent) {
+		t.Errorf("expected ErrInvalidComponent for a bad service name, got %v", err)
+	}
+
+	updated, err := service.UpdateComponent("search", &models.UpdateComponentRequest{Services: []string{"search-api"}})
+	if err != nil || updated.Name != "Search" || updated.Services[0] != "search-api" || updated.Status != models.ComponentOperational {
+		t.Fatalf("unexpected update %+v (%v)", updated, err)
+	}
+	if err := service.DeleteComponent("Search"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.GetComponent("search"); !errors.Is(err, ErrComponentNotFound) {
+		t.Errorf("expected ErrComponentNotFound, got %v", err)
+	}
+	if components := service.ListComponents(); len(components) != 3 || components[0].Name != "Checkout" {
+		t.Errorf("expected 3 components sorted by name, got %+v", components)
+	}
+}
+
+func TestPublishStatusUpdate(t *testing.T) {
+	service := newStatusPageService(t, &MockAIClient{})
+	critical := models.SeverityCritical
+	incident, err := service.CreateIncident(&models.CreateIncidentRequest{Title: "ledger-db primary down", Description: "failover stuck", Service: "ledger-db", Severity: &critical})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := []struct {
+		req      models.CreateStatusUpdateRequest
+		expected error
+	}{
+		{models.CreateStatusUpdateRequest{State: models.StatusUpdateInvestigating, Message: "Looking into it"}, ErrInvalidStatusUpdate},
+		{models.CreateStatusUpdateRequest{State: "fixed", Title: "Payment failures", Message: "Looking into it"}, ErrInvalidStatusUpdate},
+		{models.CreateStatusUpdateRequest{State: models.StatusUpdateInvestigating, Title: "Payment failures"}, ErrInvalidStatusUpdate},
+		{models.CreateStatusUpdateRequest{State: models.StatusUpdateInvestigating, Title: "Payment failures", Message: "Looking into it",
+			Components: map[string]models.ComponentStatus{"Billing": models.ComponentOutage}}, ErrComponentNotFound},
+	}
+	for _, tt := range invalid {
+		if _, err := service.PublishStatusUpdate(incident.ID, &tt.req); !errors.Is(err, tt.expected) {
+			t.Errorf("%+v: expected %v, got %v", tt.req, tt.expected, err)
+		}
+	}
+
+	// The first update marks the incident's service as down and the services depending on it as degraded
+	first, err := service.PublishStatusUpdate(incident.ID, &models.CreateStatusUpdateRequest{
+		State: models.StatusUpdateInvestigating, Title: "Payment failures", Message: "We are investigating failed payments.", Author: "priya",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]models.ComponentStatus{"Reporting": models.ComponentOutage, "Payments": models.ComponentDegraded, "Checkout": models.ComponentDegraded}
+	if len(first.Components) != 3 || first.Components["Reporting"] != expected["Reporting"] || first.Components["Checkout"] != expected["Checkout"] {
+		t.Errorf("expected components %v, got %v", expected, first.Components)
+	}
+	page := service.PublicStatus()
+	if page.Status != models.ComponentOutage || len(page.Incidents) != 1 || componentStatuses(page)["Payments"] != models.ComponentDegraded {
+		t.Errorf("unexpected page %+v", page)
+	}
+
+	// Later updates keep the title, and name components case-insensitively
+	second, _ := service.PublishStatusUpdate(incident.ID, &models.CreateStatusUpdateRequest{
+		State: models.StatusUpdateIdentified, Message: "We have found the problem.", Components: map[string]models.ComponentStatus{"payments": models.ComponentOutage},
+	})
+	if second.Title != "Payment failures" || len(second.Components) != 1 || second.Components["Payments"] != models.ComponentOutage {
+		t.Errorf("unexpected second update %+v", second)
+	}
+	if statuses := componentStatuses(service.PublicStatus()); statuses["Payments"] != models.ComponentOutage || statuses["Checkout"] != models.ComponentOperational {
+		t.Errorf("expected only Payments to be down, got %v", statuses)
+	}
+
+	resolved, _ := service.PublishStatusUpdate(incident.ID, &models.CreateStatusUpdateRequest{State: models.StatusUpdateResolved, Message: "Payments are working again."})
+	if resolved.Components["Payments"] != models.ComponentOperational {
+		t.Errorf("expected resolving to restore Payments, got %v", resolved.Components)
+	}
+	page = service.PublicStatus()
+	if page.Status != models.ComponentOperational || len(page.Incidents) != 0 || len(page.Recent) != 1 {
+		t.Fatalf("expected the incident to move to recent, got %+v", page)
+	}
+	recent := page.Recent[0]
+	if recent.Title != "Payment failures" || recent.Impact != models.ComponentOutage || recent.ResolvedAt == nil ||
+		len(recent.Updates) != 3 || recent.Updates[0].ID != resolved.ID || !recent.StartedAt.Equal(first.CreatedAt) ||
+		recent.ID != first.ID {
+		t.Errorf("unexpected recent incident %+v", recent)
+	}
+
+	updates, _ := service.ListStatusUpdates(incident.ID)
+	if len(updates) != 3 || updates[0].Author != "priya" {
+		t.Errorf("expected 3 updates oldest first, got %+v", updates)
+	}
+	if _, err := service.ListStatusUpdates("INC-0"); !errors.Is(err, ErrIncidentNotFound) {
+		t.Errorf("expected ErrIncidentNotFound, got %v", err)
+	}
+
+	// Resolved incidents leave the page after a week
+	for _, update := range service.store.statusUpdates[incident.ID] {
+		update.CreatedAt = update.CreatedAt.Add(-8 * 24 * time.Hour)
+	}
+	if page := service.PublicStatus(); len(page.Recent) != 0 {
+		t.Errorf("expected no recent incidents, got %+v", page.Recent)
+	}
+}
+
+func TestDraftStatusUpdate(t *testing.T) {
+	mockAI := &MockAIClient{statusDraft: &ai.StatusUpdateResponse{
+		Title:   "ledger-db outage",
+		State:   "investigating",
+		Message: "ledger-db at 10.0.3.7 is down and Priya was paged. Payments are failing.",
+	}}
+	service := newStatusPageService(t, mockAI)
+	critical := models.SeverityCritical
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "ledger-db primary down", Description: "failover stuck",
+		Service: "ledger-db", Severity: &critical, AssignedTo: "priya", Logs: []string{"FATAL: password authentication failed for user ledger"}})
+
+	if _, err := service.DraftStatusUpdate(incident.ID, &models.DraftStatusUpdateRequest{State: "fixed"}); !errors.Is(err, ErrInvalidStatusUpdate) {
+		t.Errorf("expected ErrInvalidStatusUpdate, got %v", err)
+	}
+
+	draft, err := service.DraftStatusUpdate(incident.ID, &models.DraftStatusUpdateRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if draft.Source != "ai" || draft.State != models.StatusUpdateInvestigating || draft.Components["Reporting"] != models.ComponentOutage {
+		t.Errorf("unexpected draft %+v", draft)
+	}
+	if draft.Title != "[redacted] outage" || draft.Message != "[redacted] at [redacted] is down and [redacted] was paged. Payments are failing." {
+		t.Errorf("expected internals to be scrubbed, got %q: %q", draft.Title, draft.Message)
+	}
+	sent := mockAI.lastStatusDraft
+	if strings.Join(sent.Components, ",") != "Checkout,Payments,Reporting" || sent.Severity != "critical" || strings.Contains(sent.IncidentDesc, "FATAL") {
+		t.Errorf("unexpected AI request %+v", sent)
+	}
+
+	// Without AI the draft comes from a template and keeps the published title
+	service.PublishStatusUpdate(incident.ID, &models.CreateStatusUpdateRequest{State: models.StatusUpdateIdentified, Title: "Payment failures", Message: "Found it."})
+	mockAI.statusDraftErr = errors.New("provider down")
+	draft, _ = service.DraftStatusUpdate(incident.ID, &models.DraftStatusUpdateRequest{})
+	if draft.Source != "template" || draft.State != models.StatusUpdateIdentified || draft.Title != "Payment failures" ||
+		!strings.Contains(draft.Message, "affecting Checkout, Payments, Reporting") {
+		t.Errorf("unexpected template draft %+v", draft)
+	}
+
+	draft, _ = service.DraftStatusUpdate(incident.ID, &models.DraftStatusUpdateRequest{State: models.StatusUpdateResolved})
+	if draft.State != models.StatusUpdateResolved || draft.Components["Reporting"] != models.ComponentOperational {
+		t.Errorf("expected a resolved draft restoring components, got %+v", draft)
+	}
+}
+
+func TestStatusFeed(t *testing.T) {
+	service := newStatusPageService(t, &MockAIClient{})
+	service.statusPage.URL = "https://status.acme.com"
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx", Service: "checkout"})
+	first, _ := service.PublishStatusUpdate(incident.ID, &models.CreateStatusUpdateRequest{State: models.StatusUpdateInvestigating, Title: "Checkout errors", Message: "Investigating checkout errors."})
+	service.PublishStatusUpdate(incident.ID, &models.CreateStatusUpdateRequest{State: models.StatusUpdateIdentified, Message: "Found it."})
+
+	rss, err := service.StatusFeed(statuspage.FormatRSS)
+	if err != nil || !strings.Contains(string(rss), "<title>Checkout errors - Investigating</title>") {
+		t.Errorf("unexpected RSS feed (%v):\n%s", err, rss)
+	}
+	atom, err := service.StatusFeed(statuspage.FormatAtom)
+	if err != nil || !strings.Contains(string(atom), "<content type=\"text\">Investigating checkout errors.</content>") {
+		t.Errorf("unexpected Atom feed (%v):\n%s", err, atom)
+	}
+	if strings.Contains(string(rss)+string(atom), incident.ID) || !strings.Contains(string(rss), "<link>https://status.acme.com#"+first.ID+"</link>") {
+		t.Errorf("expected feeds to link the public ID %s and never the incident ID %s:\n%s", first.ID, incident.ID, rss)
+	}
+	if _, err := service.StatusFeed("json"); !errors.Is(err, statuspage.ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
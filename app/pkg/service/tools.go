@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+// DeploySource lists recent deploys so the model can correlate incidents with changes
+type DeploySource interface {
+	RecentDeploys(ctx context.Context, service string, since time.Time) ([]models.Deployment, error)
+}
+
+// WithDeploySource configures where the list_recent_deploys tool reads deploys from
+func WithDeploySource(source DeploySource) ServiceOption {
+	return func(s *IncidentService) {
+		s.deploySource = source
+	}
+}
+
+// WithToolLimits overrides the step and token limits of tool-assisted analysis
+func WithToolLimits(limits ai.ToolLimits) ServiceOption {
+	return func(s *IncidentService) {
+		s.toolLimits = limits
+	}
+}
+
+// HTTPDeploySource reads deploys from an HTTP endpoint returning a JSON array of deployments
+type HTTPDeploySource struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPDeploySource creates a deploy source backed by an HTTP endpoint
+func NewHTTPDeploySource(endpoint string) *HTTPDeploySource {
+	return &HTTPDeploySource{
+		url: endpoint,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// RecentDeploys fetches deploys since the given time, optionally filtered by service
+func (d *HTTPDeploySource) RecentDeploys(ctx context.Context, service string, since time.Time) ([]models.Deployment, error) {
+	u, err := url.Parse(d.url)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	if service != "" {
+		q.Set("service", service)
+	}
+	q.Set("since", since.UTC().Format(time.RFC3339))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deploys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("deploy source error: %d", resp.StatusCode)
+	}
+
+	var deploys []models.Deployment
+	if err := json.NewDecoder(resp.Body).Decode(&deploys); err != nil {
+		return nil, fmt.Errorf("invalid deploy source response: %w", err)
+	}
+
+	// Filter again in case the endpoint ignores the query parameters
+	results := make([]models.Deployment, 0, len(deploys))
+	for _, dep := range deploys {
+		if dep.DeployedAt.Before(since) {
+			continue
+		}
+		if service != "" && !strings.EqualFold(dep.Service, service) {
+			continue
+		}
+		results = append(results, dep)
+	}
+	return results, nil
+}
+
+// AnalyzeIncidentWithTools generates AI analysis where the model gathers its own evidence through tools
+func (s *IncidentService) AnalyzeIncidentWithTools(id string) (*models.Incident, error) {
+	incident, err := s.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	s.store.mu.RLock()
+	req := ai.ToolAnalysisRequest{
+		IncidentID:    incident.ID,
+		IncidentTitle: incident.Title,
+		IncidentDesc:  incident.Description,
+		Tools:         s.buildToolRegistry(incident.ID),
+		Limits:        s.toolLimits,
+	}
+	s.store.mu.RUnlock()
+
+	analysis, err := s.aiClient.AnalyzeIncidentWithTools(ctx, req)
+	if err != nil {
+		s.logger.Error("failed to analyze incident with tools", zap.String("id", id), zap.Error(err))
+		return incident, err
+	}
+
+	trace := make([]models.ToolCallRecord, 0, len(analysis.Trace))
+	for _, entry := range analysis.Trace {
+		trace = append(trace, models.ToolCallRecord{
+			Step:       entry.Step,
+			Tool:       entry.Call.Name,
+			Arguments:  string(entry.Call.Arguments),
+			Output:     entry.Output,
+			Error:      entry.Error,
+			StartedAt:  entry.StartedAt,
+			DurationMs: entry.Duration.Milliseconds(),
+		})
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	stored, err := s.storedIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	s.recordAnalysis(stored, &models.AIAnalysis{
+		Summary:            analysis.Summary,
+		Findings:           analysis.Findings,
+		RootCauses:         analysis.RootCauses,
+		RecommendedActions: analysis.RecommendedActions,
+		SeveritySuggestion: models.Severity(analysis.SuggestedSeverity),
+		GeneratedAt:        s.now(),
+		Model:              s.aiClient.Model(),
+		Provider:           string(s.aiClient.Provider()),
+		ToolTrace:          trace,
+		Steps:              analysis.Steps,
+		TokensUsed:         analysis.TokensUsed,
+		StopReason:         analysis.StopReason,
+		PromptVersion:      ai.ToolPromptVersion,
+		InputsHash: inputsHash(ai.AnalysisRequest{
+			IncidentTitle: incident.Title,
+			IncidentDesc:  incident.Description,
+			Logs:          incident.Logs,
+			PromptVersion: ai.ToolPromptVersion,
+		}),
+	})
+	stored.UpdatedAt = s.now()
+	s.publish(models.EventAnalysisCompleted, stored, "")
+
+	s.logger.Info("incident analyzed with tools",
+		zap.String("id", id),
+		zap.Int("steps", analysis.Steps),
+		zap.Int("tool_calls", len(trace)),
+		zap.String("stop_reason", analysis.StopReason),
+	)
+	return snapshotIncident(stored), nil
+}
+
+// buildToolRegistry registers the incident data tools for an analysis of incidentID
+func (s *IncidentService) buildToolRegistry(incidentID string) *ai.ToolRegistry {
+	registry := ai.NewToolRegistry()
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "search_incidents",
+		Description: "Search other incidents by keywords in title, description, source and tags. Returns the best matches, most relevant first.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query":  map[string]interface{}{"type": "string", "description": "Space separated keywords"},
+				"status": map[string]interface{}{"type": "string", "description": "Optional status filter (open, in_progress, resolved, closed)"},
+				"limit":  map[string]interface{}{"type": "integer", "description": "Maximum results (default 10)"},
+			},
+			"required": []string{"query"},
+		},
+	}, func(ctx context.Context, args json.RawMessage) (string, error) {
+		var p struct {
+			Query  string `json:"query"`
+			Status string `json:"status"`
+			Limit  int    `json:"limit"`
+		}
+		if err := json.Unmarshal(args, &p); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		return toJSON(s.searchIncidents(incidentID, p.Query, models.IncidentStatus(p.Status), p.Limit))
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_incident_events",
+		Description: "Fetch an incident's status, severity, assignee and timeline of events. Defaults to the incident under analysis.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"incident_id": map[string]interface{}{"type": "string", "description": "Incident ID (optional)"},
+			},
+		},
+	}, func(ctx context.Context, args json.RawMessage) (string, error) {
+		var p struct {
+			IncidentID string `json:"incident_id"`
+		}
+		if err := json.Unmarshal(args, &p); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		if p.IncidentID == "" {
+			p.IncidentID = incidentID
+		}
+		return s.incidentEvents(p.IncidentID)
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "grep_logs",
+		Description: "Search an incident's logs with a case-insensitive regular expression. Returns matching lines with their line numbers.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"pattern":     map[string]interface{}{"type": "string", "description": "Regular expression"},
+				"incident_id": map[string]interface{}{"type": "string", "description": "Incident ID (optional, defaults to the incident under analysis)"},
+				"max_results": map[string]interface{}{"type": "integer", "description": "Maximum matching lines (default 50)"},
+			},
+			"required": []string{"pattern"},
+		},
+	}, func(ctx context.Context, args json.RawMessage) (string, error) {
+		var p struct {
+			Pattern    string `json:"pattern"`
+			IncidentID string `json:"incident_id"`
+			MaxResults int    `json:"max_results"`
+		}
+		if err := json.Unmarshal(args, &p); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		if p.IncidentID == "" {
+			p.IncidentID = incidentID
+		}
+		return s.grepLogs(p.IncidentID, p.Pattern, p.MaxResults)
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "list_recent_deploys",
+		Description: "List deploys in the hours before now, optionally for a single service.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service": map[string]interface{}{"type": "string", "description": "Service name (optional)"},
+				"hours":   map[string]interface{}{"type": "integer", "description": "Look-back window in hours (default 24)"},
+			},
+		},
+	}, func(ctx context.Context, args json.RawMessage) (string, error) {
+		var p struct {
+			Service string `json:"service"`
+			Hours   int    `json:"hours"`
+		}
+		if err := json.Unmarshal(args, &p); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		if p.Hours <= 0 {
+			p.Hours = 24
+		}
+		since := time.Now().Add(-time.Duration(p.Hours) * time.Hour)
+		// Without an external deploy source, fall back to change events posted to this service
+		if s.deploySource == nil {
+			return toJSON(s.recordedDeploys(p.Service, since))
+		}
+		deploys, err := s.deploySource.RecentDeploys(ctx, p.Service, since)
+		if err != nil {
+			return "", err
+		}
+		return toJSON(deploys)
+	})
+
+	return registry
+}
+
+// incidentMatch is a search_incidents result
+type incidentMatch struct {
+	ID        string                `json:"id"`
+	Title     string                `json:"title"`
+	Status    models.IncidentStatus `json:"status"`
+	Severity  models.Severity       `json:"severity"`
+	CreatedAt time.Time             `json:"created_at"`
+	score     int
+}
+
+// searchIncidents ranks incidents other than excludeID by the number of query terms they contain
+func (s *IncidentService) searchIncidents(excludeID, query string, status models.IncidentStatus, limit int) []incidentMatch {
+	if limit <= 0 {
+		limit = 10
+	}
+	terms := strings.Fields(strings.ToLower(query))
+
+	s.store.mu.RLock()
+	var matches []incidentMatch
+	for _, incident := range s.store.incidents {
+		if incident.ID == excludeID || (status != "" && incident.Status != status) {
+			continue
+		}
+		haystack := strings.ToLower(strings.Join(append([]string{incident.Title, incident.Description, incident.Source}, incident.Tags...), " "))
+		score := 0
+		for _, term := range terms {
+			if strings.Contains(haystack, term) {
+				score++
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		matches = append(matches, incidentMatch{
+			ID:        incident.ID,
+			Title:     incident.Title,
+			Status:    incident.Status,
+			Severity:  incident.Severity,
+			CreatedAt: incident.CreatedAt,
+			score:     score,
+		})
+	}
+	s.store.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].CreatedAt.After(matches[j].CreatedAt)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// incidentEvents renders an incident's state and timeline for the model
+func (s *IncidentService) incidentEvents(id string) (string, error) {
+	incident, err := s.GetIncident(id)
+	if err != nil {
+		return "", err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	return toJSON(map[string]interface{}{
+		"id":          incident.ID,
+		"title":       incident.Title,
+		"status":      incident.Status,
+		"severity":    incident.Severity,
+		"assigned_to": incident.AssignedTo,
+		"log_lines":   len(incident.Logs),
+		"timeline":    buildTimeline(incident, s.store.comments[incident.ID], s.correlateChanges(incident)),
+	})
+}
+
+// grepLogs returns the incident log lines matching pattern, prefixed with their line numbers
+func (s *IncidentService) grepLogs(id, pattern string, maxResults int) (string, error) {
+	incident, err := s.GetIncident(id)
+	if err != nil {
+		return "", err
+	}
+
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern: %w", err)
+	}
+	if maxResults <= 0 {
+		maxResults = 50
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	var b strings.Builder
+	matched := 0
+	for i, line := range incident.Logs {
+		if !re.MatchString(line) {
+			continue
+		}
+		if matched == maxResults {
+			fmt.Fprintf(&b, "... more matches truncated\n")
+			break
+		}
+		fmt.Fprintf(&b, "%d: %s\n", i+1, line)
+		matched++
+	}
+	if matched == 0 {
+		return fmt.Sprintf("no matches in %d log lines", len(incident.Logs)), nil
+	}
+	return b.String(), nil
+}
+
+// toJSON marshals a tool result
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/ai"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"go.uber.org/zap"
+)
+
+type stubDeploySource struct {
+	deploys []models.Deployment
+}
+
+func (d *stubDeploySource) RecentDeploys(ctx context.Context, service string, since time.Time) ([]models.Deployment, error) {
+	return d.deploys, nil
+}
+
+func TestAnalyzeIncidentWithToolsRecordsTrace(t *testing.T) {
+	mockAI := &MockAIClient{
+		toolCalls: []ai.ToolCall{
+			{ID: "1", Name: "grep_logs", Arguments: json.RawMessage(`{"pattern":"oomkilled"}`)},
+			{ID: "2", Name: "search_incidents", Arguments: json.RawMessage(`{"query":"checkout memory"}`)},
+			{ID: "3", Name: "list_recent_deploys", Arguments: json.RawMessage(`{"service":"checkout"}`)},
+			{ID: "4", Name: "drop_database", Arguments: json.RawMessage(`{}`)},
+		},
+	}
+	deploys := &stubDeploySource{deploys: []models.Deployment{{Service: "checkout", Version: "v1.4.2", DeployedAt: time.Now()}}}
+	service := NewIncidentService(NewIncidentStore(), mockAI, zap.NewNop(), WithDeploySource(deploys))
+
+	previous, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout memory leak",
+		Description: "Heap grew unbounded",
+	})
+	created, _ := service.CreateIncident(&models.CreateIncidentRequest{
+		Title:       "Checkout pods restarting",
+		Description: "Crash loop",
+		Logs:        []string{"starting checkout", "container checkout OOMKilled", "back-off restarting"},
+	})
+
+	analyzed, err := service.AnalyzeIncidentWithTools(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trace := analyzed.AIAnalysis.ToolTrace
+	if len(trace) != 4 {
+		t.Fatalf("expected 4 tool calls in trace, got %d", len(trace))
+	}
+	if !strings.Contains(trace[0].Output, "2: container checkout OOMKilled") {
+		t.Errorf("expected grep_logs to match line 2, got %q", trace[0].Output)
+	}
+	if !strings.Contains(trace[1].Output, previous.ID) || strings.Contains(trace[1].Output, created.ID) {
+		t.Errorf("expected search_incidents to return only the related incident, got %q", trace[1].Output)
+	}
+	if !strings.Contains(trace[2].Output, "v1.4.2") {
+		t.Errorf("expected list_recent_deploys to include v1.4.2, got %q", trace[2].Output)
+	}
+	if trace[3].Error == "" {
+		t.Error("expected an error for an unknown tool")
+	}
+	if analyzed.AIAnalysis.Summary != "Test analysis summary" {
+		t.Errorf("expected summary 'Test analysis summary', got %q", analyzed.AIAnalysis.Summary)
+	}
+}
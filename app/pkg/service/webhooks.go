@@ -0,0 +1,471 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/notify"
+	"go.uber.org/zap"
+)
+
+// Defaults for webhook delivery retries; the backoff doubles after each failed attempt
+const (
+	DefaultWebhookAttempts = 5
+	DefaultWebhookBackoff  = 5 * time.Second
+)
+
+// WebhookDeliveryHeader carries the delivery ID so receivers can drop duplicates
+const WebhookDeliveryHeader = "X-Webhook-Delivery"
+
+const (
+	webhookTimeout = 10 * time.Second
+	// webhookWorkers deliver from a queue of webhookQueueSize; deliveries that do not fit fail straight away
+	webhookWorkers   = 4
+	webhookQueueSize = 256
+	// maxWebhookDeliveries bounds the delivery log kept per subscription; the oldest are dropped first
+	maxWebhookDeliveries = 200
+)
+
+var (
+	// ErrSubscriptionNotFound is returned when a webhook subscription ID does not exist
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrInvalidSubscription is returned for subscriptions without a valid URL or with unknown event types
+	ErrInvalidSubscription = errors.New("invalid webhook subscription")
+	// ErrDeliveryNotFound is returned when a webhook delivery ID does not exist
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// WithWebhookRetry sets how many times a webhook delivery is attempted and the backoff before the first retry
+func WithWebhookRetry(attempts int, backoff time.Duration) ServiceOption {
+	return func(s *IncidentService) {
+		s.webhookAttempts = attempts
+		s.webhookBackoff = backoff
+	}
+}
+
+// CreateSubscription registers a webhook. The response is the only time a generated secret is returned.
+func (s *IncidentService) CreateSubscription(req *models.CreateSubscriptionRequest) (*models.WebhookSubscription, error) {
+	if err := validateSubscription(req.URL, req.Events); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = generateSecret()
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	s.store.webhookCounter++
+	now := time.Now()
+	subscription := &models.WebhookSubscription{
+		ID:          fmt.Sprintf("SUB-%d", s.store.webhookCounter),
+		URL:         req.URL,
+		Description: req.Description,
+		Events:      req.Events,
+		Secret:      secret,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.store.subscriptions[subscription.ID] = subscription
+
+	s.logger.Info("webhook subscription created", zap.String("id", subscription.ID), zap.String("url", subscription.URL))
+	return copySubscription(subscription, true), nil
+}
+
+// ListSubscriptions returns every webhook subscription without its secret, oldest first
+func (s *IncidentService) ListSubscriptions() []*models.WebhookSubscription {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	subscriptions := make([]*models.WebhookSubscription, 0, len(s.store.subscriptions))
+	for _, subscription := range s.store.subscriptions {
+		subscriptions = append(subscriptions, copySubscription(subscription, false))
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt) ||
+			subscriptions[i].CreatedAt.Equal(subscriptions[j].CreatedAt) && subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions
+}
+
+// GetSubscription returns a webhook subscription without its secret
+func (s *IncidentService) GetSubscription(id string) (*models.WebhookSubscription, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	subscription, ok := s.store.subscriptions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+	return copySubscription(subscription, false), nil
+}
+
+// UpdateSubscription changes a webhook subscription. Rotating the secret returns the new one.
+func (s *IncidentService) UpdateSubscription(id string, req *models.UpdateSubscriptionRequest) (*models.WebhookSubscription, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	subscription, ok := s.store.subscriptions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+
+	target, events := subscription.URL, subscription.Events
+	if req.URL != nil {
+		target = *req.URL
+	}
+	if req.Events != nil {
+		events = req.Events
+	}
+	if err := validateSubscription(target, events); err != nil {
+		return nil, err
+	}
+
+	subscription.URL = target
+	subscription.Events = events
+	if req.Description != nil {
+		subscription.Description = *req.Description
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+	if req.RotateSecret {
+		subscription.Secret = generateSecret()
+	}
+	subscription.UpdatedAt = time.Now()
+
+	return copySubscription(subscription, req.RotateSecret), nil
+}
+
+// DeleteSubscription removes a webhook subscription and its delivery log; retries in flight are abandoned
+func (s *IncidentService) DeleteSubscription(id string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, ok := s.store.subscriptions[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+	delete(s.store.subscriptions, id)
+	delete(s.store.webhookDeliveries, id)
+	return nil
+}
+
+// ListDeliveries returns a subscription's deliveries, newest first, optionally filtered by status
+func (s *IncidentService) ListDeliveries(subscriptionID string, status *models.WebhookDeliveryStatus) ([]*models.WebhookDelivery, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	if _, ok := s.store.subscriptions[subscriptionID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSubscriptionNotFound, subscriptionID)
+	}
+	deliveries := s.store.webhookDeliveries[subscriptionID]
+	results := make([]*models.WebhookDelivery, 0, len(deliveries))
+	for i := len(deliveries) - 1; i >= 0; i-- {
+		if status == nil || deliveries[i].Status == *status {
+			results = append(results, copyDelivery(deliveries[i]))
+		}
+	}
+	return results, nil
+}
+
+// GetDelivery returns a webhook delivery with its attempts and payload
+func (s *IncidentService) GetDelivery(id string) (*models.WebhookDelivery, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	delivery := s.findDelivery(id)
+	if delivery == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	return copyDelivery(delivery), nil
+}
+
+// Redeliver sends a delivery's payload again as a new delivery, signed with a fresh timestamp
+func (s *IncidentService) Redeliver(id string) (*models.WebhookDelivery, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	original := s.findDelivery(id)
+	if original == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	delivery := s.queueDelivery(s.store.subscriptions[original.SubscriptionID], models.WebhookDelivery{
+		EventID:      original.EventID,
+		EventType:    original.EventType,
+		IncidentID:   original.IncidentID,
+		RedeliveryOf: original.ID,
+		Payload:      original.Payload,
+	})
+	return copyDelivery(delivery), nil
+}
+
+// WaitForWebhooks blocks until queued webhook deliveries have succeeded or run out of attempts
+func (s *IncidentService) WaitForWebhooks() {
+	s.webhooks.Wait()
+}
+
+// StopWebhooks abandons queued deliveries and pending retries and waits for requests in flight;
+// call it on shutdown
+func (s *IncidentService) StopWebhooks() {
+	s.stopWebhooksOnce.Do(func() {
+		// queueDelivery checks webhookStop under the store lock, so nothing is sent on the closed queue
+		s.store.mu.Lock()
+		close(s.webhookStop)
+		close(s.webhookQueue)
+		s.store.mu.Unlock()
+	})
+	s.webhooks.Wait()
+}
+
+// queueWebhooks starts a delivery to every active subscription for the event; callers must hold s.store.mu
+func (s *IncidentService) queueWebhooks(event models.Event) {
+	var payload []byte
+	for _, subscription := range s.store.subscriptions {
+		if !subscription.Active || (len(subscription.Events) > 0 && !containsEventType(subscription.Events, event.Type)) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			if payload, err = json.Marshal(event); err != nil {
+				s.logger.Error("failed to encode webhook payload", zap.String("event", event.ID), zap.Error(err))
+				return
+			}
+		}
+		s.queueDelivery(subscription, models.WebhookDelivery{
+			EventID:    event.ID,
+			EventType:  event.Type,
+			IncidentID: event.Incident.ID,
+			Payload:    payload,
+		})
+	}
+}
+
+// queueDelivery records a pending delivery and hands it to the workers. It never blocks: when the
+// queue is full the delivery fails so it can be redelivered later. Callers must hold s.store.mu.
+func (s *IncidentService) queueDelivery(subscription *models.WebhookSubscription, delivery models.WebhookDelivery) *models.WebhookDelivery {
+	s.store.webhookCounter++
+	delivery.ID = fmt.Sprintf("WHD-%d", s.store.webhookCounter)
+	delivery.SubscriptionID = subscription.ID
+	delivery.Status = models.WebhookPending
+	delivery.CreatedAt = time.Now()
+
+	deliveries := append(s.store.webhookDeliveries[subscription.ID], &delivery)
+	if len(deliveries) > maxWebhookDeliveries {
+		deliveries = deliveries[len(deliveries)-maxWebhookDeliveries:]
+	}
+	s.store.webhookDeliveries[subscription.ID] = deliveries
+
+	select {
+	case <-s.webhookStop:
+		s.failDelivery(&delivery, "server is shutting down")
+		return &delivery
+	default:
+	}
+
+	s.startWebhooksOnce.Do(func() {
+		for i := 0; i < webhookWorkers; i++ {
+			go s.deliverWebhooks()
+		}
+	})
+	s.webhooks.Add(1)
+	select {
+	case s.webhookQueue <- &delivery:
+	default:
+		s.webhooks.Done()
+		s.failDelivery(&delivery, "delivery queue is full")
+	}
+	return &delivery
+}
+
+// failDelivery gives up on a delivery before any request is made; callers must hold s.store.mu
+func (s *IncidentService) failDelivery(delivery *models.WebhookDelivery, reason string) {
+	delivery.Status = models.WebhookFailed
+	delivery.Attempts = append(delivery.Attempts, models.WebhookAttempt{At: delivery.CreatedAt, Error: reason})
+	s.logger.Warn("webhook delivery failed",
+		zap.String("id", delivery.ID),
+		zap.String("subscription", delivery.SubscriptionID),
+		zap.String("error", reason),
+	)
+}
+
+// deliverWebhooks is a worker that sends queued deliveries until StopWebhooks closes the queue
+func (s *IncidentService) deliverWebhooks() {
+	for delivery := range s.webhookQueue {
+		s.deliverWebhook(delivery)
+		s.webhooks.Done()
+	}
+}
+
+// deliverWebhook attempts a delivery until it succeeds, runs out of attempts, or the subscription goes away
+func (s *IncidentService) deliverWebhook(delivery *models.WebhookDelivery) {
+	select {
+	case <-s.webhookStop:
+		s.finishDelivery(delivery, models.WebhookAttempt{At: time.Now(), Error: "server is shutting down"})
+		return
+	default:
+	}
+
+	attempts := s.webhookAttempts
+	if attempts <= 0 {
+		attempts = DefaultWebhookAttempts
+	}
+	backoff := s.webhookBackoff
+	if backoff <= 0 {
+		backoff = DefaultWebhookBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		s.store.mu.RLock()
+		subscription, ok := s.store.subscriptions[delivery.SubscriptionID]
+		var target, secret string
+		if ok && subscription.Active {
+			target, secret = subscription.URL, subscription.Secret
+		}
+		s.store.mu.RUnlock()
+		if target == "" {
+			s.finishDelivery(delivery, models.WebhookAttempt{At: time.Now(), Error: "subscription was deleted or deactivated"})
+			return
+		}
+
+		result := s.sendWebhook(target, secret, delivery)
+		if result.Error == "" || attempt == attempts {
+			s.finishDelivery(delivery, result)
+			return
+		}
+
+		s.store.mu.Lock()
+		delivery.Attempts = append(delivery.Attempts, result)
+		s.store.mu.Unlock()
+
+		select {
+		case <-time.After(backoff << (attempt - 1)):
+		case <-s.webhookStop:
+			s.finishDelivery(delivery, models.WebhookAttempt{At: time.Now(), Error: "retries abandoned at shutdown"})
+			return
+		}
+	}
+}
+
+// finishDelivery records the last attempt and whether the delivery succeeded
+func (s *IncidentService) finishDelivery(delivery *models.WebhookDelivery, attempt models.WebhookAttempt) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	if attempt.Error == "" {
+		delivery.Status = models.WebhookSucceeded
+		delivery.DeliveredAt = &attempt.At
+		return
+	}
+	delivery.Status = models.WebhookFailed
+	s.logger.Warn("webhook delivery failed",
+		zap.String("id", delivery.ID),
+		zap.String("subscription", delivery.SubscriptionID),
+		zap.Int("attempts", len(delivery.Attempts)),
+		zap.String("error", attempt.Error),
+	)
+}
+
+// sendWebhook makes one signed request. Any 2xx response counts as delivered.
+func (s *IncidentService) sendWebhook(target, secret string, delivery *models.WebhookDelivery) models.WebhookAttempt {
+	start := time.Now()
+	attempt := models.WebhookAttempt{At: start}
+
+	timestamp := strconv.FormatInt(start.Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(notify.HeaderEvent, string(delivery.EventType))
+	req.Header.Set(notify.HeaderTimestamp, timestamp)
+	req.Header.Set(notify.HeaderSignature, notify.Sign(secret, timestamp, delivery.Payload))
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+// findDelivery looks a delivery up by ID; callers must hold s.store.mu
+func (s *IncidentService) findDelivery(id string) *models.WebhookDelivery {
+	for _, deliveries := range s.store.webhookDeliveries {
+		for _, delivery := range deliveries {
+			if delivery.ID == id {
+				return delivery
+			}
+		}
+	}
+	return nil
+}
+
+func validateSubscription(target string, events []models.EventType) error {
+	parsed, err := url.Parse(target)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidSubscription)
+	}
+	for _, event := range events {
+		if !event.Valid() {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidSubscription, event)
+		}
+	}
+	return nil
+}
+
+func containsEventType(events []models.EventType, t models.EventType) bool {
+	for _, event := range events {
+		if event == t {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSecret returns a random signing secret
+func generateSecret() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// copySubscription returns a copy that is safe to hand out, with the secret only when asked for
+func copySubscription(subscription *models.WebhookSubscription, withSecret bool) *models.WebhookSubscription {
+	c := *subscription
+	c.Events = append([]models.EventType(nil), subscription.Events...)
+	if !withSecret {
+		c.Secret = ""
+	}
+	return &c
+}
+
+func copyDelivery(delivery *models.WebhookDelivery) *models.WebhookDelivery {
+	c := *delivery
+	c.Attempts = append([]models.WebhookAttempt(nil), delivery.Attempts...)
+	return &c
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/Prakash-sa/terraform-aws/app/pkg/notify"
+	"go.uber.org/zap"
+)
+
+// webhookReceiver verifies signatures and fails the first failures requests
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	failures int
+	events   []string
+	invalid  int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := notify.Verify(r.secret, req.Header.Get(notify.HeaderTimestamp), body, req.Header.Get(notify.HeaderSignature), 5*time.Minute, time.Now())
+	if err != nil || req.Header.Get(WebhookDeliveryHeader) == "" {
+		r.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	r.events = append(r.events, req.Header.Get(notify.HeaderEvent))
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	receiver := &webhookReceiver{secret: "s3cret", failures: 1}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	// The resolved-only subscription gets its own receiver so the injected failure always hits the first subscription
+	resolvedServer := httptest.NewServer(&webhookReceiver{secret: "s3cret"})
+	defer resolvedServer.Close()
+
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithWebhookRetry(3, time.Millisecond))
+	all, err := service.CreateSubscription(&models.CreateSubscriptionRequest{URL: server.URL, Secret: "s3cret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resolvedOnly, _ := service.CreateSubscription(&models.CreateSubscriptionRequest{
+		URL: resolvedServer.URL, Secret: "s3cret", Events: []models.EventType{models.EventIncidentResolved},
+	})
+
+	incident, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+	resolved := models.StatusResolved
+	service.UpdateIncident(incident.ID, &models.UpdateIncidentRequest{Status: &resolved})
+	service.WaitForWebhooks()
+
+	if receiver.invalid != 0 {
+		t.Errorf("expected every delivery to be signed, %d were rejected", receiver.invalid)
+	}
+
+	deliveries, _ := service.ListDeliveries(all.ID, nil)
+	if len(deliveries) != 3 {
+		t.Fatalf("expected created, updated and resolved deliveries, got %d", len(deliveries))
+	}
+	var retried *models.WebhookDelivery
+	for _, delivery := range deliveries {
+		if delivery.Status != models.WebhookSucceeded {
+			t.Errorf("expected %s to succeed, got %+v", delivery.EventType, delivery)
+		}
+		if len(delivery.Attempts) == 2 {
+			retried = delivery
+		}
+	}
+	if retried == nil || retried.Attempts[0].StatusCode != http.StatusInternalServerError || retried.Attempts[1].StatusCode != http.StatusNoContent {
+		t.Errorf("expected one delivery to succeed after a 500, got %+v", retried)
+	}
+
+	filtered, _ := service.ListDeliveries(resolvedOnly.ID, nil)
+	if len(filtered) != 1 || filtered[0].EventType != models.EventIncidentResolved || filtered[0].IncidentID != incident.ID {
+		t.Errorf("expected only the resolved event, got %+v", filtered)
+	}
+
+	redelivered, err := service.Redeliver(filtered[0].ID)
+	if err != nil || redelivered.RedeliveryOf != filtered[0].ID || redelivered.ID == filtered[0].ID {
+		t.Fatalf("expected a new delivery repeating the original, got %+v (%v)", redelivered, err)
+	}
+	service.WaitForWebhooks()
+	if got, _ := service.GetDelivery(redelivered.ID); got.Status != models.WebhookSucceeded || string(got.Payload) != string(filtered[0].Payload) {
+		t.Errorf("expected the redelivery to succeed with the same payload, got %+v", got)
+	}
+}
+
+func TestWebhookFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithWebhookRetry(2, time.Millisecond))
+	subscription, _ := service.CreateSubscription(&models.CreateSubscriptionRequest{URL: server.URL})
+	if len(subscription.Secret) < 32 {
+		t.Errorf("expected a generated secret, got %q", subscription.Secret)
+	}
+	if got, _ := service.GetSubscription(subscription.ID); got.Secret != "" {
+		t.Error("expected the secret to be hidden after creation")
+	}
+
+	service.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full", Description: "db-1"})
+	service.WaitForWebhooks()
+
+	failed := models.WebhookFailed
+	deliveries, _ := service.ListDeliveries(subscription.ID, &failed)
+	if len(deliveries) != 1 || len(deliveries[0].Attempts) != 2 || deliveries[0].Attempts[1].Error != "unexpected status 502" {
+		t.Fatalf("expected one failed delivery after 2 attempts, got %+v", deliveries)
+	}
+
+	inactive := false
+	rotated, _ := service.UpdateSubscription(subscription.ID, &models.UpdateSubscriptionRequest{Active: &inactive, RotateSecret: true})
+	if rotated.Secret == "" || rotated.Secret == subscription.Secret {
+		t.Error("expected rotation to return a new secret")
+	}
+	service.CreateIncident(&models.CreateIncidentRequest{Title: "Disk full again", Description: "db-2"})
+	service.WaitForWebhooks()
+	if all, _ := service.ListDeliveries(subscription.ID, nil); len(all) != 1 {
+		t.Errorf("expected an inactive subscription to get nothing, got %d deliveries", len(all))
+	}
+
+	service.StopWebhooks()
+	if redelivered, _ := service.Redeliver(deliveries[0].ID); redelivered.Status != models.WebhookFailed {
+		t.Errorf("expected redelivery after shutdown to fail immediately, got %+v", redelivered)
+	}
+}
+
+func TestWebhookQueueOverflow(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop(), WithWebhookRetry(1, time.Millisecond))
+	service.webhookQueue = make(chan *models.WebhookDelivery, 1)
+	subscription, _ := service.CreateSubscription(&models.CreateSubscriptionRequest{URL: server.URL})
+
+	// Every worker blocks on the receiver and the queue holds one more, so the rest overflow
+	for i := 0; i < webhookWorkers+5; i++ {
+		service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+	}
+	failed := models.WebhookFailed
+	overflowed, _ := service.ListDeliveries(subscription.ID, &failed)
+	if len(overflowed) < 4 {
+		t.Fatalf("expected deliveries beyond the queue to fail, got %d", len(overflowed))
+	}
+	for _, delivery := range overflowed {
+		if len(delivery.Attempts) != 1 || delivery.Attempts[0].Error != "delivery queue is full" {
+			t.Errorf("expected %s to fail without a request, got %+v", delivery.ID, delivery.Attempts)
+		}
+	}
+
+	close(release)
+	service.WaitForWebhooks()
+	redelivered, _ := service.Redeliver(overflowed[0].ID)
+	service.WaitForWebhooks()
+	if got, _ := service.GetDelivery(redelivered.ID); got.Status != models.WebhookSucceeded {
+		t.Errorf("expected an overflowed delivery to be redelivered once the queue drains, got %+v", got)
+	}
+	service.StopWebhooks()
+}
+
+func TestWebhookNumbering(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+	first, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+	subscription, _ := service.CreateSubscription(&models.CreateSubscriptionRequest{URL: server.URL})
+	second, _ := service.CreateIncident(&models.CreateIncidentRequest{Title: "Checkout errors", Description: "5xx"})
+	service.WaitForWebhooks()
+
+	// Subscriptions and deliveries are numbered on their own, so incident IDs stay consecutive
+	if subscription.ID != "SUB-1" || !strings.HasSuffix(first.ID, "-1") || !strings.HasSuffix(second.ID, "-2") {
+		t.Errorf("expected SUB-1 between incidents 1 and 2, got %s, %s and %s", first.ID, subscription.ID, second.ID)
+	}
+	if deliveries, _ := service.ListDeliveries(subscription.ID, nil); len(deliveries) != 1 || deliveries[0].ID != "WHD-2" {
+		t.Errorf("expected delivery WHD-2, got %+v", deliveries)
+	}
+	service.StopWebhooks()
+}
+
+func TestSubscriptionValidation(t *testing.T) {
+	service := NewIncidentService(NewIncidentStore(), &MockAIClient{}, zap.NewNop())
+
+	requests := []models.CreateSubscriptionRequest{
+		{URL: ""},
+		{URL: "ftp://example.com/hook"},
+		{URL: "/relative"},
+		{URL: "https://example.com/hook", Events: []models.EventType{"incident.deleted"}},
+	}
+	for _, req := range requests {
+		req := req
+		if _, err := service.CreateSubscription(&req); !errors.Is(err, ErrInvalidSubscription) {
+			t.Errorf("%+v: expected ErrInvalidSubscription, got %v", req, err)
+		}
+	}
+
+	subscription, _ := service.CreateSubscription(&models.CreateSubscriptionRequest{URL: "https://example.com/hook"})
+	bad := "not a url"
+	if _, err := service.UpdateSubscription(subscription.ID, &models.UpdateSubscriptionRequest{URL: &bad}); !errors.Is(err, ErrInvalidSubscription) {
+		t.Errorf("expected ErrInvalidSubscription, got %v", err)
+	}
+	if err := service.DeleteSubscription(subscription.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.ListDeliveries(subscription.ID, nil); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("expected ErrSubscriptionNotFound, got %v", err)
+	}
+	if _, err := service.Redeliver("WHD-0"); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// Response types: ephemeral messages are only shown to the user who ran the command
+const (
+	ResponseEphemeral = "ephemeral"
+	ResponseInChannel = "in_channel"
+)
+
+// Block is a Block Kit layout block
+type Block map[string]interface{}
+
+// Message is a reply to a slash command or interaction. Text is the notification fallback
+// when Blocks are set. ReplaceOriginal is always sent so interaction replies never overwrite
+// the message whose button was clicked.
+type Message struct {
+	ResponseType    string  `json:"response_type,omitempty"`
+	Text            string  `json:"text"`
+	Blocks          []Block `json:"blocks,omitempty"`
+	ThreadTS        string  `json:"thread_ts,omitempty"`
+	ReplaceOriginal bool    `json:"replace_original"`
+}
+
+// Ephemeral returns a plain message shown only to the user who ran the command
+func Ephemeral(format string, args ...interface{}) Message {
+	return Message{ResponseType: ResponseEphemeral, Text: fmt.Sprintf(format, args...)}
+}
+
+// IncidentMessage shows an incident with its AI summary and root cause when they exist, and buttons
+// for the actions still open to it. note is shown above the incident, for example who acknowledged it.
+func IncidentMessage(incident *models.Incident, link, note string) Message {
+	title := fmt.Sprintf("[%s] %s: %s", strings.ToUpper(string(incident.Severity)), incident.ID, incident.Title)
+	heading := "*" + escape(title) + "*"
+	if link != "" {
+		heading = fmt.Sprintf("*<%s|%s>*", link, escape(title))
+	}
+
+	var blocks []Block
+	if note != "" {
+		blocks = append(blocks, section(note))
+	}
+	assignee := incident.AssignedTo
+	if assignee == "" {
+		assignee = "unassigned"
+	}
+	blocks = append(blocks, Block{
+		"type": "section",
+		"text": text(heading),
+		"fields": []Block{
+			text("*Status*\n" + string(incident.Status)),
+			text("*Severity*\n" + string(incident.Severity)),
+			text("*Assignee*\n" + escape(assignee)),
+			text("*Service*\n" + escape(orNone(incident.Service))),
+		},
+	})
+	if incident.AIAnalysis != nil && incident.AIAnalysis.Summary != "" {
+		blocks = append(blocks, section("*AI summary*\n"+escape(incident.AIAnalysis.Summary)))
+	}
+	if incident.RCADocument != nil && incident.RCADocument.RootCause != "" {
+		blocks = append(blocks, section("*Root cause*\n"+escape(incident.RCADocument.RootCause)))
+	}
+	if actions := incidentActions(incident); len(actions) > 0 {
+		blocks = append(blocks, Block{"type": "actions", "block_id": "incident_actions", "elements": actions})
+	}
+
+	fallback := title
+	if note != "" {
+		fallback = note + "\n" + title
+	}
+	return Message{ResponseType: ResponseInChannel, Text: fallback, Blocks: blocks}
+}
+
+// HelpMessage lists the slash command's subcommands
+func HelpMessage(command string) Message {
+	if command == "" {
+		command = "/incident"
+	}
+	lines := []string{
+		"`" + command + " create [critical|high|medium|low] <title>` opens an incident",
+		"`" + command + " show <id>` shows an incident",
+		"`" + command + " ack <id>` acknowledges an incident and assigns it to you",
+		"`" + command + " resolve <id>` resolves an incident",
+		"`" + command + " escalate <id>` pages the next escalation level now",
+		"`" + command + " analyze <id>` runs AI analysis and posts the summary",
+		"`" + command + " rca <id>` generates a root cause analysis",
+	}
+	return Message{ResponseType: ResponseEphemeral, Text: strings.Join(lines, "\n")}
+}
+
+// incidentActions returns buttons for the actions an incident still allows
+func incidentActions(incident *models.Incident) []Block {
+	if incident.Status == models.StatusResolved || incident.Status == models.StatusClosed {
+		if incident.RCADocument == nil {
+			return []Block{button(ActionRCA, "Run RCA", incident.ID, "")}
+		}
+		return nil
+	}
+	var actions []Block
+	if incident.AcknowledgedAt == nil {
+		actions = append(actions, button(ActionAcknowledge, "Acknowledge", incident.ID, "primary"))
+	}
+	actions = append(actions,
+		button(ActionResolve, "Resolve", incident.ID, ""),
+		button(ActionEscalate, "Escalate", incident.ID, "danger"),
+		button(ActionRCA, "Run RCA", incident.ID, ""),
+	)
+	return actions
+}
+
+func button(actionID, label, value, style string) Block {
+	b := Block{
+		"type":      "button",
+		"action_id": actionID,
+		"text":      Block{"type": "plain_text", "text": label},
+		"value":     value,
+	}
+	if style != "" {
+		b["style"] = style
+	}
+	return b
+}
+
+func section(markdown string) Block {
+	return Block{"type": "section", "text": text(markdown)}
+}
+
+func text(markdown string) Block {
+	return Block{"type": "mrkdwn", "text": markdown}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// escape replaces the characters Slack treats as control sequences in mrkdwn
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
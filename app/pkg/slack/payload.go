@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Action IDs of the buttons on incident messages; each button's value is the incident ID
+const (
+	ActionAcknowledge = "incident_ack"
+	ActionResolve     = "incident_resolve"
+	ActionEscalate    = "incident_escalate"
+	ActionRCA         = "incident_rca"
+)
+
+// User identifies the Slack user behind a command or interaction
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"username"`
+}
+
+// Command is a slash command invocation such as "/incident ack INC-1"
+type Command struct {
+	Command     string
+	Text        string
+	User        User
+	ChannelID   string
+	ResponseURL string
+}
+
+// ParseCommand reads a slash command from its form-encoded body
+func ParseCommand(form url.Values) Command {
+	return Command{
+		Command:     form.Get("command"),
+		Text:        strings.TrimSpace(form.Get("text")),
+		User:        User{ID: form.Get("user_id"), Name: form.Get("user_name")},
+		ChannelID:   form.Get("channel_id"),
+		ResponseURL: form.Get("response_url"),
+	}
+}
+
+// Subcommand splits the command text into a lower-cased verb and its arguments
+func (c Command) Subcommand() (string, []string) {
+	fields := strings.Fields(c.Text)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), fields[1:]
+}
+
+// Action is a button clicked on an interactive message
+type Action struct {
+	ActionID string `json:"action_id"`
+	Value    string `json:"value"`
+}
+
+// Interaction is a block_actions payload sent when a user clicks a button
+type Interaction struct {
+	Type    string `json:"type"`
+	User    User   `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		TS       string `json:"ts"`
+		ThreadTS string `json:"thread_ts"`
+	} `json:"message"`
+	ResponseURL string   `json:"response_url"`
+	Actions     []Action `json:"actions"`
+}
+
+// ThreadTS returns the timestamp replies should thread under: the thread the clicked message
+// belongs to, or the message itself
+func (i *Interaction) ThreadTS() string {
+	if i.Message.ThreadTS != "" {
+		return i.Message.ThreadTS
+	}
+	return i.Message.TS
+}
+
+// ParseInteraction reads an interaction from the JSON "payload" field of its form-encoded body
+func ParseInteraction(form url.Values) (*Interaction, error) {
+	payload := form.Get("payload")
+	if payload == "" {
+		return nil, fmt.Errorf("%w: payload is required", ErrInvalidPayload)
+	}
+	var interaction Interaction
+	if err := json.Unmarshal([]byte(payload), &interaction); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return &interaction, nil
+}
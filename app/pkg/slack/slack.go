@@ -0,0 +1,203 @@
+// Package slack serves a Slack app: signed slash commands and interactive messages for incidents.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers Slack signs requests with
+const (
+	HeaderTimestamp = "X-Slack-Request-Timestamp"
+	HeaderSignature = "X-Slack-Signature"
+)
+
+const (
+	// signatureVersion prefixes the signed string and the signature, as Slack's v0 scheme requires
+	signatureVersion = "v0"
+	// maxSkew rejects requests signed longer ago than Slack recommends, so captured requests cannot be replayed
+	maxSkew = 5 * time.Minute
+	// respondTimeout bounds a single post to a response_url
+	respondTimeout = 10 * time.Second
+)
+
+var (
+	// ErrInvalidSignature is returned when a request is unsigned or its signature does not match the body
+	ErrInvalidSignature = errors.New("invalid slack signature")
+	// ErrStaleTimestamp is returned when a request was signed outside the allowed clock skew
+	ErrStaleTimestamp = errors.New("slack request timestamp outside tolerance")
+	// ErrInvalidPayload is returned for interaction payloads that cannot be read
+	ErrInvalidPayload = errors.New("invalid slack payload")
+)
+
+// Config configures the Slack app. Users maps Slack user IDs to the names incidents are assigned to;
+// users without an entry are assigned by their Slack username. BaseURL is used to link to incidents.
+type Config struct {
+	SigningSecret string            `json:"signing_secret"`
+	Users         map[string]string `json:"users,omitempty"`
+	BaseURL       string            `json:"base_url,omitempty"`
+}
+
+// LoadConfig reads a JSON Slack configuration. Environment variables in the signing secret are
+// expanded so it can stay out of the file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read slack config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid slack config %s: %w", path, err)
+	}
+	cfg.SigningSecret = os.ExpandEnv(cfg.SigningSecret)
+	return cfg, nil
+}
+
+// Option configures an App
+type Option func(*App)
+
+// WithClock replaces time.Now when checking request timestamps, so signed fixtures can be replayed
+func WithClock(clock func() time.Time) Option {
+	return func(a *App) {
+		a.now = clock
+	}
+}
+
+// WithHTTPClient replaces the client used to post to response URLs
+func WithHTTPClient(client *http.Client) Option {
+	return func(a *App) {
+		a.client = client
+	}
+}
+
+// App verifies requests from Slack and posts replies to their response URLs
+type App struct {
+	signingSecret string
+	users         map[string]string
+	baseURL       string
+	client        *http.Client
+	now           func() time.Time
+	// pending tracks work that replies after the request has been answered
+	pending sync.WaitGroup
+}
+
+// NewApp creates a Slack app; a signing secret is required so forged requests are rejected
+func NewApp(cfg Config, opts ...Option) (*App, error) {
+	if cfg.SigningSecret == "" {
+		return nil, fmt.Errorf("slack signing secret is required")
+	}
+	a := &App{
+		signingSecret: cfg.SigningSecret,
+		users:         cfg.Users,
+		baseURL:       strings.TrimRight(cfg.BaseURL, "/"),
+		client:        &http.Client{Timeout: respondTimeout},
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a, nil
+}
+
+// Sign returns the v0 signature Slack sends for a body at timestamp (Unix seconds):
+// "v0=" followed by the hex HMAC-SHA256 of "v0:<timestamp>:<body>" keyed with the signing secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signatureVersion + ":" + timestamp + ":"))
+	mac.Write(body)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature headers of a request against its raw body
+func (a *App) Verify(header http.Header, body []byte) error {
+	timestamp := header.Get(HeaderTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := a.now().Sub(time.Unix(seconds, 0)); age > maxSkew || age < -maxSkew {
+		return ErrStaleTimestamp
+	}
+	if !hmac.Equal([]byte(Sign(a.signingSecret, timestamp, body)), []byte(header.Get(HeaderSignature))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Assignee returns the name incidents are assigned to for a Slack user
+func (a *App) Assignee(user User) string {
+	if name, ok := a.users[user.ID]; ok && name != "" {
+		return name
+	}
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.ID
+}
+
+// IncidentURL links to an incident, or returns "" without a base URL
+func (a *App) IncidentURL(id string) string {
+	if a.baseURL == "" {
+		return ""
+	}
+	return a.baseURL + "/api/v1/incidents/" + id
+}
+
+// Go runs fn after the request has been answered. Slack expects a response within three seconds,
+// so slow work such as AI analysis replies through the response URL instead.
+func (a *App) Go(fn func()) {
+	a.pending.Add(1)
+	go func() {
+		defer a.pending.Done()
+		fn()
+	}()
+}
+
+// Wait blocks until work started with Go has finished
+func (a *App) Wait() {
+	a.pending.Wait()
+}
+
+// Respond posts a message to a response URL from a slash command or interaction
+func (a *App) Respond(ctx context.Context, responseURL string, message Message) error {
+	if !strings.HasPrefix(responseURL, "https://") && !strings.HasPrefix(responseURL, "http://") {
+		return fmt.Errorf("invalid response url %q", responseURL)
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, respondTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1760781600, 0)
+	app, err := NewApp(Config{SigningSecret: "8f742231b10e8888abcd99yyyzzz85a5"}, WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := []byte("command=%2Fincident&text=help")
+
+	header := func(timestamp, signature string) http.Header {
+		h := http.Header{}
+		h.Set(HeaderTimestamp, timestamp)
+		h.Set(HeaderSignature, signature)
+		return h
+	}
+	fresh := "1760781600"
+	stale := "1760781000"
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		body     []byte
+		expected error
+	}{
+		{"valid", header(fresh, Sign("8f742231b10e8888abcd99yyyzzz85a5", fresh, body)), body, nil},
+		{"tampered body", header(fresh, Sign("8f742231b10e8888abcd99yyyzzz85a5", fresh, body)), []byte("command=%2Fincident&text=resolve"), ErrInvalidSignature},
+		{"wrong secret", header(fresh, Sign("other", fresh, body)), body, ErrInvalidSignature},
+		{"stale", header(stale, Sign("8f742231b10e8888abcd99yyyzzz85a5", stale, body)), body, ErrStaleTimestamp},
+		{"unsigned", http.Header{}, body, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		if err := app.Verify(tt.header, tt.body); !errors.Is(err, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+		}
+	}
+
+	if _, err := NewApp(Config{}); err == nil {
+		t.Error("expected an error without a signing secret")
+	}
+}
+
+func TestParse(t *testing.T) {
+	form, _ := url.ParseQuery("command=%2Fincident&text=ACK+INC-1++&user_id=U01&user_name=priya&channel_id=C01&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2F1")
+	cmd := ParseCommand(form)
+	verb, args := cmd.Subcommand()
+	if verb != "ack" || len(args) != 1 || args[0] != "INC-1" || cmd.User.ID != "U01" || cmd.ResponseURL != "https://hooks.slack.com/commands/1" {
+		t.Errorf("unexpected command: %+v %q %v", cmd, verb, args)
+	}
+
+	payload := `{"type":"block_actions","user":{"id":"U01","username":"priya"},"message":{"ts":"2.0","thread_ts":"1.0"},` +
+		`"response_url":"https://hooks.slack.com/actions/1","actions":[{"action_id":"incident_resolve","value":"INC-1"}]}`
+	interaction, err := ParseInteraction(url.Values{"payload": {payload}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if interaction.ThreadTS() != "1.0" || interaction.Actions[0].ActionID != ActionResolve || interaction.User.Name != "priya" {
+		t.Errorf("unexpected interaction: %+v", interaction)
+	}
+	if _, err := ParseInteraction(url.Values{"payload": {"{"}}); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("expected ErrInvalidPayload, got %v", err)
+	}
+}
+
+func TestRespond(t *testing.T) {
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	app, _ := NewApp(Config{SigningSecret: "s3cret", BaseURL: "https://incidents.example.com/", Users: map[string]string{"U01": "alice"}})
+	if got := app.Assignee(User{ID: "U01", Name: "al"}); got != "alice" {
+		t.Errorf("expected the mapped assignee, got %q", got)
+	}
+	if got := app.Assignee(User{ID: "U02", Name: "bob"}); got != "bob" {
+		t.Errorf("expected the slack username, got %q", got)
+	}
+
+	incident := &models.Incident{
+		ID: "INC-1", Title: "Checkout <5xx>", Status: models.StatusOpen, Severity: models.SeverityHigh,
+		AIAnalysis: &models.AIAnalysis{Summary: "Connection pool exhausted"},
+	}
+	message := IncidentMessage(incident, app.IncidentURL(incident.ID), "<@U01> opened INC-1")
+	message.ThreadTS = "1.0"
+	if err := app.Respond(context.Background(), server.URL, message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blocks := fmt.Sprint(received.Blocks)
+	for _, want := range []string{"https://incidents.example.com/api/v1/incidents/INC-1", "Checkout &lt;5xx&gt;", "Connection pool exhausted", ActionAcknowledge} {
+		if !strings.Contains(blocks, want) {
+			t.Errorf("expected %q in the blocks, got %s", want, blocks)
+		}
+	}
+	if received.ThreadTS != "1.0" || received.ResponseType != ResponseInChannel || received.ReplaceOriginal {
+		t.Errorf("expected a threaded in-channel reply, got %+v", received)
+	}
+}
@@ -0,0 +1,278 @@
+// Package statuspage loads status page configuration, renders status updates as RSS and Atom
+// feeds, and scrubs internal details from text meant for customers.
+package statuspage
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// DefaultTitle names the status page when the config does not
+const DefaultTitle = "System Status"
+
+// Config is the status page file format
+type Config struct {
+	Title string `json:"title,omitempty"`
+	// URL is where customers read the status page; feed links point to it
+	URL        string             `json:"url,omitempty"`
+	Components []models.Component `json:"components,omitempty"`
+}
+
+// LoadConfig reads a status page config file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status page config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse status page config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Format is a feed format
+type Format string
+
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+)
+
+// ErrUnsupportedFormat is returned for feed formats other than rss and atom
+var ErrUnsupportedFormat = errors.New("unsupported feed format")
+
+// ContentType returns the MIME type of a feed format
+func ContentType(f Format) string {
+	if f == FormatAtom {
+		return "application/atom+xml; charset=utf-8"
+	}
+	return "application/rss+xml; charset=utf-8"
+}
+
+// Entry is one status update in a feed. IncidentID is the public incident ID from the status page,
+// not the internal one.
+type Entry struct {
+	ID         string
+	IncidentID string
+	Title      string
+	State      models.StatusUpdateState
+	Message    string
+	At         time.Time
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link,omitempty"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// RenderFeed writes entries, newest first, as an RSS 2.0 or Atom 1.0 feed. updated is the feed's
+// last change, used when there are no entries.
+func RenderFeed(w io.Writer, format Format, cfg Config, entries []Entry, updated time.Time) error {
+	title := cfg.Title
+	if title == "" {
+		title = DefaultTitle
+	}
+	if len(entries) > 0 {
+		updated = entries[0].At
+	}
+
+	var feed interface{}
+	switch format {
+	case FormatRSS:
+		channel := rssChannel{
+			Title:         title,
+			Link:          cfg.URL,
+			Description:   "Incident updates from " + title,
+			LastBuildDate: updated.UTC().Format(time.RFC1123Z),
+		}
+		for _, e := range entries {
+			channel.Items = append(channel.Items, rssItem{
+				Title:       entryTitle(e),
+				Link:        incidentLink(cfg.URL, e.IncidentID),
+				Description: e.Message,
+				GUID:        rssGUID{Value: e.ID},
+				PubDate:     e.At.UTC().Format(time.RFC1123Z),
+			})
+		}
+		feed = rssFeed{Version: "2.0", Channel: channel}
+	case FormatAtom:
+		atom := atomFeed{
+			Title:   title,
+			ID:      feedID(cfg.URL, ""),
+			Updated: updated.UTC().Format(time.RFC3339),
+		}
+		if cfg.URL != "" {
+			atom.Link = &atomLink{Href: cfg.URL}
+		}
+		for _, e := range entries {
+			entry := atomEntry{
+				Title:   entryTitle(e),
+				ID:      feedID(cfg.URL, e.ID),
+				Updated: e.At.UTC().Format(time.RFC3339),
+				Content: atomContent{Type: "text", Value: e.Message},
+			}
+			if link := incidentLink(cfg.URL, e.IncidentID); link != "" {
+				entry.Link = &atomLink{Href: link}
+			}
+			atom.Entries = append(atom.Entries, entry)
+		}
+		feed = atom
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(feed)
+}
+
+func entryTitle(e Entry) string {
+	state := string(e.State)
+	if state != "" {
+		state = strings.ToUpper(state[:1]) + state[1:]
+	}
+	return e.Title + " - " + state
+}
+
+func incidentLink(base, incidentID string) string {
+	if base == "" {
+		return ""
+	}
+	return strings.TrimRight(base, "/") + "#" + incidentID
+}
+
+// feedID is a stable Atom ID: a link into the status page, or a URN when its URL is not configured
+func feedID(base, id string) string {
+	if base == "" {
+		if id == "" {
+			return "urn:status-page"
+		}
+		return "urn:status-page:" + id
+	}
+	if id == "" {
+		return base
+	}
+	return strings.TrimRight(base, "/") + "#" + id
+}
+
+// Redacted replaces internal details removed by Scrub, so a reviewer sees what to rewrite
+const Redacted = "[redacted]"
+
+var (
+	urlPattern   = regexp.MustCompile(`(?i)\b(?:https?|ftp)://[^\s)\]]+`)
+	emailPattern = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)
+	ipPattern    = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d+)?\b`)
+	// hostPattern matches names under domains that only resolve inside a network
+	hostPattern = regexp.MustCompile(`(?i)\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:internal|local|lan|corp|svc|cluster\.local)\b`)
+)
+
+// Scrub removes URLs, email addresses, IP addresses and internal hostnames from text, and every
+// whole-word, case-insensitive occurrence of terms such as internal service names and people. A term
+// followed by a hyphen starts an internal name such as payments-db, which is removed whole.
+// Terms shorter than three characters are ignored.
+func Scrub(text string, terms ...string) string {
+	for _, pattern := range []*regexp.Regexp{urlPattern, emailPattern, ipPattern, hostPattern} {
+		text = pattern.ReplaceAllString(text, Redacted)
+	}
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if len(term) < 3 {
+			continue
+		}
+		text = scrubTerm(text, regexp.MustCompile(`(?i)`+regexp.QuoteMeta(term)))
+	}
+	return text
+}
+
+// scrubTerm redacts matches of pattern that start and end on a word boundary. The boundaries are
+// checked around each match rather than matched, so adjacent occurrences are all found.
+func scrubTerm(text string, pattern *regexp.Regexp) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		if start < last || start > 0 && (isWordByte(text[start-1]) || text[start-1] == '-') {
+			continue
+		}
+		if end < len(text) && text[end] == '-' {
+			for end < len(text) && (isWordByte(text[end]) || text[end] == '-') {
+				end++
+			}
+		} else if end < len(text) && isWordByte(text[end]) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(Redacted)
+		last = end
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
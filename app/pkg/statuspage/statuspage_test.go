@@ -0,0 +1,99 @@
+package statuspage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+var testEntries = []Entry{
+	{ID: "SU-7", IncidentID: "SU-3", Title: "Checkout errors", State: models.StatusUpdateResolved, Message: "Resolved & monitored", At: time.Date(2024, 3, 11, 15, 0, 0, 0, time.UTC)},
+	{ID: "SU-3", IncidentID: "SU-3", Title: "Checkout errors", State: models.StatusUpdateInvestigating, Message: "Investigating", At: time.Date(2024, 3, 11, 14, 0, 0, 0, time.UTC)},
+}
+
+func TestRenderRSS(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := Config{Title: "Acme Status", URL: "https://status.acme.com/"}
+	if err := RenderFeed(&buf, FormatRSS, cfg, testEntries, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid RSS: %v\n%s", err, buf.String())
+	}
+	if feed.Version != "2.0" || feed.Channel.Title != "Acme Status" || len(feed.Channel.Items) != 2 {
+		t.Fatalf("unexpected feed %+v", feed)
+	}
+	item := feed.Channel.Items[0]
+	if item.Title != "Checkout errors - Resolved" || item.Description != "Resolved & monitored" || item.GUID.Value != "SU-7" ||
+		item.Link != "https://status.acme.com#SU-3" || item.PubDate != "Mon, 11 Mar 2024 15:00:00 +0000" {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if feed.Channel.LastBuildDate != item.PubDate {
+		t.Errorf("expected the feed to be as new as its newest item, got %s", feed.Channel.LastBuildDate)
+	}
+}
+
+func TestRenderAtom(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderFeed(&buf, FormatAtom, Config{}, testEntries, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid Atom: %v\n%s", err, buf.String())
+	}
+	if feed.Title != DefaultTitle || feed.ID != "urn:status-page" || feed.Updated != "2024-03-11T15:00:00Z" || len(feed.Entries) != 2 {
+		t.Fatalf("unexpected feed %+v", feed)
+	}
+	if entry := feed.Entries[1]; entry.ID != "urn:status-page:SU-3" || entry.Link != nil || entry.Content.Value != "Investigating" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	if err := RenderFeed(&buf, "json", Config{}, nil, time.Now()); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestScrub(t *testing.T) {
+	tests := []struct {
+		text     string
+		terms    []string
+		expected string
+	}{
+		{"Errors from https://grafana.acme.io/d/x?a=1 since 14:00", nil, "Errors from [redacted] since 14:00"},
+		{"Contact priya@acme.com", nil, "Contact [redacted]"},
+		{"db at 10.0.3.7:5432 failed over", nil, "db at [redacted] failed over"},
+		{"payments-db-0.payments.svc.cluster.local is down", nil, "[redacted] is down"},
+		{"Priya restarted payments-api; payments is fine", []string{"priya", "payments-api", "db"}, "[redacted] restarted [redacted]; payments is fine"},
+		{"Checkout is degraded in version 1.2.3", nil, "Checkout is degraded in version 1.2.3"},
+		{"payments payments", []string{"payments"}, "[redacted] [redacted]"},
+		{"Failover of payments-db, payments_v2 and pre-payments", []string{"payments"}, "Failover of [redacted], payments_v2 and pre-payments"},
+	}
+	for _, tt := range tests {
+		if got := Scrub(tt.text, tt.terms...); got != tt.expected {
+			t.Errorf("Scrub(%q) = %q, expected %q", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.json")
+	os.WriteFile(path, []byte(`{"title": "Acme Status", "components": [{"name": "Checkout", "services": ["checkout-api"]}]}`), 0o644)
+
+	cfg, err := LoadConfig(path)
+	if err != nil || cfg.Title != "Acme Status" || len(cfg.Components) != 1 || cfg.Components[0].Services[0] != "checkout-api" {
+		t.Fatalf("unexpected config %+v (%v)", cfg, err)
+	}
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,212 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// defaultGitHubURL is the GitHub.com API; GitHub Enterprise servers use https://<host>/api/v3
+const defaultGitHubURL = "https://api.github.com"
+
+// GitHub connects to GitHub Issues in one repository. Issue keys are "owner/name#number".
+// GitHub issues are only open or closed, so in-progress work shows as open.
+type GitHub struct {
+	name   string
+	repo   string
+	labels []string
+	secret string
+	api    *client
+}
+
+// NewGitHub creates a GitHub Issues connector
+func NewGitHub(cfg TrackerConfig) (*GitHub, error) {
+	if parts := strings.Split(cfg.Repo, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("tracker %s: repo must be owner/name", cfg.Name)
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("tracker %s: token is required", cfg.Name)
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultGitHubURL
+	}
+	token := cfg.Token
+	return &GitHub{
+		name:   cfg.Name,
+		repo:   cfg.Repo,
+		labels: cfg.Labels,
+		secret: cfg.WebhookSecret,
+		api: newClient(baseURL, func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Accept", "application/vnd.github+json")
+		}),
+	}, nil
+}
+
+// Name returns the configured tracker name
+func (g *GitHub) Name() string {
+	return g.name
+}
+
+type githubIssue struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+}
+
+type githubComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateIssue opens an issue in the configured repository
+func (g *GitHub) CreateIssue(ctx context.Context, issue models.NewTrackerIssue) (*models.TrackerIssue, error) {
+	payload := map[string]interface{}{"title": issue.Title, "body": issue.Body}
+	if labels := append(append([]string(nil), g.labels...), issue.Labels...); len(labels) > 0 {
+		payload["labels"] = labels
+	}
+	var created githubIssue
+	if err := g.api.do(ctx, http.MethodPost, "/repos/"+g.repo+"/issues", payload, &created); err != nil {
+		return nil, err
+	}
+	return g.toModel(created), nil
+}
+
+// GetIssue reads an issue's state and comments
+func (g *GitHub) GetIssue(ctx context.Context, key string) (*models.TrackerIssue, error) {
+	number, err := g.number(key)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d", g.repo, number)
+	var issue githubIssue
+	if err := g.api.do(ctx, http.MethodGet, path, nil, &issue); err != nil {
+		return nil, err
+	}
+	var comments []githubComment
+	if err := g.api.do(ctx, http.MethodGet, path+"/comments?per_page=100", nil, &comments); err != nil {
+		return nil, err
+	}
+
+	result := g.toModel(issue)
+	for _, comment := range comments {
+		result.Comments = append(result.Comments, comment.toModel())
+	}
+	return result, nil
+}
+
+// SetState closes an issue for done and reopens it otherwise
+func (g *GitHub) SetState(ctx context.Context, key string, state models.IssueState) error {
+	number, err := g.number(key)
+	if err != nil {
+		return err
+	}
+	ghState := "open"
+	if state == models.IssueDone {
+		ghState = "closed"
+	}
+	return g.api.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", g.repo, number), map[string]string{"state": ghState}, nil)
+}
+
+// Normalize folds in progress into open, since GitHub cannot tell them apart
+func (g *GitHub) Normalize(state models.IssueState) models.IssueState {
+	if state == models.IssueInProgress {
+		return models.IssueOpen
+	}
+	return state
+}
+
+// AddComment comments on an issue
+func (g *GitHub) AddComment(ctx context.Context, key, body string) (*models.TrackerComment, error) {
+	number, err := g.number(key)
+	if err != nil {
+		return nil, err
+	}
+	var comment githubComment
+	if err := g.api.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", g.repo, number), map[string]string{"body": body}, &comment); err != nil {
+		return nil, err
+	}
+	result := comment.toModel()
+	return &result, nil
+}
+
+// ParseWebhook reads issues (closed and reopened) and issue_comment (created) webhooks for the
+// configured repository, checking the X-Hub-Signature-256 header when a secret is set
+func (g *GitHub) ParseWebhook(header http.Header, body []byte) (*models.TrackerEvent, error) {
+	if err := verify(g.secret, body, header.Get("X-Hub-Signature-256")); err != nil {
+		return nil, err
+	}
+	event := header.Get("X-GitHub-Event")
+	if event != "issues" && event != "issue_comment" {
+		return nil, fmt.Errorf("%w: %s event", ErrIgnored, event)
+	}
+
+	var payload struct {
+		Action     string         `json:"action"`
+		Issue      *githubIssue   `json:"issue"`
+		Comment    *githubComment `json:"comment"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if payload.Issue == nil {
+		return nil, fmt.Errorf("%w: %s without an issue", ErrInvalidPayload, event)
+	}
+	if !strings.EqualFold(payload.Repository.FullName, g.repo) {
+		return nil, fmt.Errorf("%w: repository %s is not %s", ErrIgnored, payload.Repository.FullName, g.repo)
+	}
+	key := g.key(payload.Issue.Number)
+
+	switch {
+	case event == "issues" && (payload.Action == "closed" || payload.Action == "reopened"):
+		return &models.TrackerEvent{Key: key, State: githubState(payload.Issue.State)}, nil
+	case event == "issue_comment" && payload.Action == "created" && payload.Comment != nil:
+		comment := payload.Comment.toModel()
+		return &models.TrackerEvent{Key: key, Comment: &comment}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s %s", ErrIgnored, event, payload.Action)
+	}
+}
+
+func (g *GitHub) key(number int) string {
+	return g.repo + "#" + strconv.Itoa(number)
+}
+
+// number reads the issue number from a key made by key
+func (g *GitHub) number(key string) (int, error) {
+	repo, number, ok := strings.Cut(key, "#")
+	n, err := strconv.Atoi(number)
+	if !ok || err != nil || !strings.EqualFold(repo, g.repo) {
+		return 0, fmt.Errorf("%q is not an issue in %s", key, g.repo)
+	}
+	return n, nil
+}
+
+func (g *GitHub) toModel(issue githubIssue) *models.TrackerIssue {
+	return &models.TrackerIssue{Key: g.key(issue.Number), URL: issue.HTMLURL, State: githubState(issue.State)}
+}
+
+func (c githubComment) toModel() models.TrackerComment {
+	return models.TrackerComment{ID: strconv.FormatInt(c.ID, 10), Author: c.User.Login, Body: c.Body, CreatedAt: c.CreatedAt}
+}
+
+func githubState(state string) models.IssueState {
+	if state == "closed" {
+		return models.IssueDone
+	}
+	return models.IssueOpen
+}
@@ -0,0 +1,213 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// jiraTimeLayout is how Jira's REST API formats timestamps
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// Jira connects to the Jira REST API (v2), which takes plain-text descriptions and comments
+type Jira struct {
+	name      string
+	baseURL   string
+	project   string
+	issueType string
+	labels    []string
+	secret    string
+	api       *client
+}
+
+// NewJira creates a Jira connector
+func NewJira(cfg TrackerConfig) (*Jira, error) {
+	if cfg.BaseURL == "" || cfg.Project == "" || cfg.Token == "" {
+		return nil, fmt.Errorf("tracker %s: base_url, project and token are required", cfg.Name)
+	}
+	issueType := cfg.IssueType
+	if issueType == "" {
+		issueType = "Task"
+	}
+	email, token := cfg.Email, cfg.Token
+	api := newClient(cfg.BaseURL, func(req *http.Request) {
+		if email != "" {
+			req.SetBasicAuth(email, token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	})
+	return &Jira{
+		name:      cfg.Name,
+		baseURL:   api.baseURL,
+		project:   cfg.Project,
+		issueType: issueType,
+		labels:    cfg.Labels,
+		secret:    cfg.WebhookSecret,
+		api:       api,
+	}, nil
+}
+
+// Name returns the configured tracker name
+func (j *Jira) Name() string {
+	return j.name
+}
+
+// jiraStatus is the part of a Jira status used for sync; the status category is the same on every workflow
+type jiraStatus struct {
+	StatusCategory struct {
+		Key string `json:"key"`
+	} `json:"statusCategory"`
+}
+
+type jiraComment struct {
+	ID     string `json:"id"`
+	Body   string `json:"body"`
+	Author struct {
+		DisplayName string `json:"displayName"`
+	} `json:"author"`
+	Created string `json:"created"`
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Status  jiraStatus `json:"status"`
+		Comment struct {
+			Comments []jiraComment `json:"comments"`
+		} `json:"comment"`
+	} `json:"fields"`
+}
+
+// CreateIssue opens an issue in the configured project
+func (j *Jira) CreateIssue(ctx context.Context, issue models.NewTrackerIssue) (*models.TrackerIssue, error) {
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": j.project},
+		"issuetype":   map[string]string{"name": j.issueType},
+		"summary":     issue.Title,
+		"description": issue.Body,
+	}
+	if labels := append(append([]string(nil), j.labels...), issue.Labels...); len(labels) > 0 {
+		fields["labels"] = labels
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.api.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created); err != nil {
+		return nil, err
+	}
+	return &models.TrackerIssue{Key: created.Key, URL: j.issueURL(created.Key), State: models.IssueOpen}, nil
+}
+
+// GetIssue reads an issue's status category and comments
+func (j *Jira) GetIssue(ctx context.Context, key string) (*models.TrackerIssue, error) {
+	var issue jiraIssue
+	if err := j.api.do(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"?fields=status,comment", nil, &issue); err != nil {
+		return nil, err
+	}
+	result := &models.TrackerIssue{Key: issue.Key, URL: j.issueURL(issue.Key), State: jiraState(issue.Fields.Status)}
+	for _, comment := range issue.Fields.Comment.Comments {
+		result.Comments = append(result.Comments, comment.toModel())
+	}
+	return result, nil
+}
+
+// SetState moves an issue through the first workflow transition that ends in the state's status category
+func (j *Jira) SetState(ctx context.Context, key string, state models.IssueState) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	var transitions struct {
+		Transitions []struct {
+			ID string     `json:"id"`
+			To jiraStatus `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := j.api.do(ctx, http.MethodGet, path, nil, &transitions); err != nil {
+		return err
+	}
+	for _, t := range transitions.Transitions {
+		if jiraState(t.To) == state {
+			return j.api.do(ctx, http.MethodPost, path, map[string]interface{}{"transition": map[string]string{"id": t.ID}}, nil)
+		}
+	}
+	return fmt.Errorf("%s has no transition to %s", key, state)
+}
+
+// Normalize returns state unchanged; every issue state maps to a Jira status category
+func (j *Jira) Normalize(state models.IssueState) models.IssueState {
+	return state
+}
+
+// AddComment comments on an issue
+func (j *Jira) AddComment(ctx context.Context, key, body string) (*models.TrackerComment, error) {
+	var comment jiraComment
+	if err := j.api.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment", map[string]string{"body": body}, &comment); err != nil {
+		return nil, err
+	}
+	result := comment.toModel()
+	return &result, nil
+}
+
+// ParseWebhook reads jira:issue_updated and comment_created webhooks. Jira signs webhooks that
+// have a secret with an X-Hub-Signature header.
+func (j *Jira) ParseWebhook(header http.Header, body []byte) (*models.TrackerEvent, error) {
+	if err := verify(j.secret, body, header.Get("X-Hub-Signature")); err != nil {
+		return nil, err
+	}
+	var payload struct {
+		WebhookEvent string       `json:"webhookEvent"`
+		Issue        *jiraIssue   `json:"issue"`
+		Comment      *jiraComment `json:"comment"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if payload.Issue == nil || payload.Issue.Key == "" {
+		return nil, fmt.Errorf("%w: %s has no issue", ErrIgnored, payload.WebhookEvent)
+	}
+
+	switch payload.WebhookEvent {
+	case "jira:issue_updated":
+		state := jiraState(payload.Issue.Fields.Status)
+		if state == "" {
+			return nil, fmt.Errorf("%w: %s has no status", ErrIgnored, payload.Issue.Key)
+		}
+		return &models.TrackerEvent{Key: payload.Issue.Key, State: state}, nil
+	case "comment_created":
+		if payload.Comment == nil {
+			return nil, fmt.Errorf("%w: comment_created without a comment", ErrInvalidPayload)
+		}
+		comment := payload.Comment.toModel()
+		return &models.TrackerEvent{Key: payload.Issue.Key, Comment: &comment}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrIgnored, payload.WebhookEvent)
+	}
+}
+
+func (j *Jira) issueURL(key string) string {
+	return j.baseURL + "/browse/" + key
+}
+
+func (c jiraComment) toModel() models.TrackerComment {
+	created, _ := time.Parse(jiraTimeLayout, c.Created)
+	return models.TrackerComment{ID: c.ID, Author: c.Author.DisplayName, Body: c.Body, CreatedAt: created}
+}
+
+// jiraState maps Jira's status categories, "new", "indeterminate" and "done", to issue states
+func jiraState(status jiraStatus) models.IssueState {
+	switch status.StatusCategory.Key {
+	case "new":
+		return models.IssueOpen
+	case "indeterminate":
+		return models.IssueInProgress
+	case "done":
+		return models.IssueDone
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,190 @@
+// Package tracker connects incidents and action items to issues in Jira and GitHub Issues.
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+)
+
+// Type selects a tracker's API
+type Type string
+
+const (
+	TypeJira   Type = "jira"
+	TypeGitHub Type = "github"
+)
+
+// requestTimeout bounds a single API call
+const requestTimeout = 15 * time.Second
+
+var (
+	// ErrInvalidSignature is returned for webhooks whose signature does not match the configured secret
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrIgnored is returned for valid webhooks that do not change a linked issue, such as pings
+	ErrIgnored = errors.New("webhook event ignored")
+	// ErrInvalidPayload is returned for webhooks that cannot be read
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+)
+
+// Connector opens, reads and updates issues in one tracker, and reads its webhooks
+type Connector interface {
+	Name() string
+	CreateIssue(ctx context.Context, issue models.NewTrackerIssue) (*models.TrackerIssue, error)
+	// GetIssue returns an issue with its state and comments
+	GetIssue(ctx context.Context, key string) (*models.TrackerIssue, error)
+	SetState(ctx context.Context, key string, state models.IssueState) error
+	// Normalize returns the closest state the tracker can hold, so states it cannot tell apart compare equal
+	Normalize(state models.IssueState) models.IssueState
+	AddComment(ctx context.Context, key, body string) (*models.TrackerComment, error)
+	// ParseWebhook verifies and reads a webhook delivery
+	ParseWebhook(header http.Header, body []byte) (*models.TrackerEvent, error)
+}
+
+// TrackerConfig describes one tracker. Jira uses Project and IssueType and authenticates with Email and
+// Token as basic auth, or Token alone as a bearer token. GitHub uses Repo ("owner/name") and Token.
+// Webhooks must carry an HMAC-SHA256 signature made with WebhookSecret. A tracker without a secret is
+// rejected unless InsecureWebhooks is set, which accepts unsigned webhooks.
+type TrackerConfig struct {
+	Name          string   `json:"name"`
+	Type          Type     `json:"type"`
+	BaseURL       string   `json:"base_url,omitempty"`
+	Project       string   `json:"project,omitempty"`
+	IssueType     string   `json:"issue_type,omitempty"`
+	Repo          string   `json:"repo,omitempty"`
+	Email         string   `json:"email,omitempty"`
+	Token         string   `json:"token,omitempty"`
+	WebhookSecret string   `json:"webhook_secret,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	// InsecureWebhooks must be set explicitly to run without a WebhookSecret
+	InsecureWebhooks bool `json:"insecure_webhooks,omitempty"`
+}
+
+// Config lists the configured trackers. The first one is used when a request does not name one.
+// SyncInterval is a duration such as "5m" between reconciliations.
+type Config struct {
+	Trackers     []TrackerConfig `json:"trackers"`
+	SyncInterval string          `json:"sync_interval,omitempty"`
+}
+
+// LoadConfig reads a JSON tracker configuration. Environment variables in emails, tokens and webhook
+// secrets are expanded so credentials can stay out of the file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read tracker config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid tracker config %s: %w", path, err)
+	}
+	for i := range cfg.Trackers {
+		tc := &cfg.Trackers[i]
+		tc.Email = os.ExpandEnv(tc.Email)
+		tc.Token = os.ExpandEnv(tc.Token)
+		tc.WebhookSecret = os.ExpandEnv(tc.WebhookSecret)
+	}
+	return cfg, nil
+}
+
+// New creates the connector for a tracker
+func New(cfg TrackerConfig) (Connector, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("tracker name is required")
+	}
+	if cfg.WebhookSecret == "" && !cfg.InsecureWebhooks {
+		return nil, fmt.Errorf("tracker %s: webhook_secret is required unless insecure_webhooks is set", cfg.Name)
+	}
+	switch cfg.Type {
+	case TypeJira:
+		return NewJira(cfg)
+	case TypeGitHub:
+		return NewGitHub(cfg)
+	default:
+		return nil, fmt.Errorf("tracker %s: unknown type %q", cfg.Name, cfg.Type)
+	}
+}
+
+// Sign returns the webhook signature both trackers use: "sha256=" followed by the hex HMAC-SHA256 of the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks a signature made by Sign; without a secret, which New only allows with InsecureWebhooks,
+// every delivery is accepted
+func verify(secret string, body []byte, signature string) error {
+	if secret == "" {
+		return nil
+	}
+	if !hmac.Equal([]byte(Sign(secret, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// client makes authenticated JSON calls to a tracker API
+type client struct {
+	baseURL   string
+	authorize func(*http.Request)
+	http      *http.Client
+}
+
+func newClient(baseURL string, authorize func(*http.Request)) *client {
+	return &client{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		authorize: authorize,
+		http:      &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// do sends payload as JSON and decodes the response into out when it is not nil.
+// Any non-2xx response is an error that includes the start of the response body.
+func (c *client) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.authorize(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: unexpected status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,307 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Prakash-sa/terraform-aws/app/pkg/models"
+	"github.com/gorilla/mux"
+)
+
+// fakeJira serves the parts of the Jira REST API the connector uses
+type fakeJira struct {
+	mu       sync.Mutex
+	category map[string]string
+	comments map[string][]map[string]interface{}
+	labels   []string
+}
+
+func newFakeJira(t *testing.T) *httptest.Server {
+	f := &fakeJira{category: map[string]string{}, comments: map[string][]map[string]interface{}{}}
+	r := mux.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if user, token, ok := req.BasicAuth(); !ok || user != "bot@example.com" || token != "jira-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			next.ServeHTTP(w, req)
+		})
+	})
+	r.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Fields struct {
+				Project struct{ Key string } `json:"project"`
+				Labels  []string             `json:"labels"`
+			} `json:"fields"`
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		key := fmt.Sprintf("%s-%d", body.Fields.Project.Key, len(f.category)+1)
+		f.category[key] = "new"
+		f.labels = body.Fields.Labels
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"id": "10001", "key": key})
+	}).Methods(http.MethodPost)
+	r.HandleFunc("/rest/api/2/issue/{key}", func(w http.ResponseWriter, req *http.Request) {
+		key := mux.Vars(req)["key"]
+		category, ok := f.category[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"key": key,
+			"fields": map[string]interface{}{
+				"status":  map[string]interface{}{"statusCategory": map[string]string{"key": category}},
+				"comment": map[string]interface{}{"comments": f.comments[key]},
+			},
+		})
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/rest/api/2/issue/{key}/transitions", func(w http.ResponseWriter, req *http.Request) {
+		transition := func(id, category string) map[string]interface{} {
+			return map[string]interface{}{"id": id, "to": map[string]interface{}{"statusCategory": map[string]string{"key": category}}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"transitions": []interface{}{
+			transition("11", "new"), transition("21", "indeterminate"), transition("31", "done"),
+		}})
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/rest/api/2/issue/{key}/transitions", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Transition struct{ ID string } `json:"transition"`
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		f.category[mux.Vars(req)["key"]] = map[string]string{"11": "new", "21": "indeterminate", "31": "done"}[body.Transition.ID]
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods(http.MethodPost)
+	r.HandleFunc("/rest/api/2/issue/{key}/comment", func(w http.ResponseWriter, req *http.Request) {
+		var body struct{ Body string }
+		json.NewDecoder(req.Body).Decode(&body)
+		key := mux.Vars(req)["key"]
+		comment := map[string]interface{}{
+			"id":      strconv.Itoa(10000 + len(f.comments[key])),
+			"body":    body.Body,
+			"author":  map[string]string{"displayName": "Incident Bot"},
+			"created": "2024-03-11T14:05:00.000+0000",
+		}
+		f.comments[key] = append(f.comments[key], comment)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(comment)
+	}).Methods(http.MethodPost)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fakeGitHub serves the parts of the GitHub Issues API the connector uses for acme/incidents
+type fakeGitHub struct {
+	mu       sync.Mutex
+	state    map[int]string
+	comments map[int][]map[string]interface{}
+}
+
+func newFakeGitHub(t *testing.T) *httptest.Server {
+	f := &fakeGitHub{state: map[int]string{}, comments: map[int][]map[string]interface{}{}}
+	r := mux.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Authorization") != "Bearer gh-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			next.ServeHTTP(w, req)
+		})
+	})
+	issue := func(w http.ResponseWriter, number int) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"number":   number,
+			"html_url": fmt.Sprintf("https://github.com/acme/incidents/issues/%d", number),
+			"state":    f.state[number],
+		})
+	}
+	number := func(req *http.Request) int {
+		n, _ := strconv.Atoi(mux.Vars(req)["number"])
+		return n
+	}
+	r.HandleFunc("/repos/acme/incidents/issues", func(w http.ResponseWriter, req *http.Request) {
+		n := len(f.state) + 1
+		f.state[n] = "open"
+		w.WriteHeader(http.StatusCreated)
+		issue(w, n)
+	}).Methods(http.MethodPost)
+	r.HandleFunc("/repos/acme/incidents/issues/{number}", func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := f.state[number(req)]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		issue(w, number(req))
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/repos/acme/incidents/issues/{number}", func(w http.ResponseWriter, req *http.Request) {
+		var body struct{ State string }
+		json.NewDecoder(req.Body).Decode(&body)
+		f.state[number(req)] = body.State
+		issue(w, number(req))
+	}).Methods(http.MethodPatch)
+	r.HandleFunc("/repos/acme/incidents/issues/{number}/comments", func(w http.ResponseWriter, req *http.Request) {
+		comments := f.comments[number(req)]
+		if comments == nil {
+			comments = []map[string]interface{}{}
+		}
+		json.NewEncoder(w).Encode(comments)
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/repos/acme/incidents/issues/{number}/comments", func(w http.ResponseWriter, req *http.Request) {
+		var body struct{ Body string }
+		json.NewDecoder(req.Body).Decode(&body)
+		comment := map[string]interface{}{
+			"id":         900 + len(f.comments[number(req)]),
+			"body":       body.Body,
+			"user":       map[string]string{"login": "incident-bot"},
+			"created_at": "2024-03-11T14:05:00Z",
+		}
+		f.comments[number(req)] = append(f.comments[number(req)], comment)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(comment)
+	}).Methods(http.MethodPost)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestJira(t *testing.T) {
+	server := newFakeJira(t)
+	jira, err := New(TrackerConfig{Name: "jira", Type: TypeJira, BaseURL: server.URL, Project: "OPS",
+		Email: "bot@example.com", Token: "jira-token", WebhookSecret: "hook-secret", Labels: []string{"sre"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	issue, err := jira.CreateIssue(ctx, models.NewTrackerIssue{Title: "Checkout errors", Body: "5xx", Labels: []string{"incident"}})
+	if err != nil || issue.Key != "OPS-1" || issue.URL != server.URL+"/browse/OPS-1" || issue.State != models.IssueOpen {
+		t.Fatalf("expected OPS-1 to be created, got %+v (%v)", issue, err)
+	}
+	if err := jira.SetState(ctx, "OPS-1", models.IssueInProgress); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := jira.AddComment(ctx, "OPS-1", "Rolled back"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := jira.GetIssue(ctx, "OPS-1")
+	if err != nil || got.State != models.IssueInProgress || len(got.Comments) != 1 {
+		t.Fatalf("expected an in-progress issue with one comment, got %+v (%v)", got, err)
+	}
+	if c := got.Comments[0]; c.Author != "Incident Bot" || c.Body != "Rolled back" || !c.CreatedAt.Equal(time.Date(2024, 3, 11, 14, 5, 0, 0, time.UTC)) {
+		t.Errorf("unexpected comment: %+v", c)
+	}
+	if _, err := jira.GetIssue(ctx, "OPS-99"); err == nil {
+		t.Error("expected an error for a missing issue")
+	}
+
+	updated := []byte(`{"webhookEvent":"jira:issue_updated","issue":{"key":"OPS-1","fields":{"status":{"statusCategory":{"key":"done"}}}}}`)
+	commented := []byte(`{"webhookEvent":"comment_created","issue":{"key":"OPS-1"},"comment":{"id":"10007","body":"Fixed in v2","author":{"displayName":"Dana"},"created":"2024-03-11T15:00:00.000+0000"}}`)
+	deleted := []byte(`{"webhookEvent":"jira:issue_deleted","issue":{"key":"OPS-1"}}`)
+	signed := func(body []byte) http.Header {
+		return http.Header{"X-Hub-Signature": {Sign("hook-secret", body)}}
+	}
+
+	if event, err := jira.ParseWebhook(signed(updated), updated); err != nil || event.Key != "OPS-1" || event.State != models.IssueDone {
+		t.Errorf("expected OPS-1 to be done, got %+v (%v)", event, err)
+	}
+	if event, err := jira.ParseWebhook(signed(commented), commented); err != nil || event.Comment == nil || event.Comment.Author != "Dana" {
+		t.Errorf("expected a comment from Dana, got %+v (%v)", event, err)
+	}
+	if _, err := jira.ParseWebhook(signed(deleted), deleted); !errors.Is(err, ErrIgnored) {
+		t.Errorf("expected ErrIgnored, got %v", err)
+	}
+	if _, err := jira.ParseWebhook(signed(commented), updated); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestGitHub(t *testing.T) {
+	server := newFakeGitHub(t)
+	github, err := New(TrackerConfig{Name: "github", Type: TypeGitHub, BaseURL: server.URL, Repo: "acme/incidents", Token: "gh-token",
+		InsecureWebhooks: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	issue, err := github.CreateIssue(ctx, models.NewTrackerIssue{Title: "Checkout errors", Body: "5xx"})
+	if err != nil || issue.Key != "acme/incidents#1" || issue.URL != "https://github.com/acme/incidents/issues/1" {
+		t.Fatalf("expected acme/incidents#1 to be created, got %+v (%v)", issue, err)
+	}
+	if err := github.SetState(ctx, issue.Key, models.IssueDone); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	github.AddComment(ctx, issue.Key, "Closed after the rollback")
+	got, err := github.GetIssue(ctx, issue.Key)
+	if err != nil || got.State != models.IssueDone || len(got.Comments) != 1 || got.Comments[0].ID != "900" || got.Comments[0].Author != "incident-bot" {
+		t.Fatalf("expected a closed issue with one comment, got %+v (%v)", got, err)
+	}
+	if github.Normalize(models.IssueInProgress) != models.IssueOpen {
+		t.Error("expected in progress to fold into open")
+	}
+	if _, err := github.GetIssue(ctx, "other/repo#1"); err == nil {
+		t.Error("expected an error for an issue in another repository")
+	}
+
+	reopened := []byte(`{"action":"reopened","issue":{"number":1,"state":"open"},"repository":{"full_name":"acme/incidents"}}`)
+	commented := []byte(`{"action":"created","issue":{"number":1,"state":"open"},"comment":{"id":901,"body":"Still failing","user":{"login":"dana"},"created_at":"2024-03-11T15:00:00Z"},"repository":{"full_name":"acme/incidents"}}`)
+	elsewhere := []byte(`{"action":"closed","issue":{"number":1,"state":"closed"},"repository":{"full_name":"acme/web"}}`)
+	header := func(event string) http.Header {
+		return http.Header{"X-Github-Event": {event}}
+	}
+
+	if event, err := github.ParseWebhook(header("issues"), reopened); err != nil || event.Key != "acme/incidents#1" || event.State != models.IssueOpen {
+		t.Errorf("expected the issue to be reopened, got %+v (%v)", event, err)
+	}
+	if event, err := github.ParseWebhook(header("issue_comment"), commented); err != nil || event.Comment == nil || event.Comment.ID != "901" {
+		t.Errorf("expected comment 901, got %+v (%v)", event, err)
+	}
+	if _, err := github.ParseWebhook(header("issues"), elsewhere); !errors.Is(err, ErrIgnored) {
+		t.Errorf("expected ErrIgnored for another repository, got %v", err)
+	}
+	if _, err := github.ParseWebhook(header("ping"), []byte(`{}`)); !errors.Is(err, ErrIgnored) {
+		t.Errorf("expected ErrIgnored for a ping, got %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("GH_TOKEN", "gh-token")
+	path := filepath.Join(t.TempDir(), "trackers.json")
+	os.WriteFile(path, []byte(`{"trackers": [{"name": "github", "type": "github", "repo": "acme/incidents", "token": "${GH_TOKEN}"}], "sync_interval": "1m"}`), 0o644)
+
+	cfg, err := LoadConfig(path)
+	if err != nil || cfg.Trackers[0].Token != "gh-token" || cfg.SyncInterval != "1m" {
+		t.Fatalf("expected the token to be expanded, got %+v (%v)", cfg, err)
+	}
+
+	invalid := []TrackerConfig{
+		{Type: TypeGitHub, Repo: "acme/incidents", Token: "x", WebhookSecret: "s"},
+		{Name: "github", Type: TypeGitHub, Repo: "acme", Token: "x", WebhookSecret: "s"},
+		{Name: "github", Type: TypeGitHub, Repo: "acme/incidents", Token: "x"},
+		{Name: "jira", Type: TypeJira, BaseURL: "https://example.atlassian.net", Token: "x", WebhookSecret: "s"},
+		{Name: "linear", Type: "linear", WebhookSecret: "s"},
+	}
+	for _, tc := range invalid {
+		if _, err := New(tc); err == nil {
+			t.Errorf("%+v: expected an error", tc)
+		}
+	}
+}
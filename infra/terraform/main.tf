@@ -121,8 +121,10 @@ module "codepipeline" {
   pipeline_name = "${var.project_name}-pipeline"
   environment   = var.environment
   
-  s3_bucket_name     = module.s3.bucket_name
-  codebuild_project  = module.codebuild_project.project_name
-  github_repo        = var.github_repository
-  github_branch      = var.github_branch
+  s3_bucket_name        = module.s3.bucket_name
+  codebuild_project     = module.codebuild_project.project_name
+  github_repo           = var.github_repository
+  github_branch         = var.github_branch
+  change_webhook_url    = var.incident_change_webhook_url
+  change_webhook_secret = var.incident_change_webhook_secret
 }
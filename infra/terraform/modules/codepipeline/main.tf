@@ -28,6 +28,19 @@ variable "github_branch" {
   type        = string
 }
 
+variable "change_webhook_url" {
+  description = "Incident service change webhook, e.g. https://api.example.com/api/v1/changes/webhooks/aws; empty disables forwarding"
+  type        = string
+  default     = ""
+}
+
+variable "change_webhook_secret" {
+  description = "The incident service's CHANGE_WEBHOOK_SECRET, sent in the X-Change-Token header; required with change_webhook_url"
+  type        = string
+  default     = ""
+  sensitive   = true
+}
+
 resource "aws_iam_role" "codepipeline_role" {
   name = "${var.pipeline_name}-role"
 
@@ -130,6 +143,87 @@ resource "aws_codepipeline" "this" {
   }
 }
 
+# Forward successful pipeline runs to the incident service so incidents can be correlated with deploys
+resource "aws_cloudwatch_event_connection" "incident_changes" {
+  count              = var.change_webhook_url == "" ? 0 : 1
+  name               = "${var.pipeline_name}-incident-changes"
+  authorization_type = "API_KEY"
+
+  auth_parameters {
+    api_key {
+      key   = "X-Change-Token"
+      value = var.change_webhook_secret
+    }
+  }
+
+  lifecycle {
+    precondition {
+      condition     = var.change_webhook_secret != ""
+      error_message = "change_webhook_secret is required when change_webhook_url is set."
+    }
+  }
+}
+
+resource "aws_cloudwatch_event_api_destination" "incident_changes" {
+  count                            = var.change_webhook_url == "" ? 0 : 1
+  name                             = "${var.pipeline_name}-incident-changes"
+  invocation_endpoint              = var.change_webhook_url
+  http_method                      = "POST"
+  invocation_rate_limit_per_second = 10
+  connection_arn                   = aws_cloudwatch_event_connection.incident_changes[0].arn
+}
+
+resource "aws_cloudwatch_event_rule" "pipeline_succeeded" {
+  count       = var.change_webhook_url == "" ? 0 : 1
+  name        = "${var.pipeline_name}-succeeded"
+  description = "Successful ${var.pipeline_name} executions"
+
+  event_pattern = jsonencode({
+    source        = ["aws.codepipeline"]
+    "detail-type" = ["CodePipeline Pipeline Execution State Change"]
+    detail = {
+      pipeline = [aws_codepipeline.this.name]
+      state    = ["SUCCEEDED"]
+    }
+  })
+}
+
+resource "aws_iam_role" "incident_changes" {
+  count = var.change_webhook_url == "" ? 0 : 1
+  name  = "${var.pipeline_name}-incident-changes"
+
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect    = "Allow"
+      Principal = { Service = "events.amazonaws.com" }
+      Action    = "sts:AssumeRole"
+    }]
+  })
+}
+
+resource "aws_iam_role_policy" "incident_changes" {
+  count = var.change_webhook_url == "" ? 0 : 1
+  name  = "${var.pipeline_name}-incident-changes"
+  role  = aws_iam_role.incident_changes[0].id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect   = "Allow"
+      Action   = ["events:InvokeApiDestination"]
+      Resource = aws_cloudwatch_event_api_destination.incident_changes[0].arn
+    }]
+  })
+}
+
+resource "aws_cloudwatch_event_target" "incident_changes" {
+  count    = var.change_webhook_url == "" ? 0 : 1
+  rule     = aws_cloudwatch_event_rule.pipeline_succeeded[0].name
+  arn      = aws_cloudwatch_event_api_destination.incident_changes[0].arn
+  role_arn = aws_iam_role.incident_changes[0].arn
+}
+
 output "pipeline_name" {
   value       = aws_codepipeline.this.name
   description = "Pipeline name"
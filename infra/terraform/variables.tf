@@ -106,6 +106,21 @@ variable "github_branch" {
   default     = "main"
 }
 
+# Incident service change webhook, e.g. https://api.example.com/api/v1/changes/webhooks/aws
+variable "incident_change_webhook_url" {
+  description = "Where successful pipeline runs are reported for incident correlation; empty disables it"
+  type        = string
+  default     = ""
+}
+
+# Must match CHANGE_WEBHOOK_SECRET on the incident service
+variable "incident_change_webhook_secret" {
+  description = "Shared secret that authenticates pipeline runs reported to the incident service"
+  type        = string
+  default     = ""
+  sensitive   = true
+}
+
 # Tags
 variable "tags" {
   description = "Common tags for all resources"